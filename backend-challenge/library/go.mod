@@ -4,6 +4,7 @@ go 1.23
 
 require (
 	github.com/stretchr/testify v1.10.0
+	go.opentelemetry.io/otel/trace v1.34.0
 	go.uber.org/mock v0.5.2
 	gopkg.in/yaml.v3 v3.0.1
 )
@@ -13,5 +14,6 @@ require (
 	github.com/kr/pretty v0.3.0 // indirect
 	github.com/pmezard/go-difflib v1.0.0 // indirect
 	github.com/rogpeppe/go-internal v1.8.0 // indirect
+	go.opentelemetry.io/otel v1.34.0 // indirect
 	gopkg.in/check.v1 v1.0.0-20201130134442-10cb98267c6c // indirect
 )
@@ -1,6 +1,7 @@
 package logger
 
 import (
+	"context"
 	"io"
 )
 
@@ -33,6 +34,12 @@ type ILogger interface {
 	// The message is formatted using fmt.Sprintf with the provided format and arguments.
 	Fatal(format string, args ...interface{})
 
+	// WithContext returns a logger that writes the request ID set by ContextWithRequestID and the trace
+	// and span IDs of the span in ctx with every entry, either in the {request_id}, {trace_id} and {span_id}
+	// placeholders of the log format or after the message.
+	// Use it to correlate log entries with the request being served.
+	WithContext(ctx context.Context) ILogger
+
 	// Close closes the logger and flushes any remaining data.
 	// This method should be called when the logger is no longer needed to ensure
 	// all buffered data is written and resources are properly cleaned up.
@@ -1,6 +1,7 @@
 package logger
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"os"
@@ -9,6 +10,8 @@ import (
 	"strings"
 	"sync"
 	"time"
+
+	"go.opentelemetry.io/otel/trace"
 )
 
 // LogLevel defines the severity level for a log message.
@@ -146,6 +149,35 @@ type logEntry struct {
 	message string
 	time    time.Time
 	caller  string
+	correlation
+}
+
+// correlation identifies the request and trace a log entry was written for.
+type correlation struct {
+	requestID string
+	traceID   string
+	spanID    string
+}
+
+// contextLogger is a Logger writing the request ID and trace of a context with every entry.
+type contextLogger struct {
+	*Logger
+	correlation
+}
+
+// requestIDKey is the context key of the request ID.
+type requestIDKey struct{}
+
+// ContextWithRequestID returns a copy of ctx holding the ID of the request it serves,
+// for the loggers returned by WithContext to write with every entry.
+func ContextWithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, requestID)
+}
+
+// RequestIDFromContext returns the request ID held by ctx, or an empty string.
+func RequestIDFromContext(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDKey{}).(string)
+	return requestID
 }
 
 // NewLogger creates a new logger instance
@@ -258,6 +290,17 @@ func (l *Logger) formatMessage(entry logEntry) string {
 		message = replacePlaceholder(message, "{caller}", entry.caller)
 	}
 
+	// Entries of a request or traced context carry their IDs, in the format's placeholders or after the message
+	if entry.traceID != "" && !strings.Contains(message, "{trace_id}") {
+		message = replacePlaceholder(message, "{message}", "{message} trace_id={trace_id} span_id={span_id}")
+	}
+	if entry.requestID != "" && !strings.Contains(message, "{request_id}") {
+		message = replacePlaceholder(message, "{message}", "{message} request_id={request_id}")
+	}
+	message = replacePlaceholder(message, "{request_id}", entry.requestID)
+	message = replacePlaceholder(message, "{trace_id}", entry.traceID)
+	message = replacePlaceholder(message, "{span_id}", entry.spanID)
+
 	message = replacePlaceholder(message, "{message}", entry.message)
 	return message
 }
@@ -270,42 +313,83 @@ func replacePlaceholder(format, placeholder, value string) string {
 // Debug logs a message at the DEBUG level.
 // Use this for verbose output useful for debugging during development.
 func (l *Logger) Debug(format string, args ...interface{}) {
-	l.log(DEBUG, format, args...)
+	l.log(DEBUG, correlation{}, format, args...)
 }
 
 // Info logs a message at the INFO level.
 // Use this for general application events or high-level progress reporting.
 func (l *Logger) Info(format string, args ...interface{}) {
-	l.log(INFO, format, args...)
+	l.log(INFO, correlation{}, format, args...)
 }
 
 // Warn logs a message at the WARN level.
 // Use this when something unexpected happens, but the app can recover or continue.
 func (l *Logger) Warn(format string, args ...interface{}) {
-	l.log(WARN, format, args...)
+	l.log(WARN, correlation{}, format, args...)
 }
 
 // Error logs a message at the ERROR level.
 // Use this for serious issues that should be investigated but don't require immediate shutdown.
 func (l *Logger) Error(format string, args ...interface{}) {
-	l.log(ERROR, format, args...)
+	l.log(ERROR, correlation{}, format, args...)
 }
 
 // Fatal logs a message at the FATAL level and then exits the application with status code 1.
 // Use this when a non-recoverable error occurs that requires the app to terminate.
 func (l *Logger) Fatal(format string, args ...interface{}) {
-	l.log(FATAL, format, args...)
+	l.log(FATAL, correlation{}, format, args...)
+	os.Exit(1)
+}
+
+// WithContext returns a logger writing the request ID and the trace and span IDs of the span
+// in ctx with every entry. The logger itself is returned if ctx holds neither.
+func (l *Logger) WithContext(ctx context.Context) ILogger {
+	ids := correlation{requestID: RequestIDFromContext(ctx)}
+	if spanContext := trace.SpanContextFromContext(ctx); spanContext.IsValid() {
+		ids.traceID = spanContext.TraceID().String()
+		ids.spanID = spanContext.SpanID().String()
+	}
+	if ids == (correlation{}) {
+		return l
+	}
+	return &contextLogger{Logger: l, correlation: ids}
+}
+
+// Debug logs a message at the DEBUG level with the request ID and trace of the logger's context.
+func (l *contextLogger) Debug(format string, args ...interface{}) {
+	l.log(DEBUG, l.correlation, format, args...)
+}
+
+// Info logs a message at the INFO level with the request ID and trace of the logger's context.
+func (l *contextLogger) Info(format string, args ...interface{}) {
+	l.log(INFO, l.correlation, format, args...)
+}
+
+// Warn logs a message at the WARN level with the request ID and trace of the logger's context.
+func (l *contextLogger) Warn(format string, args ...interface{}) {
+	l.log(WARN, l.correlation, format, args...)
+}
+
+// Error logs a message at the ERROR level with the request ID and trace of the logger's context.
+func (l *contextLogger) Error(format string, args ...interface{}) {
+	l.log(ERROR, l.correlation, format, args...)
+}
+
+// Fatal logs a message at the FATAL level with the request ID and trace of the logger's context and then exits the application.
+func (l *contextLogger) Fatal(format string, args ...interface{}) {
+	l.log(FATAL, l.correlation, format, args...)
 	os.Exit(1)
 }
 
 // log is the internal logging method
-func (l *Logger) log(level LogLevel, format string, args ...interface{}) {
+func (l *Logger) log(level LogLevel, ids correlation, format string, args ...interface{}) {
 	message := fmt.Sprintf(format, args...)
 	entry := logEntry{
-		level:   level,
-		message: message,
-		time:    time.Now(),
-		caller:  getCaller(),
+		level:       level,
+		message:     message,
+		time:        time.Now(),
+		caller:      getCaller(),
+		correlation: ids,
 	}
 
 	l.writeLogEntry(entry)
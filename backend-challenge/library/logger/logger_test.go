@@ -1,6 +1,7 @@
 package logger
 
 import (
+	"context"
 	"io"
 	"os"
 	"path/filepath"
@@ -9,6 +10,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/trace"
 )
 
 func TestLogLevel_String(t *testing.T) {
@@ -307,3 +309,81 @@ func TestGetCaller(t *testing.T) {
 	assert.NotEmpty(t, caller)
 	assert.Contains(t, caller, ".go:")
 }
+
+func TestLogger_WithContext(t *testing.T) {
+	spanContext := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    trace.TraceID{0x4b, 0xf9, 0x2f, 0x35, 0x77, 0xb3, 0x4d, 0xa6, 0xa3, 0xce, 0x92, 0x9d, 0x0e, 0x0e, 0x47, 0x36},
+		SpanID:     trace.SpanID{0x00, 0xf0, 0x67, 0xaa, 0x0b, 0xa9, 0x02, 0xb7},
+		TraceFlags: trace.FlagsSampled,
+	})
+	traced := trace.ContextWithSpanContext(context.Background(), spanContext)
+	requested := ContextWithRequestID(context.Background(), "req-42")
+
+	tests := []struct {
+		name      string
+		logFormat string
+		ctx       context.Context
+		expected  string
+	}{
+		{
+			name:      "trace appended to the message",
+			logFormat: "[{level}] {message}",
+			ctx:       traced,
+			expected:  "[ERROR] order failed trace_id=4bf92f3577b34da6a3ce929d0e0e4736 span_id=00f067aa0ba902b7\n",
+		},
+		{
+			name:      "trace in the format's placeholders",
+			logFormat: "[{level}] [{trace_id}/{span_id}] {message}",
+			ctx:       traced,
+			expected:  "[ERROR] [4bf92f3577b34da6a3ce929d0e0e4736/00f067aa0ba902b7] order failed\n",
+		},
+		{
+			name:      "request ID appended to the message",
+			logFormat: "[{level}] {message}",
+			ctx:       requested,
+			expected:  "[ERROR] order failed request_id=req-42\n",
+		},
+		{
+			name:      "request ID and trace appended to the message",
+			logFormat: "[{level}] {message}",
+			ctx:       trace.ContextWithSpanContext(requested, spanContext),
+			expected:  "[ERROR] order failed request_id=req-42 trace_id=4bf92f3577b34da6a3ce929d0e0e4736 span_id=00f067aa0ba902b7\n",
+		},
+		{
+			name:      "request ID in the format's placeholder",
+			logFormat: "[{level}] [{request_id}] {message}",
+			ctx:       requested,
+			expected:  "[ERROR] [req-42] order failed\n",
+		},
+		{
+			name:      "context without a request ID or span",
+			logFormat: "[{level}] {message}",
+			ctx:       context.Background(),
+			expected:  "[ERROR] order failed\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tempDir := t.TempDir()
+			config := &LogConfig{
+				OutputToFile:   true,
+				LogDir:         tempDir,
+				LogFilePath:    filepath.Join(tempDir, "test.log"),
+				FileWriterType: "simple",
+				Level:          DEBUG,
+				IncludeLevel:   true,
+				LogFormat:      tt.logFormat,
+			}
+			logger, err := NewLogger(config)
+			require.NoError(t, err)
+			defer logger.Close()
+
+			logger.WithContext(tt.ctx).Error("order %s", "failed")
+
+			output, err := os.ReadFile(config.LogFilePath)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, string(output))
+		})
+	}
+}
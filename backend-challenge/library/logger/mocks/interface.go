@@ -10,6 +10,7 @@
 package mocks
 
 import (
+	context "context"
 	io "io"
 	logger "library/logger"
 	reflect "reflect"
@@ -193,3 +194,17 @@ func (mr *MockILoggerMockRecorder) Warn(format any, args ...any) *gomock.Call {
 	varargs := append([]any{format}, args...)
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Warn", reflect.TypeOf((*MockILogger)(nil).Warn), varargs...)
 }
+
+// WithContext mocks base method.
+func (m *MockILogger) WithContext(ctx context.Context) logger.ILogger {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WithContext", ctx)
+	ret0, _ := ret[0].(logger.ILogger)
+	return ret0
+}
+
+// WithContext indicates an expected call of WithContext.
+func (mr *MockILoggerMockRecorder) WithContext(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithContext", reflect.TypeOf((*MockILogger)(nil).WithContext), ctx)
+}
@@ -20,28 +20,62 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/api/product": {
-            "get": {
-                "description": "Get all products",
+        "/api/cart": {
+            "post": {
+                "description": "Create a server-side cart, optionally holding items and a coupon. The cart is priced against the current catalog and expires after a period without changes. It belongs to the authenticated customer; the carts of other customers are reported as not found.",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "product"
+                    "cart"
+                ],
+                "summary": "Create a cart",
+                "parameters": [
+                    {
+                        "description": "Initial items and coupon",
+                        "name": "cart",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/models.CartCreateRequest"
+                        }
+                    }
                 ],
-                "summary": "List products",
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/service.ProductResponse"
+                            "$ref": "#/definitions/models.Cart"
+                        }
+                    },
+                    "400": {
+                        "description": "error\":\"Invalid input",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "error\":\"Product not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
                             }
                         }
                     },
+                    "422": {
+                        "description": "Validation exception, a product is unavailable (code product_unavailable), or the coupon cannot be redeemed (code is a coupon preview reason)",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProductUnavailableResponse"
+                        }
+                    },
                     "500": {
-                        "description": "error\":\"Failed to fetch products",
+                        "description": "error\":\"Failed to update cart",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -52,21 +86,21 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/product/{productId}": {
+        "/api/cart/{cartId}": {
             "get": {
-                "description": "Get a product by its ID",
+                "description": "Get a cart, re-priced against the current catalog",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "product"
+                    "cart"
                 ],
-                "summary": "Get product by ID",
+                "summary": "Get a cart",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Product ID",
-                        "name": "productId",
+                        "description": "Cart ID",
+                        "name": "cartId",
                         "in": "path",
                         "required": true
                     }
@@ -75,7 +109,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/service.ProductResponse"
+                            "$ref": "#/definitions/models.Cart"
                         }
                     },
                     "400": {
@@ -88,7 +122,7 @@ const docTemplate = `{
                         }
                     },
                     "404": {
-                        "description": "error\":\"Product not found",
+                        "description": "error\":\"Cart not found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -97,7 +131,55 @@ const docTemplate = `{
                         }
                     },
                     "500": {
-                        "description": "error\":\"Failed to fetch product",
+                        "description": "error\":\"Failed to fetch cart",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Discard a cart and everything in it",
+                "tags": [
+                    "cart"
+                ],
+                "summary": "Delete a cart",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Cart ID",
+                        "name": "cartId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "error\":\"Invalid ID supplied",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "error\":\"Cart not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "error\":\"Failed to delete cart",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -108,9 +190,83 @@ const docTemplate = `{
                 }
             }
         },
-        "/order": {
+        "/api/cart/{cartId}/checkout": {
             "post": {
-                "description": "Place a new order",
+                "description": "Place an order for the items and coupon of a cart and discard the cart. Fails like placing the order directly would; if a price or product version changed since the cart was last changed, the order is rejected with the changed lines and the cart is re-priced, so that it can be reviewed and checked out again.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "cart"
+                ],
+                "summary": "Check out a cart",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Cart ID",
+                        "name": "cartId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Unique key making retries of this request safe",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Order"
+                        }
+                    },
+                    "400": {
+                        "description": "error\":\"Invalid input",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "error\":\"Cart not found, or product not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Cart is out of date, products are out of stock (body is a models.OutOfStockResponse), or Idempotency-Key was already used with a different request",
+                        "schema": {
+                            "$ref": "#/definitions/models.StaleCartResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Cart is empty, validation exception, a product of the cart is unavailable (code product_unavailable), or the coupon cannot be redeemed (code is a coupon preview reason)",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProductUnavailableResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "error\":\"Failed to place an order",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/cart/{cartId}/coupon": {
+            "put": {
+                "description": "Apply a coupon to a cart, replacing any previous one. A valid coupon whose rule does not apply to the current items is kept and its couponReason is reported.",
                 "consumes": [
                     "application/json"
                 ],
@@ -118,17 +274,24 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "order"
+                    "cart"
                 ],
-                "summary": "Place an order",
+                "summary": "Apply a coupon to a cart",
                 "parameters": [
                     {
-                        "description": "Order request",
-                        "name": "order",
+                        "type": "string",
+                        "description": "Cart ID",
+                        "name": "cartId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Coupon code",
+                        "name": "coupon",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.OrderCreateRequest"
+                            "$ref": "#/definitions/models.CartCouponRequest"
                         }
                     }
                 ],
@@ -136,7 +299,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.Order"
+                            "$ref": "#/definitions/models.Cart"
                         }
                     },
                     "400": {
@@ -149,7 +312,16 @@ const docTemplate = `{
                         }
                     },
                     "404": {
-                        "description": "error\":\"Product not found",
+                        "description": "error\":\"Cart not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "error\":\"Cart was modified concurrently",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -158,7 +330,7 @@ const docTemplate = `{
                         }
                     },
                     "422": {
-                        "description": "error\":\"Validation exception",
+                        "description": "Validation exception, or the coupon cannot be redeemed (code is a coupon preview reason)",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -167,7 +339,7 @@ const docTemplate = `{
                         }
                     },
                     "500": {
-                        "description": "error\":\"Failed to place an order",
+                        "description": "error\":\"Failed to update cart",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -176,66 +348,1953 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        }
-    },
-    "definitions": {
-        "models.Order": {
-            "type": "object",
-            "properties": {
-                "id": {
-                    "type": "string"
-                },
-                "items": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/models.OrderItem"
-                    }
-                },
-                "products": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/models.Product"
+            },
+            "delete": {
+                "description": "Remove the coupon applied to a cart, if any",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "cart"
+                ],
+                "summary": "Remove the coupon from a cart",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Cart ID",
+                        "name": "cartId",
+                        "in": "path",
+                        "required": true
                     }
-                }
-            }
-        },
-        "models.OrderCreateRequest": {
-            "type": "object",
-            "properties": {
-                "couponCode": {
-                    "type": "string"
-                },
-                "items": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/models.OrderItem"
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Cart"
+                        }
+                    },
+                    "400": {
+                        "description": "error\":\"Invalid input",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "error\":\"Cart not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "error\":\"Cart was modified concurrently",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "error\":\"Failed to update cart",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
                     }
                 }
             }
         },
-        "models.OrderItem": {
-            "type": "object",
-            "properties": {
-                "productId": {
-                    "type": "string"
-                },
-                "quantity": {
+        "/api/cart/{cartId}/items": {
+            "post": {
+                "description": "Add units of a product to a cart. Units of a product already in the cart are added to its quantity.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "cart"
+                ],
+                "summary": "Add an item to a cart",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Cart ID",
+                        "name": "cartId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Product and quantity to add",
+                        "name": "item",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CartItem"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Cart"
+                        }
+                    },
+                    "400": {
+                        "description": "error\":\"Invalid input",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "error\":\"Cart not found, or product not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "error\":\"Cart was modified concurrently",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "422": {
+                        "description": "Validation exception, or the product is unavailable (code product_unavailable)",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProductUnavailableResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "error\":\"Failed to update cart",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/cart/{cartId}/items/{productId}": {
+            "put": {
+                "description": "Set the quantity of a product already in a cart",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "cart"
+                ],
+                "summary": "Change the quantity of a cart item",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Cart ID",
+                        "name": "cartId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Product ID",
+                        "name": "productId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "New quantity",
+                        "name": "item",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CartItemUpdateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Cart"
+                        }
+                    },
+                    "400": {
+                        "description": "error\":\"Invalid input",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "error\":\"Cart not found, or cart item not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "error\":\"Cart was modified concurrently",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "422": {
+                        "description": "error\":\"Validation exception",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "error\":\"Failed to update cart",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Remove a product from a cart",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "cart"
+                ],
+                "summary": "Remove an item from a cart",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Cart ID",
+                        "name": "cartId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Product ID",
+                        "name": "productId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Cart"
+                        }
+                    },
+                    "400": {
+                        "description": "error\":\"Invalid input",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "error\":\"Cart not found, or cart item not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "error\":\"Cart was modified concurrently",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "error\":\"Failed to update cart",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/category": {
+            "get": {
+                "description": "Get the active product categories in display order, with the number of products in each.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "category"
+                ],
+                "summary": "List categories",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.CategoryResponse"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "error\":\"Failed to fetch categories",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/coupon/{code}": {
+            "get": {
+                "description": "Check whether a coupon code would be accepted at checkout, without redeeming it. A code that does not apply is reported with applicable false and a reason.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "coupon"
+                ],
+                "summary": "Preview a coupon",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Coupon code",
+                        "name": "code",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.CouponPreviewResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "error\":\"Failed to check coupon",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/order": {
+            "get": {
+                "description": "List placed orders, newest first, optionally filtered by creation time range and status. Customers, identified by their bearer token or API key, only see their own orders unless granted the orders:admin scope.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "order"
+                ],
+                "summary": "List orders",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Only orders created at or after this time (RFC3339)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only orders created at or before this time (RFC3339)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only orders in this status",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size (default 20, max 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of orders to skip",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.OrderListResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "error\":\"Invalid input",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "error\":\"Failed to fetch orders",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/order/{orderId}": {
+            "get": {
+                "description": "Get a previously placed order by its ID. Customers, identified by their bearer token or API key, only see their own orders unless granted the orders:admin scope.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "order"
+                ],
+                "summary": "Get order by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Order ID",
+                        "name": "orderId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Order"
+                        }
+                    },
+                    "400": {
+                        "description": "error\":\"Invalid ID supplied",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "error\":\"Order not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "error\":\"Failed to fetch order",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/order/{orderId}/status": {
+            "patch": {
+                "description": "Move an order to a new lifecycle status (placed → confirmed → preparing → ready → delivered, or cancelled/refunded). Cancelling an order puts its units back in stock.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "order"
+                ],
+                "summary": "Update order status",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Order ID",
+                        "name": "orderId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "New status",
+                        "name": "status",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.OrderStatusUpdateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Order"
+                        }
+                    },
+                    "400": {
+                        "description": "error\":\"Invalid input",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Caller not granted the orders:admin scope",
+                        "schema": {
+                            "$ref": "#/definitions/models.ForbiddenResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "error\":\"Order not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "error\":\"Invalid status transition",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "422": {
+                        "description": "error\":\"Validation exception",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "error\":\"Failed to update order status",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/product": {
+            "get": {
+                "description": "Get a page of products, optionally filtered by category, price range, dietary tags, allergens and name search.\nProducts that cannot be ordered right now are included, with available set to false.\nThe total number of matching products is returned in the X-Total-Count header.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "product"
+                ],
+                "summary": "List products",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Page size (default 20, max 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor returned as next_cursor by the previous page",
+                        "name": "after",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only products in this category",
+                        "name": "category",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Only products priced at or above this amount",
+                        "name": "min_price",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Only products priced at or below this amount",
+                        "name": "max_price",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort order: name, -name, price or -price (default by ID)",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Case-insensitive search on the product name",
+                        "name": "search",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated dietary tags the products must all have, e.g. vegan,gluten-free",
+                        "name": "dietary",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated allergens the products must not contain, e.g. nuts,dairy",
+                        "name": "exclude_allergens",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProductListResponse"
+                        },
+                        "headers": {
+                            "X-Total-Count": {
+                                "type": "integer",
+                                "description": "Total number of matching products"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "error\":\"Invalid input",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "error\":\"Failed to fetch products",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Add a new product to the catalog (admin only)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "product"
+                ],
+                "summary": "Create a product",
+                "parameters": [
+                    {
+                        "description": "Product",
+                        "name": "product",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.ProductRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.Product"
+                        }
+                    },
+                    "400": {
+                        "description": "error\":\"Invalid input",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Caller not granted the catalog:admin scope",
+                        "schema": {
+                            "$ref": "#/definitions/models.ForbiddenResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "error\":\"Product already exists",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "422": {
+                        "description": "error\":\"Validation exception",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "error\":\"Failed to save product",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/product/{productId}": {
+            "get": {
+                "description": "Get a product by its ID",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "product"
+                ],
+                "summary": "Get product by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Product ID",
+                        "name": "productId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/service.ProductResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "error\":\"Invalid ID supplied",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "error\":\"Product not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "error\":\"Failed to fetch product",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Overwrite the name, price, category, description, pictures, allergens, dietary tags and availability of a product (admin only).\nFields missing from the body are cleared, except availability which defaults to true.\nThe category ID must be that of an active category, and allergens and dietary tags must be known ones.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "product"
+                ],
+                "summary": "Replace a product",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Product ID",
+                        "name": "productId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Product",
+                        "name": "product",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.ProductRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Product"
+                        }
+                    },
+                    "400": {
+                        "description": "error\":\"Invalid input",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Caller not granted the catalog:admin scope",
+                        "schema": {
+                            "$ref": "#/definitions/models.ForbiddenResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "error\":\"Product not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "422": {
+                        "description": "error\":\"Validation exception",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "error\":\"Failed to save product",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Remove a product from the catalog (admin only). Orders that reference it are kept intact.",
+                "tags": [
+                    "product"
+                ],
+                "summary": "Delete a product",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Product ID",
+                        "name": "productId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "error\":\"Invalid ID supplied",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Caller not granted the catalog:admin scope",
+                        "schema": {
+                            "$ref": "#/definitions/models.ForbiddenResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "error\":\"Product not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "error\":\"Failed to delete product",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "description": "Change some of the name, price, category, description, pictures, allergens, dietary tags and availability of a product (admin only).\nThe category ID must be that of an active category, and allergens and dietary tags must be known ones.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "product"
+                ],
+                "summary": "Update a product",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Product ID",
+                        "name": "productId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Fields to change",
+                        "name": "product",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.ProductPatchRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Product"
+                        }
+                    },
+                    "400": {
+                        "description": "error\":\"Invalid input",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Caller not granted the catalog:admin scope",
+                        "schema": {
+                            "$ref": "#/definitions/models.ForbiddenResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "error\":\"Product not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "422": {
+                        "description": "error\":\"Validation exception",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "error\":\"Failed to save product",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/product/{productId}/restock": {
+            "post": {
+                "description": "Add a delivery of units to the stock of a product (admin only)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "stock"
+                ],
+                "summary": "Restock a product",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Product ID",
+                        "name": "productId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Units delivered",
+                        "name": "stock",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.StockRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Stock"
+                        }
+                    },
+                    "400": {
+                        "description": "error\":\"Invalid input",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Caller not granted the catalog:admin scope",
+                        "schema": {
+                            "$ref": "#/definitions/models.ForbiddenResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "error\":\"Product not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "422": {
+                        "description": "error\":\"Validation exception",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "error\":\"Failed to save stock",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/product/{productId}/stock": {
+            "get": {
+                "description": "Get the number of units of a product left to order (admin only)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "stock"
+                ],
+                "summary": "Get product stock",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Product ID",
+                        "name": "productId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Stock"
+                        }
+                    },
+                    "400": {
+                        "description": "error\":\"Invalid ID supplied",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Caller not granted the catalog:admin scope",
+                        "schema": {
+                            "$ref": "#/definitions/models.ForbiddenResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "error\":\"Product not found, or its stock is not tracked",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "error\":\"Failed to fetch stock",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Overwrite the number of units of a product left to order, e.g. after a stocktake (admin only)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "stock"
+                ],
+                "summary": "Set product stock",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Product ID",
+                        "name": "productId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "New stock level",
+                        "name": "stock",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.StockRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Stock"
+                        }
+                    },
+                    "400": {
+                        "description": "error\":\"Invalid input",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Caller not granted the catalog:admin scope",
+                        "schema": {
+                            "$ref": "#/definitions/models.ForbiddenResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "error\":\"Product not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "422": {
+                        "description": "error\":\"Validation exception",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "error\":\"Failed to save stock",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/order": {
+            "post": {
+                "description": "Place a new order",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "order"
+                ],
+                "summary": "Place an order",
+                "parameters": [
+                    {
+                        "description": "Order request",
+                        "name": "order",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.OrderCreateRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Unique key making retries of this request safe",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Order"
+                        }
+                    },
+                    "400": {
+                        "description": "error\":\"Invalid input",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "error\":\"Product not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Cart is out of date, products are out of stock (body is a models.OutOfStockResponse), or Idempotency-Key was already used with a different request",
+                        "schema": {
+                            "$ref": "#/definitions/models.StaleCartResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Validation exception, a product of the order is unavailable (code product_unavailable), or the coupon cannot be redeemed (code is a coupon preview reason)",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProductUnavailableResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "error\":\"Failed to place an order",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "models.AppliedDiscount": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "description": "Amount taken off the subtotal",
+                    "type": "number",
+                    "example": 2.6
+                },
+                "description": {
+                    "description": "Explanation of the rule",
+                    "type": "string",
+                    "example": "15% off pizzas"
+                },
+                "ruleId": {
+                    "description": "Rule that was applied",
+                    "type": "string",
+                    "example": "happy-hours"
+                },
+                "type": {
+                    "description": "Kind of discount granted",
+                    "type": "string",
+                    "example": "percent"
+                }
+            }
+        },
+        "models.Cart": {
+            "type": "object",
+            "properties": {
+                "couponCode": {
+                    "description": "Coupon applied to the cart, if any",
+                    "type": "string",
+                    "example": "HAPPYHRS"
+                },
+                "couponReason": {
+                    "description": "Why the applied coupon currently grants no discount",
+                    "type": "string",
+                    "example": "minimum_not_met"
+                },
+                "createdAt": {
+                    "description": "Unix timestamp when the cart was created",
+                    "type": "integer",
+                    "example": 1718000000
+                },
+                "customerId": {
+                    "description": "Customer the cart belongs to; only requests made for this customer can see or change the cart",
+                    "type": "string",
+                    "example": "cust-42"
+                },
+                "discount": {
+                    "description": "Discount granted by the coupon",
+                    "type": "number",
+                    "example": 2.6
+                },
+                "expiresAt": {
+                    "description": "Expiry time, pushed back by every change; a BSON date so the TTL index can purge the cart",
+                    "type": "string",
+                    "example": "2024-06-17T06:18:20Z"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "items": {
+                    "description": "Products in the cart, in the order they were added",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.CartItem"
+                    }
+                },
+                "lines": {
+                    "description": "Priced lines of the items that can be ordered",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.OrderLine"
+                    }
+                },
+                "promotion": {
+                    "description": "Coupon rule that grants the discount, if any",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.AppliedDiscount"
+                        }
+                    ]
+                },
+                "subtotal": {
+                    "description": "Sum of all line totals",
+                    "type": "number",
+                    "example": 25.98
+                },
+                "total": {
+                    "description": "Amount payable at checkout (Subtotal - Discount)",
+                    "type": "number",
+                    "example": 23.38
+                },
+                "unavailableProductIds": {
+                    "description": "Items that were deleted or made unavailable and are left unpriced",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "updatedAt": {
+                    "description": "Unix timestamp of the last change",
+                    "type": "integer",
+                    "example": 1718000300
+                },
+                "version": {
+                    "description": "Incremented by every change to the cart",
+                    "type": "integer",
+                    "example": 4
+                }
+            }
+        },
+        "models.CartCouponRequest": {
+            "type": "object",
+            "properties": {
+                "couponCode": {
+                    "type": "string",
+                    "example": "HAPPYHRS"
+                }
+            }
+        },
+        "models.CartCreateRequest": {
+            "type": "object",
+            "properties": {
+                "couponCode": {
+                    "type": "string"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.CartItem"
+                    }
+                }
+            }
+        },
+        "models.CartItem": {
+            "type": "object",
+            "properties": {
+                "productId": {
+                    "type": "string",
+                    "example": "10"
+                },
+                "quantity": {
+                    "type": "integer",
+                    "example": 2
+                }
+            }
+        },
+        "models.CartItemUpdateRequest": {
+            "type": "object",
+            "properties": {
+                "quantity": {
+                    "type": "integer",
+                    "example": 3
+                }
+            }
+        },
+        "models.CategoryResponse": {
+            "type": "object",
+            "properties": {
+                "active": {
+                    "description": "Inactive categories are hidden and cannot receive products",
+                    "type": "boolean",
+                    "example": true
+                },
+                "id": {
+                    "description": "Unique, URL-safe identifier",
+                    "type": "string",
+                    "example": "pizza"
+                },
+                "name": {
+                    "description": "Display name",
+                    "type": "string",
+                    "example": "Pizza"
+                },
+                "productCount": {
+                    "description": "Number of active products in the category",
+                    "type": "integer",
+                    "example": 3
+                },
+                "sortOrder": {
+                    "description": "Position in category listings, lowest first",
+                    "type": "integer",
+                    "example": 10
+                }
+            }
+        },
+        "models.CouponPreviewResponse": {
+            "type": "object",
+            "properties": {
+                "applicable": {
+                    "description": "Whether the code would be accepted at checkout",
+                    "type": "boolean",
+                    "example": true
+                },
+                "code": {
+                    "description": "Coupon code that was checked",
+                    "type": "string",
+                    "example": "HAPPYHRS"
+                },
+                "expiresAt": {
+                    "description": "Unix timestamp after which the coupon expires, if it does",
+                    "type": "integer",
+                    "example": 1735689600
+                },
+                "reason": {
+                    "description": "Why the code does not apply; one of the CouponReason values",
+                    "type": "string",
+                    "example": "limit_reached"
+                },
+                "remainingRedemptions": {
+                    "description": "Redemptions left before the limit is reached, if there is one",
+                    "type": "integer",
+                    "example": 88
+                },
+                "rule": {
+                    "description": "Discount rule the code would apply, when applicable",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.CouponRule"
+                        }
+                    ]
+                }
+            }
+        },
+        "models.CouponRule": {
+            "type": "object",
+            "properties": {
+                "active": {
+                    "description": "Inactive rules are ignored",
+                    "type": "boolean"
+                },
+                "amount": {
+                    "description": "Amount off, for DiscountFixed",
+                    "type": "number",
+                    "example": 5
+                },
+                "categoryId": {
+                    "description": "Only lines of this category are discounted (empty = every line)",
+                    "type": "string",
+                    "example": "pizza"
+                },
+                "codePrefix": {
+                    "description": "Codes starting with this prefix, ignoring case, use the rule; empty matches every code",
+                    "type": "string",
+                    "example": "HAPPY"
+                },
+                "description": {
+                    "description": "Explanation shown to customers",
+                    "type": "string",
+                    "example": "15% off pizzas"
+                },
+                "expiresAt": {
+                    "description": "Codes of the family cannot be redeemed after this time (zero = never)",
+                    "type": "string",
+                    "example": "2024-12-31T23:59:59Z"
+                },
+                "id": {
+                    "description": "Unique rule identifier",
+                    "type": "string",
+                    "example": "happy-hours"
+                },
+                "maxRedemptions": {
+                    "description": "Maximum number of redemptions of each code of the family (0 = unlimited)",
+                    "type": "integer",
+                    "example": 100
+                },
+                "minSubtotal": {
+                    "description": "Smallest order subtotal the coupon applies to",
+                    "type": "number",
+                    "example": 20
+                },
+                "oncePerCustomer": {
+                    "description": "Each customer may redeem a code of the family only once; orders must then come from an authenticated customer",
+                    "type": "boolean"
+                },
+                "percent": {
+                    "description": "Percentage off, for DiscountPercent",
+                    "type": "integer",
+                    "example": 15
+                },
+                "type": {
+                    "description": "Kind of discount granted",
+                    "type": "string",
+                    "example": "percent"
+                }
+            }
+        },
+        "models.ForbiddenResponse": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "Machine-readable error code, always InsufficientScopeCode",
+                    "type": "string",
+                    "example": "insufficient_scope"
+                },
+                "error": {
+                    "description": "Error message",
+                    "type": "string",
+                    "example": "Forbidden"
+                },
+                "missingScopes": {
+                    "description": "Required scopes the caller was not granted",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "catalog:admin"
+                    ]
+                },
+                "requestId": {
+                    "description": "ID of the request, to quote when reporting the error",
+                    "type": "string",
+                    "example": "3f2b8c1e-0d4a-4e5b-9c6d-7a8b9c0d1e2f"
+                },
+                "requiredScopes": {
+                    "description": "Scopes the endpoint requires",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "catalog:admin"
+                    ]
+                }
+            }
+        },
+        "models.Order": {
+            "type": "object",
+            "properties": {
+                "couponCode": {
+                    "type": "string"
+                },
+                "createdAt": {
+                    "description": "Unix timestamp when the order was placed",
+                    "type": "integer",
+                    "example": 1718000000
+                },
+                "customerId": {
+                    "description": "Customer who placed the order, if known",
+                    "type": "string",
+                    "example": "cust-42"
+                },
+                "discount": {
+                    "description": "Discount granted by the coupon",
+                    "type": "number",
+                    "example": 2.6
+                },
+                "history": {
+                    "description": "Audit trail of status transitions, oldest first, starting with the order being placed",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.OrderStatusChange"
+                    }
+                },
+                "id": {
+                    "type": "string"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.OrderItem"
+                    }
+                },
+                "lines": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.OrderLine"
+                    }
+                },
+                "products": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Product"
+                    }
+                },
+                "promotion": {
+                    "description": "Coupon rule that granted the discount, if any",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.AppliedDiscount"
+                        }
+                    ]
+                },
+                "status": {
+                    "description": "Current lifecycle status",
+                    "type": "string",
+                    "example": "placed"
+                },
+                "subtotal": {
+                    "description": "Sum of all line totals",
+                    "type": "number",
+                    "example": 25.98
+                },
+                "total": {
+                    "description": "Amount payable (Subtotal - Discount)",
+                    "type": "number",
+                    "example": 23.38
+                },
+                "updatedAt": {
+                    "description": "Unix timestamp of the last status change",
+                    "type": "integer",
+                    "example": 1718000300
+                }
+            }
+        },
+        "models.OrderCreateRequest": {
+            "type": "object",
+            "properties": {
+                "couponCode": {
+                    "type": "string"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.OrderItem"
+                    }
+                }
+            }
+        },
+        "models.OrderItem": {
+            "type": "object",
+            "properties": {
+                "expectedPrice": {
+                    "description": "Unit price the client saw",
+                    "type": "number",
+                    "example": 12.99
+                },
+                "expectedVersion": {
+                    "description": "Catalog version the client saw",
+                    "type": "integer",
+                    "example": 3
+                },
+                "productId": {
+                    "type": "string"
+                },
+                "quantity": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.OrderLine": {
+            "type": "object",
+            "properties": {
+                "category": {
+                    "description": "Product category at order time",
+                    "type": "string",
+                    "example": "Waffle"
+                },
+                "categoryId": {
+                    "description": "Product category ID at order time",
+                    "type": "string",
+                    "example": "waffle"
+                },
+                "lineTotal": {
+                    "description": "UnitPrice × Quantity",
+                    "type": "number",
+                    "example": 25.98
+                },
+                "name": {
+                    "description": "Product name at order time",
+                    "type": "string",
+                    "example": "Chicken Waffle"
+                },
+                "productId": {
+                    "description": "Product being ordered",
+                    "type": "string"
+                },
+                "productVersion": {
+                    "description": "Catalog version the line was priced from",
+                    "type": "integer",
+                    "example": 3
+                },
+                "quantity": {
+                    "description": "Number of units ordered",
                     "type": "integer"
+                },
+                "unitPrice": {
+                    "description": "Price of a single unit",
+                    "type": "number",
+                    "example": 12.99
+                }
+            }
+        },
+        "models.OrderListResponse": {
+            "type": "object",
+            "properties": {
+                "limit": {
+                    "description": "Page size used for this page",
+                    "type": "integer",
+                    "example": 20
+                },
+                "offset": {
+                    "description": "Offset of the first order in this page",
+                    "type": "integer",
+                    "example": 0
+                },
+                "orders": {
+                    "description": "Orders in this page, newest first",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Order"
+                    }
+                },
+                "total": {
+                    "description": "Total number of orders matching the filter",
+                    "type": "integer",
+                    "example": 42
+                }
+            }
+        },
+        "models.OrderStatusChange": {
+            "type": "object",
+            "properties": {
+                "at": {
+                    "description": "Unix timestamp of the transition",
+                    "type": "integer",
+                    "example": 1718000300
+                },
+                "from": {
+                    "description": "Status before the transition, empty when the order was placed",
+                    "type": "string",
+                    "example": "placed"
+                },
+                "reason": {
+                    "description": "Optional free-text reason",
+                    "type": "string",
+                    "example": "accepted"
+                },
+                "to": {
+                    "description": "Status after the transition",
+                    "type": "string",
+                    "example": "confirmed"
+                }
+            }
+        },
+        "models.OrderStatusUpdateRequest": {
+            "type": "object",
+            "properties": {
+                "reason": {
+                    "type": "string",
+                    "example": "accepted by kitchen"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "confirmed"
                 }
             }
         },
         "models.Product": {
             "type": "object",
             "properties": {
+                "allergens": {
+                    "description": "Allergens the product contains, see Allergens",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "available": {
+                    "description": "Whether the product can currently be ordered",
+                    "type": "boolean"
+                },
                 "category": {
-                    "description": "Product category",
+                    "description": "Display name of the product category",
+                    "type": "string"
+                },
+                "categoryId": {
+                    "description": "ID of the category the product is filed under",
+                    "type": "string"
+                },
+                "description": {
+                    "description": "Menu description",
                     "type": "string"
                 },
+                "dietaryTags": {
+                    "description": "Diets the product is suitable for, see DietaryTags",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
                 "id": {
                     "description": "Unique identifier for the product",
                     "type": "string"
                 },
+                "image": {
+                    "description": "Product pictures for each screen size",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.ProductImage"
+                        }
+                    ]
+                },
                 "name": {
                     "description": "Product name",
                     "type": "string"
@@ -243,20 +2302,318 @@ const docTemplate = `{
                 "price": {
                     "description": "Product price",
                     "type": "number"
+                },
+                "updatedAt": {
+                    "description": "Unix timestamp of the last admin change",
+                    "type": "integer"
+                },
+                "version": {
+                    "description": "Catalog version, incremented on every change to the product",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.ProductImage": {
+            "type": "object",
+            "properties": {
+                "desktop": {
+                    "type": "string",
+                    "example": "https://cdn.example.com/10-desktop.jpg"
+                },
+                "mobile": {
+                    "type": "string",
+                    "example": "https://cdn.example.com/10-mobile.jpg"
+                },
+                "tablet": {
+                    "type": "string",
+                    "example": "https://cdn.example.com/10-tablet.jpg"
+                },
+                "thumbnail": {
+                    "type": "string",
+                    "example": "https://cdn.example.com/10-thumbnail.jpg"
+                }
+            }
+        },
+        "models.ProductListResponse": {
+            "type": "object",
+            "properties": {
+                "next_cursor": {
+                    "description": "Token for the next page; absent on the last page",
+                    "type": "string",
+                    "example": "eyJpIjoiMTAifQ"
+                },
+                "products": {
+                    "description": "Products in this page",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Product"
+                    }
+                }
+            }
+        },
+        "models.ProductPatchRequest": {
+            "type": "object",
+            "properties": {
+                "allergens": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "gluten",
+                        "dairy"
+                    ]
+                },
+                "available": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "categoryId": {
+                    "type": "string",
+                    "example": "pizza"
+                },
+                "description": {
+                    "type": "string",
+                    "example": "Tomato, mozzarella and basil"
+                },
+                "dietaryTags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "vegetarian"
+                    ]
+                },
+                "image": {
+                    "$ref": "#/definitions/models.ProductImage"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Margherita Pizza"
+                },
+                "price": {
+                    "type": "number",
+                    "example": 12.99
+                }
+            }
+        },
+        "models.ProductRequest": {
+            "type": "object",
+            "properties": {
+                "allergens": {
+                    "description": "Allergens from the Allergens list",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "gluten",
+                        "dairy"
+                    ]
+                },
+                "available": {
+                    "description": "Whether the product can be ordered (default true)",
+                    "type": "boolean",
+                    "example": true
+                },
+                "categoryId": {
+                    "description": "ID of an active category",
+                    "type": "string",
+                    "example": "pizza"
+                },
+                "description": {
+                    "description": "Menu description",
+                    "type": "string",
+                    "example": "Tomato, mozzarella and basil"
+                },
+                "dietaryTags": {
+                    "description": "Dietary tags from the DietaryTags list",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "vegetarian"
+                    ]
+                },
+                "id": {
+                    "description": "Optional ID for new products; generated when empty",
+                    "type": "string",
+                    "example": "10"
+                },
+                "image": {
+                    "description": "Product pictures for each screen size",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.ProductImage"
+                        }
+                    ]
+                },
+                "name": {
+                    "description": "Product name (required)",
+                    "type": "string",
+                    "example": "Margherita Pizza"
+                },
+                "price": {
+                    "description": "Product price, must be positive with at most two decimal places",
+                    "type": "number",
+                    "example": 12.99
+                }
+            }
+        },
+        "models.ProductUnavailableResponse": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "Machine-readable error code, always ProductUnavailableCode",
+                    "type": "string",
+                    "example": "product_unavailable"
+                },
+                "error": {
+                    "description": "Error message",
+                    "type": "string",
+                    "example": "Product unavailable"
+                },
+                "productId": {
+                    "description": "First unavailable product of the order",
+                    "type": "string",
+                    "example": "10"
+                },
+                "requestId": {
+                    "description": "ID of the request, to quote when reporting the error",
+                    "type": "string",
+                    "example": "3f2b8c1e-0d4a-4e5b-9c6d-7a8b9c0d1e2f"
+                }
+            }
+        },
+        "models.StaleCartResponse": {
+            "type": "object",
+            "properties": {
+                "changedLines": {
+                    "description": "Items whose price or version changed",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.StaleOrderLine"
+                    }
+                },
+                "error": {
+                    "description": "Error message",
+                    "type": "string",
+                    "example": "Cart is out of date"
+                },
+                "requestId": {
+                    "description": "ID of the request, to quote when reporting the error",
+                    "type": "string",
+                    "example": "3f2b8c1e-0d4a-4e5b-9c6d-7a8b9c0d1e2f"
+                }
+            }
+        },
+        "models.StaleOrderLine": {
+            "type": "object",
+            "properties": {
+                "currentPrice": {
+                    "description": "Current catalog unit price",
+                    "type": "number",
+                    "example": 13.49
+                },
+                "currentVersion": {
+                    "description": "Current catalog version",
+                    "type": "integer",
+                    "example": 4
+                },
+                "expectedPrice": {
+                    "description": "Unit price sent by the client",
+                    "type": "number",
+                    "example": 12.99
+                },
+                "expectedVersion": {
+                    "description": "Catalog version sent by the client",
+                    "type": "integer",
+                    "example": 3
+                },
+                "productId": {
+                    "description": "Product that changed",
+                    "type": "string",
+                    "example": "1"
+                }
+            }
+        },
+        "models.Stock": {
+            "type": "object",
+            "properties": {
+                "productId": {
+                    "description": "Product the stock belongs to",
+                    "type": "string",
+                    "example": "10"
+                },
+                "quantity": {
+                    "description": "Units left to order",
+                    "type": "integer",
+                    "example": 25
+                },
+                "updatedAt": {
+                    "description": "Unix timestamp of the last change",
+                    "type": "integer",
+                    "example": 1718000300
+                }
+            }
+        },
+        "models.StockRequest": {
+            "type": "object",
+            "properties": {
+                "quantity": {
+                    "description": "Units to set or to add, must not be negative",
+                    "type": "integer",
+                    "example": 10
                 }
             }
         },
         "service.ProductResponse": {
             "type": "object",
             "properties": {
+                "allergens": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "gluten",
+                        "eggs"
+                    ]
+                },
+                "available": {
+                    "type": "boolean",
+                    "example": true
+                },
                 "category": {
                     "type": "string",
                     "example": "Waffle"
                 },
+                "categoryId": {
+                    "type": "string",
+                    "example": "waffle"
+                },
+                "description": {
+                    "type": "string",
+                    "example": "Crispy fried chicken on a Belgian waffle"
+                },
+                "dietaryTags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "halal"
+                    ]
+                },
                 "id": {
                     "type": "string",
                     "example": "10"
                 },
+                "image": {
+                    "$ref": "#/definitions/models.ProductImage"
+                },
                 "name": {
                     "type": "string",
                     "example": "Chicken Waffle"
@@ -264,6 +2621,10 @@ const docTemplate = `{
                 "price": {
                     "type": "number",
                     "example": 1
+                },
+                "version": {
+                    "type": "integer",
+                    "example": 3
                 }
             }
         }
@@ -274,6 +2635,12 @@ const docTemplate = `{
             "type": "apiKey",
             "name": "api_key",
             "in": "header"
+        },
+        "BearerAuth": {
+            "description": "Provide 'Bearer \u003cjwt\u003e' to authenticate as a user when JWT authentication is enabled",
+            "type": "apiKey",
+            "name": "Authorization",
+            "in": "header"
         }
     }
 }`
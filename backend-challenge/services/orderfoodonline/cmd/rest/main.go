@@ -11,8 +11,10 @@ import (
 	"orderfoodonline/internal/http/handlers"
 	"orderfoodonline/internal/http/middlewares"
 	"orderfoodonline/internal/http/routes"
+	"orderfoodonline/internal/metrics"
 	"orderfoodonline/internal/repository"
 	"orderfoodonline/internal/service"
+	"orderfoodonline/internal/tracing"
 )
 
 // main is the entry point for the Order Food Online REST API service.
@@ -34,6 +36,11 @@ import (
 // @name api_key
 // @description Provide your API key as the value of the 'api_key' header to authenticate
 
+// @securityDefinitions.apikey BearerAuth
+// @in header
+// @name Authorization
+// @description Provide 'Bearer <jwt>' to authenticate as a user when JWT authentication is enabled
+
 // router.Init is a function that contains all routes of the application
 //
 // @description Contains all routes of the application
@@ -60,6 +67,18 @@ func main() {
 
 	ctx := context.Background()
 
+	// The histograms must be rebuilt with the configured buckets before anything is recorded
+	metrics.Init(appConfig.Metrics)
+
+	// Tracing must be set up before the database client and the router are instrumented
+	tracerProvider, err := tracing.NewTracerProvider(ctx, appConfig.Tracing)
+	if err != nil {
+		appLogger.Error("failed to initialize tracing: %v", err)
+		log.Fatalf("failed to initialize tracing: %v", err)
+	}
+	defer tracerProvider.Shutdown(context.Background())
+	tracing.Init(tracerProvider)
+
 	repo, err := repository.NewRepository(ctx, appConfig.Database)
 	if err != nil {
 		appLogger.Error("failed to initialize repository: %v", err)
@@ -73,24 +92,75 @@ func main() {
 		log.Fatalf("failed to initialize product repository: %v", err)
 	}
 
+	categoryRepository, err := repository.NewCategoryRepository(repo)
+	if err != nil {
+		appLogger.Error("failed to initialize category repository: %v", err)
+		log.Fatalf("failed to initialize category repository: %v", err)
+	}
+
+	orderRepository, err := repository.NewOrderRepository(repo)
+	if err != nil {
+		appLogger.Error("failed to initialize order repository: %v", err)
+		log.Fatalf("failed to initialize order repository: %v", err)
+	}
+
 	// Run database migrations (seed products if new migration file exists)
-	migrationService := service.NewMigrationService(productRepository, appLogger)
+	migrationService := service.NewMigrationService(productRepository, categoryRepository, orderRepository, appLogger)
 	if err := migrationService.RunMigrations(ctx, "./migrations"); err != nil {
 		appLogger.Error("failed to run migrations: %v", err)
 		log.Fatalf("failed to run migrations: %v", err)
 	}
 
-	orderRepository := repository.NewOrderRepository(repo)
 	couponRepository, err := repository.NewCouponRepository(repo)
 	if err != nil {
 		appLogger.Error("failed to initialize coupon repository: %v", err)
 		log.Fatalf("failed to initialize coupon repository: %v", err)
 	}
+	var couponRuleRepository repository.CouponRuleRepository
+	if appConfig.Coupons.RulesFile != "" {
+		couponRuleRepository, err = repository.NewFileCouponRuleRepository(appConfig.Coupons.RulesFile)
+	} else {
+		couponRuleRepository, err = repository.NewCouponRuleRepository(repo)
+	}
+	if err != nil {
+		appLogger.Error("failed to initialize coupon rule repository: %v", err)
+		log.Fatalf("failed to initialize coupon rule repository: %v", err)
+	}
+	if appConfig.Coupons.RulesFile == "" {
+		couponRuleRepository = repository.NewCachedCouponRuleRepository(couponRuleRepository, appConfig.Coupons.CacheTTL)
+	}
 
-	orderService := service.NewOrderService(orderRepository, productRepository, couponRepository, appLogger)
+	idempotencyRepository, err := repository.NewIdempotencyRepository(repo)
+	if err != nil {
+		appLogger.Error("failed to initialize idempotency repository: %v", err)
+		log.Fatalf("failed to initialize idempotency repository: %v", err)
+	}
+
+	stockRepository, err := repository.NewStockRepository(repo)
+	if err != nil {
+		appLogger.Error("failed to initialize stock repository: %v", err)
+		log.Fatalf("failed to initialize stock repository: %v", err)
+	}
+
+	cartRepository, err := repository.NewCartRepository(repo)
+	if err != nil {
+		appLogger.Error("failed to initialize cart repository: %v", err)
+		log.Fatalf("failed to initialize cart repository: %v", err)
+	}
+
+	unitOfWork := repository.NewUnitOfWork(repo)
+
+	orderService := service.NewOrderService(orderRepository, productRepository, couponRepository, couponRuleRepository,
+		stockRepository, unitOfWork, appLogger)
 	orderHandler := handlers.NewOrderHandler(orderService)
+	cartService := service.NewCartService(cartRepository, productRepository, couponRepository, couponRuleRepository,
+		orderService, appConfig.Carts.TTL, appLogger)
+	cartHandler := handlers.NewCartHandler(cartService)
 
-	productService := service.NewProductService(productRepository, appLogger)
+	productService := service.NewProductService(productRepository, categoryRepository, appLogger)
+	categoryService := service.NewCategoryService(categoryRepository, productRepository, appLogger)
+	stockService := service.NewStockService(stockRepository, productRepository, appLogger)
+	couponService := service.NewCouponService(couponRepository, couponRuleRepository, appLogger)
 
 	swaggerHandler, err := handlers.NewSwaggerHandler(appConfig.Swagger, appLogger)
 	if err != nil {
@@ -98,13 +168,53 @@ func main() {
 		log.Fatalf("failed to initialize swagger handler: %v", err)
 	}
 	productHandler := handlers.NewProductHandler(productService)
+	productAdminHandler := handlers.NewProductAdminHandler(productService)
+	categoryHandler := handlers.NewCategoryHandler(categoryService)
+	stockHandler := handlers.NewStockHandler(stockService)
+	couponHandler := handlers.NewCouponHandler(couponService)
+
+	keyStore := middlewares.NewStaticKeyStore(appConfig.Auth)
+	if appConfig.Auth.KeyStore == config.KeyStoreMongo {
+		apiKeyRepository, err := repository.NewAPIKeyRepository(repo)
+		if err != nil {
+			appLogger.Error("failed to initialize API key repository: %v", err)
+			log.Fatalf("failed to initialize API key repository: %v", err)
+		}
+		keyStore = middlewares.NewCachedKeyStore(middlewares.NewMongoKeyStore(apiKeyRepository), appConfig.Auth.CacheTTL)
+	}
+
+	var tokenVerifier middlewares.TokenVerifier
+	if appConfig.Auth.JWT.Enabled {
+		tokenVerifier, err = middlewares.NewJWTVerifier(appConfig.Auth.JWT)
+		if err != nil {
+			appLogger.Error("failed to initialize JWT verifier: %v", err)
+			log.Fatalf("failed to initialize JWT verifier: %v", err)
+		}
+	}
+
+	rateLimitStore := middlewares.NewMemoryRateLimitStore()
+	if appConfig.RateLimit.Store == config.RateLimitStoreMongo {
+		rateLimitRepository, err := repository.NewRateLimitRepository(repo)
+		if err != nil {
+			appLogger.Error("failed to initialize rate limit repository: %v", err)
+			log.Fatalf("failed to initialize rate limit repository: %v", err)
+		}
+		rateLimitStore = middlewares.NewMongoRateLimitStore(rateLimitRepository)
+	}
 
 	dep := routes.Dependencies{
-		AuthMiddleware:    middlewares.NewAuthMiddleware(appLogger),
-		MetricsMiddleware: middlewares.NewMetricsMiddleware(),
-		SwaggerHandler:    swaggerHandler,
-		ProductHandler:    productHandler,
-		OrderHandler:      orderHandler,
+		AuthMiddleware:        middlewares.NewAuthMiddleware(keyStore, tokenVerifier, appLogger),
+		MetricsMiddleware:     middlewares.NewMetricsMiddleware(),
+		IdempotencyMiddleware: middlewares.NewIdempotencyMiddleware(idempotencyRepository, appConfig.Idempotency.TTL, appConfig.Idempotency.Lease, appLogger),
+		RateLimitMiddleware:   middlewares.NewRateLimitMiddleware(appConfig.RateLimit, rateLimitStore, appLogger),
+		SwaggerHandler:        swaggerHandler,
+		ProductHandler:        productHandler,
+		ProductAdminHandler:   productAdminHandler,
+		CategoryHandler:       categoryHandler,
+		StockHandler:          stockHandler,
+		CouponHandler:         couponHandler,
+		CartHandler:           cartHandler,
+		OrderHandler:          orderHandler,
 	}
 	// create a new http router
 	router := routes.NewRouter(appConfig, appLogger)
@@ -2,20 +2,45 @@
 package config
 
 import (
+	"encoding/json"
+	"fmt"
 	"library/config"
 	"library/logger"
 	"orderfoodonline/internal/constants"
+	"os"
+	"strings"
 	"time"
 )
 
+const (
+	// EnvLocal names the local development environment.
+	EnvLocal = "local"
+
+	// KeyStoreConfig looks API keys up in the configuration and the APIKeysEnv environment variable.
+	KeyStoreConfig = "config"
+	// KeyStoreMongo looks API keys up by their hash in the api_keys collection.
+	KeyStoreMongo = "mongo"
+
+	// APIKeysEnv names the environment variable holding additional API keys, as a JSON array of APIKeyConfig.
+	APIKeysEnv = "API_KEYS"
+)
+
 // Config holds the complete application configuration including environment,
 // server settings, database connection, logging, and Swagger documentation.
 type Config struct {
-	Env      string            `json:"env"`      // Environment name (e.g., "development", "production")
-	Swagger  *SwaggerConfig    `json:"swagger"`  // Swagger documentation configuration
-	Server   *ServerConfig     `json:"server"`   // HTTP server configuration
-	Logger   *logger.LogConfig `json:"logger"`   // Logging configuration
-	Database *DbConfig         `json:"database"` // Database connection configuration
+	Env         string             `json:"env"`         // Environment name (e.g., "development", "production")
+	Swagger     *SwaggerConfig     `json:"swagger"`     // Swagger documentation configuration
+	Server      *ServerConfig      `json:"server"`      // HTTP server configuration
+	Logger      *logger.LogConfig  `json:"logger"`      // Logging configuration
+	Database    *DbConfig          `json:"database"`    // Database connection configuration
+	Idempotency *IdempotencyConfig `json:"idempotency"` // Idempotency-Key handling configuration
+	Auth        *AuthConfig        `json:"auth"`        // API key authentication configuration
+	Coupons     *CouponConfig      `json:"coupons"`     // Coupon rule source
+	Carts       *CartConfig        `json:"carts"`       // Server-side cart configuration
+	RateLimit   *RateLimitConfig   `json:"rate_limit"`  // Request rate limiting policies
+	CORS        *CORSConfig        `json:"cors"`        // Cross-origin resource sharing policy
+	Tracing     *TracingConfig     `json:"tracing"`     // OpenTelemetry tracing configuration
+	Metrics     *MetricsConfig     `json:"metrics"`     // Prometheus histogram buckets
 }
 
 // SwaggerConfig holds configuration for Swagger documentation generation and serving.
@@ -35,12 +60,126 @@ type ServerConfig struct {
 
 // DbConfig holds database connection configuration including credentials and connection details.
 type DbConfig struct {
-	Host         string `json:"host"`     // Database host address
-	Port         int    `json:"port"`     // Database port number
-	User         string `json:"user"`     // Database username
-	Password     string `json:"password"` // Database password
-	DatabaseName string `json:"dbname"`   // Database name
-	Type         string `json:"type"`     // Database type (e.g., "mongodb", "postgres")
+	Host         string `json:"host"`        // Database host address
+	Port         int    `json:"port"`        // Database port number
+	User         string `json:"user"`        // Database username
+	Password     string `json:"password"`    // Database password
+	DatabaseName string `json:"dbname"`      // Database name
+	Type         string `json:"type"`        // Database type (e.g., "mongodb", "postgres")
+	ReplicaSet   string `json:"replica_set"` // MongoDB replica set name (transactions need a replica set)
+}
+
+// IdempotencyConfig holds configuration for Idempotency-Key handling on unsafe endpoints.
+type IdempotencyConfig struct {
+	TTL   time.Duration `json:"ttl"`   // How long idempotency keys and their stored responses are kept
+	Lease time.Duration `json:"lease"` // How long a key whose request never finished blocks retries
+}
+
+// AuthConfig holds the API keys accepted by the service and where they are looked up.
+type AuthConfig struct {
+	KeyStore    string         `json:"key_store"`     // Where keys are looked up: KeyStoreConfig (default) or KeyStoreMongo
+	CacheTTL    time.Duration  `json:"cache_ttl"`     // How long key lookups in the api_keys collection are cached
+	APIKey      string         `json:"api_key"`       // Key named "default" granting access to the public catalog and order endpoints (empty = disabled); only allowed in EnvLocal
+	AdminAPIKey string         `json:"admin_api_key"` // Key named "admin" additionally granting access to the admin endpoints (empty = disabled); only allowed in EnvLocal, elsewhere admin keys come from APIKeysEnv or the api_keys collection
+	Keys        []APIKeyConfig `json:"keys"`          // Further keys, followed by those in the APIKeysEnv environment variable
+	JWT         *JWTConfig     `json:"jwt"`           // Bearer token authentication
+}
+
+// JWTConfig holds how bearer tokens are verified. At least one of HMACSecret,
+// RSAPublicKeyFile and JWKSFile must be set when Enabled is true.
+type JWTConfig struct {
+	Enabled          bool          `json:"enabled"`             // Accept Authorization: Bearer tokens alongside API keys
+	Issuer           string        `json:"issuer"`              // Required iss claim
+	Audience         string        `json:"audience"`            // Required entry of the aud claim
+	HMACSecret       string        `json:"hmac_secret"`         // Secret verifying HS256 tokens
+	RSAPublicKeyFile string        `json:"rsa_public_key_file"` // PEM file holding the public key verifying RS256 tokens
+	JWKSFile         string        `json:"jwks_file"`           // Local JWKS file holding further keys, selected by the kid header
+	Leeway           time.Duration `json:"leeway"`              // Clock skew allowed when checking exp and nbf
+	DefaultScopes    []string      `json:"default_scopes"`      // Scopes granted to tokens without a scope claim
+}
+
+// APIKeyConfig describes an API key held in the configuration.
+type APIKeyConfig struct {
+	Name       string    `json:"name"`        // Name identifying the key's holder in logs
+	Key        string    `json:"key"`         // Secret presented in the api_key header
+	Scopes     []string  `json:"scopes"`      // Scopes granted to the key
+	ExpiresAt  time.Time `json:"expires_at"`  // The key is rejected after this RFC 3339 time (zero = never)
+	Revoked    bool      `json:"revoked"`     // The key is rejected
+	CustomerID string    `json:"customer_id"` // Customer the key's orders, carts and coupon redemptions are made for (empty = none)
+}
+
+// CouponConfig holds where the coupon discount rules, which also carry the redemption limits, are read from.
+type CouponConfig struct {
+	RulesFile string        `json:"rules_file"` // JSON file holding the coupon discount rules (empty = read the coupon_rules collection)
+	CacheTTL  time.Duration `json:"cache_ttl"`  // How long the rules read from the coupon_rules collection are cached
+}
+
+const (
+	// RateLimitKeyIP counts the requests of each client IP address.
+	RateLimitKeyIP = "ip"
+	// RateLimitKeyCaller counts the requests of each JWT subject or API key, falling back to the client IP.
+	RateLimitKeyCaller = "caller"
+
+	// RateLimitStoreMemory counts requests in process memory, so each instance enforces its own limits.
+	RateLimitStoreMemory = "memory"
+	// RateLimitStoreMongo counts requests in the rate_limits collection, shared by every instance.
+	RateLimitStoreMongo = "mongo"
+)
+
+// RateLimitConfig holds the rate limiting policies of the API routes.
+type RateLimitConfig struct {
+	Store          string                     `json:"store"`          // Where requests are counted: RateLimitStoreMemory (default) or RateLimitStoreMongo
+	Default        RateLimitPolicy            `json:"default"`        // Policy of the routes without an override
+	Routes         map[string]RateLimitPolicy `json:"routes"`         // Overrides by "METHOD /path", with gin path parameters (e.g. "POST /api/order", "GET /metrics")
+	Authentication RateLimitPolicy            `json:"authentication"` // Policy counted per client IP before authentication, so failed attempts are limited too; its key is ignored
+}
+
+// RateLimitPolicy limits the requests made in fixed windows.
+type RateLimitPolicy struct {
+	Limit  int           `json:"limit"`  // Requests allowed per window (0 = unlimited)
+	Window time.Duration `json:"window"` // Length of a window
+	Key    string        `json:"key"`    // What requests are counted by: RateLimitKeyIP or RateLimitKeyCaller (default)
+}
+
+// CORSConfig holds which browser origins may call the API and with which methods and headers.
+// An origin is allowed if it equals an entry of AllowOrigins, or matches an entry of the form
+// "https://*.example.com", which allows any subdomain of example.com over https.
+type CORSConfig struct {
+	AllowOrigins     []string      `json:"allow_origins"`     // Origins allowed to call the API (empty = none)
+	AllowMethods     []string      `json:"allow_methods"`     // Methods allowed in cross-origin requests
+	AllowHeaders     []string      `json:"allow_headers"`     // Request headers allowed in cross-origin requests
+	ExposeHeaders    []string      `json:"expose_headers"`    // Response headers readable by the calling page
+	AllowCredentials bool          `json:"allow_credentials"` // Allow requests carrying cookies or HTTP authentication
+	MaxAge           time.Duration `json:"max_age"`           // How long browsers may cache preflight responses
+}
+
+const (
+	// TracingExporterNone records spans, so that trace IDs are propagated and logged, without exporting them.
+	TracingExporterNone = "none"
+	// TracingExporterStdout writes spans to standard output.
+	TracingExporterStdout = "stdout"
+	// TracingExporterOTLP sends spans to an OpenTelemetry collector over OTLP/HTTP.
+	TracingExporterOTLP = "otlp"
+)
+
+// TracingConfig holds how spans are sampled and where they are exported.
+type TracingConfig struct {
+	ServiceName string  `json:"service_name"` // Name of the service in spans (default "orderfoodonline")
+	Exporter    string  `json:"exporter"`     // Where spans are sent: TracingExporterNone (default), TracingExporterStdout or TracingExporterOTLP
+	Endpoint    string  `json:"endpoint"`     // OTLP collector host:port (empty = the OTEL_EXPORTER_OTLP_ENDPOINT environment variable or localhost:4318)
+	Insecure    bool    `json:"insecure"`     // Send OTLP spans over plain HTTP
+	SampleRatio float64 `json:"sample_ratio"` // Share of new traces that are sampled, from 0 to 1 (default 1); requests with a traceparent follow the caller's decision
+}
+
+// MetricsConfig holds the buckets of the HTTP request and database query histograms.
+type MetricsConfig struct {
+	LatencyBuckets []float64 `json:"latency_buckets"` // Upper bounds, in seconds, of the duration histograms (empty = metrics.LatencyBuckets)
+	SizeBuckets    []float64 `json:"size_buckets"`    // Upper bounds, in bytes, of the request and response size histograms (empty = metrics.SizeBuckets)
+}
+
+// CartConfig holds configuration for server-side carts.
+type CartConfig struct {
+	TTL time.Duration `json:"ttl"` // How long a cart is kept after its last change
 }
 
 // NewConfig creates a new Config instance from a configuration manager.
@@ -68,9 +207,234 @@ func NewConfig(configManager *config.Manager) (*Config, error) {
 			Password:     configManager.GetString("database.password"),
 			DatabaseName: configManager.GetString("database.dbname"),
 			Type:         configManager.GetString("database.type"),
+			ReplicaSet:   configManager.GetString("database.replica_set"),
+		},
+		Idempotency: &IdempotencyConfig{
+			TTL:   configManager.GetDuration("idempotency.ttl"),
+			Lease: configManager.GetDuration("idempotency.lease"),
+		},
+		Auth: &AuthConfig{
+			KeyStore:    configManager.GetString("auth.key_store"),
+			CacheTTL:    configManager.GetDuration("auth.cache_ttl"),
+			APIKey:      configManager.GetString("auth.api_key"),
+			AdminAPIKey: configManager.GetString("auth.admin_api_key"),
+			JWT: &JWTConfig{
+				Enabled:          configManager.GetBool("auth.jwt.enabled"),
+				Issuer:           configManager.GetString("auth.jwt.issuer"),
+				Audience:         configManager.GetString("auth.jwt.audience"),
+				HMACSecret:       configManager.GetString("auth.jwt.hmac_secret"),
+				RSAPublicKeyFile: configManager.GetString("auth.jwt.rsa_public_key_file"),
+				JWKSFile:         configManager.GetString("auth.jwt.jwks_file"),
+				Leeway:           configManager.GetDuration("auth.jwt.leeway"),
+			},
+		},
+		Coupons: &CouponConfig{
+			RulesFile: configManager.GetString("coupons.rules_file"),
+			CacheTTL:  configManager.GetDuration("coupons.cache_ttl"),
+		},
+		Carts: &CartConfig{
+			TTL: configManager.GetDuration("carts.ttl"),
+		},
+		RateLimit: &RateLimitConfig{
+			Store: configManager.GetString("rate_limit.store"),
+			Default: RateLimitPolicy{
+				Limit:  configManager.GetInt("rate_limit.default.limit"),
+				Window: configManager.GetDuration("rate_limit.default.window"),
+				Key:    configManager.GetString("rate_limit.default.key"),
+			},
+			Authentication: RateLimitPolicy{
+				Limit:  configManager.GetInt("rate_limit.authentication.limit"),
+				Window: configManager.GetDuration("rate_limit.authentication.window"),
+				Key:    RateLimitKeyIP,
+			},
+		},
+		CORS: &CORSConfig{
+			AllowCredentials: configManager.GetBool("cors.allow_credentials"),
+			MaxAge:           configManager.GetDuration("cors.max_age"),
+		},
+		Tracing: &TracingConfig{
+			ServiceName: configManager.GetString("tracing.service_name"),
+			Exporter:    configManager.GetString("tracing.exporter"),
+			Endpoint:    configManager.GetString("tracing.endpoint"),
+			Insecure:    configManager.GetBool("tracing.insecure"),
+			SampleRatio: 1,
 		},
+		Metrics: &MetricsConfig{},
+	}
+	if err := decodeValue(configManager, "auth.keys", &cfg.Auth.Keys); err != nil {
+		return nil, err
+	}
+	if err := decodeValue(configManager, "auth.jwt.default_scopes", &cfg.Auth.JWT.DefaultScopes); err != nil {
+		return nil, err
+	}
+	if cfg.Auth.APIKey != "" && cfg.Env != EnvLocal {
+		return nil, fmt.Errorf("auth.api_key is only allowed in the %s environment, provide keys through %s or the api_keys collection",
+			EnvLocal, APIKeysEnv)
+	}
+	if cfg.Auth.AdminAPIKey != "" && cfg.Env != EnvLocal {
+		return nil, fmt.Errorf("auth.admin_api_key is only allowed in the %s environment, provide admin keys through %s or the api_keys collection",
+			EnvLocal, APIKeysEnv)
+	}
+	if raw := os.Getenv(APIKeysEnv); raw != "" {
+		var keys []APIKeyConfig
+		if err := json.Unmarshal([]byte(raw), &keys); err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", APIKeysEnv, err)
+		}
+		cfg.Auth.Keys = append(cfg.Auth.Keys, keys...)
+	}
+	switch cfg.Auth.KeyStore {
+	case "":
+		cfg.Auth.KeyStore = KeyStoreConfig
+	case KeyStoreConfig, KeyStoreMongo:
+	default:
+		return nil, fmt.Errorf("invalid auth.key_store: %q", cfg.Auth.KeyStore)
+	}
+	switch cfg.RateLimit.Store {
+	case "":
+		cfg.RateLimit.Store = RateLimitStoreMemory
+	case RateLimitStoreMemory, RateLimitStoreMongo:
+	default:
+		return nil, fmt.Errorf("invalid rate_limit.store: %q", cfg.RateLimit.Store)
+	}
+	if err := decodeRateLimitRoutes(configManager, cfg.RateLimit); err != nil {
+		return nil, err
+	}
+	if err := cfg.RateLimit.Default.validate("rate_limit.default"); err != nil {
+		return nil, err
+	}
+	for key, list := range map[string]*[]string{
+		"cors.allow_origins":  &cfg.CORS.AllowOrigins,
+		"cors.allow_methods":  &cfg.CORS.AllowMethods,
+		"cors.allow_headers":  &cfg.CORS.AllowHeaders,
+		"cors.expose_headers": &cfg.CORS.ExposeHeaders,
+	} {
+		if err := decodeValue(configManager, key, list); err != nil {
+			return nil, err
+		}
+	}
+	for i, origin := range cfg.CORS.AllowOrigins {
+		if err := validateOrigin(origin); err != nil {
+			return nil, fmt.Errorf("invalid cors.allow_origins[%d]: %w", i, err)
+		}
+	}
+	if err := decodeValue(configManager, "tracing.sample_ratio", &cfg.Tracing.SampleRatio); err != nil {
+		return nil, err
+	}
+	if cfg.Tracing.SampleRatio < 0 || cfg.Tracing.SampleRatio > 1 {
+		return nil, fmt.Errorf("invalid tracing.sample_ratio: %v must be between 0 and 1", cfg.Tracing.SampleRatio)
+	}
+	if cfg.Tracing.ServiceName == "" {
+		cfg.Tracing.ServiceName = "orderfoodonline"
+	}
+	switch cfg.Tracing.Exporter {
+	case "":
+		cfg.Tracing.Exporter = TracingExporterNone
+	case TracingExporterNone, TracingExporterStdout, TracingExporterOTLP:
+	default:
+		return nil, fmt.Errorf("invalid tracing.exporter: %q", cfg.Tracing.Exporter)
+	}
+	for key, buckets := range map[string]*[]float64{
+		"metrics.latency_buckets": &cfg.Metrics.LatencyBuckets,
+		"metrics.size_buckets":    &cfg.Metrics.SizeBuckets,
+	} {
+		if err := decodeValue(configManager, key, buckets); err != nil {
+			return nil, err
+		}
+		for i := 1; i < len(*buckets); i++ {
+			if (*buckets)[i] <= (*buckets)[i-1] {
+				return nil, fmt.Errorf("invalid %s: buckets must be in increasing order", key)
+			}
+		}
+	}
+	if cfg.Auth.JWT.Enabled {
+		jwtCfg := cfg.Auth.JWT
+		if jwtCfg.Issuer == "" || jwtCfg.Audience == "" {
+			return nil, fmt.Errorf("auth.jwt requires an issuer and an audience")
+		}
+		if jwtCfg.HMACSecret == "" && jwtCfg.RSAPublicKeyFile == "" && jwtCfg.JWKSFile == "" {
+			return nil, fmt.Errorf("auth.jwt requires a hmac_secret, rsa_public_key_file or jwks_file")
+		}
 	}
 	cfg.Logger.Version = constants.Version
 	cfg.Logger.Commit = constants.CommitHash
 	return &cfg, nil
 }
+
+// decodeRateLimitRoutes reads the per-route overrides of rate_limit.routes into cfg.
+func decodeRateLimitRoutes(configManager *config.Manager, cfg *RateLimitConfig) error {
+	// Windows are written as duration strings, which encoding/json cannot decode into a time.Duration
+	var routes map[string]struct {
+		Limit  int    `json:"limit"`
+		Window string `json:"window"`
+		Key    string `json:"key"`
+	}
+	if err := decodeValue(configManager, "rate_limit.routes", &routes); err != nil {
+		return err
+	}
+
+	cfg.Routes = make(map[string]RateLimitPolicy, len(routes))
+	for route, raw := range routes {
+		name := fmt.Sprintf("rate_limit.routes[%q]", route)
+		method, path, ok := strings.Cut(route, " ")
+		if !ok || method == "" || !strings.HasPrefix(path, "/") {
+			return fmt.Errorf("invalid %s: expected \"METHOD /path\"", name)
+		}
+		window, err := time.ParseDuration(raw.Window)
+		if err != nil {
+			return fmt.Errorf("invalid %s.window: %w", name, err)
+		}
+		policy := RateLimitPolicy{Limit: raw.Limit, Window: window, Key: raw.Key}
+		if err := policy.validate(name); err != nil {
+			return err
+		}
+		cfg.Routes[route] = policy
+	}
+	return nil
+}
+
+// validate checks the policy and defaults its key to RateLimitKeyCaller.
+func (p *RateLimitPolicy) validate(name string) error {
+	switch p.Key {
+	case "":
+		p.Key = RateLimitKeyCaller
+	case RateLimitKeyIP, RateLimitKeyCaller:
+	default:
+		return fmt.Errorf("invalid %s.key: %q", name, p.Key)
+	}
+	if p.Limit > 0 && p.Window <= 0 {
+		return fmt.Errorf("invalid %s.window: must be positive", name)
+	}
+	return nil
+}
+
+// validateOrigin checks that origin is a scheme and host, optionally with a port,
+// whose host may start with a "*." wildcard.
+func validateOrigin(origin string) error {
+	scheme, host, ok := strings.Cut(origin, "://")
+	if !ok || scheme == "" || host == "" {
+		return fmt.Errorf("%q: expected \"scheme://host\"", origin)
+	}
+	if strings.ContainsAny(host, "/?#") {
+		return fmt.Errorf("%q: must not have a path", origin)
+	}
+	if strings.Contains(strings.TrimPrefix(host, "*."), "*") {
+		return fmt.Errorf("%q: a wildcard is only allowed as the first label of the host", origin)
+	}
+	return nil
+}
+
+// decodeValue decodes the generic JSON form of the value at key into v, leaving v unchanged if the key is not set.
+func decodeValue(configManager *config.Manager, key string, v interface{}) error {
+	value := configManager.Get(key)
+	if value == nil {
+		return nil
+	}
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("invalid %s: %w", key, err)
+	}
+	if err := json.Unmarshal(raw, v); err != nil {
+		return fmt.Errorf("invalid %s: %w", key, err)
+	}
+	return nil
+}
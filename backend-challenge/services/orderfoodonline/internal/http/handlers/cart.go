@@ -0,0 +1,331 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"orderfoodonline/internal/http/middlewares"
+	"orderfoodonline/internal/repository/models"
+	"orderfoodonline/internal/service"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+type cartHandler struct {
+	service service.CartService
+}
+
+// NewCartHandler creates a new CartHandler.
+func NewCartHandler(service service.CartService) CartHandler {
+	return &cartHandler{service: service}
+}
+
+// CreateCart godoc
+// @Summary Create a cart
+// @Description Create a server-side cart, optionally holding items and a coupon. The cart is priced against the current catalog and expires after a period without changes. It belongs to the authenticated customer; the carts of other customers are reported as not found.
+// @Tags cart
+// @Accept json
+// @Produce json
+// @Param cart body models.CartCreateRequest false "Initial items and coupon"
+// @Success 201 {object} models.Cart
+// @Failure 400 {object} map[string]string "error":"Invalid input"
+// @Failure 404 {object} map[string]string "error":"Product not found"
+// @Failure 422 {object} models.ProductUnavailableResponse "Validation exception, a product is unavailable (code product_unavailable), or the coupon cannot be redeemed (code is a coupon preview reason)"
+// @Failure 500 {object} map[string]string "error":"Failed to update cart"
+// @Router /api/cart [post]
+func (h *cartHandler) CreateCart(c *gin.Context) {
+	var req models.CartCreateRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			middlewares.ErrorResponse(c, http.StatusBadRequest, gin.H{"error": "Invalid input"})
+			return
+		}
+	}
+	req.CustomerID = customerID(c)
+	cart, err := h.service.CreateCart(c.Request.Context(), &req)
+	if err != nil {
+		respondCartError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, cart)
+}
+
+// GetCart godoc
+// @Summary Get a cart
+// @Description Get a cart, re-priced against the current catalog
+// @Tags cart
+// @Produce json
+// @Param cartId path string true "Cart ID"
+// @Success 200 {object} models.Cart
+// @Failure 400 {object} map[string]string "error":"Invalid ID supplied"
+// @Failure 404 {object} map[string]string "error":"Cart not found"
+// @Failure 500 {object} map[string]string "error":"Failed to fetch cart"
+// @Router /api/cart/{cartId} [get]
+func (h *cartHandler) GetCart(c *gin.Context) {
+	id := strings.TrimSpace(c.Param("cartId"))
+	if id == "" {
+		middlewares.ErrorResponse(c, http.StatusBadRequest, gin.H{"error": "Invalid ID supplied"})
+		return
+	}
+	cart, err := h.service.GetCart(c.Request.Context(), id, customerID(c))
+	if err != nil {
+		middlewares.ErrorResponse(c, http.StatusInternalServerError, gin.H{"error": "Failed to fetch cart"})
+		return
+	}
+	if cart == nil {
+		middlewares.ErrorResponse(c, http.StatusNotFound, gin.H{"error": "Cart not found"})
+		return
+	}
+	c.JSON(http.StatusOK, cart)
+}
+
+// DeleteCart godoc
+// @Summary Delete a cart
+// @Description Discard a cart and everything in it
+// @Tags cart
+// @Param cartId path string true "Cart ID"
+// @Success 204
+// @Failure 400 {object} map[string]string "error":"Invalid ID supplied"
+// @Failure 404 {object} map[string]string "error":"Cart not found"
+// @Failure 500 {object} map[string]string "error":"Failed to delete cart"
+// @Router /api/cart/{cartId} [delete]
+func (h *cartHandler) DeleteCart(c *gin.Context) {
+	id := strings.TrimSpace(c.Param("cartId"))
+	if id == "" {
+		middlewares.ErrorResponse(c, http.StatusBadRequest, gin.H{"error": "Invalid ID supplied"})
+		return
+	}
+	if err := h.service.DeleteCart(c.Request.Context(), id, customerID(c)); err != nil {
+		if err.Error() == service.CartNotFound {
+			middlewares.ErrorResponse(c, http.StatusNotFound, gin.H{"error": "Cart not found"})
+			return
+		}
+		middlewares.ErrorResponse(c, http.StatusInternalServerError, gin.H{"error": "Failed to delete cart"})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// AddCartItem godoc
+// @Summary Add an item to a cart
+// @Description Add units of a product to a cart. Units of a product already in the cart are added to its quantity.
+// @Tags cart
+// @Accept json
+// @Produce json
+// @Param cartId path string true "Cart ID"
+// @Param item body models.CartItem true "Product and quantity to add"
+// @Success 200 {object} models.Cart
+// @Failure 400 {object} map[string]string "error":"Invalid input"
+// @Failure 404 {object} map[string]string "error":"Cart not found, or product not found"
+// @Failure 409 {object} map[string]string "error":"Cart was modified concurrently"
+// @Failure 422 {object} models.ProductUnavailableResponse "Validation exception, or the product is unavailable (code product_unavailable)"
+// @Failure 500 {object} map[string]string "error":"Failed to update cart"
+// @Router /api/cart/{cartId}/items [post]
+func (h *cartHandler) AddCartItem(c *gin.Context) {
+	id := strings.TrimSpace(c.Param("cartId"))
+	var item models.CartItem
+	if id == "" {
+		middlewares.ErrorResponse(c, http.StatusBadRequest, gin.H{"error": "Invalid input"})
+		return
+	}
+	if err := c.ShouldBindJSON(&item); err != nil {
+		middlewares.ErrorResponse(c, http.StatusBadRequest, gin.H{"error": "Invalid input"})
+		return
+	}
+	cart, err := h.service.AddCartItem(c.Request.Context(), id, customerID(c), item)
+	if err != nil {
+		respondCartError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, cart)
+}
+
+// UpdateCartItem godoc
+// @Summary Change the quantity of a cart item
+// @Description Set the quantity of a product already in a cart
+// @Tags cart
+// @Accept json
+// @Produce json
+// @Param cartId path string true "Cart ID"
+// @Param productId path string true "Product ID"
+// @Param item body models.CartItemUpdateRequest true "New quantity"
+// @Success 200 {object} models.Cart
+// @Failure 400 {object} map[string]string "error":"Invalid input"
+// @Failure 404 {object} map[string]string "error":"Cart not found, or cart item not found"
+// @Failure 409 {object} map[string]string "error":"Cart was modified concurrently"
+// @Failure 422 {object} map[string]string "error":"Validation exception"
+// @Failure 500 {object} map[string]string "error":"Failed to update cart"
+// @Router /api/cart/{cartId}/items/{productId} [put]
+func (h *cartHandler) UpdateCartItem(c *gin.Context) {
+	id := strings.TrimSpace(c.Param("cartId"))
+	productID := strings.TrimSpace(c.Param("productId"))
+	var req models.CartItemUpdateRequest
+	if id == "" || productID == "" {
+		middlewares.ErrorResponse(c, http.StatusBadRequest, gin.H{"error": "Invalid input"})
+		return
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middlewares.ErrorResponse(c, http.StatusBadRequest, gin.H{"error": "Invalid input"})
+		return
+	}
+	cart, err := h.service.UpdateCartItem(c.Request.Context(), id, customerID(c), productID, req.Quantity)
+	if err != nil {
+		respondCartError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, cart)
+}
+
+// RemoveCartItem godoc
+// @Summary Remove an item from a cart
+// @Description Remove a product from a cart
+// @Tags cart
+// @Produce json
+// @Param cartId path string true "Cart ID"
+// @Param productId path string true "Product ID"
+// @Success 200 {object} models.Cart
+// @Failure 400 {object} map[string]string "error":"Invalid input"
+// @Failure 404 {object} map[string]string "error":"Cart not found, or cart item not found"
+// @Failure 409 {object} map[string]string "error":"Cart was modified concurrently"
+// @Failure 500 {object} map[string]string "error":"Failed to update cart"
+// @Router /api/cart/{cartId}/items/{productId} [delete]
+func (h *cartHandler) RemoveCartItem(c *gin.Context) {
+	id := strings.TrimSpace(c.Param("cartId"))
+	productID := strings.TrimSpace(c.Param("productId"))
+	if id == "" || productID == "" {
+		middlewares.ErrorResponse(c, http.StatusBadRequest, gin.H{"error": "Invalid input"})
+		return
+	}
+	cart, err := h.service.RemoveCartItem(c.Request.Context(), id, customerID(c), productID)
+	if err != nil {
+		respondCartError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, cart)
+}
+
+// ApplyCartCoupon godoc
+// @Summary Apply a coupon to a cart
+// @Description Apply a coupon to a cart, replacing any previous one. A valid coupon whose rule does not apply to the current items is kept and its couponReason is reported.
+// @Tags cart
+// @Accept json
+// @Produce json
+// @Param cartId path string true "Cart ID"
+// @Param coupon body models.CartCouponRequest true "Coupon code"
+// @Success 200 {object} models.Cart
+// @Failure 400 {object} map[string]string "error":"Invalid input"
+// @Failure 404 {object} map[string]string "error":"Cart not found"
+// @Failure 409 {object} map[string]string "error":"Cart was modified concurrently"
+// @Failure 422 {object} map[string]string "Validation exception, or the coupon cannot be redeemed (code is a coupon preview reason)"
+// @Failure 500 {object} map[string]string "error":"Failed to update cart"
+// @Router /api/cart/{cartId}/coupon [put]
+func (h *cartHandler) ApplyCartCoupon(c *gin.Context) {
+	id := strings.TrimSpace(c.Param("cartId"))
+	var req models.CartCouponRequest
+	if id == "" {
+		middlewares.ErrorResponse(c, http.StatusBadRequest, gin.H{"error": "Invalid input"})
+		return
+	}
+	if err := c.ShouldBindJSON(&req); err != nil || strings.TrimSpace(req.CouponCode) == "" {
+		middlewares.ErrorResponse(c, http.StatusBadRequest, gin.H{"error": "Invalid input"})
+		return
+	}
+	cart, err := h.service.ApplyCartCoupon(c.Request.Context(), id, customerID(c), req.CouponCode)
+	if err != nil {
+		respondCartError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, cart)
+}
+
+// RemoveCartCoupon godoc
+// @Summary Remove the coupon from a cart
+// @Description Remove the coupon applied to a cart, if any
+// @Tags cart
+// @Produce json
+// @Param cartId path string true "Cart ID"
+// @Success 200 {object} models.Cart
+// @Failure 400 {object} map[string]string "error":"Invalid input"
+// @Failure 404 {object} map[string]string "error":"Cart not found"
+// @Failure 409 {object} map[string]string "error":"Cart was modified concurrently"
+// @Failure 500 {object} map[string]string "error":"Failed to update cart"
+// @Router /api/cart/{cartId}/coupon [delete]
+func (h *cartHandler) RemoveCartCoupon(c *gin.Context) {
+	id := strings.TrimSpace(c.Param("cartId"))
+	if id == "" {
+		middlewares.ErrorResponse(c, http.StatusBadRequest, gin.H{"error": "Invalid input"})
+		return
+	}
+	cart, err := h.service.RemoveCartCoupon(c.Request.Context(), id, customerID(c))
+	if err != nil {
+		respondCartError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, cart)
+}
+
+// CheckoutCart godoc
+// @Summary Check out a cart
+// @Description Place an order for the items and coupon of a cart and discard the cart. Fails like placing the order directly would; if a price or product version changed since the cart was last changed, the order is rejected with the changed lines and the cart is re-priced, so that it can be reviewed and checked out again.
+// @Tags cart
+// @Produce json
+// @Param cartId path string true "Cart ID"
+// @Param Idempotency-Key header string false "Unique key making retries of this request safe"
+// @Success 200 {object} models.Order
+// @Failure 400 {object} map[string]string "error":"Invalid input"
+// @Failure 404 {object} map[string]string "error":"Cart not found, or product not found"
+// @Failure 409 {object} models.StaleCartResponse "Cart is out of date, products are out of stock (body is a models.OutOfStockResponse), or Idempotency-Key was already used with a different request"
+// @Failure 422 {object} models.ProductUnavailableResponse "Cart is empty, validation exception, a product of the cart is unavailable (code product_unavailable), or the coupon cannot be redeemed (code is a coupon preview reason)"
+// @Failure 500 {object} map[string]string "error":"Failed to place an order"
+// @Router /api/cart/{cartId}/checkout [post]
+func (h *cartHandler) CheckoutCart(c *gin.Context) {
+	id := strings.TrimSpace(c.Param("cartId"))
+	if id == "" {
+		middlewares.ErrorResponse(c, http.StatusBadRequest, gin.H{"error": "Invalid input"})
+		return
+	}
+	order, err := h.service.CheckoutCart(c.Request.Context(), id, customerID(c))
+	if err != nil {
+		switch err.Error() {
+		case service.CartNotFound:
+			middlewares.ErrorResponse(c, http.StatusNotFound, gin.H{"error": "Cart not found"})
+		case service.EmptyCart:
+			middlewares.ErrorResponse(c, http.StatusUnprocessableEntity, gin.H{"error": "Cart is empty"})
+		default:
+			respondPlaceOrderError(c, err)
+		}
+		return
+	}
+	c.JSON(http.StatusOK, order)
+}
+
+// respondCartError writes the response for an error returned by a CartService operation
+// that creates or changes a cart.
+func respondCartError(c *gin.Context, err error) {
+	var couponErr *service.CouponRejectedError
+	if errors.As(err, &couponErr) {
+		middlewares.ErrorResponse(c, http.StatusUnprocessableEntity, gin.H{"error": "Coupon not applicable", "code": couponErr.Reason})
+		return
+	}
+	switch {
+	case err.Error() == service.CartNotFound:
+		middlewares.ErrorResponse(c, http.StatusNotFound, gin.H{"error": "Cart not found"})
+	case err.Error() == service.CartItemNotFound:
+		middlewares.ErrorResponse(c, http.StatusNotFound, gin.H{"error": "Cart item not found"})
+	case err.Error() == service.CartConflict:
+		middlewares.ErrorResponse(c, http.StatusConflict, gin.H{"error": "Cart was modified concurrently"})
+	case err.Error() == service.InvalidProductOrQuantity, err.Error() == service.InvalidPromoCode:
+		middlewares.ErrorResponse(c, http.StatusUnprocessableEntity, gin.H{"error": "Validation exception"})
+	case strings.HasPrefix(err.Error(), service.ProductUnavailable):
+		c.JSON(http.StatusUnprocessableEntity, models.ProductUnavailableResponse{
+			Error:     "Product unavailable",
+			Code:      models.ProductUnavailableCode,
+			ProductID: strings.TrimPrefix(err.Error(), service.ProductUnavailable),
+			RequestID: middlewares.RequestIDFromContext(c),
+		})
+	case strings.HasPrefix(err.Error(), service.ProductNotFound):
+		middlewares.ErrorResponse(c, http.StatusNotFound, gin.H{"error": "Product not found"})
+	default:
+		middlewares.ErrorResponse(c, http.StatusInternalServerError, gin.H{"error": "Failed to update cart"})
+	}
+}
@@ -0,0 +1,307 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	"orderfoodonline/internal/repository/models"
+	"orderfoodonline/internal/service"
+	servicemocks "orderfoodonline/internal/service/mocks"
+)
+
+func TestCartHandler_CreateCart(t *testing.T) {
+	tests := []struct {
+		name         string
+		body         string
+		err          error
+		callsService bool
+		expectedCode int
+		expectedBody string
+	}{
+		{"invalid json", "not-json", nil, false, http.StatusBadRequest, "Invalid input"},
+		{"empty body", "", nil, true, http.StatusCreated, `"id":"cart-1"`},
+		{"with items", `{"items":[{"productId":"1","quantity":2}],"couponCode":"HAPPYHRS"}`, nil, true, http.StatusCreated, `"id":"cart-1"`},
+		{"invalid coupon", `{"couponCode":"bad"}`, errors.New(service.InvalidPromoCode), true, http.StatusUnprocessableEntity, "Validation exception"},
+		{"product not found", `{"items":[{"productId":"9","quantity":1}]}`, errors.New(service.ProductNotFound + "9"), true, http.StatusNotFound, "Product not found"},
+		{"service error", "", errors.New(service.SaveCartError), true, http.StatusInternalServerError, "Failed to update cart"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			mockService := servicemocks.NewMockCartService(ctrl)
+			h := NewCartHandler(mockService)
+
+			gin.SetMode(gin.TestMode)
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request, _ = http.NewRequest("POST", "/api/cart", bytes.NewBufferString(tt.body))
+			c.Request.Header.Set("Content-Type", "application/json")
+			authenticateCustomer(t, ctrl, c, " cust-1 ")
+
+			if tt.callsService {
+				var cart *models.Cart
+				if tt.err == nil {
+					cart = &models.Cart{ID: "cart-1"}
+				}
+				mockService.EXPECT().CreateCart(gomock.Any(), gomock.Any()).DoAndReturn(
+					func(_ context.Context, req *models.CartCreateRequest) (*models.Cart, error) {
+						assert.Equal(t, "cust-1", req.CustomerID)
+						return cart, tt.err
+					})
+			}
+
+			h.CreateCart(c)
+			assert.Equal(t, tt.expectedCode, w.Code)
+			assert.Contains(t, w.Body.String(), tt.expectedBody)
+		})
+	}
+}
+
+func TestCartHandler_GetCart(t *testing.T) {
+	tests := []struct {
+		name         string
+		cartID       string
+		cart         *models.Cart
+		err          error
+		callsService bool
+		expectedCode int
+		expectedBody string
+	}{
+		{"blank id", " ", nil, nil, false, http.StatusBadRequest, "Invalid ID supplied"},
+		{"not found", "missing", nil, nil, true, http.StatusNotFound, "Cart not found"},
+		{"service error", "cart-1", nil, errors.New(service.FindCartError), true, http.StatusInternalServerError, "Failed to fetch cart"},
+		{"success", "cart-1", &models.Cart{ID: "cart-1"}, nil, true, http.StatusOK, `"id":"cart-1"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			mockService := servicemocks.NewMockCartService(ctrl)
+			h := NewCartHandler(mockService)
+
+			gin.SetMode(gin.TestMode)
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request, _ = http.NewRequest("GET", "/api/cart/"+tt.cartID, nil)
+			c.Params = gin.Params{{Key: "cartId", Value: tt.cartID}}
+			authenticateCustomer(t, ctrl, c, "cust-1")
+
+			if tt.callsService {
+				mockService.EXPECT().GetCart(gomock.Any(), tt.cartID, "cust-1").Return(tt.cart, tt.err)
+			}
+
+			h.GetCart(c)
+			assert.Equal(t, tt.expectedCode, w.Code)
+			assert.Contains(t, w.Body.String(), tt.expectedBody)
+		})
+	}
+}
+
+func TestCartHandler_DeleteCart(t *testing.T) {
+	tests := []struct {
+		name         string
+		err          error
+		expectedCode int
+	}{
+		{"deleted", nil, http.StatusNoContent},
+		{"not found", errors.New(service.CartNotFound), http.StatusNotFound},
+		{"service error", errors.New(service.DeleteCartError), http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			mockService := servicemocks.NewMockCartService(ctrl)
+			h := NewCartHandler(mockService)
+
+			gin.SetMode(gin.TestMode)
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request, _ = http.NewRequest("DELETE", "/api/cart/cart-1", nil)
+			c.Params = gin.Params{{Key: "cartId", Value: "cart-1"}}
+			authenticateCustomer(t, ctrl, c, "cust-1")
+
+			mockService.EXPECT().DeleteCart(gomock.Any(), "cart-1", "cust-1").Return(tt.err)
+
+			h.DeleteCart(c)
+			assert.Equal(t, tt.expectedCode, c.Writer.Status())
+		})
+	}
+}
+
+func TestCartHandler_AddCartItem(t *testing.T) {
+	tests := []struct {
+		name         string
+		body         string
+		err          error
+		callsService bool
+		expectedCode int
+		expectedBody string
+	}{
+		{"invalid json", "not-json", nil, false, http.StatusBadRequest, "Invalid input"},
+		{"cart not found", `{"productId":"1","quantity":1}`, errors.New(service.CartNotFound), true, http.StatusNotFound, "Cart not found"},
+		{"invalid quantity", `{"productId":"1","quantity":0}`, errors.New(service.InvalidProductOrQuantity), true, http.StatusUnprocessableEntity, "Validation exception"},
+		{"unavailable product", `{"productId":"2","quantity":1}`, errors.New(service.ProductUnavailable + "2"), true, http.StatusUnprocessableEntity, `"code":"product_unavailable","productId":"2"`},
+		{"concurrent change", `{"productId":"1","quantity":1}`, errors.New(service.CartConflict), true, http.StatusConflict, "Cart was modified concurrently"},
+		{"success", `{"productId":"1","quantity":1}`, nil, true, http.StatusOK, `"id":"cart-1"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			mockService := servicemocks.NewMockCartService(ctrl)
+			h := NewCartHandler(mockService)
+
+			gin.SetMode(gin.TestMode)
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request, _ = http.NewRequest("POST", "/api/cart/cart-1/items", bytes.NewBufferString(tt.body))
+			c.Request.Header.Set("Content-Type", "application/json")
+			c.Params = gin.Params{{Key: "cartId", Value: "cart-1"}}
+			authenticateCustomer(t, ctrl, c, "cust-1")
+
+			if tt.callsService {
+				var cart *models.Cart
+				if tt.err == nil {
+					cart = &models.Cart{ID: "cart-1"}
+				}
+				mockService.EXPECT().AddCartItem(gomock.Any(), "cart-1", "cust-1", gomock.Any()).Return(cart, tt.err)
+			}
+
+			h.AddCartItem(c)
+			assert.Equal(t, tt.expectedCode, w.Code)
+			assert.Contains(t, w.Body.String(), tt.expectedBody)
+		})
+	}
+}
+
+func TestCartHandler_UpdateAndRemoveCartItem(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockService := servicemocks.NewMockCartService(ctrl)
+	h := NewCartHandler(mockService)
+	gin.SetMode(gin.TestMode)
+	params := gin.Params{{Key: "cartId", Value: "cart-1"}, {Key: "productId", Value: "1"}}
+
+	// Changing the quantity
+	mockService.EXPECT().UpdateCartItem(gomock.Any(), "cart-1", "cust-1", "1", 3).Return(&models.Cart{ID: "cart-1"}, nil)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest("PUT", "/api/cart/cart-1/items/1", bytes.NewBufferString(`{"quantity":3}`))
+	c.Request.Header.Set("Content-Type", "application/json")
+	c.Params = params
+	authenticateCustomer(t, ctrl, c, "cust-1")
+	h.UpdateCartItem(c)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	// Removing a product that is not in the cart
+	mockService.EXPECT().RemoveCartItem(gomock.Any(), "cart-1", "cust-1", "1").Return(nil, errors.New(service.CartItemNotFound))
+	w = httptest.NewRecorder()
+	c, _ = gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest("DELETE", "/api/cart/cart-1/items/1", nil)
+	c.Params = params
+	authenticateCustomer(t, ctrl, c, "cust-1")
+	h.RemoveCartItem(c)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Contains(t, w.Body.String(), "Cart item not found")
+}
+
+func TestCartHandler_ApplyCartCoupon(t *testing.T) {
+	tests := []struct {
+		name         string
+		body         string
+		err          error
+		callsService bool
+		expectedCode int
+		expectedBody string
+	}{
+		{"missing code", `{"couponCode":" "}`, nil, false, http.StatusBadRequest, "Invalid input"},
+		{"invalid code", `{"couponCode":"bad"}`, errors.New(service.InvalidPromoCode), true, http.StatusUnprocessableEntity, "Validation exception"},
+		{"rejected", `{"couponCode":"HAPPYHRS"}`, &service.CouponRejectedError{Reason: models.CouponReasonExpired}, true, http.StatusUnprocessableEntity, `{"code":"expired","error":"Coupon not applicable"}`},
+		{"success", `{"couponCode":"HAPPYHRS"}`, nil, true, http.StatusOK, `"couponCode":"HAPPYHRS"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			mockService := servicemocks.NewMockCartService(ctrl)
+			h := NewCartHandler(mockService)
+
+			gin.SetMode(gin.TestMode)
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request, _ = http.NewRequest("PUT", "/api/cart/cart-1/coupon", bytes.NewBufferString(tt.body))
+			c.Request.Header.Set("Content-Type", "application/json")
+			c.Params = gin.Params{{Key: "cartId", Value: "cart-1"}}
+			authenticateCustomer(t, ctrl, c, "cust-1")
+
+			if tt.callsService {
+				var cart *models.Cart
+				if tt.err == nil {
+					cart = &models.Cart{ID: "cart-1", CouponCode: "HAPPYHRS"}
+				}
+				mockService.EXPECT().ApplyCartCoupon(gomock.Any(), "cart-1", "cust-1", gomock.Any()).Return(cart, tt.err)
+			}
+
+			h.ApplyCartCoupon(c)
+			assert.Equal(t, tt.expectedCode, w.Code)
+			assert.Contains(t, w.Body.String(), tt.expectedBody)
+		})
+	}
+}
+
+func TestCartHandler_CheckoutCart(t *testing.T) {
+	tests := []struct {
+		name         string
+		err          error
+		expectedCode int
+		expectedBody string
+	}{
+		{"cart not found", errors.New(service.CartNotFound), http.StatusNotFound, "Cart not found"},
+		{"empty cart", errors.New(service.EmptyCart), http.StatusUnprocessableEntity, "Cart is empty"},
+		{"out of stock", &service.OutOfStockError{ProductIDs: []string{"1"}}, http.StatusConflict, `"productIds":["1"]`},
+		{"coupon rejected", &service.CouponRejectedError{Reason: models.CouponReasonMinimumNotMet}, http.StatusUnprocessableEntity, `"code":"minimum_not_met"`},
+		{"service error", errors.New("db error"), http.StatusInternalServerError, "Failed to place an order"},
+		{"success", nil, http.StatusOK, `"id":"order-1"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			mockService := servicemocks.NewMockCartService(ctrl)
+			h := NewCartHandler(mockService)
+
+			gin.SetMode(gin.TestMode)
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request, _ = http.NewRequest("POST", "/api/cart/cart-1/checkout", nil)
+			c.Params = gin.Params{{Key: "cartId", Value: "cart-1"}}
+			authenticateCustomer(t, ctrl, c, "cust-1")
+
+			var order *models.Order
+			if tt.err == nil {
+				order = &models.Order{ID: "order-1"}
+			}
+			mockService.EXPECT().CheckoutCart(gomock.Any(), "cart-1", "cust-1").Return(order, tt.err)
+
+			h.CheckoutCart(c)
+			assert.Equal(t, tt.expectedCode, w.Code)
+			assert.Contains(t, w.Body.String(), tt.expectedBody)
+		})
+	}
+}
@@ -0,0 +1,35 @@
+package handlers
+
+import (
+	"net/http"
+	"orderfoodonline/internal/http/middlewares"
+	"orderfoodonline/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type categoryHandler struct {
+	service service.CategoryService
+}
+
+// NewCategoryHandler creates a new CategoryHandler.
+func NewCategoryHandler(service service.CategoryService) CategoryHandler {
+	return &categoryHandler{service: service}
+}
+
+// ListCategories godoc
+// @Summary List categories
+// @Description Get the active product categories in display order, with the number of products in each.
+// @Tags category
+// @Produce json
+// @Success 200 {array} models.CategoryResponse
+// @Failure 500 {object} map[string]string "error":"Failed to fetch categories"
+// @Router /api/category [get]
+func (h *categoryHandler) ListCategories(c *gin.Context) {
+	categories, err := h.service.ListCategories(c)
+	if err != nil {
+		middlewares.ErrorResponse(c, http.StatusInternalServerError, gin.H{"error": "Failed to fetch categories"})
+		return
+	}
+	c.JSON(http.StatusOK, categories)
+}
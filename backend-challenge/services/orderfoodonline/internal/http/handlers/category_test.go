@@ -0,0 +1,60 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	"orderfoodonline/internal/repository/models"
+	"orderfoodonline/internal/service"
+	servicemocks "orderfoodonline/internal/service/mocks"
+)
+
+func TestCategoryHandler_ListCategories_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := servicemocks.NewMockCategoryService(ctrl)
+	h := NewCategoryHandler(mockService)
+
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest("GET", "/api/category", nil)
+
+	expected := []models.CategoryResponse{
+		{Category: models.Category{ID: "pizza", Name: "Pizza", SortOrder: 10, Active: true}, ProductCount: 3},
+	}
+	mockService.EXPECT().ListCategories(gomock.Any()).Return(expected, nil)
+
+	h.ListCategories(c)
+	assert.Equal(t, http.StatusOK, w.Code)
+	var resp []models.CategoryResponse
+	json.Unmarshal(w.Body.Bytes(), &resp)
+	assert.Equal(t, expected, resp)
+}
+
+func TestCategoryHandler_ListCategories_Error(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := servicemocks.NewMockCategoryService(ctrl)
+	h := NewCategoryHandler(mockService)
+
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest("GET", "/api/category", nil)
+
+	mockService.EXPECT().ListCategories(gomock.Any()).Return(nil, errors.New(service.CategoryListingError))
+
+	h.ListCategories(c)
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Contains(t, w.Body.String(), "Failed to fetch categories")
+}
@@ -0,0 +1,49 @@
+package handlers
+
+import (
+	"net/http"
+	"orderfoodonline/internal/http/middlewares"
+	"orderfoodonline/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// customerID returns the authenticated customer a request is made for: the subject of its
+// bearer token, or the customer bound to its API key. It is empty for API keys bound to no
+// customer, which therefore cannot redeem coupons limited to one use per customer.
+func customerID(c *gin.Context) string {
+	if subject := middlewares.SubjectFromContext(c); subject != "" {
+		return subject
+	}
+	if key := middlewares.APIKeyFromContext(c); key != nil {
+		return key.CustomerID
+	}
+	return ""
+}
+
+type couponHandler struct {
+	service service.CouponService
+}
+
+// NewCouponHandler creates a new CouponHandler.
+func NewCouponHandler(service service.CouponService) CouponHandler {
+	return &couponHandler{service: service}
+}
+
+// PreviewCoupon godoc
+// @Summary Preview a coupon
+// @Description Check whether a coupon code would be accepted at checkout, without redeeming it. A code that does not apply is reported with applicable false and a reason.
+// @Tags coupon
+// @Produce json
+// @Param code path string true "Coupon code"
+// @Success 200 {object} models.CouponPreviewResponse
+// @Failure 500 {object} map[string]string "error":"Failed to check coupon"
+// @Router /api/coupon/{code} [get]
+func (h *couponHandler) PreviewCoupon(c *gin.Context) {
+	preview, err := h.service.PreviewCoupon(c.Request.Context(), c.Param("code"), customerID(c))
+	if err != nil {
+		middlewares.ErrorResponse(c, http.StatusInternalServerError, gin.H{"error": "Failed to check coupon"})
+		return
+	}
+	c.JSON(http.StatusOK, preview)
+}
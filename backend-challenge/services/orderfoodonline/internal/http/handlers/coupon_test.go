@@ -0,0 +1,100 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	loggermocks "library/logger/mocks"
+	"orderfoodonline/internal/config"
+	"orderfoodonline/internal/http/middlewares"
+	"orderfoodonline/internal/repository/models"
+	"orderfoodonline/internal/service"
+	servicemocks "orderfoodonline/internal/service/mocks"
+)
+
+// authenticateCustomer authenticates the request of c with an API key bound to the customer.
+func authenticateCustomer(t *testing.T, ctrl *gomock.Controller, c *gin.Context, customerID string) {
+	t.Helper()
+	auth := middlewares.NewAuthMiddleware(middlewares.NewStaticKeyStore(&config.AuthConfig{
+		Keys: []config.APIKeyConfig{{Name: "shop", Key: "shop-key", Scopes: []string{middlewares.ScopeOrder}, CustomerID: customerID}},
+	}), nil, loggermocks.NewMockILogger(ctrl))
+	c.Request.Header.Set("api_key", "shop-key")
+	auth.Authenticate()(c)
+	require.False(t, c.IsAborted())
+}
+
+func TestCouponHandler_PreviewCoupon_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := servicemocks.NewMockCouponService(ctrl)
+	h := NewCouponHandler(mockService)
+
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest("GET", "/api/coupon/HAPPYHRS", nil)
+	authenticateCustomer(t, ctrl, c, " cust-1 ")
+	c.Params = gin.Params{{Key: "code", Value: "HAPPYHRS"}}
+
+	expected := &models.CouponPreviewResponse{Code: "HAPPYHRS", Reason: models.CouponReasonAlreadyRedeemed}
+	mockService.EXPECT().PreviewCoupon(gomock.Any(), "HAPPYHRS", "cust-1").Return(expected, nil)
+
+	h.PreviewCoupon(c)
+	assert.Equal(t, http.StatusOK, w.Code)
+	var resp models.CouponPreviewResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, *expected, resp)
+}
+
+func TestCouponHandler_PreviewCoupon_Error(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := servicemocks.NewMockCouponService(ctrl)
+	h := NewCouponHandler(mockService)
+
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest("GET", "/api/coupon/HAPPYHRS", nil)
+	c.Params = gin.Params{{Key: "code", Value: "HAPPYHRS"}}
+
+	mockService.EXPECT().PreviewCoupon(gomock.Any(), "HAPPYHRS", "").Return(nil, errors.New(service.CheckCouponError))
+
+	h.PreviewCoupon(c)
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Contains(t, w.Body.String(), "Failed to check coupon")
+}
+
+func TestCouponHandler_PreviewCoupon_UnboundKeyIgnoresCustomerHeader(t *testing.T) {
+	// Given: A request authenticated with an API key bound to no customer, naming a customer in a header
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockService := servicemocks.NewMockCouponService(ctrl)
+	h := NewCouponHandler(mockService)
+
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest("GET", "/api/coupon/HAPPYHRS", nil)
+	c.Request.Header.Set("X-Customer-ID", "cust-1")
+	authenticateCustomer(t, ctrl, c, "")
+	c.Params = gin.Params{{Key: "code", Value: "HAPPYHRS"}}
+
+	// Then: The coupon should be checked for no customer
+	mockService.EXPECT().PreviewCoupon(gomock.Any(), "HAPPYHRS", "").
+		Return(&models.CouponPreviewResponse{Code: "HAPPYHRS", Reason: models.CouponReasonCustomerRequired}, nil)
+
+	// When: Previewing the coupon
+	h.PreviewCoupon(c)
+	assert.Equal(t, http.StatusOK, w.Code)
+}
@@ -13,8 +13,8 @@ type SwaggerHandler interface {
 // ProductHandler defines HTTP handlers for product-related endpoints.
 // It provides REST API operations for managing products including listing and retrieval.
 type ProductHandler interface {
-	// ListProducts handles HTTP GET requests to retrieve all available products.
-	// Returns a JSON response with the list of products.
+	// ListProducts handles HTTP GET requests to retrieve a page of available products
+	// with filtering, sorting and search. Returns the page and a cursor for the next one.
 	ListProducts(c *gin.Context)
 
 	// GetProductByID handles HTTP GET requests to retrieve a specific product by ID.
@@ -22,10 +22,94 @@ type ProductHandler interface {
 	GetProductByID(c *gin.Context)
 }
 
+// ProductAdminHandler defines HTTP handlers for the catalog administration endpoints.
+// It provides REST API operations for creating, updating and deleting products.
+type ProductAdminHandler interface {
+	// CreateProduct handles HTTP POST requests that add a product to the catalog.
+	CreateProduct(c *gin.Context)
+
+	// ReplaceProduct handles HTTP PUT requests that overwrite a product.
+	ReplaceProduct(c *gin.Context)
+
+	// PatchProduct handles HTTP PATCH requests that change some fields of a product.
+	PatchProduct(c *gin.Context)
+
+	// DeleteProduct handles HTTP DELETE requests that soft delete a product.
+	DeleteProduct(c *gin.Context)
+}
+
+// StockHandler defines HTTP handlers for the stock administration endpoints.
+type StockHandler interface {
+	// GetStock handles HTTP GET requests for the stock level of a product.
+	GetStock(c *gin.Context)
+
+	// SetStock handles HTTP PUT requests that overwrite the stock level of a product.
+	SetStock(c *gin.Context)
+
+	// Restock handles HTTP POST requests that add a delivery to the stock of a product.
+	Restock(c *gin.Context)
+}
+
+// CategoryHandler defines HTTP handlers for category-related endpoints.
+type CategoryHandler interface {
+	// ListCategories handles HTTP GET requests to retrieve the active categories
+	// in display order, each with the number of products it contains.
+	ListCategories(c *gin.Context)
+}
+
+// CouponHandler defines HTTP handlers for coupon-related endpoints.
+type CouponHandler interface {
+	// PreviewCoupon handles HTTP GET requests to check whether a coupon code
+	// would be accepted at checkout, without redeeming it.
+	PreviewCoupon(c *gin.Context)
+}
+
+// CartHandler defines HTTP handlers for the server-side cart endpoints.
+type CartHandler interface {
+	// CreateCart handles HTTP POST requests that create a cart.
+	CreateCart(c *gin.Context)
+
+	// GetCart handles HTTP GET requests to retrieve a cart, priced at current catalog prices.
+	GetCart(c *gin.Context)
+
+	// DeleteCart handles HTTP DELETE requests that discard a cart.
+	DeleteCart(c *gin.Context)
+
+	// AddCartItem handles HTTP POST requests that add units of a product to a cart.
+	AddCartItem(c *gin.Context)
+
+	// UpdateCartItem handles HTTP PUT requests that change the quantity of a cart item.
+	UpdateCartItem(c *gin.Context)
+
+	// RemoveCartItem handles HTTP DELETE requests that remove a product from a cart.
+	RemoveCartItem(c *gin.Context)
+
+	// ApplyCartCoupon handles HTTP PUT requests that apply a coupon to a cart.
+	ApplyCartCoupon(c *gin.Context)
+
+	// RemoveCartCoupon handles HTTP DELETE requests that remove the coupon from a cart.
+	RemoveCartCoupon(c *gin.Context)
+
+	// CheckoutCart handles HTTP POST requests that place an order for the contents of a cart.
+	CheckoutCart(c *gin.Context)
+}
+
 // OrderHandler defines HTTP handlers for order-related endpoints.
 // It provides REST API operations for creating and managing orders.
 type OrderHandler interface {
 	// PlaceOrder handles HTTP POST requests to create new orders.
 	// Validates the order request, applies business rules, and returns the created order.
 	PlaceOrder(c *gin.Context)
+
+	// GetOrderByID handles HTTP GET requests to retrieve a specific order by ID.
+	// Returns a JSON response with the order details or a 404 error if not found.
+	GetOrderByID(c *gin.Context)
+
+	// ListOrders handles HTTP GET requests to list orders with pagination
+	// and filtering by creation time range and status.
+	ListOrders(c *gin.Context)
+
+	// UpdateOrderStatus handles HTTP PATCH requests that move an order to a new lifecycle status.
+	// Returns the updated order or a 409 error if the transition is not allowed.
+	UpdateOrderStatus(c *gin.Context)
 }
@@ -100,6 +100,342 @@ func (mr *MockProductHandlerMockRecorder) ListProducts(c any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListProducts", reflect.TypeOf((*MockProductHandler)(nil).ListProducts), c)
 }
 
+// MockProductAdminHandler is a mock of ProductAdminHandler interface.
+type MockProductAdminHandler struct {
+	ctrl     *gomock.Controller
+	recorder *MockProductAdminHandlerMockRecorder
+	isgomock struct{}
+}
+
+// MockProductAdminHandlerMockRecorder is the mock recorder for MockProductAdminHandler.
+type MockProductAdminHandlerMockRecorder struct {
+	mock *MockProductAdminHandler
+}
+
+// NewMockProductAdminHandler creates a new mock instance.
+func NewMockProductAdminHandler(ctrl *gomock.Controller) *MockProductAdminHandler {
+	mock := &MockProductAdminHandler{ctrl: ctrl}
+	mock.recorder = &MockProductAdminHandlerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockProductAdminHandler) EXPECT() *MockProductAdminHandlerMockRecorder {
+	return m.recorder
+}
+
+// CreateProduct mocks base method.
+func (m *MockProductAdminHandler) CreateProduct(c *gin.Context) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "CreateProduct", c)
+}
+
+// CreateProduct indicates an expected call of CreateProduct.
+func (mr *MockProductAdminHandlerMockRecorder) CreateProduct(c any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateProduct", reflect.TypeOf((*MockProductAdminHandler)(nil).CreateProduct), c)
+}
+
+// DeleteProduct mocks base method.
+func (m *MockProductAdminHandler) DeleteProduct(c *gin.Context) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "DeleteProduct", c)
+}
+
+// DeleteProduct indicates an expected call of DeleteProduct.
+func (mr *MockProductAdminHandlerMockRecorder) DeleteProduct(c any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteProduct", reflect.TypeOf((*MockProductAdminHandler)(nil).DeleteProduct), c)
+}
+
+// PatchProduct mocks base method.
+func (m *MockProductAdminHandler) PatchProduct(c *gin.Context) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "PatchProduct", c)
+}
+
+// PatchProduct indicates an expected call of PatchProduct.
+func (mr *MockProductAdminHandlerMockRecorder) PatchProduct(c any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PatchProduct", reflect.TypeOf((*MockProductAdminHandler)(nil).PatchProduct), c)
+}
+
+// ReplaceProduct mocks base method.
+func (m *MockProductAdminHandler) ReplaceProduct(c *gin.Context) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "ReplaceProduct", c)
+}
+
+// ReplaceProduct indicates an expected call of ReplaceProduct.
+func (mr *MockProductAdminHandlerMockRecorder) ReplaceProduct(c any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReplaceProduct", reflect.TypeOf((*MockProductAdminHandler)(nil).ReplaceProduct), c)
+}
+
+// MockStockHandler is a mock of StockHandler interface.
+type MockStockHandler struct {
+	ctrl     *gomock.Controller
+	recorder *MockStockHandlerMockRecorder
+	isgomock struct{}
+}
+
+// MockStockHandlerMockRecorder is the mock recorder for MockStockHandler.
+type MockStockHandlerMockRecorder struct {
+	mock *MockStockHandler
+}
+
+// NewMockStockHandler creates a new mock instance.
+func NewMockStockHandler(ctrl *gomock.Controller) *MockStockHandler {
+	mock := &MockStockHandler{ctrl: ctrl}
+	mock.recorder = &MockStockHandlerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockStockHandler) EXPECT() *MockStockHandlerMockRecorder {
+	return m.recorder
+}
+
+// GetStock mocks base method.
+func (m *MockStockHandler) GetStock(c *gin.Context) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "GetStock", c)
+}
+
+// GetStock indicates an expected call of GetStock.
+func (mr *MockStockHandlerMockRecorder) GetStock(c any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStock", reflect.TypeOf((*MockStockHandler)(nil).GetStock), c)
+}
+
+// Restock mocks base method.
+func (m *MockStockHandler) Restock(c *gin.Context) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Restock", c)
+}
+
+// Restock indicates an expected call of Restock.
+func (mr *MockStockHandlerMockRecorder) Restock(c any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Restock", reflect.TypeOf((*MockStockHandler)(nil).Restock), c)
+}
+
+// SetStock mocks base method.
+func (m *MockStockHandler) SetStock(c *gin.Context) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetStock", c)
+}
+
+// SetStock indicates an expected call of SetStock.
+func (mr *MockStockHandlerMockRecorder) SetStock(c any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetStock", reflect.TypeOf((*MockStockHandler)(nil).SetStock), c)
+}
+
+// MockCategoryHandler is a mock of CategoryHandler interface.
+type MockCategoryHandler struct {
+	ctrl     *gomock.Controller
+	recorder *MockCategoryHandlerMockRecorder
+	isgomock struct{}
+}
+
+// MockCategoryHandlerMockRecorder is the mock recorder for MockCategoryHandler.
+type MockCategoryHandlerMockRecorder struct {
+	mock *MockCategoryHandler
+}
+
+// NewMockCategoryHandler creates a new mock instance.
+func NewMockCategoryHandler(ctrl *gomock.Controller) *MockCategoryHandler {
+	mock := &MockCategoryHandler{ctrl: ctrl}
+	mock.recorder = &MockCategoryHandlerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCategoryHandler) EXPECT() *MockCategoryHandlerMockRecorder {
+	return m.recorder
+}
+
+// ListCategories mocks base method.
+func (m *MockCategoryHandler) ListCategories(c *gin.Context) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "ListCategories", c)
+}
+
+// ListCategories indicates an expected call of ListCategories.
+func (mr *MockCategoryHandlerMockRecorder) ListCategories(c any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCategories", reflect.TypeOf((*MockCategoryHandler)(nil).ListCategories), c)
+}
+
+// MockCouponHandler is a mock of CouponHandler interface.
+type MockCouponHandler struct {
+	ctrl     *gomock.Controller
+	recorder *MockCouponHandlerMockRecorder
+	isgomock struct{}
+}
+
+// MockCouponHandlerMockRecorder is the mock recorder for MockCouponHandler.
+type MockCouponHandlerMockRecorder struct {
+	mock *MockCouponHandler
+}
+
+// NewMockCouponHandler creates a new mock instance.
+func NewMockCouponHandler(ctrl *gomock.Controller) *MockCouponHandler {
+	mock := &MockCouponHandler{ctrl: ctrl}
+	mock.recorder = &MockCouponHandlerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCouponHandler) EXPECT() *MockCouponHandlerMockRecorder {
+	return m.recorder
+}
+
+// PreviewCoupon mocks base method.
+func (m *MockCouponHandler) PreviewCoupon(c *gin.Context) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "PreviewCoupon", c)
+}
+
+// PreviewCoupon indicates an expected call of PreviewCoupon.
+func (mr *MockCouponHandlerMockRecorder) PreviewCoupon(c any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PreviewCoupon", reflect.TypeOf((*MockCouponHandler)(nil).PreviewCoupon), c)
+}
+
+// MockCartHandler is a mock of CartHandler interface.
+type MockCartHandler struct {
+	ctrl     *gomock.Controller
+	recorder *MockCartHandlerMockRecorder
+	isgomock struct{}
+}
+
+// MockCartHandlerMockRecorder is the mock recorder for MockCartHandler.
+type MockCartHandlerMockRecorder struct {
+	mock *MockCartHandler
+}
+
+// NewMockCartHandler creates a new mock instance.
+func NewMockCartHandler(ctrl *gomock.Controller) *MockCartHandler {
+	mock := &MockCartHandler{ctrl: ctrl}
+	mock.recorder = &MockCartHandlerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCartHandler) EXPECT() *MockCartHandlerMockRecorder {
+	return m.recorder
+}
+
+// AddCartItem mocks base method.
+func (m *MockCartHandler) AddCartItem(c *gin.Context) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "AddCartItem", c)
+}
+
+// AddCartItem indicates an expected call of AddCartItem.
+func (mr *MockCartHandlerMockRecorder) AddCartItem(c any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddCartItem", reflect.TypeOf((*MockCartHandler)(nil).AddCartItem), c)
+}
+
+// ApplyCartCoupon mocks base method.
+func (m *MockCartHandler) ApplyCartCoupon(c *gin.Context) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "ApplyCartCoupon", c)
+}
+
+// ApplyCartCoupon indicates an expected call of ApplyCartCoupon.
+func (mr *MockCartHandlerMockRecorder) ApplyCartCoupon(c any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ApplyCartCoupon", reflect.TypeOf((*MockCartHandler)(nil).ApplyCartCoupon), c)
+}
+
+// CheckoutCart mocks base method.
+func (m *MockCartHandler) CheckoutCart(c *gin.Context) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "CheckoutCart", c)
+}
+
+// CheckoutCart indicates an expected call of CheckoutCart.
+func (mr *MockCartHandlerMockRecorder) CheckoutCart(c any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckoutCart", reflect.TypeOf((*MockCartHandler)(nil).CheckoutCart), c)
+}
+
+// CreateCart mocks base method.
+func (m *MockCartHandler) CreateCart(c *gin.Context) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "CreateCart", c)
+}
+
+// CreateCart indicates an expected call of CreateCart.
+func (mr *MockCartHandlerMockRecorder) CreateCart(c any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateCart", reflect.TypeOf((*MockCartHandler)(nil).CreateCart), c)
+}
+
+// DeleteCart mocks base method.
+func (m *MockCartHandler) DeleteCart(c *gin.Context) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "DeleteCart", c)
+}
+
+// DeleteCart indicates an expected call of DeleteCart.
+func (mr *MockCartHandlerMockRecorder) DeleteCart(c any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteCart", reflect.TypeOf((*MockCartHandler)(nil).DeleteCart), c)
+}
+
+// GetCart mocks base method.
+func (m *MockCartHandler) GetCart(c *gin.Context) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "GetCart", c)
+}
+
+// GetCart indicates an expected call of GetCart.
+func (mr *MockCartHandlerMockRecorder) GetCart(c any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCart", reflect.TypeOf((*MockCartHandler)(nil).GetCart), c)
+}
+
+// RemoveCartCoupon mocks base method.
+func (m *MockCartHandler) RemoveCartCoupon(c *gin.Context) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "RemoveCartCoupon", c)
+}
+
+// RemoveCartCoupon indicates an expected call of RemoveCartCoupon.
+func (mr *MockCartHandlerMockRecorder) RemoveCartCoupon(c any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveCartCoupon", reflect.TypeOf((*MockCartHandler)(nil).RemoveCartCoupon), c)
+}
+
+// RemoveCartItem mocks base method.
+func (m *MockCartHandler) RemoveCartItem(c *gin.Context) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "RemoveCartItem", c)
+}
+
+// RemoveCartItem indicates an expected call of RemoveCartItem.
+func (mr *MockCartHandlerMockRecorder) RemoveCartItem(c any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveCartItem", reflect.TypeOf((*MockCartHandler)(nil).RemoveCartItem), c)
+}
+
+// UpdateCartItem mocks base method.
+func (m *MockCartHandler) UpdateCartItem(c *gin.Context) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "UpdateCartItem", c)
+}
+
+// UpdateCartItem indicates an expected call of UpdateCartItem.
+func (mr *MockCartHandlerMockRecorder) UpdateCartItem(c any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateCartItem", reflect.TypeOf((*MockCartHandler)(nil).UpdateCartItem), c)
+}
+
 // MockOrderHandler is a mock of OrderHandler interface.
 type MockOrderHandler struct {
 	ctrl     *gomock.Controller
@@ -124,6 +460,30 @@ func (m *MockOrderHandler) EXPECT() *MockOrderHandlerMockRecorder {
 	return m.recorder
 }
 
+// GetOrderByID mocks base method.
+func (m *MockOrderHandler) GetOrderByID(c *gin.Context) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "GetOrderByID", c)
+}
+
+// GetOrderByID indicates an expected call of GetOrderByID.
+func (mr *MockOrderHandlerMockRecorder) GetOrderByID(c any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrderByID", reflect.TypeOf((*MockOrderHandler)(nil).GetOrderByID), c)
+}
+
+// ListOrders mocks base method.
+func (m *MockOrderHandler) ListOrders(c *gin.Context) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "ListOrders", c)
+}
+
+// ListOrders indicates an expected call of ListOrders.
+func (mr *MockOrderHandlerMockRecorder) ListOrders(c any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListOrders", reflect.TypeOf((*MockOrderHandler)(nil).ListOrders), c)
+}
+
 // PlaceOrder mocks base method.
 func (m *MockOrderHandler) PlaceOrder(c *gin.Context) {
 	m.ctrl.T.Helper()
@@ -135,3 +495,15 @@ func (mr *MockOrderHandlerMockRecorder) PlaceOrder(c any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PlaceOrder", reflect.TypeOf((*MockOrderHandler)(nil).PlaceOrder), c)
 }
+
+// UpdateOrderStatus mocks base method.
+func (m *MockOrderHandler) UpdateOrderStatus(c *gin.Context) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "UpdateOrderStatus", c)
+}
+
+// UpdateOrderStatus indicates an expected call of UpdateOrderStatus.
+func (mr *MockOrderHandlerMockRecorder) UpdateOrderStatus(c any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateOrderStatus", reflect.TypeOf((*MockOrderHandler)(nil).UpdateOrderStatus), c)
+}
@@ -1,10 +1,14 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
+	"orderfoodonline/internal/http/middlewares"
 	"orderfoodonline/internal/repository/models"
 	"orderfoodonline/internal/service"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
@@ -25,38 +29,225 @@ func NewOrderHandler(service service.OrderService) OrderHandler {
 // @Accept json
 // @Produce json
 // @Param order body models.OrderCreateRequest true "Order request"
+// @Param Idempotency-Key header string false "Unique key making retries of this request safe"
 // @Success 200 {object} models.Order
 // @Failure 400 {object} map[string]string "error":"Invalid input"
-// @Failure 422 {object} map[string]string "error":"Validation exception"
+// @Failure 422 {object} models.ProductUnavailableResponse "Validation exception, a product of the order is unavailable (code product_unavailable), or the coupon cannot be redeemed (code is a coupon preview reason)"
 // @Failure 404 {object} map[string]string "error":"Product not found"
+// @Failure 409 {object} models.StaleCartResponse "Cart is out of date, products are out of stock (body is a models.OutOfStockResponse), or Idempotency-Key was already used with a different request"
 // @Failure 500 {object} map[string]string "error":"Failed to place an order"
 // @Router /order [post]
 func (h *orderHandler) PlaceOrder(c *gin.Context) {
 	var req models.OrderCreateRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
+		middlewares.ErrorResponse(c, http.StatusBadRequest, gin.H{"error": "Invalid input"})
 		return
 	}
 	if len(req.Items) == 0 {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
+		middlewares.ErrorResponse(c, http.StatusBadRequest, gin.H{"error": "Invalid input"})
 		return
 	}
+	req.CustomerID = customerID(c)
 	order, err := h.service.PlaceOrder(c.Request.Context(), &req)
 	if err != nil {
-		if err.Error() == service.InvalidProductOrQuantity {
-			c.JSON(http.StatusUnprocessableEntity, gin.H{"error": "Validation exception"})
-			return
-		}
-		if strings.Contains(err.Error(), service.ProductNotFound) {
-			c.JSON(http.StatusNotFound, gin.H{"error": "Product not found"})
+		respondPlaceOrderError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, order)
+}
+
+// respondPlaceOrderError writes the response for an error returned by OrderService.PlaceOrder.
+func respondPlaceOrderError(c *gin.Context, err error) {
+	var staleErr *service.StaleCartError
+	if errors.As(err, &staleErr) {
+		c.JSON(http.StatusConflict, models.StaleCartResponse{
+			Error: "Cart is out of date", ChangedLines: staleErr.Lines, RequestID: middlewares.RequestIDFromContext(c),
+		})
+		return
+	}
+	var stockErr *service.OutOfStockError
+	if errors.As(err, &stockErr) {
+		c.JSON(http.StatusConflict, models.OutOfStockResponse{
+			Error: "Out of stock", ProductIDs: stockErr.ProductIDs, RequestID: middlewares.RequestIDFromContext(c),
+		})
+		return
+	}
+	var couponErr *service.CouponRejectedError
+	if errors.As(err, &couponErr) {
+		middlewares.ErrorResponse(c, http.StatusUnprocessableEntity, gin.H{"error": "Coupon not applicable", "code": couponErr.Reason})
+		return
+	}
+	if err.Error() == service.InvalidProductOrQuantity {
+		middlewares.ErrorResponse(c, http.StatusUnprocessableEntity, gin.H{"error": "Validation exception"})
+		return
+	}
+	if strings.HasPrefix(err.Error(), service.ProductUnavailable) {
+		c.JSON(http.StatusUnprocessableEntity, models.ProductUnavailableResponse{
+			Error:     "Product unavailable",
+			Code:      models.ProductUnavailableCode,
+			ProductID: strings.TrimPrefix(err.Error(), service.ProductUnavailable),
+			RequestID: middlewares.RequestIDFromContext(c),
+		})
+		return
+	}
+	if strings.Contains(err.Error(), service.ProductNotFound) {
+		middlewares.ErrorResponse(c, http.StatusNotFound, gin.H{"error": "Product not found"})
+		return
+	}
+	if err.Error() == service.InvalidPromoCode {
+		middlewares.ErrorResponse(c, http.StatusUnprocessableEntity, gin.H{"error": "Validation exception"})
+		return
+	}
+	middlewares.ErrorResponse(c, http.StatusInternalServerError, gin.H{"error": "Failed to place an order"})
+}
+
+// GetOrderByID godoc
+// @Summary Get order by ID
+// @Description Get a previously placed order by its ID. Customers, identified by their bearer token or API key, only see their own orders unless granted the orders:admin scope.
+// @Tags order
+// @Produce json
+// @Param orderId path string true "Order ID"
+// @Success 200 {object} models.Order
+// @Failure 400 {object} map[string]string "error":"Invalid ID supplied"
+// @Failure 404 {object} map[string]string "error":"Order not found"
+// @Failure 500 {object} map[string]string "error":"Failed to fetch order"
+// @Router /api/order/{orderId} [get]
+func (h *orderHandler) GetOrderByID(c *gin.Context) {
+	id := strings.TrimSpace(c.Param("orderId"))
+	if id == "" {
+		middlewares.ErrorResponse(c, http.StatusBadRequest, gin.H{"error": "Invalid ID supplied"})
+		return
+	}
+	order, err := h.service.GetOrder(c.Request.Context(), id, orderOwner(c))
+	if err != nil {
+		middlewares.ErrorResponse(c, http.StatusInternalServerError, gin.H{"error": "Failed to fetch order"})
+		return
+	}
+	if order == nil {
+		middlewares.ErrorResponse(c, http.StatusNotFound, gin.H{"error": "Order not found"})
+		return
+	}
+	c.JSON(http.StatusOK, order)
+}
+
+// ListOrders godoc
+// @Summary List orders
+// @Description List placed orders, newest first, optionally filtered by creation time range and status. Customers, identified by their bearer token or API key, only see their own orders unless granted the orders:admin scope.
+// @Tags order
+// @Produce json
+// @Param from query string false "Only orders created at or after this time (RFC3339)"
+// @Param to query string false "Only orders created at or before this time (RFC3339)"
+// @Param status query string false "Only orders in this status"
+// @Param limit query int false "Page size (default 20, max 100)"
+// @Param offset query int false "Number of orders to skip"
+// @Success 200 {object} models.OrderListResponse
+// @Failure 400 {object} map[string]string "error":"Invalid input"
+// @Failure 500 {object} map[string]string "error":"Failed to fetch orders"
+// @Router /api/order [get]
+func (h *orderHandler) ListOrders(c *gin.Context) {
+	filter, err := parseOrderFilter(c)
+	if err != nil {
+		middlewares.ErrorResponse(c, http.StatusBadRequest, gin.H{"error": "Invalid input"})
+		return
+	}
+	filter.CustomerID = orderOwner(c)
+	orders, err := h.service.ListOrders(c.Request.Context(), filter)
+	if err != nil {
+		if err.Error() == service.InvalidOrderFilter {
+			middlewares.ErrorResponse(c, http.StatusBadRequest, gin.H{"error": "Invalid input"})
 			return
 		}
-		if err.Error() == service.InvalidPromoCode {
-			c.JSON(http.StatusUnprocessableEntity, gin.H{"error": "Validation exception"})
-			return
+		middlewares.ErrorResponse(c, http.StatusInternalServerError, gin.H{"error": "Failed to fetch orders"})
+		return
+	}
+	c.JSON(http.StatusOK, orders)
+}
+
+// UpdateOrderStatus godoc
+// @Summary Update order status
+// @Description Move an order to a new lifecycle status (placed → confirmed → preparing → ready → delivered, or cancelled/refunded). Cancelling an order puts its units back in stock.
+// @Tags order
+// @Accept json
+// @Produce json
+// @Param orderId path string true "Order ID"
+// @Param status body models.OrderStatusUpdateRequest true "New status"
+// @Success 200 {object} models.Order
+// @Failure 400 {object} map[string]string "error":"Invalid input"
+// @Failure 403 {object} models.ForbiddenResponse "Caller not granted the orders:admin scope"
+// @Failure 404 {object} map[string]string "error":"Order not found"
+// @Failure 409 {object} map[string]string "error":"Invalid status transition"
+// @Failure 422 {object} map[string]string "error":"Validation exception"
+// @Failure 500 {object} map[string]string "error":"Failed to update order status"
+// @Router /api/order/{orderId}/status [patch]
+func (h *orderHandler) UpdateOrderStatus(c *gin.Context) {
+	id := strings.TrimSpace(c.Param("orderId"))
+	var req models.OrderStatusUpdateRequest
+	if id == "" {
+		middlewares.ErrorResponse(c, http.StatusBadRequest, gin.H{"error": "Invalid input"})
+		return
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middlewares.ErrorResponse(c, http.StatusBadRequest, gin.H{"error": "Invalid input"})
+		return
+	}
+	order, err := h.service.UpdateOrderStatus(c.Request.Context(), id, &req)
+	if err != nil {
+		switch err.Error() {
+		case service.InvalidOrderStatus:
+			middlewares.ErrorResponse(c, http.StatusUnprocessableEntity, gin.H{"error": "Validation exception"})
+		case service.OrderNotFound:
+			middlewares.ErrorResponse(c, http.StatusNotFound, gin.H{"error": "Order not found"})
+		case service.InvalidStatusTransition:
+			middlewares.ErrorResponse(c, http.StatusConflict, gin.H{"error": "Invalid status transition"})
+		default:
+			middlewares.ErrorResponse(c, http.StatusInternalServerError, gin.H{"error": "Failed to update order status"})
 		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to place an order"})
 		return
 	}
 	c.JSON(http.StatusOK, order)
 }
+
+// orderOwner returns the customer whose orders the caller may read, see customerID, unless it
+// was granted the orders:admin scope. It is empty for callers that may read every order:
+// back-office clients and API keys bound to no customer.
+func orderOwner(c *gin.Context) string {
+	if middlewares.HasScope(c, middlewares.ScopeOrdersAdmin) {
+		return ""
+	}
+	return customerID(c)
+}
+
+// parseOrderFilter builds an order filter from the query string.
+func parseOrderFilter(c *gin.Context) (models.OrderFilter, error) {
+	var filter models.OrderFilter
+	if from := c.Query("from"); from != "" {
+		t, err := time.Parse(time.RFC3339, from)
+		if err != nil {
+			return filter, err
+		}
+		filter.From = t.Unix()
+	}
+	if to := c.Query("to"); to != "" {
+		t, err := time.Parse(time.RFC3339, to)
+		if err != nil {
+			return filter, err
+		}
+		filter.To = t.Unix()
+	}
+	if limit := c.Query("limit"); limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil {
+			return filter, err
+		}
+		filter.Limit = n
+	}
+	if offset := c.Query("offset"); offset != "" {
+		n, err := strconv.Atoi(offset)
+		if err != nil {
+			return filter, err
+		}
+		filter.Offset = n
+	}
+	filter.Status = models.OrderStatus(strings.TrimSpace(c.Query("status")))
+	return filter, nil
+}
@@ -3,16 +3,23 @@ package handlers
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/mock/gomock"
 
 	"errors"
+	loggermocks "library/logger/mocks"
+	"orderfoodonline/internal/config"
+	"orderfoodonline/internal/http/middlewares"
+	middlewaremocks "orderfoodonline/internal/http/middlewares/mocks"
 	"orderfoodonline/internal/repository/models"
 	"orderfoodonline/internal/service"
 	servicemocks "orderfoodonline/internal/service/mocks"
@@ -132,6 +139,191 @@ func TestOrderHandler_PlaceOrder_InternalServerError(t *testing.T) {
 	assert.Contains(t, w.Body.String(), "Failed to place an order")
 }
 
+func TestOrderHandler_PlaceOrder_StaleCart(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockService := servicemocks.NewMockOrderService(ctrl)
+	h := NewOrderHandler(mockService)
+
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	body, _ := json.Marshal(models.OrderCreateRequest{Items: []models.OrderItem{{ProductID: "p1", Quantity: 1}}})
+	c.Request, _ = http.NewRequest("POST", "/order", bytes.NewBuffer(body))
+	c.Request.Header.Set("Content-Type", "application/json")
+
+	expected := models.Money(1299)
+	staleErr := &service.StaleCartError{Lines: []models.StaleOrderLine{
+		{ProductID: "p1", ExpectedPrice: &expected, CurrentPrice: 1349, CurrentVersion: 2},
+	}}
+	mockService.EXPECT().PlaceOrder(gomock.Any(), gomock.Any()).Return(nil, staleErr)
+
+	h.PlaceOrder(c)
+	assert.Equal(t, http.StatusConflict, w.Code)
+	var resp models.StaleCartResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, "Cart is out of date", resp.Error)
+	assert.Equal(t, staleErr.Lines, resp.ChangedLines)
+}
+
+func TestOrderHandler_PlaceOrder_ProductUnavailable(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockService := servicemocks.NewMockOrderService(ctrl)
+	h := NewOrderHandler(mockService)
+
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	body, _ := json.Marshal(models.OrderCreateRequest{Items: []models.OrderItem{{ProductID: "p1", Quantity: 1}}})
+	c.Request, _ = http.NewRequest("POST", "/order", bytes.NewBuffer(body))
+	c.Request.Header.Set("Content-Type", "application/json")
+
+	mockService.EXPECT().PlaceOrder(gomock.Any(), gomock.Any()).Return(nil, errors.New(service.ProductUnavailable+"p1"))
+
+	h.PlaceOrder(c)
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	var resp models.ProductUnavailableResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, models.ProductUnavailableResponse{
+		Error: "Product unavailable", Code: models.ProductUnavailableCode, ProductID: "p1",
+	}, resp)
+}
+
+func TestOrderHandler_PlaceOrder_OutOfStock(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockService := servicemocks.NewMockOrderService(ctrl)
+	h := NewOrderHandler(mockService)
+
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	body, _ := json.Marshal(models.OrderCreateRequest{Items: []models.OrderItem{{ProductID: "p1", Quantity: 9}}})
+	c.Request, _ = http.NewRequest("POST", "/order", bytes.NewBuffer(body))
+	c.Request.Header.Set("Content-Type", "application/json")
+
+	mockService.EXPECT().PlaceOrder(gomock.Any(), gomock.Any()).Return(nil, &service.OutOfStockError{ProductIDs: []string{"p1"}})
+
+	h.PlaceOrder(c)
+	assert.Equal(t, http.StatusConflict, w.Code)
+	var resp models.OutOfStockResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, models.OutOfStockResponse{Error: "Out of stock", ProductIDs: []string{"p1"}}, resp)
+}
+
+func TestOrderHandler_PlaceOrder_RequestID(t *testing.T) {
+	tests := []struct {
+		name         string
+		err          error
+		expectedCode int
+	}{
+		{name: "error message", err: errors.New("database unavailable"), expectedCode: http.StatusInternalServerError},
+		{name: "error response model", err: &service.OutOfStockError{ProductIDs: []string{"p1"}}, expectedCode: http.StatusConflict},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given: A router identifying requests in front of the order handler
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			mockService := servicemocks.NewMockOrderService(ctrl)
+			h := NewOrderHandler(mockService)
+			gin.SetMode(gin.TestMode)
+			router := gin.New()
+			router.POST("/order", middlewares.RequestIDHandler(), h.PlaceOrder)
+
+			mockService.EXPECT().PlaceOrder(gomock.Any(), gomock.Any()).Return(nil, tt.err)
+
+			// When: Placing an order that fails
+			body, _ := json.Marshal(models.OrderCreateRequest{Items: []models.OrderItem{{ProductID: "p1", Quantity: 1}}})
+			req, _ := http.NewRequest("POST", "/order", bytes.NewBuffer(body))
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set(middlewares.RequestIDHeader, "req-42")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			// Then: The error body should carry the request's ID
+			assert.Equal(t, tt.expectedCode, w.Code)
+			assert.Equal(t, "req-42", w.Header().Get(middlewares.RequestIDHeader))
+			var resp map[string]interface{}
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+			assert.NotEmpty(t, resp["error"])
+			assert.Equal(t, "req-42", resp["requestId"])
+		})
+	}
+}
+
+func TestOrderHandler_PlaceOrder_CouponRejected(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockService := servicemocks.NewMockOrderService(ctrl)
+	h := NewOrderHandler(mockService)
+
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	body, _ := json.Marshal(models.OrderCreateRequest{CouponCode: "HAPPYHRS", Items: []models.OrderItem{{ProductID: "p1", Quantity: 1}}})
+	c.Request, _ = http.NewRequest("POST", "/order", bytes.NewBuffer(body))
+	c.Request.Header.Set("Content-Type", "application/json")
+	authenticateCustomer(t, ctrl, c, "cust-1")
+
+	mockService.EXPECT().PlaceOrder(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, req *models.OrderCreateRequest) (*models.Order, error) {
+			assert.Equal(t, "cust-1", req.CustomerID)
+			return nil, &service.CouponRejectedError{Reason: models.CouponReasonAlreadyRedeemed}
+		})
+
+	h.PlaceOrder(c)
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.JSONEq(t, `{"error":"Coupon not applicable","code":"already_redeemed"}`, w.Body.String())
+}
+
+func TestOrderHandler_PlaceOrder_BearerTokenCustomer(t *testing.T) {
+	// Given: A router authenticating bearer tokens in front of the order handler
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockService := servicemocks.NewMockOrderService(ctrl)
+	mockLogger := loggermocks.NewMockILogger(ctrl)
+	h := NewOrderHandler(mockService)
+
+	verifier, err := middlewares.NewJWTVerifier(&config.JWTConfig{
+		Enabled: true, Issuer: "https://auth.example.com", Audience: "orderfoodonline", HMACSecret: "secret",
+	})
+	assert.NoError(t, err)
+	auth := middlewares.NewAuthMiddleware(middlewares.NewStaticKeyStore(nil), verifier, mockLogger)
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/order", auth.Authenticate(), h.PlaceOrder)
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"sub": "cust-42",
+		"iss": "https://auth.example.com",
+		"aud": "orderfoodonline",
+		"exp": time.Now().Add(time.Hour).Unix(),
+	}).SignedString([]byte("secret"))
+	assert.NoError(t, err)
+	body, _ := json.Marshal(models.OrderCreateRequest{Items: []models.OrderItem{{ProductID: "p1", Quantity: 1}}})
+	req, _ := http.NewRequest("POST", "/order", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	mockService.EXPECT().PlaceOrder(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, req *models.OrderCreateRequest) (*models.Order, error) {
+			return &models.Order{ID: "o1", CustomerID: req.CustomerID}, nil
+		})
+
+	// When: Placing an order with the token
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// Then: The order should be recorded for the token's subject
+	assert.Equal(t, http.StatusOK, w.Code)
+	var order models.Order
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &order))
+	assert.Equal(t, "cust-42", order.CustomerID)
+}
+
 func TestOrderHandler_PlaceOrder_Success(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -155,3 +347,236 @@ func TestOrderHandler_PlaceOrder_Success(t *testing.T) {
 	json.Unmarshal(w.Body.Bytes(), &resp)
 	assert.Equal(t, expectedOrder.ID, resp.ID)
 }
+
+func TestOrderHandler_GetOrderByID(t *testing.T) {
+	tests := []struct {
+		name         string
+		orderID      string
+		order        *models.Order
+		err          error
+		callsService bool
+		expectedCode int
+		expectedBody string
+	}{
+		{"blank id", " ", nil, nil, false, http.StatusBadRequest, "Invalid ID supplied"},
+		{"not found", "missing", nil, nil, true, http.StatusNotFound, "Order not found"},
+		{"service error", "order1", nil, errors.New(service.FindOrderByIDError), true, http.StatusInternalServerError, "Failed to fetch order"},
+		{"success", "order1", &models.Order{ID: "order1"}, nil, true, http.StatusOK, `"id":"order1"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			mockService := servicemocks.NewMockOrderService(ctrl)
+			h := NewOrderHandler(mockService)
+
+			gin.SetMode(gin.TestMode)
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request, _ = http.NewRequest("GET", "/api/order/"+tt.orderID, nil)
+			c.Params = gin.Params{{Key: "orderId", Value: tt.orderID}}
+
+			if tt.callsService {
+				mockService.EXPECT().GetOrder(gomock.Any(), tt.orderID, "").Return(tt.order, tt.err)
+			}
+
+			h.GetOrderByID(c)
+			assert.Equal(t, tt.expectedCode, w.Code)
+			assert.Contains(t, w.Body.String(), tt.expectedBody)
+		})
+	}
+}
+
+func TestOrderHandler_ListOrders(t *testing.T) {
+	tests := []struct {
+		name         string
+		query        string
+		filter       *models.OrderFilter
+		err          error
+		expectedCode int
+		expectedBody string
+	}{
+		{
+			name:         "parses filter",
+			query:        "?from=2024-06-01T00:00:00Z&to=2024-06-02T00:00:00Z&status=placed&limit=5&offset=10",
+			filter:       &models.OrderFilter{From: 1717200000, To: 1717286400, Status: models.OrderStatusPlaced, Limit: 5, Offset: 10},
+			expectedCode: http.StatusOK,
+			expectedBody: `"total":1`,
+		},
+		{
+			name:         "invalid time",
+			query:        "?from=yesterday",
+			expectedCode: http.StatusBadRequest,
+			expectedBody: "Invalid input",
+		},
+		{
+			name:         "invalid limit",
+			query:        "?limit=ten",
+			expectedCode: http.StatusBadRequest,
+			expectedBody: "Invalid input",
+		},
+		{
+			name:         "invalid filter rejected by service",
+			query:        "?status=unknown",
+			filter:       &models.OrderFilter{Status: "unknown"},
+			err:          errors.New(service.InvalidOrderFilter),
+			expectedCode: http.StatusBadRequest,
+			expectedBody: "Invalid input",
+		},
+		{
+			name:         "service error",
+			filter:       &models.OrderFilter{},
+			err:          errors.New(service.OrderListingError),
+			expectedCode: http.StatusInternalServerError,
+			expectedBody: "Failed to fetch orders",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			mockService := servicemocks.NewMockOrderService(ctrl)
+			h := NewOrderHandler(mockService)
+
+			gin.SetMode(gin.TestMode)
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request, _ = http.NewRequest("GET", "/api/order"+tt.query, nil)
+
+			if tt.filter != nil {
+				var resp *models.OrderListResponse
+				if tt.err == nil {
+					resp = &models.OrderListResponse{Orders: []models.Order{{ID: "order1"}}, Total: 1, Limit: tt.filter.Limit, Offset: tt.filter.Offset}
+				}
+				mockService.EXPECT().ListOrders(gomock.Any(), *tt.filter).Return(resp, tt.err)
+			}
+
+			h.ListOrders(c)
+			assert.Equal(t, tt.expectedCode, w.Code)
+			assert.Contains(t, w.Body.String(), tt.expectedBody)
+		})
+	}
+}
+
+func TestOrderHandler_UpdateOrderStatus(t *testing.T) {
+	tests := []struct {
+		name         string
+		body         string
+		err          error
+		callsService bool
+		expectedCode int
+		expectedBody string
+	}{
+		{"invalid json", "not-json", nil, false, http.StatusBadRequest, "Invalid input"},
+		{"invalid status", `{"status":"lost"}`, errors.New(service.InvalidOrderStatus), true, http.StatusUnprocessableEntity, "Validation exception"},
+		{"not found", `{"status":"confirmed"}`, errors.New(service.OrderNotFound), true, http.StatusNotFound, "Order not found"},
+		{"invalid transition", `{"status":"delivered"}`, errors.New(service.InvalidStatusTransition), true, http.StatusConflict, "Invalid status transition"},
+		{"service error", `{"status":"confirmed"}`, errors.New(service.UpdateOrderStatusError), true, http.StatusInternalServerError, "Failed to update order status"},
+		{"success", `{"status":"confirmed","reason":"ok"}`, nil, true, http.StatusOK, `"status":"confirmed"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			mockService := servicemocks.NewMockOrderService(ctrl)
+			h := NewOrderHandler(mockService)
+
+			gin.SetMode(gin.TestMode)
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request, _ = http.NewRequest("PATCH", "/api/order/order1/status", bytes.NewBufferString(tt.body))
+			c.Request.Header.Set("Content-Type", "application/json")
+			c.Params = gin.Params{{Key: "orderId", Value: "order1"}}
+
+			if tt.callsService {
+				var order *models.Order
+				if tt.err == nil {
+					order = &models.Order{ID: "order1", Status: models.OrderStatusConfirmed}
+				}
+				mockService.EXPECT().UpdateOrderStatus(gomock.Any(), "order1", gomock.Any()).Return(order, tt.err)
+			}
+
+			h.UpdateOrderStatus(c)
+			assert.Equal(t, tt.expectedCode, w.Code)
+			assert.Contains(t, w.Body.String(), tt.expectedBody)
+		})
+	}
+}
+
+func TestOrderHandler_ReadOrders_BearerToken(t *testing.T) {
+	tests := []struct {
+		name     string
+		scopes   []string
+		customer string
+	}{
+		{"customer sees own orders", []string{middlewares.ScopeOrder}, "cust-42"},
+		{"orders admin sees every order", []string{middlewares.ScopeOrder, middlewares.ScopeOrdersAdmin}, ""},
+		{"admin role sees every order", []string{middlewares.ScopeAdmin}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given: A router authenticating bearer tokens issued to cust-42 in front of the order handler
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			mockService := servicemocks.NewMockOrderService(ctrl)
+			verifier := middlewaremocks.NewMockTokenVerifier(ctrl)
+			verifier.EXPECT().VerifyToken("token").Return(&middlewares.TokenClaims{Subject: "cust-42", Scopes: tt.scopes}, nil).Times(2)
+			auth := middlewares.NewAuthMiddleware(middlewares.NewStaticKeyStore(nil), verifier, loggermocks.NewMockILogger(ctrl))
+			h := NewOrderHandler(mockService)
+			gin.SetMode(gin.TestMode)
+			router := gin.New()
+			router.GET("/order", auth.Authenticate(), h.ListOrders)
+			router.GET("/order/:orderId", auth.Authenticate(), h.GetOrderByID)
+
+			mockService.EXPECT().ListOrders(gomock.Any(), models.OrderFilter{CustomerID: tt.customer}).Return(&models.OrderListResponse{}, nil)
+			mockService.EXPECT().GetOrder(gomock.Any(), "order1", tt.customer).Return(nil, nil)
+
+			// When: Listing orders and fetching one with the token
+			for _, path := range []string{"/order", "/order/order1"} {
+				req, _ := http.NewRequest("GET", path, nil)
+				req.Header.Set("Authorization", "Bearer token")
+				router.ServeHTTP(httptest.NewRecorder(), req)
+			}
+
+			// Then: The service should be asked only for the orders the caller may read
+		})
+	}
+}
+
+func TestOrderHandler_ReadOrders_CustomerAPIKey(t *testing.T) {
+	// Given: An API key bound to cust-1 and an order placed by cust-2
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockService := servicemocks.NewMockOrderService(ctrl)
+	h := NewOrderHandler(mockService)
+	gin.SetMode(gin.TestMode)
+
+	mockService.EXPECT().GetOrder(gomock.Any(), "order-2", "cust-1").Return(nil, nil)
+	mockService.EXPECT().ListOrders(gomock.Any(), models.OrderFilter{CustomerID: "cust-1"}).Return(&models.OrderListResponse{Orders: []models.Order{}}, nil)
+
+	// When: Fetching the other customer's order with the key
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest("GET", "/api/order/order-2", nil)
+	c.Params = gin.Params{{Key: "orderId", Value: "order-2"}}
+	authenticateCustomer(t, ctrl, c, "cust-1")
+	h.GetOrderByID(c)
+
+	// Then: It should be reported as missing
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	// When: Listing orders with the key
+	w = httptest.NewRecorder()
+	c, _ = gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest("GET", "/api/order", nil)
+	authenticateCustomer(t, ctrl, c, "cust-1")
+	h.ListOrders(c)
+
+	// Then: Only the orders of the key's customer should be asked for
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NotContains(t, w.Body.String(), "order-2")
+}
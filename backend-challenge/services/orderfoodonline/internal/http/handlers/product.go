@@ -2,7 +2,10 @@ package handlers
 
 import (
 	"net/http"
+	"orderfoodonline/internal/http/middlewares"
+	"orderfoodonline/internal/repository/models"
 	"orderfoodonline/internal/service"
+	"strconv"
 	"strings"
 
 	"github.com/gin-gonic/gin"
@@ -19,19 +22,42 @@ func NewProductHandler(service service.ProductService) ProductHandler {
 
 // ListProducts godoc
 // @Summary List products
-// @Description Get all products
+// @Description Get a page of products, optionally filtered by category, price range, dietary tags, allergens and name search.
+// @Description Products that cannot be ordered right now are included, with available set to false.
+// @Description The total number of matching products is returned in the X-Total-Count header.
 // @Tags product
 // @Produce json
-// @Success 200 {array} service.ProductResponse
+// @Param limit query int false "Page size (default 20, max 100)"
+// @Param after query string false "Cursor returned as next_cursor by the previous page"
+// @Param category query string false "Only products in this category"
+// @Param min_price query number false "Only products priced at or above this amount"
+// @Param max_price query number false "Only products priced at or below this amount"
+// @Param sort query string false "Sort order: name, -name, price or -price (default by ID)"
+// @Param search query string false "Case-insensitive search on the product name"
+// @Param dietary query string false "Comma-separated dietary tags the products must all have, e.g. vegan,gluten-free"
+// @Param exclude_allergens query string false "Comma-separated allergens the products must not contain, e.g. nuts,dairy"
+// @Success 200 {object} models.ProductListResponse
+// @Header 200 {integer} X-Total-Count "Total number of matching products"
+// @Failure 400 {object} map[string]string "error":"Invalid input"
 // @Failure 500 {object} map[string]string "error":"Failed to fetch products"
 // @Router /api/product [get]
 func (h *productHandler) ListProducts(c *gin.Context) {
-	products, err := h.service.ListProducts(c)
+	filter, err := parseProductFilter(c)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch products"})
+		middlewares.ErrorResponse(c, http.StatusBadRequest, gin.H{"error": "Invalid input"})
 		return
 	}
-	c.JSON(http.StatusOK, products)
+	page, err := h.service.ListProducts(c, filter)
+	if err != nil {
+		if err.Error() == service.InvalidProductFilter {
+			middlewares.ErrorResponse(c, http.StatusBadRequest, gin.H{"error": "Invalid input"})
+			return
+		}
+		middlewares.ErrorResponse(c, http.StatusInternalServerError, gin.H{"error": "Failed to fetch products"})
+		return
+	}
+	c.Header("X-Total-Count", strconv.FormatInt(page.Total, 10))
+	c.JSON(http.StatusOK, page)
 }
 
 // GetProductByID godoc
@@ -48,17 +74,67 @@ func (h *productHandler) ListProducts(c *gin.Context) {
 func (h *productHandler) GetProductByID(c *gin.Context) {
 	id := strings.TrimSpace(c.Param("productId"))
 	if id == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID supplied"})
+		middlewares.ErrorResponse(c, http.StatusBadRequest, gin.H{"error": "Invalid ID supplied"})
 		return
 	}
 	product, err := h.service.FindProductByID(c, id)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch product"})
+		middlewares.ErrorResponse(c, http.StatusInternalServerError, gin.H{"error": "Failed to fetch product"})
 		return
 	}
 	if product == nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Product not found"})
+		middlewares.ErrorResponse(c, http.StatusNotFound, gin.H{"error": "Product not found"})
 		return
 	}
 	c.JSON(http.StatusOK, product)
 }
+
+// parseProductFilter builds a product filter from the query string.
+func parseProductFilter(c *gin.Context) (models.ProductFilter, error) {
+	var filter models.ProductFilter
+	if limit := c.Query("limit"); limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil {
+			return filter, err
+		}
+		filter.Limit = n
+	}
+	if after := c.Query("after"); after != "" {
+		cursor, err := models.DecodeProductCursor(after)
+		if err != nil {
+			return filter, err
+		}
+		filter.After = cursor
+	}
+	if minPrice := c.Query("min_price"); minPrice != "" {
+		f, err := strconv.ParseFloat(minPrice, 64)
+		if err != nil {
+			return filter, err
+		}
+		filter.MinPrice = f
+	}
+	if maxPrice := c.Query("max_price"); maxPrice != "" {
+		f, err := strconv.ParseFloat(maxPrice, 64)
+		if err != nil {
+			return filter, err
+		}
+		filter.MaxPrice = f
+	}
+	filter.CategoryID = strings.TrimSpace(c.Query("category"))
+	filter.Sort = strings.TrimSpace(c.Query("sort"))
+	filter.Search = strings.TrimSpace(c.Query("search"))
+	filter.DietaryTags = splitQueryList(c.Query("dietary"))
+	filter.ExcludeAllergens = splitQueryList(c.Query("exclude_allergens"))
+	return filter, nil
+}
+
+// splitQueryList splits a comma-separated query parameter, dropping empty entries.
+func splitQueryList(value string) []string {
+	var values []string
+	for _, v := range strings.Split(value, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+	return values
+}
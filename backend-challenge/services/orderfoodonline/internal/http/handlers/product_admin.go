@@ -0,0 +1,153 @@
+package handlers
+
+import (
+	"net/http"
+	"orderfoodonline/internal/http/middlewares"
+	"orderfoodonline/internal/repository/models"
+	"orderfoodonline/internal/service"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+type productAdminHandler struct {
+	service service.ProductService
+}
+
+// NewProductAdminHandler creates a new ProductAdminHandler.
+func NewProductAdminHandler(service service.ProductService) ProductAdminHandler {
+	return &productAdminHandler{service: service}
+}
+
+// CreateProduct godoc
+// @Summary Create a product
+// @Description Add a new product to the catalog (admin only)
+// @Tags product
+// @Accept json
+// @Produce json
+// @Param product body models.ProductRequest true "Product"
+// @Success 201 {object} models.Product
+// @Failure 400 {object} map[string]string "error":"Invalid input"
+// @Failure 403 {object} models.ForbiddenResponse "Caller not granted the catalog:admin scope"
+// @Failure 409 {object} map[string]string "error":"Product already exists"
+// @Failure 422 {object} map[string]string "error":"Validation exception"
+// @Failure 500 {object} map[string]string "error":"Failed to save product"
+// @Router /api/product [post]
+func (h *productAdminHandler) CreateProduct(c *gin.Context) {
+	var req models.ProductRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middlewares.ErrorResponse(c, http.StatusBadRequest, gin.H{"error": "Invalid input"})
+		return
+	}
+	product, err := h.service.CreateProduct(c.Request.Context(), &req)
+	if err != nil {
+		writeProductAdminError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, product)
+}
+
+// ReplaceProduct godoc
+// @Summary Replace a product
+// @Description Overwrite the name, price, category, description, pictures, allergens, dietary tags and availability of a product (admin only).
+// @Description Fields missing from the body are cleared, except availability which defaults to true.
+// @Description The category ID must be that of an active category, and allergens and dietary tags must be known ones.
+// @Tags product
+// @Accept json
+// @Produce json
+// @Param productId path string true "Product ID"
+// @Param product body models.ProductRequest true "Product"
+// @Success 200 {object} models.Product
+// @Failure 400 {object} map[string]string "error":"Invalid input"
+// @Failure 403 {object} models.ForbiddenResponse "Caller not granted the catalog:admin scope"
+// @Failure 404 {object} map[string]string "error":"Product not found"
+// @Failure 422 {object} map[string]string "error":"Validation exception"
+// @Failure 500 {object} map[string]string "error":"Failed to save product"
+// @Router /api/product/{productId} [put]
+func (h *productAdminHandler) ReplaceProduct(c *gin.Context) {
+	id := strings.TrimSpace(c.Param("productId"))
+	var req models.ProductRequest
+	if id == "" || c.ShouldBindJSON(&req) != nil {
+		middlewares.ErrorResponse(c, http.StatusBadRequest, gin.H{"error": "Invalid input"})
+		return
+	}
+	product, err := h.service.ReplaceProduct(c.Request.Context(), id, &req)
+	if err != nil {
+		writeProductAdminError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, product)
+}
+
+// PatchProduct godoc
+// @Summary Update a product
+// @Description Change some of the name, price, category, description, pictures, allergens, dietary tags and availability of a product (admin only).
+// @Description The category ID must be that of an active category, and allergens and dietary tags must be known ones.
+// @Tags product
+// @Accept json
+// @Produce json
+// @Param productId path string true "Product ID"
+// @Param product body models.ProductPatchRequest true "Fields to change"
+// @Success 200 {object} models.Product
+// @Failure 400 {object} map[string]string "error":"Invalid input"
+// @Failure 403 {object} models.ForbiddenResponse "Caller not granted the catalog:admin scope"
+// @Failure 404 {object} map[string]string "error":"Product not found"
+// @Failure 422 {object} map[string]string "error":"Validation exception"
+// @Failure 500 {object} map[string]string "error":"Failed to save product"
+// @Router /api/product/{productId} [patch]
+func (h *productAdminHandler) PatchProduct(c *gin.Context) {
+	id := strings.TrimSpace(c.Param("productId"))
+	var req models.ProductPatchRequest
+	if id == "" || c.ShouldBindJSON(&req) != nil {
+		middlewares.ErrorResponse(c, http.StatusBadRequest, gin.H{"error": "Invalid input"})
+		return
+	}
+	product, err := h.service.PatchProduct(c.Request.Context(), id, &req)
+	if err != nil {
+		writeProductAdminError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, product)
+}
+
+// DeleteProduct godoc
+// @Summary Delete a product
+// @Description Remove a product from the catalog (admin only). Orders that reference it are kept intact.
+// @Tags product
+// @Param productId path string true "Product ID"
+// @Success 204
+// @Failure 400 {object} map[string]string "error":"Invalid ID supplied"
+// @Failure 403 {object} models.ForbiddenResponse "Caller not granted the catalog:admin scope"
+// @Failure 404 {object} map[string]string "error":"Product not found"
+// @Failure 500 {object} map[string]string "error":"Failed to delete product"
+// @Router /api/product/{productId} [delete]
+func (h *productAdminHandler) DeleteProduct(c *gin.Context) {
+	id := strings.TrimSpace(c.Param("productId"))
+	if id == "" {
+		middlewares.ErrorResponse(c, http.StatusBadRequest, gin.H{"error": "Invalid ID supplied"})
+		return
+	}
+	if err := h.service.DeleteProduct(c.Request.Context(), id); err != nil {
+		if strings.Contains(err.Error(), service.ProductNotFound) {
+			middlewares.ErrorResponse(c, http.StatusNotFound, gin.H{"error": "Product not found"})
+			return
+		}
+		middlewares.ErrorResponse(c, http.StatusInternalServerError, gin.H{"error": "Failed to delete product"})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// writeProductAdminError maps a product service error to its HTTP response.
+func writeProductAdminError(c *gin.Context, err error) {
+	switch {
+	case err.Error() == service.InvalidProductDetails:
+		middlewares.ErrorResponse(c, http.StatusUnprocessableEntity, gin.H{"error": "Validation exception"})
+	case err.Error() == service.ProductAlreadyExists:
+		middlewares.ErrorResponse(c, http.StatusConflict, gin.H{"error": "Product already exists"})
+	case strings.Contains(err.Error(), service.ProductNotFound):
+		middlewares.ErrorResponse(c, http.StatusNotFound, gin.H{"error": "Product not found"})
+	default:
+		middlewares.ErrorResponse(c, http.StatusInternalServerError, gin.H{"error": "Failed to save product"})
+	}
+}
@@ -0,0 +1,186 @@
+// Package handlers provides HTTP handlers for the Order Food Online service.
+package handlers
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	"orderfoodonline/internal/repository/models"
+	"orderfoodonline/internal/service"
+	servicemocks "orderfoodonline/internal/service/mocks"
+)
+
+func TestProductAdminHandler_CreateProduct(t *testing.T) {
+	tests := []struct {
+		name         string
+		body         string
+		mockSetup    func(m *servicemocks.MockProductService)
+		expectedCode int
+		expectedBody string
+	}{
+		{
+			name:         "invalid JSON",
+			body:         "not-json",
+			mockSetup:    func(m *servicemocks.MockProductService) {},
+			expectedCode: http.StatusBadRequest,
+			expectedBody: "Invalid input",
+		},
+		{
+			name: "created",
+			body: `{"name":"Margherita Pizza","price":12.99,"categoryId":"pizza"}`,
+			mockSetup: func(m *servicemocks.MockProductService) {
+				m.EXPECT().CreateProduct(gomock.Any(), &models.ProductRequest{Name: "Margherita Pizza", Price: 12.99, CategoryID: "pizza"}).
+					Return(&models.Product{ID: "p1", Name: "Margherita Pizza", Price: 12.99, Category: "Pizza", Version: 1}, nil)
+			},
+			expectedCode: http.StatusCreated,
+			expectedBody: `"id":"p1"`,
+		},
+		{
+			name: "validation error",
+			body: `{"name":"","price":12.99,"categoryId":"pizza"}`,
+			mockSetup: func(m *servicemocks.MockProductService) {
+				m.EXPECT().CreateProduct(gomock.Any(), gomock.Any()).Return(nil, errors.New(service.InvalidProductDetails))
+			},
+			expectedCode: http.StatusUnprocessableEntity,
+			expectedBody: "Validation exception",
+		},
+		{
+			name: "duplicate ID",
+			body: `{"id":"10","name":"Margherita Pizza","price":12.99,"categoryId":"pizza"}`,
+			mockSetup: func(m *servicemocks.MockProductService) {
+				m.EXPECT().CreateProduct(gomock.Any(), gomock.Any()).Return(nil, errors.New(service.ProductAlreadyExists))
+			},
+			expectedCode: http.StatusConflict,
+			expectedBody: "Product already exists",
+		},
+		{
+			name: "service error",
+			body: `{"name":"Margherita Pizza","price":12.99,"categoryId":"pizza"}`,
+			mockSetup: func(m *servicemocks.MockProductService) {
+				m.EXPECT().CreateProduct(gomock.Any(), gomock.Any()).Return(nil, errors.New(service.SaveProductError))
+			},
+			expectedCode: http.StatusInternalServerError,
+			expectedBody: "Failed to save product",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			mockService := servicemocks.NewMockProductService(ctrl)
+			tt.mockSetup(mockService)
+			h := NewProductAdminHandler(mockService)
+
+			gin.SetMode(gin.TestMode)
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request, _ = http.NewRequest("POST", "/api/product", bytes.NewBufferString(tt.body))
+			c.Request.Header.Set("Content-Type", "application/json")
+
+			h.CreateProduct(c)
+			assert.Equal(t, tt.expectedCode, w.Code)
+			assert.Contains(t, w.Body.String(), tt.expectedBody)
+		})
+	}
+}
+
+func TestProductAdminHandler_ReplaceAndPatchProduct(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockService := servicemocks.NewMockProductService(ctrl)
+	h := NewProductAdminHandler(mockService)
+
+	updated := &models.Product{ID: "10", Name: "Margherita Pizza", Price: 13.49, Category: "Pizza", Version: 2}
+	mockService.EXPECT().ReplaceProduct(gomock.Any(), "10", gomock.Any()).Return(updated, nil)
+	mockService.EXPECT().PatchProduct(gomock.Any(), "99", gomock.Any()).Return(nil, errors.New(service.ProductNotFound+"99"))
+
+	gin.SetMode(gin.TestMode)
+
+	// PUT an existing product
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Params = gin.Params{{Key: "productId", Value: "10"}}
+	c.Request, _ = http.NewRequest("PUT", "/api/product/10",
+		bytes.NewBufferString(`{"name":"Margherita Pizza","price":13.49,"categoryId":"pizza"}`))
+	c.Request.Header.Set("Content-Type", "application/json")
+	h.ReplaceProduct(c)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"version":2`)
+
+	// PATCH a missing product
+	w = httptest.NewRecorder()
+	c, _ = gin.CreateTestContext(w)
+	c.Params = gin.Params{{Key: "productId", Value: "99"}}
+	c.Request, _ = http.NewRequest("PATCH", "/api/product/99", bytes.NewBufferString(`{"price":13.49}`))
+	c.Request.Header.Set("Content-Type", "application/json")
+	h.PatchProduct(c)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Contains(t, w.Body.String(), "Product not found")
+}
+
+func TestProductAdminHandler_DeleteProduct(t *testing.T) {
+	tests := []struct {
+		name         string
+		id           string
+		mockSetup    func(m *servicemocks.MockProductService)
+		expectedCode int
+	}{
+		{
+			name:         "empty ID",
+			id:           " ",
+			mockSetup:    func(m *servicemocks.MockProductService) {},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name: "deleted",
+			id:   "10",
+			mockSetup: func(m *servicemocks.MockProductService) {
+				m.EXPECT().DeleteProduct(gomock.Any(), "10").Return(nil)
+			},
+			expectedCode: http.StatusNoContent,
+		},
+		{
+			name: "not found",
+			id:   "99",
+			mockSetup: func(m *servicemocks.MockProductService) {
+				m.EXPECT().DeleteProduct(gomock.Any(), "99").Return(errors.New(service.ProductNotFound + "99"))
+			},
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			name: "service error",
+			id:   "10",
+			mockSetup: func(m *servicemocks.MockProductService) {
+				m.EXPECT().DeleteProduct(gomock.Any(), "10").Return(errors.New(service.DeleteProductError))
+			},
+			expectedCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			mockService := servicemocks.NewMockProductService(ctrl)
+			tt.mockSetup(mockService)
+			h := NewProductAdminHandler(mockService)
+
+			gin.SetMode(gin.TestMode)
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Params = gin.Params{{Key: "productId", Value: tt.id}}
+			c.Request, _ = http.NewRequest("DELETE", "/api/product/"+tt.id, nil)
+
+			h.DeleteProduct(c)
+			assert.Equal(t, tt.expectedCode, c.Writer.Status())
+		})
+	}
+}
@@ -13,6 +13,7 @@ import (
 	"go.uber.org/mock/gomock"
 
 	"orderfoodonline/internal/repository/models"
+	"orderfoodonline/internal/service"
 	servicemocks "orderfoodonline/internal/service/mocks"
 )
 
@@ -27,14 +28,55 @@ func TestProductHandler_ListProducts_Success(t *testing.T) {
 	w := httptest.NewRecorder()
 	c, _ := gin.CreateTestContext(w)
 
+	c.Request, _ = http.NewRequest("GET", "/api/product?category=food&min_price=1.5&max_price=200&sort=-price&search=piz&limit=1&dietary=vegan,+gluten-free&exclude_allergens=nuts", nil)
+
 	expected := []models.Product{{ID: "1", Name: "Pizza", Price: 123, Category: "food"}}
-	mockService.EXPECT().ListProducts(gomock.Any()).Return(expected, nil)
+	filter := models.ProductFilter{CategoryID: "food", MinPrice: 1.5, MaxPrice: 200, Sort: "-price", Search: "piz", Limit: 1,
+		DietaryTags: []string{"vegan", "gluten-free"}, ExcludeAllergens: []string{"nuts"}}
+	mockService.EXPECT().ListProducts(gomock.Any(), filter).
+		Return(&models.ProductListResponse{Products: expected, NextCursor: "next", Total: 7}, nil)
 
 	h.ListProducts(c)
 	assert.Equal(t, http.StatusOK, w.Code)
-	var resp []models.Product
+	assert.Equal(t, "7", w.Header().Get("X-Total-Count"))
+	var resp models.ProductListResponse
 	json.Unmarshal(w.Body.Bytes(), &resp)
-	assert.Equal(t, expected, resp)
+	assert.Equal(t, expected, resp.Products)
+	assert.Equal(t, "next", resp.NextCursor)
+}
+
+func TestProductHandler_ListProducts_InvalidInput(t *testing.T) {
+	tests := []struct {
+		name      string
+		query     string
+		mockSetup func(m *servicemocks.MockProductService)
+	}{
+		{name: "bad limit", query: "limit=abc", mockSetup: func(m *servicemocks.MockProductService) {}},
+		{name: "bad cursor", query: "after=!!", mockSetup: func(m *servicemocks.MockProductService) {}},
+		{name: "bad price", query: "min_price=cheap", mockSetup: func(m *servicemocks.MockProductService) {}},
+		{name: "invalid filter", query: "sort=rating", mockSetup: func(m *servicemocks.MockProductService) {
+			m.EXPECT().ListProducts(gomock.Any(), gomock.Any()).Return(nil, errors.New(service.InvalidProductFilter))
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			mockService := servicemocks.NewMockProductService(ctrl)
+			tt.mockSetup(mockService)
+			h := NewProductHandler(mockService)
+
+			gin.SetMode(gin.TestMode)
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request, _ = http.NewRequest("GET", "/api/product?"+tt.query, nil)
+
+			h.ListProducts(c)
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			assert.Contains(t, w.Body.String(), "Invalid input")
+		})
+	}
 }
 
 func TestProductHandler_ListProducts_Error(t *testing.T) {
@@ -47,7 +89,8 @@ func TestProductHandler_ListProducts_Error(t *testing.T) {
 	w := httptest.NewRecorder()
 	c, _ := gin.CreateTestContext(w)
 
-	mockService.EXPECT().ListProducts(gomock.Any()).Return(nil, errors.New("db error"))
+	c.Request, _ = http.NewRequest("GET", "/api/product", nil)
+	mockService.EXPECT().ListProducts(gomock.Any(), gomock.Any()).Return(nil, errors.New("db error"))
 
 	h.ListProducts(c)
 	assert.Equal(t, http.StatusInternalServerError, w.Code)
@@ -0,0 +1,125 @@
+package handlers
+
+import (
+	"net/http"
+	"orderfoodonline/internal/http/middlewares"
+	"orderfoodonline/internal/repository/models"
+	"orderfoodonline/internal/service"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+type stockHandler struct {
+	service service.StockService
+}
+
+// NewStockHandler creates a new StockHandler.
+func NewStockHandler(service service.StockService) StockHandler {
+	return &stockHandler{service: service}
+}
+
+// GetStock godoc
+// @Summary Get product stock
+// @Description Get the number of units of a product left to order (admin only)
+// @Tags stock
+// @Produce json
+// @Param productId path string true "Product ID"
+// @Success 200 {object} models.Stock
+// @Failure 400 {object} map[string]string "error":"Invalid ID supplied"
+// @Failure 403 {object} models.ForbiddenResponse "Caller not granted the catalog:admin scope"
+// @Failure 404 {object} map[string]string "error":"Product not found, or its stock is not tracked"
+// @Failure 500 {object} map[string]string "error":"Failed to fetch stock"
+// @Router /api/product/{productId}/stock [get]
+func (h *stockHandler) GetStock(c *gin.Context) {
+	id := strings.TrimSpace(c.Param("productId"))
+	if id == "" {
+		middlewares.ErrorResponse(c, http.StatusBadRequest, gin.H{"error": "Invalid ID supplied"})
+		return
+	}
+	stock, err := h.service.GetStock(c.Request.Context(), id)
+	if err != nil {
+		switch {
+		case err.Error() == service.StockNotTracked:
+			middlewares.ErrorResponse(c, http.StatusNotFound, gin.H{"error": "Stock not tracked"})
+		case strings.Contains(err.Error(), service.ProductNotFound):
+			middlewares.ErrorResponse(c, http.StatusNotFound, gin.H{"error": "Product not found"})
+		default:
+			middlewares.ErrorResponse(c, http.StatusInternalServerError, gin.H{"error": "Failed to fetch stock"})
+		}
+		return
+	}
+	c.JSON(http.StatusOK, stock)
+}
+
+// SetStock godoc
+// @Summary Set product stock
+// @Description Overwrite the number of units of a product left to order, e.g. after a stocktake (admin only)
+// @Tags stock
+// @Accept json
+// @Produce json
+// @Param productId path string true "Product ID"
+// @Param stock body models.StockRequest true "New stock level"
+// @Success 200 {object} models.Stock
+// @Failure 400 {object} map[string]string "error":"Invalid input"
+// @Failure 403 {object} models.ForbiddenResponse "Caller not granted the catalog:admin scope"
+// @Failure 404 {object} map[string]string "error":"Product not found"
+// @Failure 422 {object} map[string]string "error":"Validation exception"
+// @Failure 500 {object} map[string]string "error":"Failed to save stock"
+// @Router /api/product/{productId}/stock [put]
+func (h *stockHandler) SetStock(c *gin.Context) {
+	id := strings.TrimSpace(c.Param("productId"))
+	var req models.StockRequest
+	if id == "" || c.ShouldBindJSON(&req) != nil {
+		middlewares.ErrorResponse(c, http.StatusBadRequest, gin.H{"error": "Invalid input"})
+		return
+	}
+	stock, err := h.service.SetStock(c.Request.Context(), id, req.Quantity)
+	if err != nil {
+		writeStockError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, stock)
+}
+
+// Restock godoc
+// @Summary Restock a product
+// @Description Add a delivery of units to the stock of a product (admin only)
+// @Tags stock
+// @Accept json
+// @Produce json
+// @Param productId path string true "Product ID"
+// @Param stock body models.StockRequest true "Units delivered"
+// @Success 200 {object} models.Stock
+// @Failure 400 {object} map[string]string "error":"Invalid input"
+// @Failure 403 {object} models.ForbiddenResponse "Caller not granted the catalog:admin scope"
+// @Failure 404 {object} map[string]string "error":"Product not found"
+// @Failure 422 {object} map[string]string "error":"Validation exception"
+// @Failure 500 {object} map[string]string "error":"Failed to save stock"
+// @Router /api/product/{productId}/restock [post]
+func (h *stockHandler) Restock(c *gin.Context) {
+	id := strings.TrimSpace(c.Param("productId"))
+	var req models.StockRequest
+	if id == "" || c.ShouldBindJSON(&req) != nil {
+		middlewares.ErrorResponse(c, http.StatusBadRequest, gin.H{"error": "Invalid input"})
+		return
+	}
+	stock, err := h.service.Restock(c.Request.Context(), id, req.Quantity)
+	if err != nil {
+		writeStockError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, stock)
+}
+
+// writeStockError maps a stock service error to the matching HTTP response.
+func writeStockError(c *gin.Context, err error) {
+	switch {
+	case err.Error() == service.InvalidStockQuantity:
+		middlewares.ErrorResponse(c, http.StatusUnprocessableEntity, gin.H{"error": "Validation exception"})
+	case strings.Contains(err.Error(), service.ProductNotFound):
+		middlewares.ErrorResponse(c, http.StatusNotFound, gin.H{"error": "Product not found"})
+	default:
+		middlewares.ErrorResponse(c, http.StatusInternalServerError, gin.H{"error": "Failed to save stock"})
+	}
+}
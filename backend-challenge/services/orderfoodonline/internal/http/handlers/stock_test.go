@@ -0,0 +1,134 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	"orderfoodonline/internal/repository/models"
+	"orderfoodonline/internal/service"
+	servicemocks "orderfoodonline/internal/service/mocks"
+)
+
+func TestStockHandler_GetStock(t *testing.T) {
+	tests := []struct {
+		name         string
+		stock        *models.Stock
+		err          error
+		expectedCode int
+	}{
+		{name: "tracked", stock: &models.Stock{ProductID: "10", Quantity: 3}, expectedCode: http.StatusOK},
+		{name: "not tracked", err: errors.New(service.StockNotTracked), expectedCode: http.StatusNotFound},
+		{name: "unknown product", err: errors.New(service.ProductNotFound + "10"), expectedCode: http.StatusNotFound},
+		{name: "service error", err: errors.New(service.FindStockError), expectedCode: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			mockService := servicemocks.NewMockStockService(ctrl)
+			mockService.EXPECT().GetStock(gomock.Any(), "10").Return(tt.stock, tt.err)
+			h := NewStockHandler(mockService)
+
+			gin.SetMode(gin.TestMode)
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request, _ = http.NewRequest("GET", "/api/product/10/stock", nil)
+			c.Params = gin.Params{{Key: "productId", Value: "10"}}
+
+			h.GetStock(c)
+			assert.Equal(t, tt.expectedCode, w.Code)
+			if tt.stock != nil {
+				var resp models.Stock
+				assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+				assert.Equal(t, *tt.stock, resp)
+			}
+		})
+	}
+}
+
+func TestStockHandler_Restock(t *testing.T) {
+	tests := []struct {
+		name         string
+		body         string
+		mockSetup    func(m *servicemocks.MockStockService)
+		expectedCode int
+	}{
+		{
+			name: "restocked",
+			body: `{"quantity":5}`,
+			mockSetup: func(m *servicemocks.MockStockService) {
+				m.EXPECT().Restock(gomock.Any(), "10", int64(5)).Return(&models.Stock{ProductID: "10", Quantity: 7}, nil)
+			},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "invalid JSON",
+			body:         `{"quantity":"five"}`,
+			mockSetup:    func(m *servicemocks.MockStockService) {},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name: "invalid quantity",
+			body: `{"quantity":0}`,
+			mockSetup: func(m *servicemocks.MockStockService) {
+				m.EXPECT().Restock(gomock.Any(), "10", int64(0)).Return(nil, errors.New(service.InvalidStockQuantity))
+			},
+			expectedCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name: "unknown product",
+			body: `{"quantity":5}`,
+			mockSetup: func(m *servicemocks.MockStockService) {
+				m.EXPECT().Restock(gomock.Any(), "10", int64(5)).Return(nil, errors.New(service.ProductNotFound+"10"))
+			},
+			expectedCode: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			mockService := servicemocks.NewMockStockService(ctrl)
+			tt.mockSetup(mockService)
+			h := NewStockHandler(mockService)
+
+			gin.SetMode(gin.TestMode)
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request, _ = http.NewRequest("POST", "/api/product/10/restock", bytes.NewBufferString(tt.body))
+			c.Request.Header.Set("Content-Type", "application/json")
+			c.Params = gin.Params{{Key: "productId", Value: "10"}}
+
+			h.Restock(c)
+			assert.Equal(t, tt.expectedCode, w.Code)
+		})
+	}
+}
+
+func TestStockHandler_SetStock(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockService := servicemocks.NewMockStockService(ctrl)
+	mockService.EXPECT().SetStock(gomock.Any(), "10", int64(0)).Return(&models.Stock{ProductID: "10"}, nil)
+	h := NewStockHandler(mockService)
+
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest("PUT", "/api/product/10/stock", bytes.NewBufferString(`{"quantity":0}`))
+	c.Request.Header.Set("Content-Type", "application/json")
+	c.Params = gin.Params{{Key: "productId", Value: "10"}}
+
+	h.SetStock(c)
+	assert.Equal(t, http.StatusOK, w.Code)
+}
@@ -5,6 +5,7 @@ import (
 	"library/logger"
 	"net/http"
 	"orderfoodonline/internal/config"
+	"orderfoodonline/internal/http/middlewares"
 	"os"
 
 	"github.com/gin-gonic/gin"
@@ -35,5 +36,5 @@ func (s *swaggerHandler) GetSwaggerJSONHandler(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusInternalServerError, gin.H{"error": "Unable to serve Swagger Json"})
+	middlewares.ErrorResponse(c, http.StatusInternalServerError, gin.H{"error": "Unable to serve Swagger Json"})
 }
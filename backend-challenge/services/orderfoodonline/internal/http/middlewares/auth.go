@@ -1,52 +1,240 @@
 package middlewares
 
 import (
+	"errors"
+	"fmt"
 	"library/logger"
 	"net/http"
+	"orderfoodonline/internal/repository/models"
 	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
 )
 
+const (
+	// ScopeCatalogRead grants reading products, categories and coupons.
+	ScopeCatalogRead = "catalog:read"
+	// ScopeCatalogAdmin grants managing products and their stock.
+	ScopeCatalogAdmin = "catalog:admin"
+	// ScopeOrdersRead grants reading orders and carts.
+	ScopeOrdersRead = "orders:read"
+	// ScopeOrdersWrite grants managing carts and placing orders.
+	ScopeOrdersWrite = "orders:write"
+	// ScopeOrdersAdmin grants updating the status of orders.
+	ScopeOrdersAdmin = "orders:admin"
+
+	// ScopeOrder is the role of customer-facing clients, see roleScopes.
+	ScopeOrder = "order"
+	// ScopeAdmin is the role of back-office clients, see roleScopes.
+	ScopeAdmin = "admin"
+
+	// apiKeyContextKey is the gin context key holding the *models.APIKey the caller authenticated with.
+	apiKeyContextKey = "auth.api_key"
+	// claimsContextKey is the gin context key holding the *TokenClaims of the caller's bearer token.
+	claimsContextKey = "auth.claims"
+	// scopesContextKey is the gin context key holding the scopes granted to the caller.
+	scopesContextKey = "auth.scopes"
+)
+
+// roleScopes lists the scopes implied by each role. Keys and tokens may be granted
+// roles, scopes or both.
+var roleScopes = map[string][]string{
+	ScopeOrder: {ScopeCatalogRead, ScopeOrdersRead, ScopeOrdersWrite},
+	ScopeAdmin: {ScopeCatalogAdmin, ScopeOrdersAdmin},
+}
+
 // auth provides authentication and authorization middleware.
 type auth struct {
-	logger logger.ILogger
+	store    KeyStore
+	verifier TokenVerifier
+	logger   logger.ILogger
 }
 
 // NewAuthMiddleware creates a new instance of auth middleware which implements AuthMiddleware.
-func NewAuthMiddleware(logger logger.ILogger) AuthMiddleware {
-	return &auth{logger: logger}
+// API keys are looked up in store, and bearer tokens are verified by verifier.
+// A nil verifier rejects every bearer token.
+func NewAuthMiddleware(store KeyStore, verifier TokenVerifier, logger logger.ILogger) AuthMiddleware {
+	return &auth{store: store, verifier: verifier, logger: logger}
+}
+
+// APIKeyFromContext returns the key the request was authenticated with, or nil before Authenticate has run.
+func APIKeyFromContext(c *gin.Context) *models.APIKey {
+	value, ok := c.Get(apiKeyContextKey)
+	if !ok {
+		return nil
+	}
+	key, _ := value.(*models.APIKey)
+	return key
 }
 
-// Authentication middleware to protect routes
+// ClaimsFromContext returns the claims of the bearer token the request was authenticated with,
+// or nil if it was authenticated with an API key.
+func ClaimsFromContext(c *gin.Context) *TokenClaims {
+	value, ok := c.Get(claimsContextKey)
+	if !ok {
+		return nil
+	}
+	claims, _ := value.(*TokenClaims)
+	return claims
+}
+
+// SubjectFromContext returns the user the request's bearer token was issued to,
+// or "" if it was authenticated with an API key.
+func SubjectFromContext(c *gin.Context) string {
+	if claims := ClaimsFromContext(c); claims != nil {
+		return claims.Subject
+	}
+	return ""
+}
+
+// HasScope reports whether the authenticated caller was granted scope, directly or through a role.
+// It is false before Authenticate has run.
+func HasScope(c *gin.Context, scope string) bool {
+	return hasScope(c.GetStringSlice(scopesContextKey), scope)
+}
+
+// callerIdentity identifies the authenticated caller: "sub:" followed by the subject of its bearer
+// token, or "key:" followed by the name of its API key. It returns "" before Authenticate has run.
+func callerIdentity(c *gin.Context) string {
+	if subject := SubjectFromContext(c); subject != "" {
+		return "sub:" + subject
+	}
+	if apiKey := APIKeyFromContext(c); apiKey != nil {
+		return "key:" + apiKey.Name
+	}
+	return ""
+}
+
+// Authentication middleware to protect routes.
+// Requests carrying an Authorization: Bearer token are authenticated with it, the others with
+// the api_key header. The matched key or token claims are stored in the context, see
+// APIKeyFromContext and ClaimsFromContext.
 func (a *auth) Authenticate() gin.HandlerFunc {
 	return func(c *gin.Context) {
+		if token, ok := bearerToken(c.GetHeader("Authorization")); ok {
+			a.authenticateToken(c, token)
+			return
+		}
+
 		apiKey := strings.TrimSpace(c.GetHeader("api_key"))
 		if apiKey == "" {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "API key required"})
+			ErrorResponse(c, http.StatusUnauthorized, gin.H{"error": "API key required"})
 			c.Abort()
 			return
 		}
 
-		// Example validation (replace with your actual logic)
-		const validAPIKey = "apitest"
-
-		if apiKey != validAPIKey {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid API key"})
+		key, err := a.store.LookupAPIKey(c.Request.Context(), apiKey)
+		if err != nil {
+			a.logger.WithContext(c.Request.Context()).Error("error looking up API key: %v", err)
+			ErrorResponse(c, http.StatusServiceUnavailable, gin.H{"error": "Authentication unavailable"})
+			c.Abort()
+			return
+		}
+		if key == nil {
+			ErrorResponse(c, http.StatusUnauthorized, gin.H{"error": "Invalid API key"})
+			c.Abort()
+			return
+		}
+		if key.Revoked {
+			a.logger.WithContext(c.Request.Context()).Warn("rejected revoked API key %q", key.Name)
+			ErrorResponse(c, http.StatusUnauthorized, gin.H{"error": "API key revoked"})
+			c.Abort()
+			return
+		}
+		if key.Expired(time.Now()) {
+			a.logger.WithContext(c.Request.Context()).Warn("rejected expired API key %q", key.Name)
+			ErrorResponse(c, http.StatusUnauthorized, gin.H{"error": "API key expired"})
 			c.Abort()
 			return
 		}
 
+		c.Set(apiKeyContextKey, key)
+		c.Set(scopesContextKey, key.Scopes)
 		c.Next()
 	}
 }
 
-// Authorize middleware to protect resources from bad access
-func (a *auth) Authorize() gin.HandlerFunc {
+// authenticateToken authenticates the request with a bearer token.
+func (a *auth) authenticateToken(c *gin.Context, token string) {
+	if a.verifier == nil {
+		ErrorResponse(c, http.StatusUnauthorized, gin.H{"error": "Bearer tokens not accepted"})
+		c.Abort()
+		return
+	}
+
+	claims, err := a.verifier.VerifyToken(token)
+	if errors.Is(err, jwt.ErrTokenExpired) {
+		ErrorResponse(c, http.StatusUnauthorized, gin.H{"error": "Token expired"})
+		c.Abort()
+		return
+	}
+	if err != nil {
+		a.logger.WithContext(c.Request.Context()).Debug("rejected bearer token: %v", err)
+		ErrorResponse(c, http.StatusUnauthorized, gin.H{"error": "Invalid token"})
+		c.Abort()
+		return
+	}
+
+	c.Set(claimsContextKey, claims)
+	c.Set(scopesContextKey, claims.Scopes)
+	c.Next()
+}
+
+// bearerToken returns the token of an Authorization header using the Bearer scheme.
+func bearerToken(header string) (string, bool) {
+	scheme, token, ok := strings.Cut(strings.TrimSpace(header), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return "", false
+	}
+	token = strings.TrimSpace(token)
+	return token, token != ""
+}
+
+// Authorize middleware to protect resources from bad access.
+// The request is rejected with 403 and a models.ForbiddenResponse unless the authenticated
+// key or token was granted every one of the given scopes, directly or through a role.
+func (a *auth) Authorize(scopes ...string) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		// logic to verify user access could go here (if necessary)
+		granted := c.GetStringSlice(scopesContextKey)
+		var missing []string
+		for _, scope := range scopes {
+			if !hasScope(granted, scope) {
+				missing = append(missing, scope)
+			}
+		}
+		if len(missing) > 0 {
+			if ClaimsFromContext(c) != nil {
+				c.Header("WWW-Authenticate", fmt.Sprintf(`Bearer error="insufficient_scope", scope="%s"`, strings.Join(scopes, " ")))
+			}
+			c.JSON(http.StatusForbidden, models.ForbiddenResponse{
+				Error:          "Forbidden",
+				Code:           models.InsufficientScopeCode,
+				RequiredScopes: scopes,
+				MissingScopes:  missing,
+				RequestID:      RequestIDFromContext(c),
+			})
+			c.Abort()
+			return
+		}
 
 		// If everything is fine, continue to the next handler
 		c.Next()
 	}
 }
+
+// hasScope reports whether scope is one of the granted scopes or implied by a granted role.
+func hasScope(granted []string, scope string) bool {
+	for _, g := range granted {
+		if g == scope {
+			return true
+		}
+		for _, implied := range roleScopes[g] {
+			if implied == scope {
+				return true
+			}
+		}
+	}
+	return false
+}
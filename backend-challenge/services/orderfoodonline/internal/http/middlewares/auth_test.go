@@ -2,16 +2,27 @@
 package middlewares
 
 import (
+	"context"
+	"errors"
 	"library/logger/mocks"
 	"net/http"
 	"net/http/httptest"
+	"orderfoodonline/internal/config"
+	"orderfoodonline/internal/repository/models"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/mock/gomock"
 )
 
+// testKeyStore returns a key store accepting "apitest" and the admin key "admintest".
+func testKeyStore() KeyStore {
+	return NewStaticKeyStore(&config.AuthConfig{APIKey: "apitest", AdminAPIKey: "admintest"})
+}
+
 func TestNewAuthMiddleware(t *testing.T) {
 	// Given: A logger mock
 	ctrl := gomock.NewController(t)
@@ -19,7 +30,7 @@ func TestNewAuthMiddleware(t *testing.T) {
 	mockLogger := mocks.NewMockILogger(ctrl)
 
 	// When: Creating a new auth middleware
-	authMiddleware := NewAuthMiddleware(mockLogger)
+	authMiddleware := NewAuthMiddleware(testKeyStore(), nil, mockLogger)
 
 	// Then: It should not be nil and implement the interface
 	assert.NotNil(t, authMiddleware)
@@ -31,7 +42,7 @@ func TestAuthMiddleware_Authenticate_ValidAPIKey(t *testing.T) {
 	defer ctrl.Finish()
 	mockLogger := mocks.NewMockILogger(ctrl)
 
-	authMiddleware := NewAuthMiddleware(mockLogger)
+	authMiddleware := NewAuthMiddleware(testKeyStore(), nil, mockLogger)
 	handler := authMiddleware.Authenticate()
 
 	// Set up Gin context
@@ -58,7 +69,7 @@ func TestAuthMiddleware_Authenticate_MissingAPIKey(t *testing.T) {
 	defer ctrl.Finish()
 	mockLogger := mocks.NewMockILogger(ctrl)
 
-	authMiddleware := NewAuthMiddleware(mockLogger)
+	authMiddleware := NewAuthMiddleware(testKeyStore(), nil, mockLogger)
 	handler := authMiddleware.Authenticate()
 
 	// Set up Gin context
@@ -87,7 +98,7 @@ func TestAuthMiddleware_Authenticate_InvalidAPIKey(t *testing.T) {
 	defer ctrl.Finish()
 	mockLogger := mocks.NewMockILogger(ctrl)
 
-	authMiddleware := NewAuthMiddleware(mockLogger)
+	authMiddleware := NewAuthMiddleware(testKeyStore(), nil, mockLogger)
 	handler := authMiddleware.Authenticate()
 
 	// Set up Gin context
@@ -117,7 +128,7 @@ func TestAuthMiddleware_Authenticate_EmptyAPIKey(t *testing.T) {
 	defer ctrl.Finish()
 	mockLogger := mocks.NewMockILogger(ctrl)
 
-	authMiddleware := NewAuthMiddleware(mockLogger)
+	authMiddleware := NewAuthMiddleware(testKeyStore(), nil, mockLogger)
 	handler := authMiddleware.Authenticate()
 
 	// Set up Gin context
@@ -147,7 +158,7 @@ func TestAuthMiddleware_Authenticate_WhitespaceAPIKey(t *testing.T) {
 	defer ctrl.Finish()
 	mockLogger := mocks.NewMockILogger(ctrl)
 
-	authMiddleware := NewAuthMiddleware(mockLogger)
+	authMiddleware := NewAuthMiddleware(testKeyStore(), nil, mockLogger)
 	handler := authMiddleware.Authenticate()
 
 	// Set up Gin context
@@ -177,7 +188,7 @@ func TestAuthMiddleware_Authorize(t *testing.T) {
 	defer ctrl.Finish()
 	mockLogger := mocks.NewMockILogger(ctrl)
 
-	authMiddleware := NewAuthMiddleware(mockLogger)
+	authMiddleware := NewAuthMiddleware(testKeyStore(), nil, mockLogger)
 	handler := authMiddleware.Authorize()
 
 	// Set up Gin context
@@ -203,7 +214,7 @@ func TestAuthMiddleware_Authenticate_WithNextHandler(t *testing.T) {
 	defer ctrl.Finish()
 	mockLogger := mocks.NewMockILogger(ctrl)
 
-	authMiddleware := NewAuthMiddleware(mockLogger)
+	authMiddleware := NewAuthMiddleware(testKeyStore(), nil, mockLogger)
 	authHandler := authMiddleware.Authenticate()
 
 	// Set up Gin context
@@ -241,7 +252,7 @@ func TestAuthMiddleware_Authenticate_AbortedWithNextHandler(t *testing.T) {
 	defer ctrl.Finish()
 	mockLogger := mocks.NewMockILogger(ctrl)
 
-	authMiddleware := NewAuthMiddleware(mockLogger)
+	authMiddleware := NewAuthMiddleware(testKeyStore(), nil, mockLogger)
 	authHandler := authMiddleware.Authenticate()
 
 	// Set up Gin context
@@ -271,3 +282,363 @@ func TestAuthMiddleware_Authenticate_AbortedWithNextHandler(t *testing.T) {
 	assert.Equal(t, http.StatusUnauthorized, w.Code)
 	assert.Contains(t, w.Body.String(), "API key required")
 }
+
+func TestAuthMiddleware_Authenticate_ConfiguredKeys(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockLogger := mocks.NewMockILogger(ctrl)
+
+	authMiddleware := NewAuthMiddleware(NewStaticKeyStore(&config.AuthConfig{APIKey: "client-key", AdminAPIKey: "admin-key"}), nil, mockLogger)
+
+	tests := []struct {
+		name         string
+		apiKey       string
+		expectedCode int
+	}{
+		{name: "configured key", apiKey: "client-key", expectedCode: http.StatusOK},
+		{name: "admin key", apiKey: "admin-key", expectedCode: http.StatusOK},
+		{name: "default key no longer accepted", apiKey: "apitest", expectedCode: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given: A request carrying the API key
+			gin.SetMode(gin.TestMode)
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request, _ = http.NewRequest("GET", "/test", nil)
+			c.Request.Header.Set("api_key", tt.apiKey)
+
+			// When: Calling the authenticate middleware
+			authMiddleware.Authenticate()(c)
+
+			// Then: Only the configured keys should be accepted
+			assert.Equal(t, tt.expectedCode, w.Code)
+		})
+	}
+}
+
+func TestAuthMiddleware_Authorize_Scopes(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockLogger := mocks.NewMockILogger(ctrl)
+
+	authMiddleware := NewAuthMiddleware(testKeyStore(), nil, mockLogger)
+
+	tests := []struct {
+		name         string
+		apiKey       string
+		scopes       []string
+		expectedCode int
+	}{
+		{name: "api key reaches order scope", apiKey: "apitest", scopes: []string{ScopeOrder}, expectedCode: http.StatusOK},
+		{name: "api key denied admin scope", apiKey: "apitest", scopes: []string{ScopeAdmin}, expectedCode: http.StatusForbidden},
+		{name: "admin key reaches admin scope", apiKey: "admintest", scopes: []string{ScopeAdmin}, expectedCode: http.StatusOK},
+		{name: "admin key reaches all scopes", apiKey: "admintest", scopes: []string{ScopeOrder, ScopeAdmin}, expectedCode: http.StatusOK},
+		{name: "order role implies orders:write", apiKey: "apitest", scopes: []string{ScopeCatalogRead, ScopeOrdersWrite}, expectedCode: http.StatusOK},
+		{name: "order role does not imply catalog:admin", apiKey: "apitest", scopes: []string{ScopeCatalogAdmin}, expectedCode: http.StatusForbidden},
+		{name: "admin role implies catalog:admin", apiKey: "admintest", scopes: []string{ScopeCatalogAdmin, ScopeOrdersAdmin}, expectedCode: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given: A router that authenticates and then authorizes the scopes
+			gin.SetMode(gin.TestMode)
+			router := gin.New()
+			router.GET("/test", authMiddleware.Authenticate(), authMiddleware.Authorize(tt.scopes...), func(c *gin.Context) {
+				c.Status(http.StatusOK)
+			})
+			req, _ := http.NewRequest("GET", "/test", nil)
+			req.Header.Set("api_key", tt.apiKey)
+
+			// When: Serving the request
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			// Then: The request should only pass if the key holds every scope
+			assert.Equal(t, tt.expectedCode, w.Code)
+			if tt.expectedCode == http.StatusForbidden {
+				assert.Contains(t, w.Body.String(), "Forbidden")
+			}
+		})
+	}
+}
+
+func TestAuthMiddleware_Authenticate_KeyState(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockLogger := mocks.NewMockILogger(ctrl)
+	mockLogger.EXPECT().WithContext(gomock.Any()).Return(mockLogger).AnyTimes()
+	mockLogger.EXPECT().Warn(gomock.Any(), gomock.Any()).AnyTimes()
+
+	authMiddleware := NewAuthMiddleware(NewStaticKeyStore(&config.AuthConfig{Keys: []config.APIKeyConfig{
+		{Name: "partner", Key: "partner-key", Scopes: []string{ScopeOrder}, ExpiresAt: time.Now().Add(time.Hour)},
+		{Name: "revoked", Key: "revoked-key", Scopes: []string{ScopeOrder}, Revoked: true},
+		{Name: "expired", Key: "expired-key", Scopes: []string{ScopeOrder}, ExpiresAt: time.Now().Add(-time.Hour)},
+	}}), nil, mockLogger)
+
+	tests := []struct {
+		name          string
+		apiKey        string
+		expectedCode  int
+		expectedError string
+	}{
+		{name: "unexpired key", apiKey: "partner-key", expectedCode: http.StatusOK},
+		{name: "revoked key", apiKey: "revoked-key", expectedCode: http.StatusUnauthorized, expectedError: "API key revoked"},
+		{name: "expired key", apiKey: "expired-key", expectedCode: http.StatusUnauthorized, expectedError: "API key expired"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given: A request carrying the API key
+			gin.SetMode(gin.TestMode)
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request, _ = http.NewRequest("GET", "/test", nil)
+			c.Request.Header.Set("api_key", tt.apiKey)
+
+			// When: Calling the authenticate middleware
+			authMiddleware.Authenticate()(c)
+
+			// Then: Revoked and expired keys should be rejected with the reason
+			assert.Equal(t, tt.expectedCode, w.Code)
+			if tt.expectedError != "" {
+				assert.True(t, c.IsAborted())
+				assert.Contains(t, w.Body.String(), tt.expectedError)
+			}
+		})
+	}
+}
+
+func TestAuthMiddleware_Authenticate_KeyStoreError(t *testing.T) {
+	// Given: A key store that fails
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockLogger := mocks.NewMockILogger(ctrl)
+	store := keyStoreFunc(func(_ context.Context, _ string) (*models.APIKey, error) {
+		return nil, errors.New("connection refused")
+	})
+	mockLogger.EXPECT().WithContext(gomock.Any()).Return(mockLogger).AnyTimes()
+	mockLogger.EXPECT().Error(gomock.Any(), gomock.Any())
+
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest("GET", "/test", nil)
+	c.Request.Header.Set("api_key", "apitest")
+
+	// When: Calling the authenticate middleware
+	NewAuthMiddleware(store, nil, mockLogger).Authenticate()(c)
+
+	// Then: The request should be aborted as unavailable rather than unauthorized
+	assert.True(t, c.IsAborted())
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.Contains(t, w.Body.String(), "Authentication unavailable")
+}
+
+func TestAuthMiddleware_Authenticate_SetsAPIKey(t *testing.T) {
+	// Given: An admin key and a handler reading the authenticated key
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockLogger := mocks.NewMockILogger(ctrl)
+	authMiddleware := NewAuthMiddleware(testKeyStore(), nil, mockLogger)
+
+	var key *models.APIKey
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/test", authMiddleware.Authenticate(), func(c *gin.Context) {
+		key = APIKeyFromContext(c)
+		c.Status(http.StatusOK)
+	})
+	req, _ := http.NewRequest("GET", "/test", nil)
+	req.Header.Set("api_key", "admintest")
+
+	// When: Serving the request
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// Then: The handler should see the identity and scopes of the key
+	assert.Equal(t, http.StatusOK, w.Code)
+	if assert.NotNil(t, key) {
+		assert.Equal(t, "admin", key.Name)
+		assert.Equal(t, []string{ScopeOrder, ScopeAdmin}, key.Scopes)
+	}
+}
+
+func TestAPIKeyFromContext_Unauthenticated(t *testing.T) {
+	// Given: A context that was not authenticated
+	gin.SetMode(gin.TestMode)
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+
+	// When/Then: No key should be returned
+	assert.Nil(t, APIKeyFromContext(c))
+}
+
+func TestHasScope(t *testing.T) {
+	tests := []struct {
+		name    string
+		granted []string
+		scope   string
+		want    bool
+	}{
+		{"unauthenticated", nil, ScopeOrdersRead, false},
+		{"granted directly", []string{ScopeOrdersAdmin}, ScopeOrdersAdmin, true},
+		{"implied by role", []string{ScopeAdmin}, ScopeOrdersAdmin, true},
+		{"not granted", []string{ScopeOrder}, ScopeOrdersAdmin, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given: A context authenticated with the granted scopes
+			gin.SetMode(gin.TestMode)
+			c, _ := gin.CreateTestContext(httptest.NewRecorder())
+			if tt.granted != nil {
+				c.Set(scopesContextKey, tt.granted)
+			}
+
+			// When/Then: The scope should be reported as granted or not
+			assert.Equal(t, tt.want, HasScope(c, tt.scope))
+		})
+	}
+}
+
+func TestAuthMiddleware_Authenticate_BearerToken(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockLogger := mocks.NewMockILogger(ctrl)
+	mockLogger.EXPECT().WithContext(gomock.Any()).Return(mockLogger).AnyTimes()
+	mockLogger.EXPECT().Debug(gomock.Any(), gomock.Any()).AnyTimes()
+
+	verifier, err := NewJWTVerifier(&config.JWTConfig{
+		Enabled: true, Issuer: testIssuer, Audience: testAudience, HMACSecret: testHMACSecret,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	authMiddleware := NewAuthMiddleware(testKeyStore(), verifier, mockLogger)
+
+	expired := testClaims()
+	expired["exp"] = time.Now().Add(-time.Hour).Unix()
+	valid := signToken(t, jwt.SigningMethodHS256, []byte(testHMACSecret), "", testClaims())
+
+	tests := []struct {
+		name          string
+		authorization string
+		apiKey        string
+		expectedCode  int
+		expectedError string
+		subject       string
+	}{
+		{name: "valid token", authorization: "Bearer " + valid, expectedCode: http.StatusOK, subject: "cust-42"},
+		{name: "scheme is case-insensitive", authorization: "bearer " + valid, expectedCode: http.StatusOK, subject: "cust-42"},
+		{name: "token takes precedence over API key", authorization: "Bearer invalid", apiKey: "apitest", expectedCode: http.StatusUnauthorized, expectedError: "Invalid token"},
+		{name: "expired token", authorization: "Bearer " + signToken(t, jwt.SigningMethodHS256, []byte(testHMACSecret), "", expired), expectedCode: http.StatusUnauthorized, expectedError: "Token expired"},
+		{name: "other scheme falls back to API key", authorization: "Basic dXNlcjpwYXNz", apiKey: "apitest", expectedCode: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given: A router that authenticates and records the caller
+			var subject string
+			var key *models.APIKey
+			gin.SetMode(gin.TestMode)
+			router := gin.New()
+			router.GET("/test", authMiddleware.Authenticate(), authMiddleware.Authorize(ScopeOrder), func(c *gin.Context) {
+				subject = SubjectFromContext(c)
+				key = APIKeyFromContext(c)
+				c.Status(http.StatusOK)
+			})
+			req, _ := http.NewRequest("GET", "/test", nil)
+			req.Header.Set("Authorization", tt.authorization)
+			if tt.apiKey != "" {
+				req.Header.Set("api_key", tt.apiKey)
+			}
+
+			// When: Serving the request
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			// Then: Bearer tokens should authenticate the subject with the default scopes
+			assert.Equal(t, tt.expectedCode, w.Code)
+			if tt.expectedError != "" {
+				assert.Contains(t, w.Body.String(), tt.expectedError)
+			}
+			assert.Equal(t, tt.subject, subject)
+			if tt.subject != "" {
+				assert.Nil(t, key)
+			}
+		})
+	}
+}
+
+func TestAuthMiddleware_Authenticate_BearerTokenWithoutVerifier(t *testing.T) {
+	// Given: Auth middleware that accepts API keys only
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockLogger := mocks.NewMockILogger(ctrl)
+	authMiddleware := NewAuthMiddleware(testKeyStore(), nil, mockLogger)
+
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest("GET", "/test", nil)
+	c.Request.Header.Set("Authorization", "Bearer "+signToken(t, jwt.SigningMethodHS256, []byte(testHMACSecret), "", testClaims()))
+
+	// When: Calling the authenticate middleware with a bearer token
+	authMiddleware.Authenticate()(c)
+
+	// Then: The request should be rejected
+	assert.True(t, c.IsAborted())
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Contains(t, w.Body.String(), "Bearer tokens not accepted")
+	assert.Empty(t, SubjectFromContext(c))
+}
+
+func TestAuthMiddleware_Authorize_ForbiddenResponse(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockLogger := mocks.NewMockILogger(ctrl)
+
+	verifier, err := NewJWTVerifier(&config.JWTConfig{
+		Enabled: true, Issuer: testIssuer, Audience: testAudience, HMACSecret: testHMACSecret,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	authMiddleware := NewAuthMiddleware(testKeyStore(), verifier, mockLogger)
+	claims := testClaims()
+	claims["scope"] = ScopeCatalogRead
+
+	tests := []struct {
+		name            string
+		header          string
+		value           string
+		wwwAuthenticate string
+	}{
+		{name: "API key", header: "api_key", value: "apitest"},
+		{name: "bearer token", header: "Authorization", value: "Bearer " + signToken(t, jwt.SigningMethodHS256, []byte(testHMACSecret), "", claims),
+			wwwAuthenticate: `Bearer error="insufficient_scope", scope="catalog:read catalog:admin"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given: A route requiring a scope the caller lacks
+			gin.SetMode(gin.TestMode)
+			router := gin.New()
+			router.POST("/test", authMiddleware.Authenticate(), authMiddleware.Authorize(ScopeCatalogRead, ScopeCatalogAdmin), func(c *gin.Context) {
+				c.Status(http.StatusOK)
+			})
+			req, _ := http.NewRequest("POST", "/test", nil)
+			req.Header.Set(tt.header, tt.value)
+
+			// When: Serving the request
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			// Then: It should be rejected with the required and missing scopes
+			assert.Equal(t, http.StatusForbidden, w.Code)
+			assert.JSONEq(t, `{"error":"Forbidden","code":"insufficient_scope","requiredScopes":["catalog:read","catalog:admin"],"missingScopes":["catalog:admin"]}`, w.Body.String())
+			assert.Equal(t, tt.wwwAuthenticate, w.Header().Get("WWW-Authenticate"))
+		})
+	}
+}
@@ -1,20 +1,52 @@
 package middlewares
 
 import (
-	"time"
+	"orderfoodonline/internal/config"
+	"strings"
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
 )
 
-// CorsHandler returns a Gin middleware handler for CORS configuration.
-func CorsHandler() gin.HandlerFunc {
+// CorsHandler returns a Gin middleware handler applying the CORS policy in cfg.
+// Preflight requests from allowed origins are answered with 204 No Content, and requests
+// from other origins are rejected with 403 Forbidden. A nil cfg allows no origin.
+func CorsHandler(cfg *config.CORSConfig) gin.HandlerFunc {
+	if cfg == nil {
+		cfg = &config.CORSConfig{}
+	}
+	origins := make([]string, len(cfg.AllowOrigins))
+	for i, origin := range cfg.AllowOrigins {
+		origins[i] = strings.ToLower(origin)
+	}
 	return cors.New(cors.Config{
-		AllowOrigins:     []string{"*"}, // Allow requests from this origin
-		AllowMethods:     []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-		AllowHeaders:     []string{"Origin", "Content-Type", "Accept", "Authorization", "X-Geo-Location", "X-Language", "X-Timezone"},
-		ExposeHeaders:    []string{"Content-Length"},
-		AllowCredentials: true,
-		MaxAge:           12 * time.Hour,
+		AllowOriginFunc:  func(origin string) bool { return originAllowed(origins, origin) },
+		AllowMethods:     cfg.AllowMethods,
+		AllowHeaders:     cfg.AllowHeaders,
+		ExposeHeaders:    cfg.ExposeHeaders,
+		AllowCredentials: cfg.AllowCredentials,
+		MaxAge:           cfg.MaxAge,
 	})
 }
+
+// originAllowed reports whether origin matches one of the lower-case patterns, which are either
+// exact origins or allow any subdomain with a "*." wildcard (e.g. "https://*.example.com").
+func originAllowed(patterns []string, origin string) bool {
+	origin = strings.ToLower(origin)
+	for _, pattern := range patterns {
+		prefix, suffix, wildcard := strings.Cut(pattern, "*")
+		if !wildcard {
+			if origin == pattern {
+				return true
+			}
+			continue
+		}
+		// The wildcard must stand for at least one whole label, so "https://*.example.com"
+		// matches neither "https://example.com" nor "https://evilexample.com"
+		if strings.HasPrefix(origin, prefix) && strings.HasSuffix(origin, suffix) &&
+			len(origin) > len(prefix)+len(suffix) && !strings.ContainsAny(origin[len(prefix):len(origin)-len(suffix)], "/:") {
+			return true
+		}
+	}
+	return false
+}
@@ -4,179 +4,132 @@ package middlewares
 import (
 	"net/http"
 	"net/http/httptest"
+	"orderfoodonline/internal/config"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
 )
 
-func TestCorsHandler(t *testing.T) {
-	// Given: CORS middleware
-	corsHandler := CorsHandler()
-
-	// Set up Gin context
-	gin.SetMode(gin.TestMode)
-	w := httptest.NewRecorder()
-	c, _ := gin.CreateTestContext(w)
-
-	// Create test request
-	req, _ := http.NewRequest("GET", "/api/test", nil)
-	c.Request = req
-
-	// Add a next handler
-	nextCalled := false
-	nextHandler := func(c *gin.Context) {
-		nextCalled = true
-		c.JSON(http.StatusOK, gin.H{"message": "success"})
-	}
-
-	// When: Processing the request through the CORS middleware
-	corsHandler(c)
-	if !c.IsAborted() {
-		nextHandler(c)
+// testCORSConfig allows one exact origin and the subdomains of example.com.
+func testCORSConfig() *config.CORSConfig {
+	return &config.CORSConfig{
+		AllowOrigins:     []string{"http://localhost:3000", "https://*.example.com"},
+		AllowMethods:     []string{"GET", "POST", "PATCH"},
+		AllowHeaders:     []string{"Content-Type", "Authorization", "api_key", "Idempotency-Key"},
+		ExposeHeaders:    []string{"X-Total-Count", "Retry-After"},
+		AllowCredentials: true,
+		MaxAge:           12 * time.Hour,
 	}
-
-	// Then: Next handler should be called and response should be successful
-	assert.True(t, nextCalled)
-	assert.Equal(t, http.StatusOK, w.Code)
-	assert.Contains(t, w.Body.String(), "success")
 }
 
-func TestCorsHandler_DifferentHTTPMethods(t *testing.T) {
-	// Given: CORS middleware
-	corsHandler := CorsHandler()
+// corsRouter returns a router serving GET and POST /api/order behind the CORS policy.
+func corsRouter(cfg *config.CORSConfig) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(CorsHandler(cfg))
+	ok := func(c *gin.Context) { c.JSON(http.StatusOK, gin.H{"message": "success"}) }
+	router.GET("/api/order", ok)
+	router.POST("/api/order", ok)
+	return router
+}
 
-	testCases := []struct {
-		method     string
-		path       string
-		statusCode int
+func TestCorsHandler_Preflight(t *testing.T) {
+	tests := []struct {
+		name    string
+		origin  string
+		allowed bool
 	}{
-		{"GET", "/api/health", http.StatusOK},
-		{"POST", "/api/order", http.StatusOK},
-		{"PUT", "/api/product/123", http.StatusOK},
-		{"PATCH", "/api/product/123", http.StatusOK},
+		{name: "exact origin", origin: "http://localhost:3000", allowed: true},
+		{name: "exact origin in another case", origin: "HTTP://LOCALHOST:3000", allowed: true},
+		{name: "subdomain of wildcard origin", origin: "https://shop.example.com", allowed: true},
+		{name: "nested subdomain of wildcard origin", origin: "https://eu.shop.example.com", allowed: true},
+		{name: "exact origin on another port", origin: "http://localhost:8080"},
+		{name: "exact origin over another scheme", origin: "https://localhost:3000"},
+		{name: "wildcard origin's bare domain", origin: "https://example.com"},
+		{name: "wildcard origin over another scheme", origin: "http://shop.example.com"},
+		{name: "domain ending like wildcard origin", origin: "https://shopexample.com"},
+		{name: "unlisted origin", origin: "https://evil.com"},
 	}
 
-	for _, tc := range testCases {
-		t.Run(tc.method+"_"+tc.path, func(t *testing.T) {
-			// Set up Gin context
-			gin.SetMode(gin.TestMode)
-			w := httptest.NewRecorder()
-			c, _ := gin.CreateTestContext(w)
-
-			// Create test request
-			req, _ := http.NewRequest(tc.method, tc.path, nil)
-			req.Header.Set("Origin", "http://localhost:3000")
-			c.Request = req
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given: A router behind the CORS policy
+			router := corsRouter(testCORSConfig())
 
-			// Add a next handler
-			nextHandler := func(c *gin.Context) {
-				c.Status(tc.statusCode)
-			}
+			// When: A browser sends a preflight request for an authenticated order placement
+			req, _ := http.NewRequest(http.MethodOptions, "/api/order", nil)
+			req.Header.Set("Origin", tt.origin)
+			req.Header.Set("Access-Control-Request-Method", "POST")
+			req.Header.Set("Access-Control-Request-Headers", "content-type,api_key,idempotency-key")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
 
-			// When: Processing the request through the CORS middleware
-			corsHandler(c)
-			if !c.IsAborted() {
-				nextHandler(c)
+			// Then: Only allowed origins should be answered with the configured policy
+			if !tt.allowed {
+				assert.Equal(t, http.StatusForbidden, w.Code)
+				assert.Empty(t, w.Header().Get("Access-Control-Allow-Origin"))
+				return
 			}
-
-			// Then: Request should be processed with correct status code and CORS headers
-			assert.Equal(t, tc.statusCode, w.Code)
-			assert.Equal(t, "*", w.Header().Get("Access-Control-Allow-Origin"))
+			assert.Equal(t, http.StatusNoContent, w.Code)
+			assert.Equal(t, tt.origin, w.Header().Get("Access-Control-Allow-Origin"))
+			assert.Equal(t, "true", w.Header().Get("Access-Control-Allow-Credentials"))
+			assert.Equal(t, "GET,POST,PATCH", w.Header().Get("Access-Control-Allow-Methods"))
+			assert.Equal(t, "Content-Type,Authorization,Api_key,Idempotency-Key", w.Header().Get("Access-Control-Allow-Headers"))
+			assert.Equal(t, "43200", w.Header().Get("Access-Control-Max-Age"))
+			assert.Empty(t, w.Body.String())
 		})
 	}
 }
 
-func TestCorsHandler_CustomHeaders(t *testing.T) {
-	// Given: CORS middleware
-	corsHandler := CorsHandler()
-
-	// Set up Gin context
-	gin.SetMode(gin.TestMode)
-	w := httptest.NewRecorder()
-	c, _ := gin.CreateTestContext(w)
-
-	// Create test request with custom headers
-	req, _ := http.NewRequest("POST", "/api/test", nil)
-	req.Header.Set("Origin", "http://localhost:3000")
-	req.Header.Set("X-Geo-Location", "US")
-	req.Header.Set("X-Language", "en")
-	req.Header.Set("X-Timezone", "UTC")
-	c.Request = req
-
-	// Add a next handler
-	nextHandler := func(c *gin.Context) {
-		c.JSON(http.StatusOK, gin.H{"message": "success"})
-	}
-
-	// When: Processing the request through the CORS middleware
-	corsHandler(c)
-	if !c.IsAborted() {
-		nextHandler(c)
+func TestCorsHandler_Request(t *testing.T) {
+	tests := []struct {
+		name         string
+		origin       string
+		expectedCode int
+		allowOrigin  string
+	}{
+		{name: "allowed origin", origin: "https://shop.example.com", expectedCode: http.StatusOK, allowOrigin: "https://shop.example.com"},
+		{name: "unlisted origin", origin: "https://evil.com", expectedCode: http.StatusForbidden},
+		{name: "no origin", origin: "", expectedCode: http.StatusOK},
 	}
 
-	// Then: Request should be processed successfully with CORS headers
-	assert.Equal(t, http.StatusOK, w.Code)
-	assert.Equal(t, "*", w.Header().Get("Access-Control-Allow-Origin"))
-}
-
-func TestCorsHandler_ExposeHeaders(t *testing.T) {
-	// Given: CORS middleware
-	corsHandler := CorsHandler()
-
-	// Set up Gin context
-	gin.SetMode(gin.TestMode)
-	w := httptest.NewRecorder()
-	c, _ := gin.CreateTestContext(w)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given: A router behind the CORS policy
+			router := corsRouter(testCORSConfig())
 
-	// Create test request
-	req, _ := http.NewRequest("GET", "/api/test", nil)
-	req.Header.Set("Origin", "http://localhost:3000")
-	c.Request = req
-
-	// Add a next handler that sets custom headers
-	nextHandler := func(c *gin.Context) {
-		c.Header("Content-Length", "123")
-		c.JSON(http.StatusOK, gin.H{"message": "success"})
-	}
-
-	// When: Processing the request through the CORS middleware
-	corsHandler(c)
-	if !c.IsAborted() {
-		nextHandler(c)
+			// When: Sending a request from the origin
+			req, _ := http.NewRequest(http.MethodGet, "/api/order", nil)
+			if tt.origin != "" {
+				req.Header.Set("Origin", tt.origin)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			// Then: The allowed origin should be echoed back, never a wildcard
+			assert.Equal(t, tt.expectedCode, w.Code)
+			assert.Equal(t, tt.allowOrigin, w.Header().Get("Access-Control-Allow-Origin"))
+			if tt.allowOrigin != "" {
+				assert.Equal(t, "X-Total-Count,Retry-After", w.Header().Get("Access-Control-Expose-Headers"))
+				assert.Contains(t, w.Body.String(), "success")
+			}
+		})
 	}
-
-	// Then: Request should be processed successfully with exposed headers
-	assert.Equal(t, http.StatusOK, w.Code)
-	assert.Equal(t, "*", w.Header().Get("Access-Control-Allow-Origin"))
-	assert.Contains(t, w.Header().Get("Access-Control-Expose-Headers"), "Content-Length")
 }
 
-func TestCorsHandler_NoOriginHeader(t *testing.T) {
-	// Given: CORS middleware
-	corsHandler := CorsHandler()
+func TestCorsHandler_NilConfig(t *testing.T) {
+	// Given: A router behind a CORS handler created without configuration
+	router := corsRouter(nil)
 
-	// Set up Gin context
-	gin.SetMode(gin.TestMode)
+	// When: Sending a preflight request from any origin
+	req, _ := http.NewRequest(http.MethodOptions, "/api/order", nil)
+	req.Header.Set("Origin", "http://localhost:3000")
+	req.Header.Set("Access-Control-Request-Method", "GET")
 	w := httptest.NewRecorder()
-	c, _ := gin.CreateTestContext(w)
-
-	// Create test request without Origin header
-	req, _ := http.NewRequest("GET", "/api/test", nil)
-	c.Request = req
-
-	// Add a next handler
-	nextHandler := func(c *gin.Context) {
-		c.JSON(http.StatusOK, gin.H{"message": "success"})
-	}
-
-	// When: Processing the request through the CORS middleware
-	corsHandler(c)
-	if !c.IsAborted() {
-		nextHandler(c)
-	}
+	router.ServeHTTP(w, req)
 
-	// Then: Request should be processed successfully
-	assert.Equal(t, http.StatusOK, w.Code)
-	assert.Contains(t, w.Body.String(), "success")
+	// Then: It should be rejected
+	assert.Equal(t, http.StatusForbidden, w.Code)
 }
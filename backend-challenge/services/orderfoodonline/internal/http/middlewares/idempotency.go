@@ -0,0 +1,180 @@
+package middlewares
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"library/logger"
+	"net/http"
+	"orderfoodonline/internal/repository"
+	"orderfoodonline/internal/repository/models"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// IdempotencyKeyHeader is the request header carrying the client-supplied idempotency key.
+	IdempotencyKeyHeader = "Idempotency-Key"
+	// IdempotentReplayedHeader is set on responses that were replayed from a previous request.
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+	// DefaultIdempotencyTTL is how long idempotency keys are kept when no TTL is configured.
+	DefaultIdempotencyTTL = 24 * time.Hour
+	// DefaultIdempotencyLease is how long an in-progress key is held when no lease is configured.
+	DefaultIdempotencyLease = time.Minute
+	// idempotencyCleanupTimeout bounds the release or completion of a key once its request is done.
+	idempotencyCleanupTimeout = 5 * time.Second
+	// maxIdempotencyKeyLength bounds the size of keys accepted from clients.
+	maxIdempotencyKeyLength = 255
+)
+
+// idempotency replays stored responses for requests retried with the same Idempotency-Key.
+type idempotency struct {
+	repo   repository.IdempotencyRepository
+	ttl    time.Duration
+	lease  time.Duration
+	logger logger.ILogger
+}
+
+// NewIdempotencyMiddleware creates a new instance of idempotency middleware which implements IdempotencyMiddleware.
+// Stored responses expire after ttl. A key whose request is still running is only held for lease,
+// so that a request lost without releasing its key blocks retries for that long at most.
+// Non-positive values fall back to DefaultIdempotencyTTL and DefaultIdempotencyLease.
+func NewIdempotencyMiddleware(repo repository.IdempotencyRepository, ttl, lease time.Duration, logger logger.ILogger) IdempotencyMiddleware {
+	if ttl <= 0 {
+		ttl = DefaultIdempotencyTTL
+	}
+	if lease <= 0 {
+		lease = DefaultIdempotencyLease
+	}
+	return &idempotency{repo: repo, ttl: ttl, lease: lease, logger: logger}
+}
+
+// responseRecorder captures the response body while still writing it to the client.
+type responseRecorder struct {
+	gin.ResponseWriter
+	body *bytes.Buffer
+}
+
+// Write writes the data to the client and keeps a copy of it.
+func (w *responseRecorder) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+// Idempotent makes the wrapped handler safe to retry. Requests without an Idempotency-Key
+// header pass through untouched. The first request with a key is executed and its response
+// stored; a retry with the same key and body receives the stored response, while a retry
+// with a different body, or one arriving while the first is still running, gets a 409.
+// Server errors and panics are not stored, so the client may retry them with the same key.
+// Keys are scoped to the authenticated caller, so it must run after Authenticate.
+func (i *idempotency) Idempotent() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := strings.TrimSpace(c.GetHeader(IdempotencyKeyHeader))
+		if key == "" {
+			c.Next()
+			return
+		}
+		if len(key) > maxIdempotencyKeyLength {
+			ErrorResponse(c, http.StatusBadRequest, gin.H{"error": "Invalid Idempotency-Key"})
+			c.Abort()
+			return
+		}
+
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			ErrorResponse(c, http.StatusBadRequest, gin.H{"error": "Invalid input"})
+			c.Abort()
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		ctx := c.Request.Context()
+		now := time.Now()
+		record := &models.IdempotencyRecord{
+			Scope:       callerIdentity(c),
+			Key:         key,
+			RequestHash: requestHash(c.Request.Method, c.Request.URL.Path, body),
+			Status:      models.IdempotencyStatusInProgress,
+			CreatedAt:   now.Unix(),
+			ExpiresAt:   now.Add(i.lease),
+		}
+
+		created, err := i.repo.CreateIdempotencyKey(ctx, record)
+		if err != nil {
+			i.logger.WithContext(c.Request.Context()).Error("error storing idempotency key: %v", err)
+			ErrorResponse(c, http.StatusInternalServerError, gin.H{"error": "Failed to process request"})
+			c.Abort()
+			return
+		}
+		if !created {
+			i.replay(c, record)
+			return
+		}
+
+		recorder := &responseRecorder{ResponseWriter: c.Writer, body: &bytes.Buffer{}}
+		c.Writer = recorder
+		finished := false
+		defer func() {
+			i.finish(ctx, record, recorder, finished)
+		}()
+		c.Next()
+		finished = true
+	}
+}
+
+// finish completes the key with the recorded response, or releases it if the handler failed
+// with a server error or did not return. It runs on a context detached from the request's,
+// which is cancelled as soon as the client goes away.
+func (i *idempotency) finish(ctx context.Context, record *models.IdempotencyRecord, recorder *responseRecorder, finished bool) {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), idempotencyCleanupTimeout)
+	defer cancel()
+
+	status := recorder.Status()
+	if !finished || status >= http.StatusInternalServerError {
+		if err := i.repo.DeleteIdempotencyKey(ctx, record.Scope, record.Key); err != nil {
+			i.logger.WithContext(ctx).Error("error releasing idempotency key: %v", err)
+		}
+		return
+	}
+	if err := i.repo.CompleteIdempotencyKey(ctx, record.Scope, record.Key, status, recorder.body.Bytes(), time.Now().Add(i.ttl)); err != nil {
+		i.logger.WithContext(ctx).Error("error completing idempotency key: %v", err)
+	}
+}
+
+// replay answers a request whose key was already used.
+func (i *idempotency) replay(c *gin.Context, record *models.IdempotencyRecord) {
+	defer c.Abort()
+
+	existing, err := i.repo.FindIdempotencyKey(c.Request.Context(), record.Scope, record.Key)
+	if err != nil {
+		i.logger.WithContext(c.Request.Context()).Error("error fetching idempotency key: %v", err)
+		ErrorResponse(c, http.StatusInternalServerError, gin.H{"error": "Failed to process request"})
+		return
+	}
+	if existing == nil || existing.Status != models.IdempotencyStatusCompleted {
+		ErrorResponse(c, http.StatusConflict, gin.H{"error": "A request with this Idempotency-Key is already in progress"})
+		return
+	}
+	if existing.RequestHash != record.RequestHash {
+		ErrorResponse(c, http.StatusConflict, gin.H{"error": "Idempotency-Key was already used with a different request"})
+		return
+	}
+
+	c.Header(IdempotentReplayedHeader, "true")
+	c.Data(existing.ResponseCode, "application/json; charset=utf-8", existing.ResponseBody)
+}
+
+// requestHash fingerprints a request so a reused key can be matched to its original request.
+func requestHash(method, path string, body []byte) string {
+	h := sha256.New()
+	h.Write([]byte(method))
+	h.Write([]byte{0})
+	h.Write([]byte(path))
+	h.Write([]byte{0})
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,290 @@
+// Package middlewares provides HTTP middleware components for the Order Food Online service.
+package middlewares
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"library/logger/mocks"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	repomocks "orderfoodonline/internal/repository/mocks"
+	"orderfoodonline/internal/repository/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+// authenticatedAs stands in for Authenticate, authenticating every request with the named API key.
+func authenticatedAs(name string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set(apiKeyContextKey, &models.APIKey{Name: name})
+	}
+}
+
+// newIdempotencyTestRouter wires the idempotency middleware, for requests authenticated with
+// the "shop" API key, in front of a handler that echoes the request body and counts how many times it ran.
+func newIdempotencyTestRouter(m IdempotencyMiddleware, status int, calls *int) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/api/order", authenticatedAs("shop"), m.Idempotent(), func(c *gin.Context) {
+		*calls++
+		body, _ := io.ReadAll(c.Request.Body)
+		c.Data(status, "application/json; charset=utf-8", body)
+	})
+	return router
+}
+
+func newOrderRequest(key, body string) *http.Request {
+	req, _ := http.NewRequest("POST", "/api/order", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	if key != "" {
+		req.Header.Set(IdempotencyKeyHeader, key)
+	}
+	return req
+}
+
+func TestNewIdempotencyMiddleware_DefaultTTL(t *testing.T) {
+	// Given: A repository and logger mock
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// When: Creating the middleware without a TTL nor a lease
+	m := NewIdempotencyMiddleware(repomocks.NewMockIdempotencyRepository(ctrl), 0, 0, mocks.NewMockILogger(ctrl))
+
+	// Then: The defaults should be used
+	assert.Equal(t, DefaultIdempotencyTTL, m.(*idempotency).ttl)
+	assert.Equal(t, DefaultIdempotencyLease, m.(*idempotency).lease)
+}
+
+func TestIdempotency_NoKeyPassesThrough(t *testing.T) {
+	// Given: The middleware with a repository that must not be called
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	m := NewIdempotencyMiddleware(repomocks.NewMockIdempotencyRepository(ctrl), time.Hour, time.Minute, mocks.NewMockILogger(ctrl))
+	calls := 0
+	router := newIdempotencyTestRouter(m, http.StatusOK, &calls)
+
+	// When: Sending a request without an Idempotency-Key
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, newOrderRequest("", `{"items":[]}`))
+
+	// Then: The handler should run normally
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, 1, calls)
+}
+
+func TestIdempotency_FirstRequestIsStored(t *testing.T) {
+	// Given: A key that has not been seen before
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockRepo := repomocks.NewMockIdempotencyRepository(ctrl)
+	m := NewIdempotencyMiddleware(mockRepo, time.Hour, time.Minute, mocks.NewMockILogger(ctrl))
+	calls := 0
+	router := newIdempotencyTestRouter(m, http.StatusOK, &calls)
+
+	mockRepo.EXPECT().CreateIdempotencyKey(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ interface{}, record *models.IdempotencyRecord) (bool, error) {
+			assert.Equal(t, "key:shop", record.Scope)
+			assert.Equal(t, "key-1", record.Key)
+			assert.Equal(t, models.IdempotencyStatusInProgress, record.Status)
+			// The in-progress record is only held for the lease
+			assert.WithinDuration(t, time.Now().Add(time.Minute), record.ExpiresAt, 5*time.Second)
+			return true, nil
+		})
+	mockRepo.EXPECT().CompleteIdempotencyKey(gomock.Any(), "key:shop", "key-1", http.StatusOK, []byte(`{"id":"1"}`), gomock.Any()).DoAndReturn(
+		func(_ context.Context, _, _ string, _ int, _ []byte, expiresAt time.Time) error {
+			// The stored response is kept for the TTL
+			assert.WithinDuration(t, time.Now().Add(time.Hour), expiresAt, 5*time.Second)
+			return nil
+		})
+
+	// When: Sending the request
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, newOrderRequest("key-1", `{"id":"1"}`))
+
+	// Then: The handler runs once and its response is stored
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, 1, calls)
+	assert.Equal(t, `{"id":"1"}`, w.Body.String())
+}
+
+func TestIdempotency_ServerErrorReleasesKey(t *testing.T) {
+	// Given: A handler that fails with a server error
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockRepo := repomocks.NewMockIdempotencyRepository(ctrl)
+	m := NewIdempotencyMiddleware(mockRepo, time.Hour, time.Minute, mocks.NewMockILogger(ctrl))
+	calls := 0
+	router := newIdempotencyTestRouter(m, http.StatusInternalServerError, &calls)
+
+	mockRepo.EXPECT().CreateIdempotencyKey(gomock.Any(), gomock.Any()).Return(true, nil)
+	mockRepo.EXPECT().DeleteIdempotencyKey(gomock.Any(), "key:shop", "key-1").Return(nil)
+
+	// When: Sending the request
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, newOrderRequest("key-1", `{}`))
+
+	// Then: The key is released so the client can retry
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+}
+
+func TestIdempotency_PanicReleasesKey(t *testing.T) {
+	// Given: A handler that panics
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockRepo := repomocks.NewMockIdempotencyRepository(ctrl)
+	m := NewIdempotencyMiddleware(mockRepo, time.Hour, time.Minute, mocks.NewMockILogger(ctrl))
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/api/order", authenticatedAs("shop"), m.Idempotent(), func(c *gin.Context) { panic("boom") })
+
+	mockRepo.EXPECT().CreateIdempotencyKey(gomock.Any(), gomock.Any()).Return(true, nil)
+	mockRepo.EXPECT().DeleteIdempotencyKey(gomock.Any(), "key:shop", "key-1").Return(nil)
+
+	// When / Then: The panic goes on to the recovery middleware after the key is released
+	assert.Panics(t, func() {
+		router.ServeHTTP(httptest.NewRecorder(), newOrderRequest("key-1", `{}`))
+	})
+}
+
+func TestIdempotency_ClientGoneStillCompletesKey(t *testing.T) {
+	// Given: A request whose client disconnects while the handler runs
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockRepo := repomocks.NewMockIdempotencyRepository(ctrl)
+	m := NewIdempotencyMiddleware(mockRepo, time.Hour, time.Minute, mocks.NewMockILogger(ctrl))
+	ctx, cancel := context.WithCancel(context.Background())
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/api/order", authenticatedAs("shop"), m.Idempotent(), func(c *gin.Context) {
+		cancel()
+		c.JSON(http.StatusCreated, gin.H{"id": "order-1"})
+	})
+
+	var completeCtxErr error
+	mockRepo.EXPECT().CreateIdempotencyKey(gomock.Any(), gomock.Any()).Return(true, nil)
+	mockRepo.EXPECT().CompleteIdempotencyKey(gomock.Any(), "key:shop", "key-1", http.StatusCreated, gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, _, _ string, _ int, _ []byte, _ time.Time) error {
+			completeCtxErr = ctx.Err()
+			return nil
+		})
+
+	// When: Sending the request
+	router.ServeHTTP(httptest.NewRecorder(), newOrderRequest("key-1", `{}`).WithContext(ctx))
+
+	// Then: The response is stored on a context that outlives the request
+	assert.Error(t, ctx.Err())
+	assert.NoError(t, completeCtxErr)
+}
+
+func TestIdempotency_RepeatedRequests(t *testing.T) {
+	body := `{"items":[{"productId":"10","quantity":1}]}`
+	hash := requestHash("POST", "/api/order", []byte(body))
+
+	tests := []struct {
+		name         string
+		body         string
+		existing     *models.IdempotencyRecord
+		findErr      error
+		expectedCode int
+		expectedBody string
+		replayed     bool
+	}{
+		{
+			name: "same body replays original response",
+			body: body,
+			existing: &models.IdempotencyRecord{Key: "key-1", RequestHash: hash, Status: models.IdempotencyStatusCompleted,
+				ResponseCode: http.StatusOK, ResponseBody: []byte(`{"id":"order-1"}`)},
+			expectedCode: http.StatusOK,
+			expectedBody: `{"id":"order-1"}`,
+			replayed:     true,
+		},
+		{
+			name: "different body conflicts",
+			body: `{"items":[{"productId":"10","quantity":2}]}`,
+			existing: &models.IdempotencyRecord{Key: "key-1", RequestHash: hash, Status: models.IdempotencyStatusCompleted,
+				ResponseCode: http.StatusOK, ResponseBody: []byte(`{"id":"order-1"}`)},
+			expectedCode: http.StatusConflict,
+			expectedBody: "different request",
+		},
+		{
+			name:         "original still in progress conflicts",
+			body:         body,
+			existing:     &models.IdempotencyRecord{Key: "key-1", RequestHash: hash, Status: models.IdempotencyStatusInProgress},
+			expectedCode: http.StatusConflict,
+			expectedBody: "already in progress",
+		},
+		{
+			name:         "lookup error",
+			body:         body,
+			findErr:      errors.New("db error"),
+			expectedCode: http.StatusInternalServerError,
+			expectedBody: "Failed to process request",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			mockRepo := repomocks.NewMockIdempotencyRepository(ctrl)
+			mockLogger := mocks.NewMockILogger(ctrl)
+			mockLogger.EXPECT().WithContext(gomock.Any()).Return(mockLogger).AnyTimes()
+			mockLogger.EXPECT().Error(gomock.Any(), gomock.Any()).AnyTimes()
+			m := NewIdempotencyMiddleware(mockRepo, time.Hour, time.Minute, mockLogger)
+			calls := 0
+			router := newIdempotencyTestRouter(m, http.StatusOK, &calls)
+
+			mockRepo.EXPECT().CreateIdempotencyKey(gomock.Any(), gomock.Any()).Return(false, nil)
+			mockRepo.EXPECT().FindIdempotencyKey(gomock.Any(), "key:shop", "key-1").Return(tt.existing, tt.findErr)
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, newOrderRequest("key-1", tt.body))
+
+			assert.Equal(t, 0, calls, "handler must not run again")
+			assert.Equal(t, tt.expectedCode, w.Code)
+			assert.Contains(t, w.Body.String(), tt.expectedBody)
+			if tt.replayed {
+				assert.Equal(t, "true", w.Header().Get(IdempotentReplayedHeader))
+			}
+		})
+	}
+}
+
+func TestIdempotency_KeysAreScopedToTheCaller(t *testing.T) {
+	// Given: Two callers using the same Idempotency-Key
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockRepo := repomocks.NewMockIdempotencyRepository(ctrl)
+	m := NewIdempotencyMiddleware(mockRepo, time.Hour, time.Minute, mocks.NewMockILogger(ctrl))
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	handler := func(c *gin.Context) { c.JSON(http.StatusCreated, gin.H{"id": "order-1"}) }
+	router.POST("/api/order", authenticatedAs("shop"), m.Idempotent(), handler)
+	router.POST("/api/other", func(c *gin.Context) {
+		c.Set(claimsContextKey, &TokenClaims{Subject: "user-1"})
+	}, m.Idempotent(), handler)
+
+	var scopes []string
+	mockRepo.EXPECT().CreateIdempotencyKey(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, record *models.IdempotencyRecord) (bool, error) {
+			scopes = append(scopes, record.Scope)
+			return true, nil
+		}).Times(2)
+	mockRepo.EXPECT().CompleteIdempotencyKey(gomock.Any(), gomock.Any(), "key-1", http.StatusCreated, gomock.Any(), gomock.Any()).Return(nil).Times(2)
+
+	// When: Each caller sends a request with the key
+	router.ServeHTTP(httptest.NewRecorder(), newOrderRequest("key-1", `{}`))
+	req, _ := http.NewRequest("POST", "/api/other", bytes.NewBufferString(`{}`))
+	req.Header.Set(IdempotencyKeyHeader, "key-1")
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	// Then: The key is stored once per caller
+	assert.Equal(t, []string{"key:shop", "sub:user-1"}, scopes)
+}
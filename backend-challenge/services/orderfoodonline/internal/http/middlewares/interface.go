@@ -1,7 +1,13 @@
 // Package middlewares provides HTTP middleware components for the Order Food Online service.
 package middlewares
 
-import "github.com/gin-gonic/gin"
+import (
+	"context"
+	"orderfoodonline/internal/repository/models"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
 
 // AuthMiddleware defines authentication and authorization middleware methods.
 // It provides middleware functions for securing API endpoints through
@@ -12,8 +18,23 @@ type AuthMiddleware interface {
 	Authenticate() gin.HandlerFunc
 
 	// Authorize creates a middleware function that checks user permissions
-	// and authorizes access to protected endpoints.
-	Authorize() gin.HandlerFunc
+	// and authorizes access to protected endpoints. It must run after Authenticate
+	// and rejects callers that were not granted all of the given scopes.
+	Authorize(scopes ...string) gin.HandlerFunc
+}
+
+// KeyStore looks up the API keys accepted by the service.
+type KeyStore interface {
+	// LookupAPIKey returns the key whose secret is presented, or nil if no key matches.
+	// Expired and revoked keys are returned as well, so the caller can tell why they are rejected.
+	LookupAPIKey(ctx context.Context, secret string) (*models.APIKey, error)
+}
+
+// TokenVerifier verifies the bearer tokens accepted by the service.
+type TokenVerifier interface {
+	// VerifyToken checks the signature and the registered claims of a compact JWT
+	// and returns its claims. Expired tokens fail with an error wrapping jwt.ErrTokenExpired.
+	VerifyToken(token string) (*TokenClaims, error)
 }
 
 // MetricsMiddleware provides HTTP request metrics collection using Prometheus.
@@ -21,3 +42,30 @@ type MetricsMiddleware interface {
 	// RecordMetrics is a Gin middleware that records HTTP request metrics.
 	RecordMetrics() gin.HandlerFunc
 }
+
+// RateLimitMiddleware limits the rate of requests per route and caller.
+type RateLimitMiddleware interface {
+	// Limit creates a middleware function that counts the request against the policy of its
+	// route, sets the RateLimit-* headers and rejects it with 429 once the limit is reached.
+	// It must run after Authenticate for requests to be counted per caller.
+	Limit() gin.HandlerFunc
+
+	// LimitAuthentication creates a middleware function that counts the request per client IP
+	// against the authentication policy. It must run before Authenticate, so that requests
+	// failing authentication are counted as well.
+	LimitAuthentication() gin.HandlerFunc
+}
+
+// RateLimitStore counts requests in fixed windows.
+type RateLimitStore interface {
+	// Increment counts a request against key in the current window of the given length
+	// and returns the number of requests counted in that window and when it ends.
+	Increment(ctx context.Context, key string, window time.Duration) (count int64, resetAt time.Time, err error)
+}
+
+// IdempotencyMiddleware makes unsafe endpoints safe to retry using the Idempotency-Key header.
+type IdempotencyMiddleware interface {
+	// Idempotent creates a middleware function that replays the stored response
+	// for requests repeated with the same Idempotency-Key.
+	Idempotent() gin.HandlerFunc
+}
@@ -0,0 +1,218 @@
+package middlewares
+
+import (
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"orderfoodonline/internal/config"
+	"os"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+var (
+	// errNoVerificationKey is returned when no configured key matches the alg and kid of a token.
+	errNoVerificationKey = errors.New("no key verifies the token")
+	// errMissingSubject is returned for tokens that do not identify a user.
+	errMissingSubject = errors.New("token has no subject")
+)
+
+// TokenClaims holds the identity carried by a verified bearer token.
+type TokenClaims struct {
+	Subject string        // sub claim identifying the user
+	Scopes  []string      // Scopes from the space-separated scope claim, or the configured defaults
+	Claims  jwt.MapClaims // Every claim of the token
+}
+
+// verificationKey is a key accepted for tokens signed with alg, selected by kid when both are set.
+type verificationKey struct {
+	kid string
+	alg string
+	key jwt.VerificationKey // []byte for HS256, *rsa.PublicKey for RS256
+}
+
+// jwtVerifier verifies HS256 and RS256 tokens against keys read at start-up.
+type jwtVerifier struct {
+	keys          []verificationKey
+	parser        *jwt.Parser
+	defaultScopes []string
+}
+
+// NewJWTVerifier creates a TokenVerifier from cfg. It reads the HMAC secret, the RSA public key
+// file and the JWKS file, and fails if none of them yields a key.
+// Tokens must carry exp and sub, must match the configured iss and aud, and must not be used before nbf.
+func NewJWTVerifier(cfg *config.JWTConfig) (TokenVerifier, error) {
+	v := &jwtVerifier{defaultScopes: cfg.DefaultScopes}
+	if len(v.defaultScopes) == 0 {
+		v.defaultScopes = []string{ScopeOrder}
+	}
+	if cfg.HMACSecret != "" {
+		v.keys = append(v.keys, verificationKey{alg: jwt.SigningMethodHS256.Alg(), key: []byte(cfg.HMACSecret)})
+	}
+	if cfg.RSAPublicKeyFile != "" {
+		key, err := readRSAPublicKey(cfg.RSAPublicKeyFile)
+		if err != nil {
+			return nil, err
+		}
+		v.keys = append(v.keys, verificationKey{alg: jwt.SigningMethodRS256.Alg(), key: key})
+	}
+	if cfg.JWKSFile != "" {
+		keys, err := readJWKS(cfg.JWKSFile)
+		if err != nil {
+			return nil, err
+		}
+		v.keys = append(v.keys, keys...)
+	}
+	if len(v.keys) == 0 {
+		return nil, errors.New("no JWT verification key configured")
+	}
+
+	v.parser = jwt.NewParser(
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg(), jwt.SigningMethodRS256.Alg()}),
+		jwt.WithIssuer(cfg.Issuer),
+		jwt.WithAudience(cfg.Audience),
+		jwt.WithExpirationRequired(),
+		jwt.WithLeeway(cfg.Leeway),
+	)
+	return v, nil
+}
+
+// VerifyToken parses the token, checks its signature with the keys matching its alg and kid
+// and validates exp, nbf, iss and aud.
+func (v *jwtVerifier) VerifyToken(token string) (*TokenClaims, error) {
+	claims := jwt.MapClaims{}
+	if _, err := v.parser.ParseWithClaims(token, claims, v.keyFor); err != nil {
+		return nil, err
+	}
+	subject, err := claims.GetSubject()
+	if err != nil {
+		return nil, err
+	}
+	if subject == "" {
+		return nil, errMissingSubject
+	}
+
+	scopes := v.defaultScopes
+	if scope, ok := claims["scope"].(string); ok {
+		scopes = strings.Fields(scope)
+	}
+	return &TokenClaims{Subject: subject, Scopes: scopes, Claims: claims}, nil
+}
+
+// keyFor returns the keys that may verify the token. Keys are only offered for the alg they
+// were configured for, so an RSA public key can never be used as an HMAC secret.
+func (v *jwtVerifier) keyFor(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+	alg := token.Method.Alg()
+
+	var keys []jwt.VerificationKey
+	for _, k := range v.keys {
+		if k.alg != alg {
+			continue
+		}
+		if kid != "" && k.kid != "" && k.kid != kid {
+			continue
+		}
+		keys = append(keys, k.key)
+	}
+	if len(keys) == 0 {
+		return nil, errNoVerificationKey
+	}
+	return jwt.VerificationKeySet{Keys: keys}, nil
+}
+
+// readRSAPublicKey reads a PEM-encoded RSA public key.
+func readRSAPublicKey(path string) (*rsa.PublicKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read RSA public key: %w", err)
+	}
+	key, err := jwt.ParseRSAPublicKeyFromPEM(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid RSA public key %s: %w", path, err)
+	}
+	return key, nil
+}
+
+// jwk is the subset of a JSON Web Key (RFC 7517) used to verify HS256 and RS256 tokens.
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Alg string `json:"alg"`
+	Use string `json:"use"`
+	N   string `json:"n"` // RSA modulus
+	E   string `json:"e"` // RSA public exponent
+	K   string `json:"k"` // Symmetric key
+}
+
+// readJWKS reads the signature keys of a JWKS file. Keys of other types are ignored.
+func readJWKS(path string) ([]verificationKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read JWKS: %w", err)
+	}
+	var set struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &set); err != nil {
+		return nil, fmt.Errorf("invalid JWKS %s: %w", path, err)
+	}
+
+	var keys []verificationKey
+	for _, k := range set.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		key, alg, err := k.verificationKey()
+		if err != nil {
+			return nil, fmt.Errorf("invalid JWKS %s: key %q: %w", path, k.Kid, err)
+		}
+		if key == nil {
+			continue
+		}
+		keys = append(keys, verificationKey{kid: k.Kid, alg: alg, key: key})
+	}
+	return keys, nil
+}
+
+// verificationKey decodes the key and returns the alg it verifies, or a nil key for
+// key types and algs that are not supported.
+func (k jwk) verificationKey() (jwt.VerificationKey, string, error) {
+	switch k.Kty {
+	case "RSA":
+		if k.Alg != "" && k.Alg != jwt.SigningMethodRS256.Alg() {
+			return nil, "", nil
+		}
+		n, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil {
+			return nil, "", fmt.Errorf("invalid modulus: %w", err)
+		}
+		e, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil {
+			return nil, "", fmt.Errorf("invalid exponent: %w", err)
+		}
+		exponent := new(big.Int).SetBytes(e)
+		if len(n) == 0 || !exponent.IsInt64() || exponent.Int64() < 3 || exponent.Int64() > 1<<31-1 {
+			return nil, "", errors.New("invalid RSA key")
+		}
+		return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(exponent.Int64())}, jwt.SigningMethodRS256.Alg(), nil
+	case "oct":
+		if k.Alg != "" && k.Alg != jwt.SigningMethodHS256.Alg() {
+			return nil, "", nil
+		}
+		secret, err := base64.RawURLEncoding.DecodeString(k.K)
+		if err != nil {
+			return nil, "", fmt.Errorf("invalid symmetric key: %w", err)
+		}
+		if len(secret) == 0 {
+			return nil, "", errors.New("empty symmetric key")
+		}
+		return secret, jwt.SigningMethodHS256.Alg(), nil
+	default:
+		return nil, "", nil
+	}
+}
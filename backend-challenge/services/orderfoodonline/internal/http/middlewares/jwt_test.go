@@ -0,0 +1,260 @@
+package middlewares
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"orderfoodonline/internal/config"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testIssuer     = "https://auth.example.com"
+	testAudience   = "orderfoodonline"
+	testHMACSecret = "test-hmac-secret"
+)
+
+// testClaims returns valid claims for the test issuer and audience.
+func testClaims() jwt.MapClaims {
+	now := time.Now()
+	return jwt.MapClaims{
+		"sub": "cust-42",
+		"iss": testIssuer,
+		"aud": testAudience,
+		"iat": now.Unix(),
+		"exp": now.Add(time.Hour).Unix(),
+	}
+}
+
+// signToken signs claims with the given method and key, setting the kid header if not empty.
+func signToken(t *testing.T, method jwt.SigningMethod, key interface{}, kid string, claims jwt.MapClaims) string {
+	t.Helper()
+	token := jwt.NewWithClaims(method, claims)
+	if kid != "" {
+		token.Header["kid"] = kid
+	}
+	signed, err := token.SignedString(key)
+	require.NoError(t, err)
+	return signed
+}
+
+// generateRSAKey generates an RSA key for the test.
+func generateRSAKey(t *testing.T) *rsa.PrivateKey {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	return key
+}
+
+// writePublicKeyPEM writes the public half of key to a PEM file and returns its path.
+func writePublicKeyPEM(t *testing.T, key *rsa.PrivateKey) string {
+	t.Helper()
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	require.NoError(t, err)
+	path := filepath.Join(t.TempDir(), "public.pem")
+	require.NoError(t, os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), 0o600))
+	return path
+}
+
+// writeJWKS writes a JWKS file holding the public keys by kid and returns its path.
+func writeJWKS(t *testing.T, keys map[string]*rsa.PrivateKey) string {
+	t.Helper()
+	var set struct {
+		Keys []map[string]string `json:"keys"`
+	}
+	for kid, key := range keys {
+		set.Keys = append(set.Keys, map[string]string{
+			"kty": "RSA",
+			"kid": kid,
+			"alg": "RS256",
+			"use": "sig",
+			"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		})
+	}
+	data, err := json.Marshal(set)
+	require.NoError(t, err)
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	require.NoError(t, os.WriteFile(path, data, 0o600))
+	return path
+}
+
+func TestNewJWTVerifier_NoKeys(t *testing.T) {
+	// When: Creating a verifier without any key
+	verifier, err := NewJWTVerifier(&config.JWTConfig{Enabled: true, Issuer: testIssuer, Audience: testAudience})
+
+	// Then: It should fail
+	assert.Error(t, err)
+	assert.Nil(t, verifier)
+}
+
+func TestNewJWTVerifier_InvalidFiles(t *testing.T) {
+	invalid := filepath.Join(t.TempDir(), "invalid")
+	require.NoError(t, os.WriteFile(invalid, []byte("not a key"), 0o600))
+
+	tests := []struct {
+		name string
+		cfg  *config.JWTConfig
+	}{
+		{name: "missing public key", cfg: &config.JWTConfig{RSAPublicKeyFile: filepath.Join(t.TempDir(), "missing.pem")}},
+		{name: "invalid public key", cfg: &config.JWTConfig{RSAPublicKeyFile: invalid}},
+		{name: "missing JWKS", cfg: &config.JWTConfig{JWKSFile: filepath.Join(t.TempDir(), "missing.json")}},
+		{name: "invalid JWKS", cfg: &config.JWTConfig{JWKSFile: invalid}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// When: Creating a verifier from an unreadable key file
+			verifier, err := NewJWTVerifier(tt.cfg)
+
+			// Then: It should fail
+			assert.Error(t, err)
+			assert.Nil(t, verifier)
+		})
+	}
+}
+
+func TestJWTVerifier_VerifyToken_HS256(t *testing.T) {
+	// Given: A verifier holding an HMAC secret
+	verifier, err := NewJWTVerifier(&config.JWTConfig{
+		Enabled: true, Issuer: testIssuer, Audience: testAudience, HMACSecret: testHMACSecret,
+	})
+	require.NoError(t, err)
+
+	claims := testClaims()
+	claims["scope"] = "order admin"
+	claims["name"] = "Jane"
+
+	// When: Verifying a token signed with the secret
+	verified, err := verifier.VerifyToken(signToken(t, jwt.SigningMethodHS256, []byte(testHMACSecret), "", claims))
+
+	// Then: The subject, scopes and claims should be returned
+	require.NoError(t, err)
+	assert.Equal(t, "cust-42", verified.Subject)
+	assert.Equal(t, []string{ScopeOrder, ScopeAdmin}, verified.Scopes)
+	assert.Equal(t, "Jane", verified.Claims["name"])
+}
+
+func TestJWTVerifier_VerifyToken_RS256(t *testing.T) {
+	// Given: A verifier holding a PEM public key and a JWKS of two keys
+	pemKey := generateRSAKey(t)
+	first, second := generateRSAKey(t), generateRSAKey(t)
+	verifier, err := NewJWTVerifier(&config.JWTConfig{
+		Enabled:          true,
+		Issuer:           testIssuer,
+		Audience:         testAudience,
+		RSAPublicKeyFile: writePublicKeyPEM(t, pemKey),
+		JWKSFile:         writeJWKS(t, map[string]*rsa.PrivateKey{"first": first, "second": second}),
+		DefaultScopes:    []string{ScopeOrder},
+	})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name  string
+		key   *rsa.PrivateKey
+		kid   string
+		valid bool
+	}{
+		{name: "PEM key without kid", key: pemKey, valid: true},
+		{name: "JWKS key by kid", key: second, kid: "second", valid: true},
+		{name: "JWKS key without kid", key: first, valid: true},
+		{name: "JWKS key under another kid", key: first, kid: "second", valid: false},
+		{name: "unknown key", key: generateRSAKey(t), valid: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// When: Verifying a token signed with the key
+			verified, err := verifier.VerifyToken(signToken(t, jwt.SigningMethodRS256, tt.key, tt.kid, testClaims()))
+
+			// Then: Only tokens signed with a configured key should be accepted
+			if !tt.valid {
+				assert.Error(t, err)
+				assert.Nil(t, verified)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "cust-42", verified.Subject)
+			assert.Equal(t, []string{ScopeOrder}, verified.Scopes)
+		})
+	}
+}
+
+func TestJWTVerifier_VerifyToken_Claims(t *testing.T) {
+	// Given: A verifier holding an HMAC secret and an RSA key
+	rsaKey := generateRSAKey(t)
+	verifier, err := NewJWTVerifier(&config.JWTConfig{
+		Enabled:          true,
+		Issuer:           testIssuer,
+		Audience:         testAudience,
+		HMACSecret:       testHMACSecret,
+		RSAPublicKeyFile: writePublicKeyPEM(t, rsaKey),
+	})
+	require.NoError(t, err)
+	publicPEM, err := os.ReadFile(writePublicKeyPEM(t, rsaKey))
+	require.NoError(t, err)
+
+	hs256 := func(change func(jwt.MapClaims)) string {
+		claims := testClaims()
+		change(claims)
+		return signToken(t, jwt.SigningMethodHS256, []byte(testHMACSecret), "", claims)
+	}
+
+	tests := []struct {
+		name    string
+		token   string
+		expired bool
+	}{
+		{name: "expired", token: hs256(func(c jwt.MapClaims) { c["exp"] = time.Now().Add(-time.Hour).Unix() }), expired: true},
+		{name: "missing exp", token: hs256(func(c jwt.MapClaims) { delete(c, "exp") })},
+		{name: "not yet valid", token: hs256(func(c jwt.MapClaims) { c["nbf"] = time.Now().Add(time.Hour).Unix() })},
+		{name: "wrong issuer", token: hs256(func(c jwt.MapClaims) { c["iss"] = "https://other.example.com" })},
+		{name: "wrong audience", token: hs256(func(c jwt.MapClaims) { c["aud"] = "other" })},
+		{name: "missing subject", token: hs256(func(c jwt.MapClaims) { delete(c, "sub") })},
+		{name: "wrong secret", token: signToken(t, jwt.SigningMethodHS256, []byte("other-secret"), "", testClaims())},
+		{name: "HS256 signed with the RSA public key", token: signToken(t, jwt.SigningMethodHS256, publicPEM, "", testClaims())},
+		{name: "unsigned", token: signToken(t, jwt.SigningMethodNone, jwt.UnsafeAllowNoneSignatureType, "", testClaims())},
+		{name: "malformed", token: "not.a.token"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// When: Verifying the token
+			verified, err := verifier.VerifyToken(tt.token)
+
+			// Then: It should be rejected
+			assert.Error(t, err)
+			assert.Nil(t, verified)
+			assert.Equal(t, tt.expired, err != nil && errors.Is(err, jwt.ErrTokenExpired))
+		})
+	}
+}
+
+func TestJWTVerifier_VerifyToken_AudienceList(t *testing.T) {
+	// Given: A verifier holding an HMAC secret
+	verifier, err := NewJWTVerifier(&config.JWTConfig{
+		Enabled: true, Issuer: testIssuer, Audience: testAudience, HMACSecret: testHMACSecret,
+	})
+	require.NoError(t, err)
+	claims := testClaims()
+	claims["aud"] = []string{"other", testAudience}
+
+	// When: Verifying a token issued to several audiences
+	verified, err := verifier.VerifyToken(signToken(t, jwt.SigningMethodHS256, []byte(testHMACSecret), "", claims))
+
+	// Then: It should be accepted
+	require.NoError(t, err)
+	assert.Equal(t, "cust-42", verified.Subject)
+}
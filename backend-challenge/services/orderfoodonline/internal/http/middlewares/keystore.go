@@ -0,0 +1,180 @@
+package middlewares
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"orderfoodonline/internal/config"
+	"orderfoodonline/internal/repository"
+	"orderfoodonline/internal/repository/models"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultKeyCacheTTL is how long key lookups are cached when no TTL is configured.
+	DefaultKeyCacheTTL = 30 * time.Second
+
+	// maxCachedKeys bounds the number of lookups, including misses, a cachedKeyStore holds.
+	maxCachedKeys = 10000
+)
+
+// HashAPIKey returns the hex-encoded SHA-256 hash under which a key's secret is stored.
+func HashAPIKey(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+// staticKey is a configured key together with the hash of its secret.
+type staticKey struct {
+	hash [sha256.Size]byte
+	key  models.APIKey
+}
+
+// staticKeyStore holds a fixed set of keys read from the configuration.
+type staticKeyStore struct {
+	keys []staticKey
+}
+
+// NewStaticKeyStore creates a KeyStore holding the keys of cfg. The api_key is named "default"
+// and granted the ScopeOrder role, the admin_api_key is named "admin" and granted both roles,
+// and the listed keys are taken as configured. Keys with an empty secret are ignored.
+func NewStaticKeyStore(cfg *config.AuthConfig) KeyStore {
+	store := &staticKeyStore{}
+	if cfg == nil {
+		return store
+	}
+	store.add(config.APIKeyConfig{Name: "default", Key: cfg.APIKey, Scopes: []string{ScopeOrder}})
+	store.add(config.APIKeyConfig{Name: "admin", Key: cfg.AdminAPIKey, Scopes: []string{ScopeOrder, ScopeAdmin}})
+	for _, key := range cfg.Keys {
+		store.add(key)
+	}
+	return store
+}
+
+func (s *staticKeyStore) add(cfg config.APIKeyConfig) {
+	secret := strings.TrimSpace(cfg.Key)
+	if secret == "" {
+		return
+	}
+	s.keys = append(s.keys, staticKey{
+		hash: sha256.Sum256([]byte(secret)),
+		key: models.APIKey{
+			Name:       cfg.Name,
+			KeyHash:    HashAPIKey(secret),
+			Scopes:     cfg.Scopes,
+			ExpiresAt:  cfg.ExpiresAt,
+			Revoked:    cfg.Revoked,
+			CustomerID: strings.TrimSpace(cfg.CustomerID),
+		},
+	})
+}
+
+// LookupAPIKey compares the hash of the secret with every configured key in constant time,
+// so neither the position nor the prefix of a matching key can be learnt from the response time.
+func (s *staticKeyStore) LookupAPIKey(_ context.Context, secret string) (*models.APIKey, error) {
+	hash := sha256.Sum256([]byte(secret))
+	var found *models.APIKey
+	for i := range s.keys {
+		if subtle.ConstantTimeCompare(hash[:], s.keys[i].hash[:]) == 1 && found == nil {
+			key := s.keys[i].key
+			found = &key
+		}
+	}
+	return found, nil
+}
+
+// mongoKeyStore looks keys up by the hash of their secret in the api_keys collection.
+type mongoKeyStore struct {
+	repo repository.APIKeyRepository
+}
+
+// NewMongoKeyStore creates a KeyStore reading hashed keys through repo.
+// Lookups hit the database every time; wrap the store with NewCachedKeyStore.
+func NewMongoKeyStore(repo repository.APIKeyRepository) KeyStore {
+	return &mongoKeyStore{repo: repo}
+}
+
+// LookupAPIKey finds the key stored under the hash of the secret. Only the hash is sent to
+// the database, and the stored hash is compared again in constant time.
+func (s *mongoKeyStore) LookupAPIKey(ctx context.Context, secret string) (*models.APIKey, error) {
+	hash := HashAPIKey(secret)
+	key, err := s.repo.FindAPIKeyByHash(ctx, hash)
+	if err != nil || key == nil {
+		return nil, err
+	}
+	if subtle.ConstantTimeCompare([]byte(key.KeyHash), []byte(hash)) != 1 {
+		return nil, nil
+	}
+	return key, nil
+}
+
+// cachedKey is the outcome of a lookup and when it stops being served from the cache.
+type cachedKey struct {
+	key       *models.APIKey // nil for a secret that matched no key
+	expiresAt time.Time
+}
+
+// cachedKeyStore serves recent lookups of another KeyStore from memory.
+type cachedKeyStore struct {
+	store   KeyStore
+	ttl     time.Duration
+	now     func() time.Time
+	mu      sync.Mutex
+	entries map[[sha256.Size]byte]cachedKey
+}
+
+// NewCachedKeyStore creates a KeyStore caching the lookups of store for ttl, so that revoking
+// a key takes at most ttl to be noticed. Unknown secrets are cached too, so that repeated
+// attempts with a bad key do not reach the store. A non-positive ttl falls back to DefaultKeyCacheTTL.
+func NewCachedKeyStore(store KeyStore, ttl time.Duration) KeyStore {
+	if ttl <= 0 {
+		ttl = DefaultKeyCacheTTL
+	}
+	return &cachedKeyStore{
+		store:   store,
+		ttl:     ttl,
+		now:     time.Now,
+		entries: make(map[[sha256.Size]byte]cachedKey),
+	}
+}
+
+// LookupAPIKey returns the cached lookup of the secret, or looks it up in the underlying store.
+// Secrets are cached by their hash; lookups that fail with an error are not cached.
+func (s *cachedKeyStore) LookupAPIKey(ctx context.Context, secret string) (*models.APIKey, error) {
+	hash := sha256.Sum256([]byte(secret))
+	now := s.now()
+
+	s.mu.Lock()
+	entry, ok := s.entries[hash]
+	s.mu.Unlock()
+	if ok && now.Before(entry.expiresAt) {
+		return entry.key, nil
+	}
+
+	key, err := s.store.LookupAPIKey(ctx, secret)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.entries) >= maxCachedKeys {
+		s.evictExpired(now)
+	}
+	if len(s.entries) < maxCachedKeys {
+		s.entries[hash] = cachedKey{key: key, expiresAt: now.Add(s.ttl)}
+	}
+	return key, nil
+}
+
+// evictExpired removes the expired entries. The caller must hold mu.
+func (s *cachedKeyStore) evictExpired(now time.Time) {
+	for hash, entry := range s.entries {
+		if !now.Before(entry.expiresAt) {
+			delete(s.entries, hash)
+		}
+	}
+}
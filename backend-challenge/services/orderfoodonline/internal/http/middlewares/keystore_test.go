@@ -0,0 +1,187 @@
+package middlewares
+
+import (
+	"context"
+	"errors"
+	"orderfoodonline/internal/config"
+	repoMocks "orderfoodonline/internal/repository/mocks"
+	"orderfoodonline/internal/repository/models"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+// keyStoreFunc adapts a function to the KeyStore interface.
+type keyStoreFunc func(ctx context.Context, secret string) (*models.APIKey, error)
+
+func (f keyStoreFunc) LookupAPIKey(ctx context.Context, secret string) (*models.APIKey, error) {
+	return f(ctx, secret)
+}
+
+// countingKeyStore returns a KeyStore answering with lookup and the lookups made per secret.
+func countingKeyStore(lookup func(secret string) (*models.APIKey, error)) (KeyStore, map[string]int) {
+	calls := map[string]int{}
+	return keyStoreFunc(func(_ context.Context, secret string) (*models.APIKey, error) {
+		calls[secret]++
+		return lookup(secret)
+	}), calls
+}
+
+func TestHashAPIKey(t *testing.T) {
+	// When/Then: The hash should be the hex-encoded SHA-256 of the secret
+	assert.Equal(t, "2bb80d537b1da3e38bd30361aa855686bde0eacd7162fef6a25fe97bf527a25b", HashAPIKey("secret"))
+}
+
+func TestStaticKeyStore_LookupAPIKey(t *testing.T) {
+	// Given: A store holding the default, admin and listed keys
+	store := NewStaticKeyStore(&config.AuthConfig{
+		APIKey:      "client-key",
+		AdminAPIKey: "admin-key",
+		Keys: []config.APIKeyConfig{
+			{Name: "partner", Key: "partner-key", Scopes: []string{ScopeOrder}},
+			{Name: "blank", Key: "  ", Scopes: []string{ScopeAdmin}},
+		},
+	})
+
+	tests := []struct {
+		name         string
+		secret       string
+		expectedName string
+		scopes       []string
+	}{
+		{name: "default key", secret: "client-key", expectedName: "default", scopes: []string{ScopeOrder}},
+		{name: "admin key", secret: "admin-key", expectedName: "admin", scopes: []string{ScopeOrder, ScopeAdmin}},
+		{name: "listed key", secret: "partner-key", expectedName: "partner", scopes: []string{ScopeOrder}},
+		{name: "unknown key", secret: "other-key"},
+		{name: "empty key is never accepted", secret: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// When: Looking up the secret
+			key, err := store.LookupAPIKey(context.Background(), tt.secret)
+
+			// Then: The matching key should be returned with its hash
+			assert.NoError(t, err)
+			if tt.expectedName == "" {
+				assert.Nil(t, key)
+				return
+			}
+			if assert.NotNil(t, key) {
+				assert.Equal(t, tt.expectedName, key.Name)
+				assert.Equal(t, tt.scopes, key.Scopes)
+				assert.Equal(t, HashAPIKey(tt.secret), key.KeyHash)
+			}
+		})
+	}
+}
+
+func TestStaticKeyStore_NilConfig(t *testing.T) {
+	// Given: A store created without configuration
+	store := NewStaticKeyStore(nil)
+
+	// When: Looking up the former default key
+	key, err := store.LookupAPIKey(context.Background(), "apitest")
+
+	// Then: No key should be accepted
+	assert.NoError(t, err)
+	assert.Nil(t, key)
+}
+
+func TestMongoKeyStore_LookupAPIKey(t *testing.T) {
+	storedErr := errors.New("database error")
+
+	tests := []struct {
+		name        string
+		found       *models.APIKey
+		findErr     error
+		expectedKey bool
+		expectedErr error
+	}{
+		{name: "stored key", found: &models.APIKey{Name: "partner", KeyHash: HashAPIKey("partner-key")}, expectedKey: true},
+		{name: "unknown key", found: nil},
+		{name: "stored hash mismatch", found: &models.APIKey{Name: "partner", KeyHash: HashAPIKey("other-key")}},
+		{name: "database error", findErr: storedErr, expectedErr: storedErr},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given: A repository looked up by the hash of the secret
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			mockRepo := repoMocks.NewMockAPIKeyRepository(ctrl)
+			mockRepo.EXPECT().FindAPIKeyByHash(gomock.Any(), HashAPIKey("partner-key")).Return(tt.found, tt.findErr)
+
+			// When: Looking up the secret
+			key, err := NewMongoKeyStore(mockRepo).LookupAPIKey(context.Background(), "partner-key")
+
+			// Then: Only a key whose stored hash matches should be returned
+			assert.Equal(t, tt.expectedErr, err)
+			if tt.expectedKey {
+				assert.Equal(t, tt.found, key)
+			} else {
+				assert.Nil(t, key)
+			}
+		})
+	}
+}
+
+func TestCachedKeyStore_LookupAPIKey(t *testing.T) {
+	// Given: A cached store in front of a store holding one key
+	key := &models.APIKey{Name: "partner", Scopes: []string{ScopeOrder}}
+	backing, calls := countingKeyStore(func(secret string) (*models.APIKey, error) {
+		if secret == "partner-key" {
+			return key, nil
+		}
+		return nil, nil
+	})
+
+	store := NewCachedKeyStore(backing, time.Minute).(*cachedKeyStore)
+	now := time.Now()
+	store.now = func() time.Time { return now }
+
+	// When: Looking the keys up repeatedly within the TTL
+	for i := 0; i < 3; i++ {
+		found, err := store.LookupAPIKey(context.Background(), "partner-key")
+		assert.NoError(t, err)
+		assert.Equal(t, key, found)
+
+		found, err = store.LookupAPIKey(context.Background(), "other-key")
+		assert.NoError(t, err)
+		assert.Nil(t, found)
+	}
+
+	// Then: Hits and misses should be served from the cache until the TTL has passed
+	assert.Equal(t, map[string]int{"partner-key": 1, "other-key": 1}, calls)
+	now = now.Add(time.Minute)
+	found, err := store.LookupAPIKey(context.Background(), "partner-key")
+	assert.NoError(t, err)
+	assert.Equal(t, key, found)
+	assert.Equal(t, 2, calls["partner-key"])
+}
+
+func TestCachedKeyStore_DoesNotCacheErrors(t *testing.T) {
+	// Given: A cached store in front of a store that fails once
+	key := &models.APIKey{Name: "partner"}
+	failed := false
+	backing, calls := countingKeyStore(func(string) (*models.APIKey, error) {
+		if !failed {
+			failed = true
+			return nil, errors.New("database error")
+		}
+		return key, nil
+	})
+	store := NewCachedKeyStore(backing, 0)
+
+	// When: Looking the key up after the failure
+	_, err := store.LookupAPIKey(context.Background(), "partner-key")
+	assert.Error(t, err)
+	found, err := store.LookupAPIKey(context.Background(), "partner-key")
+
+	// Then: The store should have been asked again
+	assert.NoError(t, err)
+	assert.Equal(t, key, found)
+	assert.Equal(t, 2, calls["partner-key"])
+}
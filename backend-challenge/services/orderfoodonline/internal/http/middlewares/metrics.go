@@ -18,10 +18,13 @@ func NewMetricsMiddleware() MetricsMiddleware {
 }
 
 // RecordMetrics is a Gin middleware that records HTTP request metrics.
-// It captures request method, endpoint, status code, and duration for each HTTP request.
+// It captures request method, endpoint, status code, duration, and request and response sizes
+// for each HTTP request, and counts the requests in flight.
 func (m *metricsMiddleware) RecordMetrics() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		start := time.Now()
+		metrics.IncHTTPRequestsInFlight()
+		defer metrics.DecHTTPRequestsInFlight()
 
 		// Process request
 		c.Next()
@@ -29,19 +32,29 @@ func (m *metricsMiddleware) RecordMetrics() gin.HandlerFunc {
 		// Calculate duration
 		duration := time.Since(start).Seconds()
 
-		// Extract endpoint (remove query parameters)
+		// Label requests by their route pattern; requests matching no route share a single label
 		endpoint := c.FullPath()
 		if endpoint == "" {
-			endpoint = c.Request.URL.Path
+			endpoint = metrics.UnmatchedEndpoint
+		}
+
+		// Chunked request bodies and responses without a body are recorded with a size of 0
+		var requestSize, responseSize float64
+		if c.Request.ContentLength > 0 {
+			requestSize = float64(c.Request.ContentLength)
+		}
+		if c.Writer.Size() > 0 {
+			responseSize = float64(c.Writer.Size())
 		}
 
 		// Record metrics
-		statusCode := string(rune(c.Writer.Status()))
 		metrics.RecordHTTPRequest(
 			c.Request.Method,
 			endpoint,
-			statusCode,
+			c.Writer.Status(),
 			duration,
+			requestSize,
+			responseSize,
 		)
 	}
 }
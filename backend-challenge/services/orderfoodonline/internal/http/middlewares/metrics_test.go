@@ -4,13 +4,27 @@ package middlewares
 import (
 	"net/http"
 	"net/http/httptest"
+	"orderfoodonline/internal/metrics"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	dto "github.com/prometheus/client_model/go"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
+// histogramSample returns the sample count and sum observed by a histogram.
+func histogramSample(t *testing.T, observer prometheus.Observer) (uint64, float64) {
+	t.Helper()
+	var m dto.Metric
+	require.NoError(t, observer.(prometheus.Histogram).Write(&m))
+	return m.GetHistogram().GetSampleCount(), m.GetHistogram().GetSampleSum()
+}
+
 func TestNewMetricsMiddleware(t *testing.T) {
 	// When: Creating a new metrics middleware
 	metricsMiddleware := NewMetricsMiddleware()
@@ -247,3 +261,67 @@ func TestMetricsMiddleware_RecordMetrics_EmptyPath(t *testing.T) {
 	assert.Equal(t, http.StatusOK, w.Code)
 	assert.Contains(t, w.Body.String(), "root")
 }
+
+func TestMetricsMiddleware_RecordMetrics_Labels(t *testing.T) {
+	// Given: A router recording metrics for a parameterised route
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(NewMetricsMiddleware().RecordMetrics())
+	var inFlight float64
+	router.POST("/api/product/:productId", func(c *gin.Context) {
+		inFlight = testutil.ToFloat64(metrics.HTTPRequestsInFlight)
+		c.String(http.StatusCreated, "created")
+	})
+
+	created := metrics.HTTPRequestTotal.WithLabelValues("POST", "/api/product/:productId", "201", "2xx")
+	unmatched := metrics.HTTPRequestTotal.WithLabelValues("GET", metrics.UnmatchedEndpoint, "404", "4xx")
+	createdBefore, unmatchedBefore := testutil.ToFloat64(created), testutil.ToFloat64(unmatched)
+	requestCount, requestSum := histogramSample(t, metrics.HTTPRequestSize.WithLabelValues("POST", "/api/product/:productId"))
+	responseCount, responseSum := histogramSample(t, metrics.HTTPResponseSize.WithLabelValues("POST", "/api/product/:productId"))
+	inFlightBefore := testutil.ToFloat64(metrics.HTTPRequestsInFlight)
+
+	// When: Serving a request for the route and requests for two unknown paths
+	req, _ := http.NewRequest("POST", "/api/product/123", strings.NewReader(`{"name":"Waffle"}`))
+	router.ServeHTTP(httptest.NewRecorder(), req)
+	for _, path := range []string{"/wp-admin", "/api/unknown/123"} {
+		req, _ = http.NewRequest("GET", path, nil)
+		router.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	// Then: The route should be labelled with its pattern and numeric status code
+	assert.Equal(t, createdBefore+1, testutil.ToFloat64(created))
+	assert.Equal(t, inFlightBefore+1, inFlight)
+	assert.Equal(t, inFlightBefore, testutil.ToFloat64(metrics.HTTPRequestsInFlight))
+
+	// And: The request and response sizes should be observed
+	count, sum := histogramSample(t, metrics.HTTPRequestSize.WithLabelValues("POST", "/api/product/:productId"))
+	assert.Equal(t, requestCount+1, count)
+	assert.Equal(t, requestSum+17, sum)
+	count, sum = histogramSample(t, metrics.HTTPResponseSize.WithLabelValues("POST", "/api/product/:productId"))
+	assert.Equal(t, responseCount+1, count)
+	assert.Equal(t, responseSum+7, sum)
+
+	// And: Unknown paths should share the unmatched endpoint label
+	assert.Equal(t, unmatchedBefore+2, testutil.ToFloat64(unmatched))
+}
+
+func TestStatusClass(t *testing.T) {
+	tests := []struct {
+		statusCode int
+		expected   string
+	}{
+		{http.StatusOK, "2xx"},
+		{http.StatusNoContent, "2xx"},
+		{http.StatusFound, "3xx"},
+		{http.StatusTooManyRequests, "4xx"},
+		{http.StatusServiceUnavailable, "5xx"},
+		{0, "unknown"},
+	}
+
+	for _, tt := range tests {
+		t.Run(http.StatusText(tt.statusCode), func(t *testing.T) {
+			// When/Then: The status code should be grouped by its first digit
+			assert.Equal(t, tt.expected, metrics.StatusClass(tt.statusCode))
+		})
+	}
+}
@@ -10,7 +10,11 @@
 package mocks
 
 import (
+	context "context"
+	middlewares "orderfoodonline/internal/http/middlewares"
+	models "orderfoodonline/internal/repository/models"
 	reflect "reflect"
+	time "time"
 
 	gin "github.com/gin-gonic/gin"
 	gomock "go.uber.org/mock/gomock"
@@ -55,17 +59,99 @@ func (mr *MockAuthMiddlewareMockRecorder) Authenticate() *gomock.Call {
 }
 
 // Authorize mocks base method.
-func (m *MockAuthMiddleware) Authorize() gin.HandlerFunc {
+func (m *MockAuthMiddleware) Authorize(scopes ...string) gin.HandlerFunc {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Authorize")
+	varargs := []any{}
+	for _, a := range scopes {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Authorize", varargs...)
 	ret0, _ := ret[0].(gin.HandlerFunc)
 	return ret0
 }
 
 // Authorize indicates an expected call of Authorize.
-func (mr *MockAuthMiddlewareMockRecorder) Authorize() *gomock.Call {
+func (mr *MockAuthMiddlewareMockRecorder) Authorize(scopes ...any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Authorize", reflect.TypeOf((*MockAuthMiddleware)(nil).Authorize))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Authorize", reflect.TypeOf((*MockAuthMiddleware)(nil).Authorize), scopes...)
+}
+
+// MockKeyStore is a mock of KeyStore interface.
+type MockKeyStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockKeyStoreMockRecorder
+	isgomock struct{}
+}
+
+// MockKeyStoreMockRecorder is the mock recorder for MockKeyStore.
+type MockKeyStoreMockRecorder struct {
+	mock *MockKeyStore
+}
+
+// NewMockKeyStore creates a new mock instance.
+func NewMockKeyStore(ctrl *gomock.Controller) *MockKeyStore {
+	mock := &MockKeyStore{ctrl: ctrl}
+	mock.recorder = &MockKeyStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockKeyStore) EXPECT() *MockKeyStoreMockRecorder {
+	return m.recorder
+}
+
+// LookupAPIKey mocks base method.
+func (m *MockKeyStore) LookupAPIKey(ctx context.Context, secret string) (*models.APIKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LookupAPIKey", ctx, secret)
+	ret0, _ := ret[0].(*models.APIKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LookupAPIKey indicates an expected call of LookupAPIKey.
+func (mr *MockKeyStoreMockRecorder) LookupAPIKey(ctx, secret any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LookupAPIKey", reflect.TypeOf((*MockKeyStore)(nil).LookupAPIKey), ctx, secret)
+}
+
+// MockTokenVerifier is a mock of TokenVerifier interface.
+type MockTokenVerifier struct {
+	ctrl     *gomock.Controller
+	recorder *MockTokenVerifierMockRecorder
+	isgomock struct{}
+}
+
+// MockTokenVerifierMockRecorder is the mock recorder for MockTokenVerifier.
+type MockTokenVerifierMockRecorder struct {
+	mock *MockTokenVerifier
+}
+
+// NewMockTokenVerifier creates a new mock instance.
+func NewMockTokenVerifier(ctrl *gomock.Controller) *MockTokenVerifier {
+	mock := &MockTokenVerifier{ctrl: ctrl}
+	mock.recorder = &MockTokenVerifierMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTokenVerifier) EXPECT() *MockTokenVerifierMockRecorder {
+	return m.recorder
+}
+
+// VerifyToken mocks base method.
+func (m *MockTokenVerifier) VerifyToken(token string) (*middlewares.TokenClaims, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerifyToken", token)
+	ret0, _ := ret[0].(*middlewares.TokenClaims)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// VerifyToken indicates an expected call of VerifyToken.
+func (mr *MockTokenVerifierMockRecorder) VerifyToken(token any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyToken", reflect.TypeOf((*MockTokenVerifier)(nil).VerifyToken), token)
 }
 
 // MockMetricsMiddleware is a mock of MetricsMiddleware interface.
@@ -105,3 +191,133 @@ func (mr *MockMetricsMiddlewareMockRecorder) RecordMetrics() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordMetrics", reflect.TypeOf((*MockMetricsMiddleware)(nil).RecordMetrics))
 }
+
+// MockRateLimitMiddleware is a mock of RateLimitMiddleware interface.
+type MockRateLimitMiddleware struct {
+	ctrl     *gomock.Controller
+	recorder *MockRateLimitMiddlewareMockRecorder
+	isgomock struct{}
+}
+
+// MockRateLimitMiddlewareMockRecorder is the mock recorder for MockRateLimitMiddleware.
+type MockRateLimitMiddlewareMockRecorder struct {
+	mock *MockRateLimitMiddleware
+}
+
+// NewMockRateLimitMiddleware creates a new mock instance.
+func NewMockRateLimitMiddleware(ctrl *gomock.Controller) *MockRateLimitMiddleware {
+	mock := &MockRateLimitMiddleware{ctrl: ctrl}
+	mock.recorder = &MockRateLimitMiddlewareMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRateLimitMiddleware) EXPECT() *MockRateLimitMiddlewareMockRecorder {
+	return m.recorder
+}
+
+// Limit mocks base method.
+func (m *MockRateLimitMiddleware) Limit() gin.HandlerFunc {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Limit")
+	ret0, _ := ret[0].(gin.HandlerFunc)
+	return ret0
+}
+
+// Limit indicates an expected call of Limit.
+func (mr *MockRateLimitMiddlewareMockRecorder) Limit() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Limit", reflect.TypeOf((*MockRateLimitMiddleware)(nil).Limit))
+}
+
+// LimitAuthentication mocks base method.
+func (m *MockRateLimitMiddleware) LimitAuthentication() gin.HandlerFunc {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LimitAuthentication")
+	ret0, _ := ret[0].(gin.HandlerFunc)
+	return ret0
+}
+
+// LimitAuthentication indicates an expected call of LimitAuthentication.
+func (mr *MockRateLimitMiddlewareMockRecorder) LimitAuthentication() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LimitAuthentication", reflect.TypeOf((*MockRateLimitMiddleware)(nil).LimitAuthentication))
+}
+
+// MockRateLimitStore is a mock of RateLimitStore interface.
+type MockRateLimitStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockRateLimitStoreMockRecorder
+	isgomock struct{}
+}
+
+// MockRateLimitStoreMockRecorder is the mock recorder for MockRateLimitStore.
+type MockRateLimitStoreMockRecorder struct {
+	mock *MockRateLimitStore
+}
+
+// NewMockRateLimitStore creates a new mock instance.
+func NewMockRateLimitStore(ctrl *gomock.Controller) *MockRateLimitStore {
+	mock := &MockRateLimitStore{ctrl: ctrl}
+	mock.recorder = &MockRateLimitStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRateLimitStore) EXPECT() *MockRateLimitStoreMockRecorder {
+	return m.recorder
+}
+
+// Increment mocks base method.
+func (m *MockRateLimitStore) Increment(ctx context.Context, key string, window time.Duration) (int64, time.Time, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Increment", ctx, key, window)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(time.Time)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Increment indicates an expected call of Increment.
+func (mr *MockRateLimitStoreMockRecorder) Increment(ctx, key, window any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Increment", reflect.TypeOf((*MockRateLimitStore)(nil).Increment), ctx, key, window)
+}
+
+// MockIdempotencyMiddleware is a mock of IdempotencyMiddleware interface.
+type MockIdempotencyMiddleware struct {
+	ctrl     *gomock.Controller
+	recorder *MockIdempotencyMiddlewareMockRecorder
+	isgomock struct{}
+}
+
+// MockIdempotencyMiddlewareMockRecorder is the mock recorder for MockIdempotencyMiddleware.
+type MockIdempotencyMiddlewareMockRecorder struct {
+	mock *MockIdempotencyMiddleware
+}
+
+// NewMockIdempotencyMiddleware creates a new mock instance.
+func NewMockIdempotencyMiddleware(ctrl *gomock.Controller) *MockIdempotencyMiddleware {
+	mock := &MockIdempotencyMiddleware{ctrl: ctrl}
+	mock.recorder = &MockIdempotencyMiddlewareMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIdempotencyMiddleware) EXPECT() *MockIdempotencyMiddlewareMockRecorder {
+	return m.recorder
+}
+
+// Idempotent mocks base method.
+func (m *MockIdempotencyMiddleware) Idempotent() gin.HandlerFunc {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Idempotent")
+	ret0, _ := ret[0].(gin.HandlerFunc)
+	return ret0
+}
+
+// Idempotent indicates an expected call of Idempotent.
+func (mr *MockIdempotencyMiddlewareMockRecorder) Idempotent() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Idempotent", reflect.TypeOf((*MockIdempotencyMiddleware)(nil).Idempotent))
+}
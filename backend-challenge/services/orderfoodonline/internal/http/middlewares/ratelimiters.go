@@ -1,26 +1,190 @@
 package middlewares
 
 import (
+	"context"
+	"library/logger"
+	"math"
+	"net/http"
+	"orderfoodonline/internal/config"
+	"orderfoodonline/internal/repository"
+	"strconv"
+	"sync"
 	"time"
 
-	ratelimit "github.com/JGLTechnologies/gin-rate-limit"
 	"github.com/gin-gonic/gin"
 )
 
-// RateLimiterHandler returns a Gin middleware handler for rate limiting.
-func RateLimiterHandler() gin.HandlerFunc {
-	store := ratelimit.InMemoryStore(&ratelimit.InMemoryOptions{
-		Rate:  5 * time.Minute,
-		Limit: 300,
-	})
-
-	rateLimiter := ratelimit.RateLimiter(store, &ratelimit.Options{
-		ErrorHandler: func(c *gin.Context, info ratelimit.Info) {
-			c.String(429, "Too many requests. Try again in "+time.Until(info.ResetTime).String())
-		},
-		KeyFunc: func(c *gin.Context) string {
-			return c.ClientIP()
-		},
-	})
-	return rateLimiter
+const (
+	// defaultRateLimitPolicy names the policy of the routes without an override in store keys.
+	defaultRateLimitPolicy = "default"
+	// authenticationRateLimitPolicy names the policy counted before authentication in store keys.
+	authenticationRateLimitPolicy = "authentication"
+
+	// rateLimitSweepInterval is how often a memoryRateLimitStore drops the windows that have ended.
+	rateLimitSweepInterval = time.Minute
+)
+
+// rateLimiter limits requests with the policy configured for their route.
+type rateLimiter struct {
+	defaultPolicy config.RateLimitPolicy
+	routes        map[string]config.RateLimitPolicy // by "METHOD /api/path"
+	authPolicy    config.RateLimitPolicy            // counted per client IP before authentication
+	store         RateLimitStore
+	logger        logger.ILogger
+	now           func() time.Time
+}
+
+// NewRateLimitMiddleware creates a new instance of rate limit middleware which implements RateLimitMiddleware.
+// Requests are counted in store against the policy of their route in cfg, or the default policy.
+func NewRateLimitMiddleware(cfg *config.RateLimitConfig, store RateLimitStore, logger logger.ILogger) RateLimitMiddleware {
+	r := &rateLimiter{routes: map[string]config.RateLimitPolicy{}, store: store, logger: logger, now: time.Now}
+	if cfg != nil {
+		r.defaultPolicy = cfg.Default
+		r.authPolicy = cfg.Authentication
+		r.authPolicy.Key = config.RateLimitKeyIP
+		for route, policy := range cfg.Routes {
+			r.routes[route] = policy
+		}
+	}
+	return r
+}
+
+// Limit counts the request in the current window of its policy. Every response carries
+// the RateLimit-Limit, RateLimit-Remaining and RateLimit-Reset headers; rejected requests
+// also carry Retry-After. Requests are let through if the store fails.
+func (r *rateLimiter) Limit() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		name, policy := r.policy(c)
+		r.limit(c, name, policy)
+	}
+}
+
+// LimitAuthentication counts the request per client IP against the authentication policy,
+// with the same headers and rejection as Limit.
+func (r *rateLimiter) LimitAuthentication() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		r.limit(c, authenticationRateLimitPolicy, r.authPolicy)
+	}
+}
+
+// limit counts the request against the named policy and rejects it once the limit is reached.
+func (r *rateLimiter) limit(c *gin.Context, name string, policy config.RateLimitPolicy) {
+	if policy.Limit <= 0 {
+		c.Next()
+		return
+	}
+
+	count, resetAt, err := r.store.Increment(c.Request.Context(), name+"|"+rateLimitKey(c, policy.Key), policy.Window)
+	if err != nil {
+		r.logger.WithContext(c.Request.Context()).Error("error counting request for rate limiting: %v", err)
+		c.Next()
+		return
+	}
+
+	reset := int64(math.Ceil(resetAt.Sub(r.now()).Seconds()))
+	if reset < 0 {
+		reset = 0
+	}
+	remaining := int64(policy.Limit) - count
+	if remaining < 0 {
+		remaining = 0
+	}
+	c.Header("RateLimit-Limit", strconv.Itoa(policy.Limit))
+	c.Header("RateLimit-Remaining", strconv.FormatInt(remaining, 10))
+	c.Header("RateLimit-Reset", strconv.FormatInt(reset, 10))
+
+	if count > int64(policy.Limit) {
+		c.Header("Retry-After", strconv.FormatInt(reset, 10))
+		ErrorResponse(c, http.StatusTooManyRequests, gin.H{"error": "Too many requests", "retryAfter": reset})
+		c.Abort()
+		return
+	}
+	c.Next()
+}
+
+// policy returns the name and policy applying to the request's route.
+func (r *rateLimiter) policy(c *gin.Context) (string, config.RateLimitPolicy) {
+	route := c.Request.Method + " " + c.FullPath()
+	if policy, ok := r.routes[route]; ok {
+		return route, policy
+	}
+	return defaultRateLimitPolicy, r.defaultPolicy
+}
+
+// rateLimitKey identifies who the request is counted for under the given config.RateLimitKey* value.
+func rateLimitKey(c *gin.Context, key string) string {
+	if key == config.RateLimitKeyCaller {
+		if caller := callerIdentity(c); caller != "" {
+			return caller
+		}
+	}
+	return "ip:" + c.ClientIP()
+}
+
+// rateLimitWindow is the count of a key in its current window.
+type rateLimitWindow struct {
+	count   int64
+	resetAt time.Time
+}
+
+// memoryRateLimitStore counts requests in process memory.
+type memoryRateLimitStore struct {
+	mu        sync.Mutex
+	windows   map[string]*rateLimitWindow
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+// NewMemoryRateLimitStore creates a RateLimitStore keeping its counts in process memory,
+// so each instance of the service enforces its own limits.
+func NewMemoryRateLimitStore() RateLimitStore {
+	return &memoryRateLimitStore{windows: map[string]*rateLimitWindow{}, now: time.Now}
+}
+
+// Increment counts a request against key. Windows are aligned on multiples of their length.
+func (s *memoryRateLimitStore) Increment(_ context.Context, key string, window time.Duration) (int64, time.Time, error) {
+	now := s.now()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if now.Sub(s.lastSweep) >= rateLimitSweepInterval {
+		for k, w := range s.windows {
+			if !now.Before(w.resetAt) {
+				delete(s.windows, k)
+			}
+		}
+		s.lastSweep = now
+	}
+
+	w, ok := s.windows[key]
+	if !ok || !now.Before(w.resetAt) {
+		w = &rateLimitWindow{resetAt: now.Truncate(window).Add(window)}
+		s.windows[key] = w
+	}
+	w.count++
+	return w.count, w.resetAt, nil
+}
+
+// mongoRateLimitStore counts requests in MongoDB.
+type mongoRateLimitStore struct {
+	repo repository.RateLimitRepository
+	now  func() time.Time
+}
+
+// NewMongoRateLimitStore creates a RateLimitStore keeping its counts in the given repository,
+// so that every instance of the service sharing the database enforces the same limits.
+func NewMongoRateLimitStore(repo repository.RateLimitRepository) RateLimitStore {
+	return &mongoRateLimitStore{repo: repo, now: time.Now}
+}
+
+// Increment counts a request against key. Windows are aligned on multiples of their length,
+// so that instances agree on the window a request falls in, and each has its own counter
+// which expires when the window ends.
+func (s *mongoRateLimitStore) Increment(ctx context.Context, key string, window time.Duration) (int64, time.Time, error) {
+	resetAt := s.now().Truncate(window).Add(window)
+	count, err := s.repo.IncrementRateLimit(ctx, key+"|"+strconv.FormatInt(resetAt.Unix(), 10), resetAt)
+	if err != nil {
+		return 0, time.Time{}, err
+	}
+	return count, resetAt, nil
 }
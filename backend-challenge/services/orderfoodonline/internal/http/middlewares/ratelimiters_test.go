@@ -2,260 +2,383 @@
 package middlewares
 
 import (
+	"context"
+	"errors"
+	"library/logger/mocks"
 	"net/http"
 	"net/http/httptest"
+	"orderfoodonline/internal/config"
+	repoMocks "orderfoodonline/internal/repository/mocks"
+	"orderfoodonline/internal/repository/models"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
 )
 
-func TestRateLimiterHandler(t *testing.T) {
-	// Given: Rate limiter middleware
-	rateLimiterHandler := RateLimiterHandler()
+// rateLimitStoreFunc adapts a function to the RateLimitStore interface.
+type rateLimitStoreFunc func(ctx context.Context, key string, window time.Duration) (int64, time.Time, error)
 
-	// Set up Gin context
-	gin.SetMode(gin.TestMode)
-	w := httptest.NewRecorder()
-	c, _ := gin.CreateTestContext(w)
-
-	// Create test request
-	req, _ := http.NewRequest("GET", "/api/test", nil)
-	req.RemoteAddr = "127.0.0.1:12345" // Set client IP
-	c.Request = req
-
-	// Add a next handler
-	nextCalled := false
-	nextHandler := func(c *gin.Context) {
-		nextCalled = true
-		c.JSON(http.StatusOK, gin.H{"message": "success"})
-	}
-
-	// When: Processing the request through the rate limiter middleware
-	rateLimiterHandler(c)
-	if !c.IsAborted() {
-		nextHandler(c)
-	}
-
-	// Then: Next handler should be called and response should be successful
-	assert.True(t, nextCalled)
-	assert.Equal(t, http.StatusOK, w.Code)
-	assert.Contains(t, w.Body.String(), "success")
+func (f rateLimitStoreFunc) Increment(ctx context.Context, key string, window time.Duration) (int64, time.Time, error) {
+	return f(ctx, key, window)
 }
 
-func TestRateLimiterHandler_DifferentClientIPs(t *testing.T) {
-	// Given: Rate limiter middleware
-	rateLimiterHandler := RateLimiterHandler()
-
-	testCases := []struct {
-		clientIP   string
-		statusCode int
-	}{
-		{"127.0.0.1:12345", http.StatusOK},
-		{"192.168.1.1:54321", http.StatusOK},
-		{"10.0.0.1:8080", http.StatusOK},
+// rateLimitRouter returns a router serving GET /api/product and POST /api/order behind the rate
+// limiter. Requests carrying a caller header are authenticated as the API key of that name.
+func rateLimitRouter(limiter RateLimitMiddleware) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	authenticate := func(c *gin.Context) {
+		if name := c.GetHeader("caller"); name != "" {
+			c.Set(apiKeyContextKey, &models.APIKey{Name: name})
+		}
 	}
+	ok := func(c *gin.Context) { c.JSON(http.StatusOK, gin.H{"message": "success"}) }
+	router.GET("/api/product", authenticate, limiter.Limit(), ok)
+	router.POST("/api/order", authenticate, limiter.Limit(), ok)
+	return router
+}
 
-	for _, tc := range testCases {
-		t.Run("ClientIP_"+tc.clientIP, func(t *testing.T) {
-			// Set up Gin context
-			gin.SetMode(gin.TestMode)
-			w := httptest.NewRecorder()
-			c, _ := gin.CreateTestContext(w)
-
-			// Create test request
-			req, _ := http.NewRequest("GET", "/api/test", nil)
-			req.RemoteAddr = tc.clientIP
-			c.Request = req
-
-			// Add a next handler
-			nextHandler := func(c *gin.Context) {
-				c.Status(tc.statusCode)
-			}
-
-			// When: Processing the request through the rate limiter middleware
-			rateLimiterHandler(c)
-			if !c.IsAborted() {
-				nextHandler(c)
-			}
-
-			// Then: Request should be processed with correct status code
-			assert.Equal(t, tc.statusCode, w.Code)
-		})
+// serve sends a request from the client IP, as the named caller if not empty.
+func serve(router *gin.Engine, method, path, clientIP, caller string) *httptest.ResponseRecorder {
+	req, _ := http.NewRequest(method, path, nil)
+	req.RemoteAddr = clientIP + ":12345"
+	if caller != "" {
+		req.Header.Set("caller", caller)
 	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
 }
 
-func TestRateLimiterHandler_DifferentHTTPMethods(t *testing.T) {
-	// Given: Rate limiter middleware
-	rateLimiterHandler := RateLimiterHandler()
+func TestRateLimitMiddleware_Limit(t *testing.T) {
+	// Given: A default policy of 2 requests per minute
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockLogger := mocks.NewMockILogger(ctrl)
+	router := rateLimitRouter(NewRateLimitMiddleware(&config.RateLimitConfig{
+		Default: config.RateLimitPolicy{Limit: 2, Window: time.Minute, Key: config.RateLimitKeyCaller},
+	}, NewMemoryRateLimitStore(), mockLogger))
+
+	// When: Sending three requests from the same client
+	first := serve(router, "GET", "/api/product", "10.0.0.1", "")
+	second := serve(router, "GET", "/api/product", "10.0.0.1", "")
+	third := serve(router, "GET", "/api/product", "10.0.0.1", "")
+
+	// Then: The first two should pass with the remaining budget in the headers
+	assert.Equal(t, http.StatusOK, first.Code)
+	assert.Equal(t, "2", first.Header().Get("RateLimit-Limit"))
+	assert.Equal(t, "1", first.Header().Get("RateLimit-Remaining"))
+	assert.NotEmpty(t, first.Header().Get("RateLimit-Reset"))
+	assert.Empty(t, first.Header().Get("Retry-After"))
+	assert.Equal(t, http.StatusOK, second.Code)
+	assert.Equal(t, "0", second.Header().Get("RateLimit-Remaining"))
+
+	// And: The third should be rejected with a JSON body and Retry-After
+	assert.Equal(t, http.StatusTooManyRequests, third.Code)
+	assert.Equal(t, "0", third.Header().Get("RateLimit-Remaining"))
+	assert.NotEmpty(t, third.Header().Get("Retry-After"))
+	assert.Equal(t, third.Header().Get("RateLimit-Reset"), third.Header().Get("Retry-After"))
+	assert.Contains(t, third.Header().Get("Content-Type"), "application/json")
+	assert.Contains(t, third.Body.String(), `"error":"Too many requests"`)
+	assert.Contains(t, third.Body.String(), `"retryAfter"`)
+}
 
-	testCases := []struct {
-		method     string
-		path       string
-		statusCode int
+func TestRateLimitMiddleware_Limit_Keys(t *testing.T) {
+	tests := []struct {
+		name     string
+		key      string
+		requests [][2]string // client IP and caller of each request
+		lastCode int
 	}{
-		{"GET", "/api/health", http.StatusOK},
-		{"PUT", "/api/product/123", http.StatusOK},
-		{"PATCH", "/api/product/123", http.StatusOK},
+		{name: "callers behind one IP are counted apart", key: config.RateLimitKeyCaller,
+			requests: [][2]string{{"10.0.0.1", "mobile"}, {"10.0.0.1", "partner"}}, lastCode: http.StatusOK},
+		{name: "one caller is counted across IPs", key: config.RateLimitKeyCaller,
+			requests: [][2]string{{"10.0.0.1", "mobile"}, {"10.0.0.2", "mobile"}}, lastCode: http.StatusTooManyRequests},
+		{name: "anonymous callers are counted by IP", key: config.RateLimitKeyCaller,
+			requests: [][2]string{{"10.0.0.1", ""}, {"10.0.0.2", ""}}, lastCode: http.StatusOK},
+		{name: "IP policy ignores the caller", key: config.RateLimitKeyIP,
+			requests: [][2]string{{"10.0.0.1", "mobile"}, {"10.0.0.1", "partner"}}, lastCode: http.StatusTooManyRequests},
 	}
 
-	for _, tc := range testCases {
-		t.Run(tc.method+"_"+tc.path, func(t *testing.T) {
-			// Set up Gin context
-			gin.SetMode(gin.TestMode)
-			w := httptest.NewRecorder()
-			c, _ := gin.CreateTestContext(w)
-
-			// Create test request
-			req, _ := http.NewRequest(tc.method, tc.path, nil)
-			req.RemoteAddr = "127.0.0.1:12345"
-			c.Request = req
-
-			// Add a next handler
-			nextHandler := func(c *gin.Context) {
-				c.Status(tc.statusCode)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given: A default policy of 1 request per minute
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			mockLogger := mocks.NewMockILogger(ctrl)
+			router := rateLimitRouter(NewRateLimitMiddleware(&config.RateLimitConfig{
+				Default: config.RateLimitPolicy{Limit: 1, Window: time.Minute, Key: tt.key},
+			}, NewMemoryRateLimitStore(), mockLogger))
+
+			// When: Sending the requests
+			var w *httptest.ResponseRecorder
+			for _, r := range tt.requests {
+				w = serve(router, "GET", "/api/product", r[0], r[1])
 			}
 
-			// When: Processing the request through the rate limiter middleware
-			rateLimiterHandler(c)
-			if !c.IsAborted() {
-				nextHandler(c)
-			}
-
-			// Then: Request should be processed with correct status code
-			assert.Equal(t, tc.statusCode, w.Code)
+			// Then: The last request should only be rejected if it shares a counter with the first
+			assert.Equal(t, tt.lastCode, w.Code)
 		})
 	}
 }
 
-func TestRateLimiterHandler_WithXForwardedFor(t *testing.T) {
-	// Given: Rate limiter middleware
-	rateLimiterHandler := RateLimiterHandler()
-
-	// Set up Gin context
-	gin.SetMode(gin.TestMode)
-	w := httptest.NewRecorder()
-	c, _ := gin.CreateTestContext(w)
-
-	// Create test request with X-Forwarded-For header
-	req, _ := http.NewRequest("GET", "/api/test", nil)
-	req.RemoteAddr = "127.0.0.1:12345"
-	req.Header.Set("X-Forwarded-For", "203.0.113.1")
-	c.Request = req
+func TestRateLimitMiddleware_Limit_RouteOverride(t *testing.T) {
+	// Given: A lenient default policy and a strict policy for placing orders
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockLogger := mocks.NewMockILogger(ctrl)
+	router := rateLimitRouter(NewRateLimitMiddleware(&config.RateLimitConfig{
+		Default: config.RateLimitPolicy{Limit: 100, Window: time.Minute, Key: config.RateLimitKeyCaller},
+		Routes: map[string]config.RateLimitPolicy{
+			"POST /api/order": {Limit: 1, Window: time.Minute, Key: config.RateLimitKeyCaller},
+		},
+	}, NewMemoryRateLimitStore(), mockLogger))
+
+	// When: Placing two orders and then listing products
+	first := serve(router, "POST", "/api/order", "10.0.0.1", "mobile")
+	second := serve(router, "POST", "/api/order", "10.0.0.1", "mobile")
+	list := serve(router, "GET", "/api/product", "10.0.0.1", "mobile")
+
+	// Then: Only the order route should be limited, and by its own policy
+	assert.Equal(t, http.StatusOK, first.Code)
+	assert.Equal(t, "1", first.Header().Get("RateLimit-Limit"))
+	assert.Equal(t, http.StatusTooManyRequests, second.Code)
+	assert.Equal(t, http.StatusOK, list.Code)
+	assert.Equal(t, "100", list.Header().Get("RateLimit-Limit"))
+	assert.Equal(t, "99", list.Header().Get("RateLimit-Remaining"))
+}
 
-	// Add a next handler
-	nextHandler := func(c *gin.Context) {
-		c.JSON(http.StatusOK, gin.H{"message": "success"})
-	}
+func TestRateLimitMiddleware_Limit_Unlimited(t *testing.T) {
+	// Given: No rate limiting configured
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockLogger := mocks.NewMockILogger(ctrl)
+	router := rateLimitRouter(NewRateLimitMiddleware(nil, NewMemoryRateLimitStore(), mockLogger))
 
-	// When: Processing the request through the rate limiter middleware
-	rateLimiterHandler(c)
-	if !c.IsAborted() {
-		nextHandler(c)
-	}
+	// When: Sending a request
+	w := serve(router, "GET", "/api/product", "10.0.0.1", "")
 
-	// Then: Request should be processed successfully
+	// Then: It should pass without rate limit headers
 	assert.Equal(t, http.StatusOK, w.Code)
 	assert.Contains(t, w.Body.String(), "success")
+	assert.Empty(t, w.Header().Get("RateLimit-Limit"))
 }
 
-func TestRateLimiterHandler_WithXRealIP(t *testing.T) {
-	// Given: Rate limiter middleware
-	rateLimiterHandler := RateLimiterHandler()
-
-	// Set up Gin context
-	gin.SetMode(gin.TestMode)
-	w := httptest.NewRecorder()
-	c, _ := gin.CreateTestContext(w)
-
-	// Create test request with X-Real-IP header
-	req, _ := http.NewRequest("GET", "/api/test", nil)
-	req.RemoteAddr = "127.0.0.1:12345"
-	req.Header.Set("X-Real-IP", "198.51.100.1")
-	c.Request = req
-
-	// Add a next handler
-	nextHandler := func(c *gin.Context) {
-		c.JSON(http.StatusOK, gin.H{"message": "success"})
-	}
-
-	// When: Processing the request through the rate limiter middleware
-	rateLimiterHandler(c)
-	if !c.IsAborted() {
-		nextHandler(c)
-	}
-
-	// Then: Request should be processed successfully
+func TestRateLimitMiddleware_Limit_StoreError(t *testing.T) {
+	// Given: A store that fails
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockLogger := mocks.NewMockILogger(ctrl)
+	mockLogger.EXPECT().WithContext(gomock.Any()).Return(mockLogger).AnyTimes()
+	mockLogger.EXPECT().Error(gomock.Any(), gomock.Any())
+	store := rateLimitStoreFunc(func(context.Context, string, time.Duration) (int64, time.Time, error) {
+		return 0, time.Time{}, errors.New("database error")
+	})
+	router := rateLimitRouter(NewRateLimitMiddleware(&config.RateLimitConfig{
+		Default: config.RateLimitPolicy{Limit: 1, Window: time.Minute},
+	}, store, mockLogger))
+
+	// When: Sending a request
+	w := serve(router, "GET", "/api/product", "10.0.0.1", "")
+
+	// Then: It should be let through
 	assert.Equal(t, http.StatusOK, w.Code)
-	assert.Contains(t, w.Body.String(), "success")
 }
 
-func TestRateLimiterHandler_NoRemoteAddr(t *testing.T) {
-	// Given: Rate limiter middleware
-	rateLimiterHandler := RateLimiterHandler()
+func TestRateLimitMiddleware_Limit_StoreKey(t *testing.T) {
+	// Given: A store recording the keys it is asked to count
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockLogger := mocks.NewMockILogger(ctrl)
+	var keys []string
+	store := rateLimitStoreFunc(func(_ context.Context, key string, window time.Duration) (int64, time.Time, error) {
+		keys = append(keys, key)
+		assert.Equal(t, time.Minute, window)
+		return 1, time.Now().Add(window), nil
+	})
+	router := rateLimitRouter(NewRateLimitMiddleware(&config.RateLimitConfig{
+		Default: config.RateLimitPolicy{Limit: 5, Window: time.Minute, Key: config.RateLimitKeyCaller},
+		Routes: map[string]config.RateLimitPolicy{
+			"POST /api/order": {Limit: 1, Window: time.Minute, Key: config.RateLimitKeyIP},
+		},
+	}, store, mockLogger))
+
+	// When: Sending requests to both routes
+	serve(router, "GET", "/api/product", "10.0.0.1", "mobile")
+	serve(router, "POST", "/api/order", "10.0.0.1", "mobile")
+
+	// Then: Each policy should count under its own name and key
+	assert.Equal(t, []string{"default|key:mobile", "POST /api/order|ip:10.0.0.1"}, keys)
+}
 
-	// Set up Gin context
+func TestRateLimitMiddleware_LimitAuthentication(t *testing.T) {
+	// Given: An authentication policy of 2 requests per minute in front of an authentication
+	// that rejects requests without a caller
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockLogger := mocks.NewMockILogger(ctrl)
+	limiter := NewRateLimitMiddleware(&config.RateLimitConfig{
+		Authentication: config.RateLimitPolicy{Limit: 2, Window: time.Minute, Key: config.RateLimitKeyCaller},
+	}, NewMemoryRateLimitStore(), mockLogger)
 	gin.SetMode(gin.TestMode)
-	w := httptest.NewRecorder()
-	c, _ := gin.CreateTestContext(w)
-
-	// Create test request without RemoteAddr
-	req, _ := http.NewRequest("GET", "/api/test", nil)
-	// Note: RemoteAddr is empty
-	c.Request = req
-
-	// Add a next handler
-	nextHandler := func(c *gin.Context) {
-		c.JSON(http.StatusOK, gin.H{"message": "success"})
-	}
-
-	// When: Processing the request through the rate limiter middleware
-	rateLimiterHandler(c)
-	if !c.IsAborted() {
-		nextHandler(c)
+	router := gin.New()
+	authenticate := func(c *gin.Context) {
+		name := c.GetHeader("caller")
+		if name == "" {
+			ErrorResponse(c, http.StatusUnauthorized, gin.H{"error": "Invalid API key"})
+			c.Abort()
+			return
+		}
+		c.Set(apiKeyContextKey, &models.APIKey{Name: name})
 	}
-
-	// Then: Request should still be processed successfully
-	assert.Equal(t, http.StatusOK, w.Code)
-	assert.Contains(t, w.Body.String(), "success")
+	router.POST("/api/order", limiter.LimitAuthentication(), authenticate, func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"message": "success"})
+	})
+
+	// When: A client fails to authenticate three times
+	first := serve(router, "POST", "/api/order", "10.0.0.1", "")
+	second := serve(router, "POST", "/api/order", "10.0.0.1", "")
+	third := serve(router, "POST", "/api/order", "10.0.0.1", "")
+	// And: Then presents a valid key from the same address
+	valid := serve(router, "POST", "/api/order", "10.0.0.1", "mobile")
+	other := serve(router, "POST", "/api/order", "10.0.0.2", "mobile")
+
+	// Then: The failed attempts count against the client IP, whatever the configured key
+	assert.Equal(t, http.StatusUnauthorized, first.Code)
+	assert.Equal(t, http.StatusUnauthorized, second.Code)
+	assert.Equal(t, http.StatusTooManyRequests, third.Code)
+	assert.Equal(t, http.StatusTooManyRequests, valid.Code)
+	// And: Other addresses are not affected
+	assert.Equal(t, http.StatusOK, other.Code)
 }
 
-func TestRateLimiterHandler_ConcurrentRequests(t *testing.T) {
-	// Given: Rate limiter middleware
-	rateLimiterHandler := RateLimiterHandler()
+func TestMemoryRateLimitStore_Increment(t *testing.T) {
+	// Given: A memory store with a controllable clock
+	store := NewMemoryRateLimitStore().(*memoryRateLimitStore)
+	now := time.Date(2024, 1, 1, 12, 0, 30, 0, time.UTC)
+	store.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	// When: Counting requests within a window
+	count, resetAt, err := store.Increment(ctx, "a", time.Minute)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+	assert.Equal(t, time.Date(2024, 1, 1, 12, 1, 0, 0, time.UTC), resetAt)
+
+	count, _, _ = store.Increment(ctx, "a", time.Minute)
+	assert.Equal(t, int64(2), count)
+	count, _, _ = store.Increment(ctx, "b", time.Minute)
+	assert.Equal(t, int64(1), count)
+
+	// Then: The count should start over in the next window, and ended windows be dropped
+	now = now.Add(2 * time.Minute)
+	count, resetAt, _ = store.Increment(ctx, "a", time.Minute)
+	assert.Equal(t, int64(1), count)
+	assert.Equal(t, time.Date(2024, 1, 1, 12, 3, 0, 0, time.UTC), resetAt)
+	assert.NotContains(t, store.windows, "b")
+}
 
-	// Test concurrent requests from the same IP
-	// Note: This is a basic test - in a real scenario, you'd want to test actual rate limiting
-	// but that would require more complex setup with time-based testing
+// sharedRateLimitRepository returns a mock repository holding its counters in memory,
+// standing in for the rate_limits collection shared by several instances.
+func sharedRateLimitRepository(ctrl *gomock.Controller) (*repoMocks.MockRateLimitRepository, map[string]int64) {
+	counters := map[string]int64{}
+	repo := repoMocks.NewMockRateLimitRepository(ctrl)
+	repo.EXPECT().IncrementRateLimit(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, id string, _ time.Time) (int64, error) {
+			counters[id]++
+			return counters[id], nil
+		}).AnyTimes()
+	return repo, counters
+}
 
-	// Set up Gin context
-	gin.SetMode(gin.TestMode)
-	w := httptest.NewRecorder()
-	c, _ := gin.CreateTestContext(w)
+func TestRateLimitMiddleware_Limit_SharedStore(t *testing.T) {
+	tests := []struct {
+		name  string
+		store func(ctrl *gomock.Controller) (RateLimitStore, RateLimitStore)
+	}{
+		{name: "memory", store: func(*gomock.Controller) (RateLimitStore, RateLimitStore) {
+			store := NewMemoryRateLimitStore()
+			return store, store
+		}},
+		{name: "mongo", store: func(ctrl *gomock.Controller) (RateLimitStore, RateLimitStore) {
+			repo, _ := sharedRateLimitRepository(ctrl)
+			return NewMongoRateLimitStore(repo), NewMongoRateLimitStore(repo)
+		}},
+	}
 
-	// Create test request
-	req, _ := http.NewRequest("GET", "/api/test", nil)
-	req.RemoteAddr = "127.0.0.1:12345"
-	c.Request = req
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given: Two instances limiting a caller to 3 requests per minute in the same store
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			mockLogger := mocks.NewMockILogger(ctrl)
+			cfg := &config.RateLimitConfig{
+				Default: config.RateLimitPolicy{Limit: 3, Window: time.Minute, Key: config.RateLimitKeyCaller},
+			}
+			firstStore, secondStore := tt.store(ctrl)
+			first := rateLimitRouter(NewRateLimitMiddleware(cfg, firstStore, mockLogger))
+			second := rateLimitRouter(NewRateLimitMiddleware(cfg, secondStore, mockLogger))
+
+			// When: The caller's requests are spread across both instances
+			codes := []int{
+				serve(first, "GET", "/api/product", "10.0.0.1", "mobile").Code,
+				serve(second, "GET", "/api/product", "10.0.0.1", "mobile").Code,
+				serve(first, "GET", "/api/product", "10.0.0.1", "mobile").Code,
+				serve(second, "GET", "/api/product", "10.0.0.1", "mobile").Code,
+				serve(first, "GET", "/api/product", "10.0.0.1", "mobile").Code,
+			}
 
-	// Add a next handler
-	nextHandler := func(c *gin.Context) {
-		c.JSON(http.StatusOK, gin.H{"message": "success"})
+			// Then: The limit should apply to the requests of both instances together
+			assert.Equal(t, []int{
+				http.StatusOK, http.StatusOK, http.StatusOK, http.StatusTooManyRequests, http.StatusTooManyRequests,
+			}, codes)
+		})
 	}
+}
 
-	// When: Processing multiple requests through the rate limiter middleware
-	for i := 0; i < 5; i++ {
-		w = httptest.NewRecorder()
-		c, _ = gin.CreateTestContext(w)
-		c.Request = req
+func TestMongoRateLimitStore_Increment(t *testing.T) {
+	// Given: A Mongo store with a controllable clock
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	repo, counters := sharedRateLimitRepository(ctrl)
+	store := NewMongoRateLimitStore(repo).(*mongoRateLimitStore)
+	now := time.Date(2024, 1, 1, 12, 0, 30, 0, time.UTC)
+	store.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	// When: Counting requests within a window
+	count, resetAt, err := store.Increment(ctx, "a", time.Minute)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+	assert.Equal(t, time.Date(2024, 1, 1, 12, 1, 0, 0, time.UTC), resetAt)
+
+	count, _, _ = store.Increment(ctx, "a", time.Minute)
+	assert.Equal(t, int64(2), count)
+	count, _, _ = store.Increment(ctx, "b", time.Minute)
+	assert.Equal(t, int64(1), count)
+
+	// Then: The next window should be counted in a new counter
+	now = now.Add(2 * time.Minute)
+	count, resetAt, _ = store.Increment(ctx, "a", time.Minute)
+	assert.Equal(t, int64(1), count)
+	assert.Equal(t, time.Date(2024, 1, 1, 12, 3, 0, 0, time.UTC), resetAt)
+	assert.Equal(t, map[string]int64{"a|1704110460": 2, "b|1704110460": 1, "a|1704110580": 1}, counters)
+}
 
-		rateLimiterHandler(c)
-		if !c.IsAborted() {
-			nextHandler(c)
-		}
+func TestMongoRateLimitStore_Increment_Error(t *testing.T) {
+	// Given: A repository that fails
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockRepo := repoMocks.NewMockRateLimitRepository(ctrl)
+	mockRepo.EXPECT().IncrementRateLimit(gomock.Any(), gomock.Any(), gomock.Any()).Return(int64(0), errors.New("database error"))
 
-		// Then: Each request should be processed successfully
-		assert.Equal(t, http.StatusOK, w.Code)
-		assert.Contains(t, w.Body.String(), "success")
-	}
+	// When: Counting a request
+	count, _, err := NewMongoRateLimitStore(mockRepo).Increment(context.Background(), "a", time.Minute)
+
+	// Then: The error should be returned
+	assert.Error(t, err)
+	assert.Equal(t, int64(0), count)
 }
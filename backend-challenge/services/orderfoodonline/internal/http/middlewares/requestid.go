@@ -0,0 +1,69 @@
+package middlewares
+
+import (
+	"library/logger"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// RequestIDHeader is the header carrying the ID of a request, in the request and its response.
+	RequestIDHeader = "X-Request-ID"
+
+	// maxRequestIDLength is the length of the longest request ID accepted from a caller.
+	maxRequestIDLength = 128
+
+	// requestIDContextKey is the gin context key holding the ID of the request.
+	requestIDContextKey = "request_id"
+)
+
+// RequestIDHandler returns a middleware identifying every request by the X-Request-ID header
+// it was sent with, or by a new UUID if the header is missing or invalid. The ID is echoed in
+// the response's header, added to error responses by ErrorResponse, and stored on the request
+// context so that loggers returned by logger.WithContext write it with every entry.
+func RequestIDHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := c.GetHeader(RequestIDHeader)
+		if !validRequestID(requestID) {
+			requestID = uuid.New().String()
+		}
+
+		c.Set(requestIDContextKey, requestID)
+		ctx := logger.ContextWithRequestID(c.Request.Context(), requestID)
+		c.Request = c.Request.WithContext(ctx)
+		trace.SpanFromContext(ctx).SetAttributes(attribute.String("http.request_id", requestID))
+		c.Header(RequestIDHeader, requestID)
+		c.Next()
+	}
+}
+
+// RequestIDFromContext returns the ID of the request, or an empty string outside of RequestIDHandler.
+func RequestIDFromContext(c *gin.Context) string {
+	return c.GetString(requestIDContextKey)
+}
+
+// ErrorResponse writes body as the JSON response with the given status code, adding the ID
+// of the request to it so that customers can quote it when reporting the error.
+func ErrorResponse(c *gin.Context, code int, body gin.H) {
+	if requestID := RequestIDFromContext(c); requestID != "" {
+		body["requestId"] = requestID
+	}
+	c.JSON(code, body)
+}
+
+// validRequestID reports whether a request ID sent by a caller is short and only made of
+// printable ASCII characters, so that it is safe to echo and to write to the logs.
+func validRequestID(requestID string) bool {
+	if requestID == "" || len(requestID) > maxRequestIDLength {
+		return false
+	}
+	for i := 0; i < len(requestID); i++ {
+		if requestID[i] < '!' || requestID[i] > '~' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,125 @@
+package middlewares
+
+import (
+	"encoding/json"
+	"library/logger"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// requestIDRouter returns a router identifying requests, serving GET /api/order/:orderId with the
+// request ID seen by the handler and GET /api/fail with an error response.
+func requestIDRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(RequestIDHandler())
+	router.GET("/api/order/:orderId", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{
+			"gin":     RequestIDFromContext(c),
+			"context": logger.RequestIDFromContext(c.Request.Context()),
+		})
+	})
+	router.GET("/api/fail", func(c *gin.Context) {
+		ErrorResponse(c, http.StatusInternalServerError, gin.H{"error": "Failed to fetch order"})
+	})
+	return router
+}
+
+func TestRequestIDHandler(t *testing.T) {
+	tests := []struct {
+		name      string
+		requestID string
+		accepted  bool
+	}{
+		{name: "caller's ID", requestID: "3f2b8c1e-0d4a-4e5b-9c6d-7a8b9c0d1e2f", accepted: true},
+		{name: "caller's opaque ID", requestID: "gateway:req-42", accepted: true},
+		{name: "no ID"},
+		{name: "ID with spaces", requestID: "req 42"},
+		{name: "ID with control characters", requestID: "req-42\x1b[31m"},
+		{name: "ID too long", requestID: strings.Repeat("a", maxRequestIDLength+1)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given: A router identifying requests
+			router := requestIDRouter()
+
+			// When: Sending a request with the X-Request-ID header
+			req, _ := http.NewRequest(http.MethodGet, "/api/order/42", nil)
+			if tt.requestID != "" {
+				req.Header.Set(RequestIDHeader, tt.requestID)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			// Then: A valid ID should be kept, others replaced by a new UUID, and the ID should be
+			// echoed and available to the handler and its context
+			assert.Equal(t, http.StatusOK, w.Code)
+			requestID := w.Header().Get(RequestIDHeader)
+			if tt.accepted {
+				assert.Equal(t, tt.requestID, requestID)
+			} else {
+				_, err := uuid.Parse(requestID)
+				assert.NoError(t, err)
+			}
+			var body map[string]string
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+			assert.Equal(t, requestID, body["gin"])
+			assert.Equal(t, requestID, body["context"])
+		})
+	}
+}
+
+func TestRequestIDHandler_UniqueIDs(t *testing.T) {
+	// Given: A router identifying requests
+	router := requestIDRouter()
+
+	// When: Sending two requests without an ID
+	ids := make([]string, 2)
+	for i := range ids {
+		req, _ := http.NewRequest(http.MethodGet, "/api/order/42", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		ids[i] = w.Header().Get(RequestIDHeader)
+	}
+
+	// Then: Each should be given its own ID
+	assert.NotEmpty(t, ids[0])
+	assert.NotEqual(t, ids[0], ids[1])
+}
+
+func TestErrorResponse(t *testing.T) {
+	// Given: A router identifying requests
+	router := requestIDRouter()
+
+	// When: A request fails
+	req, _ := http.NewRequest(http.MethodGet, "/api/fail", nil)
+	req.Header.Set(RequestIDHeader, "req-42")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// Then: The error body should carry the request's ID
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.JSONEq(t, `{"error":"Failed to fetch order","requestId":"req-42"}`, w.Body.String())
+}
+
+func TestErrorResponse_WithoutRequestID(t *testing.T) {
+	// Given: A request that was not identified
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+
+	// When: Writing an error response
+	ErrorResponse(c, http.StatusBadRequest, gin.H{"error": "Invalid input"})
+
+	// Then: The body should be written as is
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.JSONEq(t, `{"error":"Invalid input"}`, w.Body.String())
+}
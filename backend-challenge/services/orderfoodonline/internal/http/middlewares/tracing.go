@@ -0,0 +1,17 @@
+package middlewares
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
+)
+
+// TracingHandler returns a middleware starting a server span for every request but the
+// Prometheus scrapes, as a child of the span in the request's W3C traceparent header if any.
+// The span is available to the handlers through the request context.
+func TracingHandler(serviceName string) gin.HandlerFunc {
+	return otelgin.Middleware(serviceName, otelgin.WithFilter(func(r *http.Request) bool {
+		return r.URL.Path != "/metrics"
+	}))
+}
@@ -0,0 +1,87 @@
+package middlewares
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracingRouter returns a router serving GET /api/order/:orderId and /metrics behind the tracing
+// middleware, spans being recorded in the returned recorder.
+func tracingRouter(t *testing.T) (*gin.Engine, *tracetest.SpanRecorder) {
+	t.Helper()
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(TracingHandler("orderfoodonline"))
+	router.GET("/api/order/:orderId", func(c *gin.Context) {
+		assert.True(t, trace.SpanFromContext(c.Request.Context()).SpanContext().IsValid())
+		c.JSON(http.StatusOK, gin.H{"message": "success"})
+	})
+	router.GET("/metrics", func(c *gin.Context) { c.String(http.StatusOK, "") })
+	return router, recorder
+}
+
+func TestTracingHandler(t *testing.T) {
+	tests := []struct {
+		name        string
+		traceparent string
+		remote      bool
+	}{
+		{name: "new trace"},
+		{name: "propagated trace", traceparent: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", remote: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given: A router behind the tracing middleware
+			router, recorder := tracingRouter(t)
+
+			// When: Sending a request with the traceparent header
+			req, _ := http.NewRequest(http.MethodGet, "/api/order/42", nil)
+			if tt.traceparent != "" {
+				req.Header.Set("traceparent", tt.traceparent)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			// Then: A server span should be recorded for the route, continuing the propagated trace
+			assert.Equal(t, http.StatusOK, w.Code)
+			spans := recorder.Ended()
+			require.Len(t, spans, 1)
+			assert.Equal(t, "/api/order/:orderId", spans[0].Name())
+			assert.Equal(t, trace.SpanKindServer, spans[0].SpanKind())
+			assert.Equal(t, tt.remote, spans[0].Parent().IsRemote())
+			if tt.remote {
+				assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", spans[0].SpanContext().TraceID().String())
+				assert.Equal(t, "00f067aa0ba902b7", spans[0].Parent().SpanID().String())
+			}
+		})
+	}
+}
+
+func TestTracingHandler_Metrics(t *testing.T) {
+	// Given: A router behind the tracing middleware
+	router, recorder := tracingRouter(t)
+
+	// When: Prometheus scrapes the metrics
+	req, _ := http.NewRequest(http.MethodGet, "/metrics", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// Then: No span should be recorded
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Empty(t, recorder.Ended())
+}
@@ -10,9 +10,16 @@ import (
 // It encapsulates all HTTP handlers and middleware components needed to set up
 // the complete routing configuration for the Order Food Online service.
 type Dependencies struct {
-	SwaggerHandler    handlers.SwaggerHandler       // Handler for serving Swagger documentation
-	AuthMiddleware    middlewares.AuthMiddleware    // Middleware for authentication and authorization
-	MetricsMiddleware middlewares.MetricsMiddleware // Middleware for Prometheus metrics collection
-	ProductHandler    handlers.ProductHandler       // Handler for product-related endpoints
-	OrderHandler      handlers.OrderHandler         // Handler for order-related endpoints
+	SwaggerHandler        handlers.SwaggerHandler           // Handler for serving Swagger documentation
+	AuthMiddleware        middlewares.AuthMiddleware        // Middleware for authentication and authorization
+	MetricsMiddleware     middlewares.MetricsMiddleware     // Middleware for Prometheus metrics collection
+	IdempotencyMiddleware middlewares.IdempotencyMiddleware // Middleware for Idempotency-Key handling on order placement and cart checkout
+	RateLimitMiddleware   middlewares.RateLimitMiddleware   // Middleware for per-route and per-caller rate limiting
+	ProductHandler        handlers.ProductHandler           // Handler for product-related endpoints
+	ProductAdminHandler   handlers.ProductAdminHandler      // Handler for catalog administration endpoints
+	CategoryHandler       handlers.CategoryHandler          // Handler for category-related endpoints
+	StockHandler          handlers.StockHandler             // Handler for stock administration endpoints
+	CouponHandler         handlers.CouponHandler            // Handler for coupon-related endpoints
+	CartHandler           handlers.CartHandler              // Handler for server-side cart endpoints
+	OrderHandler          handlers.OrderHandler             // Handler for order-related endpoints
 }
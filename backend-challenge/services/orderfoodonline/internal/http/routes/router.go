@@ -39,6 +39,8 @@ func NewRouter(config *config.Config, logger logger.ILogger) *Router {
 	// Use gin.ReleaseMode for production
 	// Use gin.DebugMode for local development
 	engine := gin.Default()
+	// Let handlers passing the *gin.Context as context reach the request's span
+	engine.ContextWithFallback = true
 
 	router := &Router{
 		engine: engine,
@@ -98,8 +100,8 @@ func (r *Router) GetEngine() *gin.Engine {
 }
 
 // Init initializes the router with dependencies and sets up all middleware and routes.
-// It configures middleware stack, API routes, health checks, and Swagger documentation
-// based on the environment configuration.
+// It configures middleware stack, API routes, health checks, the metrics endpoint and
+// Swagger documentation based on the environment configuration.
 func (r *Router) Init(dep Dependencies) error {
 	// setup middlewares first
 	r.setupMiddleware(dep)
@@ -109,49 +111,59 @@ func (r *Router) Init(dep Dependencies) error {
 		return fmt.Errorf("failed to setup api routes: %w", err)
 	}
 
+	// setup metrics and documentation routes, once the dependencies are validated
+	r.setupOperationalRoutes(dep)
+
 	return nil
 }
 
 // setupMiddleware sets up all required middlewares for the router.
-// It configures rate limiting, CORS, authentication, and health check endpoints.
-// Swagger documentation is only enabled in local development environment.
+// It configures tracing, request IDs, metrics and CORS; the health check endpoints are public API routes,
+// and rate limiting is applied per route by setupAPIRoutes and setupOperationalRoutes.
 func (r *Router) setupMiddleware(dep Dependencies) {
-	// Metrics middleware (should be first to capture all requests)
-	r.engine.Use(dep.MetricsMiddleware.RecordMetrics())
-
-	// RateLimiter middleware
-	r.engine.Use(middlewares.RateLimiterHandler())
+	// Tracing middleware, starting the server span of every request
+	r.engine.Use(middlewares.TracingHandler(r.config.Tracing.ServiceName))
 
-	// CORS middleware
-	r.engine.Use(middlewares.CorsHandler())
+	// Request ID middleware, identifying the request in its response, error body and logs
+	r.engine.Use(middlewares.RequestIDHandler())
 
-	// OPTIONS method handler
-	r.engine.Use(middlewares.OptionsHandler())
+	// Metrics middleware (should be first to capture all requests)
+	r.engine.Use(dep.MetricsMiddleware.RecordMetrics())
 
-	// Health handler
-	r.engine.GET("/api/health", func(c *gin.Context) {
-		c.JSON(http.StatusOK, "Healthy")
-	})
+	// CORS middleware, answering preflight requests
+	r.engine.Use(middlewares.CorsHandler(r.config.CORS))
+}
 
-	// VERSION handler
-	r.engine.GET("/api/version", func(ctx *gin.Context) {
-		ctx.JSON(200, gin.H{
-			"version":     constants.Version,
-			"commit_hash": constants.CommitHash,
-		})
-	})
+// setupOperationalRoutes sets up the metrics endpoint and, in the local development environment
+// only, the Swagger documentation. Like the public API routes, they are rate limited per client IP
+// under the default policy, unless rate_limit.routes overrides it (e.g. "GET /metrics").
+func (r *Router) setupOperationalRoutes(dep Dependencies) {
+	rateLimit := dep.RateLimitMiddleware.Limit()
 
 	// Metrics endpoint for Prometheus
-	r.engine.GET("/metrics", gin.WrapH(promhttp.Handler()))
+	r.engine.GET("/metrics", rateLimit, gin.WrapH(promhttp.Handler()))
 
 	// Swagger Page handler
-	if r.config.Env == "local" {
+	if r.config.Env == config.EnvLocal {
 		// Serve filtered Swagger JSON
-		r.engine.GET("/api/swagger.json", dep.SwaggerHandler.GetSwaggerJSONHandler)
+		r.engine.GET("/api/swagger.json", rateLimit, dep.SwaggerHandler.GetSwaggerJSONHandler)
 
 		// Serve Swagger UI (pointing to filtered doc.json)
-		r.engine.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler, ginSwagger.URL("/api/swagger.json")))
+		r.engine.GET("/swagger/*any", rateLimit, ginSwagger.WrapHandler(swaggerFiles.Handler, ginSwagger.URL("/api/swagger.json")))
 	} else {
 		log.Println("Swagger is disabled in dev/production")
 	}
 }
+
+// healthHandler reports that the service is up.
+func healthHandler(c *gin.Context) {
+	c.JSON(http.StatusOK, "Healthy")
+}
+
+// versionHandler reports the version and commit the service was built from.
+func versionHandler(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"version":     constants.Version,
+		"commit_hash": constants.CommitHash,
+	})
+}
@@ -3,6 +3,10 @@ package routes
 
 import (
 	"fmt"
+	"net/http"
+	"orderfoodonline/internal/http/middlewares"
+
+	"github.com/gin-gonic/gin"
 )
 
 // validateDependencies checks that all required dependencies are provided.
@@ -15,34 +19,115 @@ func validateDependencies(d Dependencies) error {
 	if d.MetricsMiddleware == nil {
 		return fmt.Errorf("metricsMiddleware cannot be nil")
 	}
+	if d.IdempotencyMiddleware == nil {
+		return fmt.Errorf("idempotencyMiddleware cannot be nil")
+	}
+	if d.RateLimitMiddleware == nil {
+		return fmt.Errorf("rateLimitMiddleware cannot be nil")
+	}
 	if d.ProductHandler == nil {
 		return fmt.Errorf("productHandler cannot be nil")
 	}
+	if d.ProductAdminHandler == nil {
+		return fmt.Errorf("productAdminHandler cannot be nil")
+	}
+	if d.CategoryHandler == nil {
+		return fmt.Errorf("categoryHandler cannot be nil")
+	}
+	if d.StockHandler == nil {
+		return fmt.Errorf("stockHandler cannot be nil")
+	}
+	if d.CouponHandler == nil {
+		return fmt.Errorf("couponHandler cannot be nil")
+	}
+	if d.CartHandler == nil {
+		return fmt.Errorf("cartHandler cannot be nil")
+	}
+	if d.OrderHandler == nil {
+		return fmt.Errorf("orderHandler cannot be nil")
+	}
 	if d.SwaggerHandler == nil {
 		return fmt.Errorf("swaggerHandler cannot be nil")
 	}
 	return nil
 }
 
+// route is an endpoint under /api together with the scopes it requires.
+type route struct {
+	method   string
+	path     string
+	public   bool     // Served without authentication
+	scopes   []string // Scopes the caller must be granted; every non-public route declares at least one
+	handlers []gin.HandlerFunc
+}
+
+// apiRoutes returns the table of all endpoints served under /api.
+func apiRoutes(di Dependencies) []route {
+	return []route{
+		// Public routes
+		{method: http.MethodGet, path: "/health", public: true, handlers: []gin.HandlerFunc{healthHandler}},
+		{method: http.MethodGet, path: "/version", public: true, handlers: []gin.HandlerFunc{versionHandler}},
+
+		// Catalog
+		{method: http.MethodGet, path: "/product", scopes: []string{middlewares.ScopeCatalogRead}, handlers: []gin.HandlerFunc{di.ProductHandler.ListProducts}},
+		{method: http.MethodGet, path: "/product/:productId", scopes: []string{middlewares.ScopeCatalogRead}, handlers: []gin.HandlerFunc{di.ProductHandler.GetProductByID}},
+		{method: http.MethodGet, path: "/category", scopes: []string{middlewares.ScopeCatalogRead}, handlers: []gin.HandlerFunc{di.CategoryHandler.ListCategories}},
+		{method: http.MethodGet, path: "/coupon/:code", scopes: []string{middlewares.ScopeCatalogRead}, handlers: []gin.HandlerFunc{di.CouponHandler.PreviewCoupon}},
+
+		// Carts
+		{method: http.MethodPost, path: "/cart", scopes: []string{middlewares.ScopeOrdersWrite}, handlers: []gin.HandlerFunc{di.CartHandler.CreateCart}},
+		{method: http.MethodGet, path: "/cart/:cartId", scopes: []string{middlewares.ScopeOrdersRead}, handlers: []gin.HandlerFunc{di.CartHandler.GetCart}},
+		{method: http.MethodDelete, path: "/cart/:cartId", scopes: []string{middlewares.ScopeOrdersWrite}, handlers: []gin.HandlerFunc{di.CartHandler.DeleteCart}},
+		{method: http.MethodPost, path: "/cart/:cartId/items", scopes: []string{middlewares.ScopeOrdersWrite}, handlers: []gin.HandlerFunc{di.CartHandler.AddCartItem}},
+		{method: http.MethodPut, path: "/cart/:cartId/items/:productId", scopes: []string{middlewares.ScopeOrdersWrite}, handlers: []gin.HandlerFunc{di.CartHandler.UpdateCartItem}},
+		{method: http.MethodDelete, path: "/cart/:cartId/items/:productId", scopes: []string{middlewares.ScopeOrdersWrite}, handlers: []gin.HandlerFunc{di.CartHandler.RemoveCartItem}},
+		{method: http.MethodPut, path: "/cart/:cartId/coupon", scopes: []string{middlewares.ScopeOrdersWrite}, handlers: []gin.HandlerFunc{di.CartHandler.ApplyCartCoupon}},
+		{method: http.MethodDelete, path: "/cart/:cartId/coupon", scopes: []string{middlewares.ScopeOrdersWrite}, handlers: []gin.HandlerFunc{di.CartHandler.RemoveCartCoupon}},
+		{method: http.MethodPost, path: "/cart/:cartId/checkout", scopes: []string{middlewares.ScopeOrdersWrite}, handlers: []gin.HandlerFunc{di.IdempotencyMiddleware.Idempotent(), di.CartHandler.CheckoutCart}},
+
+		// Orders
+		{method: http.MethodPost, path: "/order", scopes: []string{middlewares.ScopeOrdersWrite}, handlers: []gin.HandlerFunc{di.IdempotencyMiddleware.Idempotent(), di.OrderHandler.PlaceOrder}},
+		{method: http.MethodGet, path: "/order", scopes: []string{middlewares.ScopeOrdersRead}, handlers: []gin.HandlerFunc{di.OrderHandler.ListOrders}},
+		{method: http.MethodGet, path: "/order/:orderId", scopes: []string{middlewares.ScopeOrdersRead}, handlers: []gin.HandlerFunc{di.OrderHandler.GetOrderByID}},
+		{method: http.MethodPatch, path: "/order/:orderId/status", scopes: []string{middlewares.ScopeOrdersAdmin}, handlers: []gin.HandlerFunc{di.OrderHandler.UpdateOrderStatus}},
+
+		// Catalog administration
+		{method: http.MethodPost, path: "/product", scopes: []string{middlewares.ScopeCatalogAdmin}, handlers: []gin.HandlerFunc{di.ProductAdminHandler.CreateProduct}},
+		{method: http.MethodPut, path: "/product/:productId", scopes: []string{middlewares.ScopeCatalogAdmin}, handlers: []gin.HandlerFunc{di.ProductAdminHandler.ReplaceProduct}},
+		{method: http.MethodPatch, path: "/product/:productId", scopes: []string{middlewares.ScopeCatalogAdmin}, handlers: []gin.HandlerFunc{di.ProductAdminHandler.PatchProduct}},
+		{method: http.MethodDelete, path: "/product/:productId", scopes: []string{middlewares.ScopeCatalogAdmin}, handlers: []gin.HandlerFunc{di.ProductAdminHandler.DeleteProduct}},
+		{method: http.MethodGet, path: "/product/:productId/stock", scopes: []string{middlewares.ScopeCatalogAdmin}, handlers: []gin.HandlerFunc{di.StockHandler.GetStock}},
+		{method: http.MethodPut, path: "/product/:productId/stock", scopes: []string{middlewares.ScopeCatalogAdmin}, handlers: []gin.HandlerFunc{di.StockHandler.SetStock}},
+		{method: http.MethodPost, path: "/product/:productId/restock", scopes: []string{middlewares.ScopeCatalogAdmin}, handlers: []gin.HandlerFunc{di.StockHandler.Restock}},
+	}
+}
+
 // setupAPIRoutes sets up API routes using the provided dependencies.
-// It configures all REST API endpoints under the /api prefix with authentication
-// middleware applied to all routes. Routes include product listing, product details,
-// and order placement.
+// It registers every endpoint of apiRoutes under the /api prefix. Non-public routes are rate
+// limited per client IP, so that failed authentications are counted too, then authenticated,
+// rate limited per caller and authorized against the scopes they declare;
+// a non-public route without scopes is refused so that no endpoint is left open by mistake.
+// Public routes are rate limited per client IP.
 func (r *Router) setupAPIRoutes(di Dependencies) error {
 	if err := validateDependencies(di); err != nil {
 		return err
 	}
 	// Group all routes under /api
 	api := r.engine.Group("/api")
+	authenticate := di.AuthMiddleware.Authenticate()
+	rateLimit := di.RateLimitMiddleware.Limit()
+	limitAuthentication := di.RateLimitMiddleware.LimitAuthentication()
 
-	// Public routes goes here(no auth required)
-
-	// Protect all /api routes with auth middleware
-	api.Use(di.AuthMiddleware.Authenticate())
-	{
-		api.GET("/product", di.ProductHandler.ListProducts)
-		api.GET("/product/:productId", di.ProductHandler.GetProductByID)
-		api.POST("/order", di.OrderHandler.PlaceOrder)
+	for _, rt := range apiRoutes(di) {
+		if rt.public {
+			api.Handle(rt.method, rt.path, append([]gin.HandlerFunc{rateLimit}, rt.handlers...)...)
+			continue
+		}
+		if len(rt.scopes) == 0 {
+			return fmt.Errorf("route %s %s declares no scope", rt.method, rt.path)
+		}
+		chain := []gin.HandlerFunc{limitAuthentication, authenticate, rateLimit, di.AuthMiddleware.Authorize(rt.scopes...)}
+		api.Handle(rt.method, rt.path, append(chain, rt.handlers...)...)
 	}
 
 	return nil
@@ -1,10 +1,18 @@
 package routes
 
 import (
+	"encoding/json"
+	loggerMock "library/logger/mocks"
+	"net/http"
+	"net/http/httptest"
+	"orderfoodonline/internal/config"
 	handlersMock "orderfoodonline/internal/http/handlers/mocks"
+	"orderfoodonline/internal/http/middlewares"
 	middlewaresMock "orderfoodonline/internal/http/middlewares/mocks"
+	"orderfoodonline/internal/repository/models"
 	"testing"
 
+	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/mock/gomock"
 )
@@ -18,6 +26,14 @@ func Test_validateDependencies(t *testing.T) {
 	mockProductHandler := handlersMock.NewMockProductHandler(ctrl)
 	mocksSwaggerHandler := handlersMock.NewMockSwaggerHandler(ctrl)
 	mocksMetricsHandler := middlewaresMock.NewMockMetricsMiddleware(ctrl)
+	mockIdempotencyMiddleware := middlewaresMock.NewMockIdempotencyMiddleware(ctrl)
+	mockRateLimitMiddleware := middlewaresMock.NewMockRateLimitMiddleware(ctrl)
+	mockProductAdminHandler := handlersMock.NewMockProductAdminHandler(ctrl)
+	mockCategoryHandler := handlersMock.NewMockCategoryHandler(ctrl)
+	mockStockHandler := handlersMock.NewMockStockHandler(ctrl)
+	mockCouponHandler := handlersMock.NewMockCouponHandler(ctrl)
+	mockCartHandler := handlersMock.NewMockCartHandler(ctrl)
+	mockOrderHandler := handlersMock.NewMockOrderHandler(ctrl)
 
 	tests := []struct {
 		name        string
@@ -28,10 +44,18 @@ func Test_validateDependencies(t *testing.T) {
 		{
 			name: "All dependencies are provided",
 			args: Dependencies{
-				AuthMiddleware:    mockAuthMiddleware,
-				ProductHandler:    mockProductHandler,
-				SwaggerHandler:    mocksSwaggerHandler,
-				MetricsMiddleware: mocksMetricsHandler,
+				ProductAdminHandler:   mockProductAdminHandler,
+				CategoryHandler:       mockCategoryHandler,
+				StockHandler:          mockStockHandler,
+				CouponHandler:         mockCouponHandler,
+				CartHandler:           mockCartHandler,
+				OrderHandler:          mockOrderHandler,
+				AuthMiddleware:        mockAuthMiddleware,
+				ProductHandler:        mockProductHandler,
+				SwaggerHandler:        mocksSwaggerHandler,
+				MetricsMiddleware:     mocksMetricsHandler,
+				IdempotencyMiddleware: mockIdempotencyMiddleware,
+				RateLimitMiddleware:   mockRateLimitMiddleware,
 			},
 			wantErr:     false,
 			expectedErr: "",
@@ -39,20 +63,54 @@ func Test_validateDependencies(t *testing.T) {
 		{
 			name: "AuthMiddleware is nil",
 			args: Dependencies{
-				AuthMiddleware:    nil,
-				ProductHandler:    mockProductHandler,
-				SwaggerHandler:    mocksSwaggerHandler,
-				MetricsMiddleware: mocksMetricsHandler,
+				ProductAdminHandler:   mockProductAdminHandler,
+				CategoryHandler:       mockCategoryHandler,
+				StockHandler:          mockStockHandler,
+				CouponHandler:         mockCouponHandler,
+				CartHandler:           mockCartHandler,
+				OrderHandler:          mockOrderHandler,
+				AuthMiddleware:        nil,
+				ProductHandler:        mockProductHandler,
+				SwaggerHandler:        mocksSwaggerHandler,
+				MetricsMiddleware:     mocksMetricsHandler,
+				IdempotencyMiddleware: mockIdempotencyMiddleware,
+				RateLimitMiddleware:   mockRateLimitMiddleware,
 			},
 			wantErr:     true,
 			expectedErr: "authMiddleware cannot be nil",
 		},
+		{
+			name: "RateLimitMiddleware is nil",
+			args: Dependencies{
+				ProductAdminHandler:   mockProductAdminHandler,
+				CategoryHandler:       mockCategoryHandler,
+				StockHandler:          mockStockHandler,
+				CouponHandler:         mockCouponHandler,
+				CartHandler:           mockCartHandler,
+				OrderHandler:          mockOrderHandler,
+				AuthMiddleware:        mockAuthMiddleware,
+				ProductHandler:        mockProductHandler,
+				SwaggerHandler:        mocksSwaggerHandler,
+				MetricsMiddleware:     mocksMetricsHandler,
+				IdempotencyMiddleware: mockIdempotencyMiddleware,
+			},
+			wantErr:     true,
+			expectedErr: "rateLimitMiddleware cannot be nil",
+		},
 		{
 			name: "ProductMiddleware is nil",
 			args: Dependencies{
-				AuthMiddleware:    mockAuthMiddleware,
-				SwaggerHandler:    mocksSwaggerHandler,
-				MetricsMiddleware: mocksMetricsHandler,
+				ProductAdminHandler:   mockProductAdminHandler,
+				CategoryHandler:       mockCategoryHandler,
+				StockHandler:          mockStockHandler,
+				CouponHandler:         mockCouponHandler,
+				CartHandler:           mockCartHandler,
+				OrderHandler:          mockOrderHandler,
+				AuthMiddleware:        mockAuthMiddleware,
+				SwaggerHandler:        mocksSwaggerHandler,
+				MetricsMiddleware:     mocksMetricsHandler,
+				IdempotencyMiddleware: mockIdempotencyMiddleware,
+				RateLimitMiddleware:   mockRateLimitMiddleware,
 			},
 			wantErr:     true,
 			expectedErr: "productHandler cannot be nil",
@@ -60,9 +118,17 @@ func Test_validateDependencies(t *testing.T) {
 		{
 			name: "SwaggerHandler is nil",
 			args: Dependencies{
-				AuthMiddleware:    mockAuthMiddleware,
-				ProductHandler:    mockProductHandler,
-				MetricsMiddleware: mocksMetricsHandler,
+				ProductAdminHandler:   mockProductAdminHandler,
+				CategoryHandler:       mockCategoryHandler,
+				StockHandler:          mockStockHandler,
+				CouponHandler:         mockCouponHandler,
+				CartHandler:           mockCartHandler,
+				OrderHandler:          mockOrderHandler,
+				AuthMiddleware:        mockAuthMiddleware,
+				ProductHandler:        mockProductHandler,
+				MetricsMiddleware:     mocksMetricsHandler,
+				IdempotencyMiddleware: mockIdempotencyMiddleware,
+				RateLimitMiddleware:   mockRateLimitMiddleware,
 			},
 			wantErr:     true,
 			expectedErr: "swaggerHandler cannot be nil",
@@ -70,13 +136,134 @@ func Test_validateDependencies(t *testing.T) {
 		{
 			name: "MetricsMiddleware is nil",
 			args: Dependencies{
-				AuthMiddleware: mockAuthMiddleware,
-				ProductHandler: mockProductHandler,
-				SwaggerHandler: mocksSwaggerHandler,
+				ProductAdminHandler:   mockProductAdminHandler,
+				CategoryHandler:       mockCategoryHandler,
+				StockHandler:          mockStockHandler,
+				CouponHandler:         mockCouponHandler,
+				CartHandler:           mockCartHandler,
+				OrderHandler:          mockOrderHandler,
+				AuthMiddleware:        mockAuthMiddleware,
+				ProductHandler:        mockProductHandler,
+				SwaggerHandler:        mocksSwaggerHandler,
+				IdempotencyMiddleware: mockIdempotencyMiddleware,
+				RateLimitMiddleware:   mockRateLimitMiddleware,
 			},
 			wantErr:     true,
 			expectedErr: "metricsMiddleware cannot be nil",
 		},
+		{
+			name: "IdempotencyMiddleware is nil",
+			args: Dependencies{
+				ProductAdminHandler: mockProductAdminHandler,
+				CategoryHandler:     mockCategoryHandler,
+				StockHandler:        mockStockHandler,
+				AuthMiddleware:      mockAuthMiddleware,
+				ProductHandler:      mockProductHandler,
+				SwaggerHandler:      mocksSwaggerHandler,
+				MetricsMiddleware:   mocksMetricsHandler,
+			},
+			wantErr:     true,
+			expectedErr: "idempotencyMiddleware cannot be nil",
+		},
+		{
+			name: "ProductAdminHandler is nil",
+			args: Dependencies{
+				CategoryHandler:       mockCategoryHandler,
+				StockHandler:          mockStockHandler,
+				CouponHandler:         mockCouponHandler,
+				CartHandler:           mockCartHandler,
+				OrderHandler:          mockOrderHandler,
+				AuthMiddleware:        mockAuthMiddleware,
+				ProductHandler:        mockProductHandler,
+				SwaggerHandler:        mocksSwaggerHandler,
+				MetricsMiddleware:     mocksMetricsHandler,
+				IdempotencyMiddleware: mockIdempotencyMiddleware,
+				RateLimitMiddleware:   mockRateLimitMiddleware,
+			},
+			wantErr:     true,
+			expectedErr: "productAdminHandler cannot be nil",
+		},
+		{
+			name: "CategoryHandler is nil",
+			args: Dependencies{
+				ProductAdminHandler:   mockProductAdminHandler,
+				AuthMiddleware:        mockAuthMiddleware,
+				ProductHandler:        mockProductHandler,
+				SwaggerHandler:        mocksSwaggerHandler,
+				MetricsMiddleware:     mocksMetricsHandler,
+				IdempotencyMiddleware: mockIdempotencyMiddleware,
+				RateLimitMiddleware:   mockRateLimitMiddleware,
+			},
+			wantErr:     true,
+			expectedErr: "categoryHandler cannot be nil",
+		},
+		{
+			name: "StockHandler is nil",
+			args: Dependencies{
+				ProductAdminHandler:   mockProductAdminHandler,
+				CategoryHandler:       mockCategoryHandler,
+				AuthMiddleware:        mockAuthMiddleware,
+				ProductHandler:        mockProductHandler,
+				SwaggerHandler:        mocksSwaggerHandler,
+				MetricsMiddleware:     mocksMetricsHandler,
+				IdempotencyMiddleware: mockIdempotencyMiddleware,
+				RateLimitMiddleware:   mockRateLimitMiddleware,
+			},
+			wantErr:     true,
+			expectedErr: "stockHandler cannot be nil",
+		},
+		{
+			name: "CouponHandler is nil",
+			args: Dependencies{
+				ProductAdminHandler:   mockProductAdminHandler,
+				CategoryHandler:       mockCategoryHandler,
+				StockHandler:          mockStockHandler,
+				AuthMiddleware:        mockAuthMiddleware,
+				ProductHandler:        mockProductHandler,
+				SwaggerHandler:        mocksSwaggerHandler,
+				MetricsMiddleware:     mocksMetricsHandler,
+				IdempotencyMiddleware: mockIdempotencyMiddleware,
+				RateLimitMiddleware:   mockRateLimitMiddleware,
+			},
+			wantErr:     true,
+			expectedErr: "couponHandler cannot be nil",
+		},
+		{
+			name: "CartHandler is nil",
+			args: Dependencies{
+				ProductAdminHandler:   mockProductAdminHandler,
+				CategoryHandler:       mockCategoryHandler,
+				StockHandler:          mockStockHandler,
+				CouponHandler:         mockCouponHandler,
+				OrderHandler:          mockOrderHandler,
+				AuthMiddleware:        mockAuthMiddleware,
+				ProductHandler:        mockProductHandler,
+				SwaggerHandler:        mocksSwaggerHandler,
+				MetricsMiddleware:     mocksMetricsHandler,
+				IdempotencyMiddleware: mockIdempotencyMiddleware,
+				RateLimitMiddleware:   mockRateLimitMiddleware,
+			},
+			wantErr:     true,
+			expectedErr: "cartHandler cannot be nil",
+		},
+		{
+			name: "OrderHandler is nil",
+			args: Dependencies{
+				ProductAdminHandler:   mockProductAdminHandler,
+				CategoryHandler:       mockCategoryHandler,
+				StockHandler:          mockStockHandler,
+				CouponHandler:         mockCouponHandler,
+				CartHandler:           mockCartHandler,
+				AuthMiddleware:        mockAuthMiddleware,
+				ProductHandler:        mockProductHandler,
+				SwaggerHandler:        mocksSwaggerHandler,
+				MetricsMiddleware:     mocksMetricsHandler,
+				IdempotencyMiddleware: mockIdempotencyMiddleware,
+				RateLimitMiddleware:   mockRateLimitMiddleware,
+			},
+			wantErr:     true,
+			expectedErr: "orderHandler cannot be nil",
+		},
 		// Add more test cases for each nil dependency as needed
 	}
 
@@ -92,3 +279,184 @@ func Test_validateDependencies(t *testing.T) {
 		})
 	}
 }
+
+// testDependencies returns dependencies whose handlers and middlewares are all mocks.
+func testDependencies(ctrl *gomock.Controller) Dependencies {
+	return Dependencies{
+		SwaggerHandler:        handlersMock.NewMockSwaggerHandler(ctrl),
+		AuthMiddleware:        middlewaresMock.NewMockAuthMiddleware(ctrl),
+		MetricsMiddleware:     middlewaresMock.NewMockMetricsMiddleware(ctrl),
+		IdempotencyMiddleware: passThroughIdempotency(ctrl),
+		RateLimitMiddleware:   passThroughRateLimit(ctrl),
+		ProductHandler:        handlersMock.NewMockProductHandler(ctrl),
+		ProductAdminHandler:   handlersMock.NewMockProductAdminHandler(ctrl),
+		CategoryHandler:       handlersMock.NewMockCategoryHandler(ctrl),
+		StockHandler:          handlersMock.NewMockStockHandler(ctrl),
+		CouponHandler:         handlersMock.NewMockCouponHandler(ctrl),
+		CartHandler:           handlersMock.NewMockCartHandler(ctrl),
+		OrderHandler:          handlersMock.NewMockOrderHandler(ctrl),
+	}
+}
+
+// passThroughIdempotency returns an idempotency middleware that does nothing.
+func passThroughIdempotency(ctrl *gomock.Controller) *middlewaresMock.MockIdempotencyMiddleware {
+	mock := middlewaresMock.NewMockIdempotencyMiddleware(ctrl)
+	mock.EXPECT().Idempotent().Return(func(c *gin.Context) { c.Next() }).AnyTimes()
+	return mock
+}
+
+// passThroughRateLimit returns a rate limit middleware that does nothing.
+func passThroughRateLimit(ctrl *gomock.Controller) *middlewaresMock.MockRateLimitMiddleware {
+	mock := middlewaresMock.NewMockRateLimitMiddleware(ctrl)
+	mock.EXPECT().Limit().Return(func(c *gin.Context) { c.Next() }).AnyTimes()
+	mock.EXPECT().LimitAuthentication().Return(func(c *gin.Context) { c.Next() }).AnyTimes()
+	return mock
+}
+
+func Test_apiRoutes_DeclareScopes(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// Given: The route table
+	routes := apiRoutes(testDependencies(ctrl))
+
+	seen := map[string]bool{}
+	for _, rt := range routes {
+		name := rt.method + " /api" + rt.path
+		t.Run(name, func(t *testing.T) {
+			// Then: Every non-public route should declare at least one known scope
+			if rt.public {
+				assert.Empty(t, rt.scopes, "public route should not declare scopes")
+			} else {
+				assert.NotEmpty(t, rt.scopes, "non-public route should declare a scope")
+			}
+			for _, scope := range rt.scopes {
+				assert.Contains(t, []string{
+					middlewares.ScopeCatalogRead, middlewares.ScopeCatalogAdmin,
+					middlewares.ScopeOrdersRead, middlewares.ScopeOrdersWrite, middlewares.ScopeOrdersAdmin,
+				}, scope)
+			}
+			assert.NotEmpty(t, rt.handlers)
+			assert.False(t, seen[name], "route declared twice")
+			seen[name] = true
+		})
+	}
+}
+
+func TestRouter_setupAPIRoutes_RegistersRouteTable(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// Given: Dependencies with a pass-through auth middleware
+	di := testDependencies(ctrl)
+	auth := middlewaresMock.NewMockAuthMiddleware(ctrl)
+	auth.EXPECT().Authenticate().Return(func(c *gin.Context) { c.Next() }).AnyTimes()
+	auth.EXPECT().Authorize(gomock.Any()).Return(func(c *gin.Context) { c.Next() }).AnyTimes()
+	di.AuthMiddleware = auth
+	gin.SetMode(gin.TestMode)
+	r := &Router{engine: gin.New()}
+
+	// When: Setting up the API routes
+	assert.NoError(t, r.setupAPIRoutes(di))
+
+	// Then: Exactly the routes of the table should be registered
+	var want, got []string
+	for _, rt := range apiRoutes(di) {
+		want = append(want, rt.method+" /api"+rt.path)
+	}
+	for _, info := range r.engine.Routes() {
+		got = append(got, info.Method+" "+info.Path)
+	}
+	assert.ElementsMatch(t, want, got)
+}
+
+func TestRouter_setupAPIRoutes_Authorization(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// Given: The API routes behind the real auth middleware, with a customer key,
+	// a back-office key and a read-only catalog key
+	di := testDependencies(ctrl)
+	di.AuthMiddleware = middlewares.NewAuthMiddleware(middlewares.NewStaticKeyStore(&config.AuthConfig{
+		APIKey: "customer-key",
+		Keys: []config.APIKeyConfig{
+			{Name: "back-office", Key: "admin-key", Scopes: []string{middlewares.ScopeAdmin}},
+			{Name: "catalog", Key: "catalog-key", Scopes: []string{middlewares.ScopeCatalogRead}},
+		},
+	}), nil, loggerMock.NewMockILogger(ctrl))
+	ok := func(c *gin.Context) { c.Status(http.StatusOK) }
+	di.ProductHandler.(*handlersMock.MockProductHandler).EXPECT().ListProducts(gomock.Any()).Do(ok).AnyTimes()
+	di.ProductAdminHandler.(*handlersMock.MockProductAdminHandler).EXPECT().CreateProduct(gomock.Any()).Do(ok).AnyTimes()
+	di.OrderHandler.(*handlersMock.MockOrderHandler).EXPECT().PlaceOrder(gomock.Any()).Do(ok).AnyTimes()
+	gin.SetMode(gin.TestMode)
+	r := &Router{engine: gin.New()}
+	assert.NoError(t, r.setupAPIRoutes(di))
+
+	tests := []struct {
+		name          string
+		method        string
+		path          string
+		apiKey        string
+		expectedCode  int
+		missingScopes []string
+	}{
+		{name: "public route without key", method: http.MethodGet, path: "/api/health", expectedCode: http.StatusOK},
+		{name: "customer reads catalog", method: http.MethodGet, path: "/api/product", apiKey: "customer-key", expectedCode: http.StatusOK},
+		{name: "customer places order", method: http.MethodPost, path: "/api/order", apiKey: "customer-key", expectedCode: http.StatusOK},
+		{name: "customer denied catalog admin", method: http.MethodPost, path: "/api/product", apiKey: "customer-key", expectedCode: http.StatusForbidden, missingScopes: []string{middlewares.ScopeCatalogAdmin}},
+		{name: "back office manages catalog", method: http.MethodPost, path: "/api/product", apiKey: "admin-key", expectedCode: http.StatusOK},
+		{name: "back office denied placing orders", method: http.MethodPost, path: "/api/order", apiKey: "admin-key", expectedCode: http.StatusForbidden, missingScopes: []string{middlewares.ScopeOrdersWrite}},
+		{name: "catalog key reads catalog", method: http.MethodGet, path: "/api/product", apiKey: "catalog-key", expectedCode: http.StatusOK},
+		{name: "catalog key denied placing orders", method: http.MethodPost, path: "/api/order", apiKey: "catalog-key", expectedCode: http.StatusForbidden, missingScopes: []string{middlewares.ScopeOrdersWrite}},
+		{name: "no key", method: http.MethodGet, path: "/api/product", expectedCode: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest(tt.method, tt.path, nil)
+			if tt.apiKey != "" {
+				req.Header.Set("api_key", tt.apiKey)
+			}
+
+			// When: Serving the request
+			w := httptest.NewRecorder()
+			r.engine.ServeHTTP(w, req)
+
+			// Then: Only callers granted the route's scopes should reach the handler
+			assert.Equal(t, tt.expectedCode, w.Code)
+			if tt.expectedCode == http.StatusForbidden {
+				var resp models.ForbiddenResponse
+				assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+				assert.Equal(t, models.InsufficientScopeCode, resp.Code)
+				assert.Equal(t, tt.missingScopes, resp.MissingScopes)
+			}
+		})
+	}
+}
+
+func TestRouter_setupOperationalRoutes_RateLimited(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// Given: The metrics and documentation routes of a local router behind a rate limiter
+	// that rejects every request
+	di := testDependencies(ctrl)
+	limiter := middlewaresMock.NewMockRateLimitMiddleware(ctrl)
+	limiter.EXPECT().Limit().Return(func(c *gin.Context) { c.AbortWithStatus(http.StatusTooManyRequests) })
+	di.RateLimitMiddleware = limiter
+	gin.SetMode(gin.TestMode)
+	r := &Router{engine: gin.New(), config: &config.Config{Env: config.EnvLocal}}
+	r.setupOperationalRoutes(di)
+
+	for _, path := range []string{"/metrics", "/api/swagger.json", "/swagger/index.html"} {
+		t.Run(path, func(t *testing.T) {
+			// When: Requesting the route
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodGet, path, nil)
+			r.engine.ServeHTTP(w, req)
+
+			// Then: The request should be rate limited
+			assert.Equal(t, http.StatusTooManyRequests, w.Code)
+		})
+	}
+}
@@ -3,39 +3,82 @@
 package metrics
 
 import (
+	"orderfoodonline/internal/config"
+	"strconv"
+	"sync"
+
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
 )
 
+// Outcomes of coupon code validations, the values of the outcome label of coupon_validations_total.
+const (
+	CouponTooShort = "too_short" // The code is shorter than the shortest valid code
+	CouponTooLong  = "too_long"  // The code is longer than the longest valid code
+	CouponInvalid  = "invalid"   // Fewer than two coupon files hold the code
+	CouponValid    = "valid"     // The code is valid
+	CouponError    = "error"     // The code could not be looked up
+)
+
+const (
+	// MaxProductLabels is the number of distinct products recorded under their own product_id label
+	// in products_ordered_total. Admins can add products without bound, so further products are
+	// recorded under OtherProduct.
+	MaxProductLabels = 500
+	// OtherProduct is the product_id label of the products ordered after MaxProductLabels others.
+	OtherProduct = "other"
+)
+
+// UnmatchedEndpoint is the endpoint label of requests that matched no route, so that
+// requests for arbitrary paths do not each create a new time series.
+const UnmatchedEndpoint = "unmatched"
+
+var (
+	// LatencyBuckets are the default buckets, in seconds, of the HTTP request and database query
+	// duration histograms. They are tuned for an API answering most requests within 100ms, and
+	// replaced by the configured ones in Init.
+	LatencyBuckets = []float64{0.001, 0.0025, 0.005, 0.01, 0.025, 0.05, 0.075, 0.1, 0.25, 0.5, 1, 2.5}
+
+	// SizeBuckets are the default buckets, in bytes, of the HTTP request and response size
+	// histograms, from 100B to about 1.6MB. They are replaced by the configured ones in Init.
+	SizeBuckets = prometheus.ExponentialBuckets(100, 4, 8)
+
+	// MoneyBuckets are the buckets, in major currency units, of the order value and discount histograms.
+	MoneyBuckets = []float64{1, 2.5, 5, 10, 15, 20, 30, 50, 75, 100, 150, 250}
+
+	// ItemCountBuckets are the buckets of the items per order histogram.
+	ItemCountBuckets = []float64{1, 2, 3, 4, 5, 7, 10, 15, 20, 50}
+)
+
 var (
-	// HTTPRequestTotal tracks the total number of HTTP requests by method and status code
+	// HTTPRequestTotal tracks the total number of HTTP requests by method, endpoint, status code and status class
 	HTTPRequestTotal = promauto.NewCounterVec(
 		prometheus.CounterOpts{
 			Name: "http_requests_total",
 			Help: "Total number of HTTP requests",
 		},
-		[]string{"method", "endpoint", "status_code"},
+		[]string{"method", "endpoint", "status_code", "status_class"},
 	)
 
-	// HTTPRequestDuration tracks the duration of HTTP requests by method and endpoint
-	HTTPRequestDuration = promauto.NewHistogramVec(
-		prometheus.HistogramOpts{
-			Name:    "http_request_duration_seconds",
-			Help:    "Duration of HTTP requests in seconds",
-			Buckets: prometheus.DefBuckets,
+	// HTTPRequestDuration tracks the duration of HTTP requests by method, endpoint and status class
+	HTTPRequestDuration = register(newHTTPRequestDuration())
+
+	// HTTPRequestsInFlight tracks the number of HTTP requests being served
+	HTTPRequestsInFlight = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "http_requests_in_flight",
+			Help: "Number of HTTP requests being served",
 		},
-		[]string{"method", "endpoint"},
 	)
 
+	// HTTPRequestSize tracks the size of HTTP request bodies by method and endpoint
+	HTTPRequestSize = register(newHTTPRequestSize())
+
+	// HTTPResponseSize tracks the size of HTTP response bodies by method and endpoint
+	HTTPResponseSize = register(newHTTPResponseSize())
+
 	// DatabaseQueryDuration tracks the duration of database queries by operation
-	DatabaseQueryDuration = promauto.NewHistogramVec(
-		prometheus.HistogramOpts{
-			Name:    "database_query_duration_seconds",
-			Help:    "Duration of database queries in seconds",
-			Buckets: prometheus.DefBuckets,
-		},
-		[]string{"operation", "collection"},
-	)
+	DatabaseQueryDuration = register(newDatabaseQueryDuration())
 
 	// DatabaseQueryTotal tracks the total number of database queries by operation and status
 	DatabaseQueryTotal = promauto.NewCounterVec(
@@ -72,12 +115,174 @@ var (
 		},
 		[]string{"status"},
 	)
+
+	// OrderStatusTransitionsTotal tracks the number of order lifecycle transitions by source and target status
+	OrderStatusTransitionsTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "order_status_transitions_total",
+			Help: "Total number of order status transitions",
+		},
+		[]string{"from", "to"},
+	)
+
+	// OrderValue tracks the amount payable of placed orders
+	OrderValue = promauto.NewHistogram(
+		prometheus.HistogramOpts{
+			Name:    "order_value",
+			Help:    "Amount payable of placed orders, in major currency units",
+			Buckets: MoneyBuckets,
+		},
+	)
+
+	// OrderItems tracks the number of items, counting quantities, of placed orders
+	OrderItems = promauto.NewHistogram(
+		prometheus.HistogramOpts{
+			Name:    "order_items",
+			Help:    "Number of items in placed orders",
+			Buckets: ItemCountBuckets,
+		},
+	)
+
+	// OrderDiscount tracks the discount granted to placed orders by the type of the coupon rule that granted it
+	OrderDiscount = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "order_discount",
+			Help:    "Discount granted to placed orders by coupons, in major currency units",
+			Buckets: MoneyBuckets,
+		},
+		[]string{"discount_type"},
+	)
+
+	// CouponValidationsTotal tracks the coupon codes of order attempts by validation outcome
+	CouponValidationsTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "coupon_validations_total",
+			Help: "Total number of coupon codes validated when placing an order",
+		},
+		[]string{"outcome"},
+	)
+
+	// ProductsOrderedTotal tracks the quantity ordered of each product. Its series are bounded by
+	// MaxProductLabels, see RecordProductOrdered.
+	ProductsOrderedTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "products_ordered_total",
+			Help: "Total quantity ordered of each product",
+		},
+		[]string{"product_id"},
+	)
+
+	// StockOutsTotal tracks the number of order lines rejected because the product ran out of stock.
+	// It has no product label, as admins can add products without bound; the products are logged instead.
+	StockOutsTotal = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Name: "stock_outs_total",
+			Help: "Total number of order lines rejected for lack of stock",
+		},
+	)
 )
 
-// RecordHTTPRequest records an HTTP request with method, endpoint, status code, and duration
-func RecordHTTPRequest(method, endpoint, statusCode string, duration float64) {
-	HTTPRequestTotal.WithLabelValues(method, endpoint, statusCode).Inc()
-	HTTPRequestDuration.WithLabelValues(method, endpoint).Observe(duration)
+// newHTTPRequestDuration builds the HTTP request duration histogram with the current LatencyBuckets.
+func newHTTPRequestDuration() *prometheus.HistogramVec {
+	return prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "http_request_duration_seconds",
+			Help:    "Duration of HTTP requests in seconds",
+			Buckets: LatencyBuckets,
+		},
+		[]string{"method", "endpoint", "status_class"},
+	)
+}
+
+// newHTTPRequestSize builds the HTTP request size histogram with the current SizeBuckets.
+func newHTTPRequestSize() *prometheus.HistogramVec {
+	return prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "http_request_size_bytes",
+			Help:    "Size of HTTP request bodies in bytes",
+			Buckets: SizeBuckets,
+		},
+		[]string{"method", "endpoint"},
+	)
+}
+
+// newHTTPResponseSize builds the HTTP response size histogram with the current SizeBuckets.
+func newHTTPResponseSize() *prometheus.HistogramVec {
+	return prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "http_response_size_bytes",
+			Help:    "Size of HTTP response bodies in bytes",
+			Buckets: SizeBuckets,
+		},
+		[]string{"method", "endpoint"},
+	)
+}
+
+// newDatabaseQueryDuration builds the database query duration histogram with the current LatencyBuckets.
+func newDatabaseQueryDuration() *prometheus.HistogramVec {
+	return prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "database_query_duration_seconds",
+			Help:    "Duration of database queries in seconds",
+			Buckets: LatencyBuckets,
+		},
+		[]string{"operation", "collection"},
+	)
+}
+
+// register registers a histogram with the default registry.
+func register(histogram *prometheus.HistogramVec) *prometheus.HistogramVec {
+	prometheus.MustRegister(histogram)
+	return histogram
+}
+
+// replace swaps a registered histogram for one built with other buckets.
+func replace(old, histogram *prometheus.HistogramVec) *prometheus.HistogramVec {
+	prometheus.Unregister(old)
+	return register(histogram)
+}
+
+// Init rebuilds the HTTP request and database query histograms with the buckets of cfg,
+// keeping LatencyBuckets and SizeBuckets where cfg sets none. It must be called before the
+// router is set up and the database is queried, as earlier observations are discarded.
+func Init(cfg *config.MetricsConfig) {
+	if len(cfg.LatencyBuckets) > 0 {
+		LatencyBuckets = cfg.LatencyBuckets
+	}
+	if len(cfg.SizeBuckets) > 0 {
+		SizeBuckets = cfg.SizeBuckets
+	}
+	HTTPRequestDuration = replace(HTTPRequestDuration, newHTTPRequestDuration())
+	HTTPRequestSize = replace(HTTPRequestSize, newHTTPRequestSize())
+	HTTPResponseSize = replace(HTTPResponseSize, newHTTPResponseSize())
+	DatabaseQueryDuration = replace(DatabaseQueryDuration, newDatabaseQueryDuration())
+}
+
+// RecordHTTPRequest records an HTTP request with method, endpoint, status code, duration, and request and response sizes
+func RecordHTTPRequest(method, endpoint string, statusCode int, duration, requestSize, responseSize float64) {
+	statusClass := StatusClass(statusCode)
+	HTTPRequestTotal.WithLabelValues(method, endpoint, strconv.Itoa(statusCode), statusClass).Inc()
+	HTTPRequestDuration.WithLabelValues(method, endpoint, statusClass).Observe(duration)
+	HTTPRequestSize.WithLabelValues(method, endpoint).Observe(requestSize)
+	HTTPResponseSize.WithLabelValues(method, endpoint).Observe(responseSize)
+}
+
+// IncHTTPRequestsInFlight records the start of serving an HTTP request
+func IncHTTPRequestsInFlight() {
+	HTTPRequestsInFlight.Inc()
+}
+
+// DecHTTPRequestsInFlight records the end of serving an HTTP request
+func DecHTTPRequestsInFlight() {
+	HTTPRequestsInFlight.Dec()
+}
+
+// StatusClass returns the class of an HTTP status code, such as "2xx" for 201
+func StatusClass(statusCode int) string {
+	if statusCode < 100 || statusCode > 599 {
+		return "unknown"
+	}
+	return strconv.Itoa(statusCode/100) + "xx"
 }
 
 // RecordDatabaseQuery records a database query with operation, collection, status, and duration
@@ -100,3 +305,55 @@ func RecordOrderProcessing(status string, duration float64) {
 func RecordOrder(status string) {
 	OrdersTotal.WithLabelValues(status).Inc()
 }
+
+// RecordOrderTransition records an order moving from one lifecycle status to another
+func RecordOrderTransition(from, to string) {
+	OrderStatusTransitionsTotal.WithLabelValues(from, to).Inc()
+}
+
+// RecordStockOuts records order lines rejected because their products ran out of stock
+func RecordStockOuts(lines int) {
+	StockOutsTotal.Add(float64(lines))
+}
+
+// RecordPlacedOrder records the amount payable and number of items of a placed order, and the discount
+// it was granted if a coupon rule of the given type applied
+func RecordPlacedOrder(total float64, items int, discount float64, discountType string) {
+	OrderValue.Observe(total)
+	OrderItems.Observe(float64(items))
+	if discountType != "" {
+		OrderDiscount.WithLabelValues(discountType).Observe(discount)
+	}
+}
+
+// productLabels holds the products recorded under their own product_id label.
+var productLabels = struct {
+	sync.Mutex
+	seen map[string]struct{}
+}{seen: make(map[string]struct{})}
+
+// productLabel returns the product_id label of a product: its ID if it already has a label or
+// fewer than MaxProductLabels products do, OtherProduct otherwise.
+func productLabel(productID string) string {
+	productLabels.Lock()
+	defer productLabels.Unlock()
+	if _, ok := productLabels.seen[productID]; ok {
+		return productID
+	}
+	if len(productLabels.seen) >= MaxProductLabels {
+		return OtherProduct
+	}
+	productLabels.seen[productID] = struct{}{}
+	return productID
+}
+
+// RecordProductOrdered records a quantity of a catalog product in a placed order. Products
+// ordered once MaxProductLabels others have been are recorded under OtherProduct.
+func RecordProductOrdered(productID string, quantity int) {
+	ProductsOrderedTotal.WithLabelValues(productLabel(productID)).Add(float64(quantity))
+}
+
+// RecordCouponValidation records the validation outcome of the coupon code of an order attempt, one of the Coupon* outcomes
+func RecordCouponValidation(outcome string) {
+	CouponValidationsTotal.WithLabelValues(outcome).Inc()
+}
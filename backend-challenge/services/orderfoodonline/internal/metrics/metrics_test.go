@@ -0,0 +1,61 @@
+package metrics
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"orderfoodonline/internal/config"
+)
+
+func TestInit_ConfiguredBuckets(t *testing.T) {
+	// Given: Configured latency buckets and default size buckets
+	latency, size := LatencyBuckets, SizeBuckets
+	defer func() {
+		LatencyBuckets, SizeBuckets = latency, size
+		Init(&config.MetricsConfig{})
+	}()
+
+	// When: Initializing the metrics
+	Init(&config.MetricsConfig{LatencyBuckets: []float64{0.1, 1}})
+	RecordHTTPRequest("GET", "/buckets", 200, 0.05, 10, 10)
+
+	// Then: The duration histogram uses the configured buckets, the size histograms the defaults
+	var m dto.Metric
+	require.NoError(t, HTTPRequestDuration.WithLabelValues("GET", "/buckets", "2xx").(prometheus.Histogram).Write(&m))
+	require.Len(t, m.GetHistogram().GetBucket(), 2)
+	assert.Equal(t, 0.1, m.GetHistogram().GetBucket()[0].GetUpperBound())
+	require.NoError(t, HTTPResponseSize.WithLabelValues("GET", "/buckets").(prometheus.Histogram).Write(&m))
+	assert.Len(t, m.GetHistogram().GetBucket(), len(size))
+}
+
+func TestRecordProductOrdered_BoundedLabels(t *testing.T) {
+	// Given: Every product label already taken
+	productLabels.Lock()
+	saved := productLabels.seen
+	productLabels.seen = make(map[string]struct{})
+	for i := 0; i < MaxProductLabels-1; i++ {
+		productLabels.seen["filler-"+strconv.Itoa(i)] = struct{}{}
+	}
+	productLabels.Unlock()
+	defer func() {
+		productLabels.Lock()
+		productLabels.seen = saved
+		productLabels.Unlock()
+	}()
+	other := testutil.ToFloat64(ProductsOrderedTotal.WithLabelValues(OtherProduct))
+
+	// When: Ordering a product taking the last label, then a new one, then the first again
+	RecordProductOrdered("bounded-last", 2)
+	RecordProductOrdered("bounded-new", 3)
+	RecordProductOrdered("bounded-last", 1)
+
+	// Then: The new product is recorded under the shared label
+	assert.Equal(t, 3.0, testutil.ToFloat64(ProductsOrderedTotal.WithLabelValues("bounded-last")))
+	assert.Equal(t, other+3, testutil.ToFloat64(ProductsOrderedTotal.WithLabelValues(OtherProduct)))
+}
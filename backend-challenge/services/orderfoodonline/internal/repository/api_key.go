@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"context"
+	"orderfoodonline/internal/metrics"
+	"orderfoodonline/internal/repository/models"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// apiKeyRepository provides MongoDB-backed access to API keys.
+type apiKeyRepository struct {
+	collection *mongo.Collection
+}
+
+// NewAPIKeyRepository creates a new APIKeyRepository using the given Repository.
+func NewAPIKeyRepository(repo *Repository) (APIKeyRepository, error) {
+	collection := repo.db.Collection("api_keys")
+
+	apiKeyRepo := &apiKeyRepository{collection: collection}
+	if err := apiKeyRepo.createAPIKeyIndexes(context.Background()); err != nil {
+		return nil, err
+	}
+	return apiKeyRepo, nil
+}
+
+func (r *apiKeyRepository) createAPIKeyIndexes(ctx context.Context) error {
+	indexModels := []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "key_hash", Value: 1}},
+			Options: options.Index().SetUnique(true).SetName("api_key_hash_idx"),
+		},
+		{
+			Keys:    bson.D{{Key: "name", Value: 1}},
+			Options: options.Index().SetUnique(true).SetName("api_key_name_idx"),
+		},
+	}
+
+	// Set a timeout context
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	_, err := r.collection.Indexes().CreateMany(ctx, indexModels)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// FindAPIKeyByHash returns the key with the given secret hash, or nil if not found.
+func (r *apiKeyRepository) FindAPIKeyByHash(ctx context.Context, keyHash string) (*models.APIKey, error) {
+	start := time.Now()
+
+	var k models.APIKey
+	err := r.collection.FindOne(ctx, bson.M{"key_hash": keyHash}).Decode(&k)
+	if err == mongo.ErrNoDocuments {
+		metrics.RecordDatabaseQuery("find_one", "api_keys", "not_found", time.Since(start).Seconds())
+		return nil, nil
+	}
+	if err != nil {
+		metrics.RecordDatabaseQuery("find_one", "api_keys", "error", time.Since(start).Seconds())
+		return nil, err
+	}
+
+	metrics.RecordDatabaseQuery("find_one", "api_keys", "success", time.Since(start).Seconds())
+	return &k, nil
+}
@@ -0,0 +1,136 @@
+package repository
+
+import (
+	"context"
+	"orderfoodonline/internal/metrics"
+	"orderfoodonline/internal/repository/models"
+	"time"
+
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// cartRepository provides MongoDB-backed storage for carts.
+type cartRepository struct {
+	collection *mongo.Collection
+}
+
+// NewCartRepository creates a new CartRepository using the given Repository.
+func NewCartRepository(repo *Repository) (CartRepository, error) {
+	collection := repo.db.Collection("carts")
+
+	cartRepo := &cartRepository{collection: collection}
+	if err := cartRepo.createCartIndexes(context.Background()); err != nil {
+		return nil, err
+	}
+	return cartRepo, nil
+}
+
+func (r *cartRepository) createCartIndexes(ctx context.Context) error {
+	indexModels := []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "id", Value: 1}},
+			Options: options.Index().SetUnique(true).SetName("cart_id_idx"),
+		},
+		{
+			// Abandoned carts are removed by MongoDB once expires_at is in the past
+			Keys:    bson.D{{Key: "expires_at", Value: 1}},
+			Options: options.Index().SetExpireAfterSeconds(0).SetName("cart_expires_at_ttl_idx"),
+		},
+	}
+
+	// Set a timeout context
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	_, err := r.collection.Indexes().CreateMany(ctx, indexModels)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// CreateCart inserts a new cart with a fresh ID at version 1 and returns it.
+func (r *cartRepository) CreateCart(ctx context.Context, cart *models.Cart) (*models.Cart, error) {
+	start := time.Now()
+
+	cart.ID = uuid.New().String()
+	cart.Version = 1
+	_, err := r.collection.InsertOne(ctx, cart)
+	if err != nil {
+		metrics.RecordDatabaseQuery("insert_one", "carts", "error", time.Since(start).Seconds())
+		return nil, err
+	}
+
+	metrics.RecordDatabaseQuery("insert_one", "carts", "success", time.Since(start).Seconds())
+	return cart, nil
+}
+
+// FindCartByID returns a cart by its ID, or nil if not found or already expired.
+func (r *cartRepository) FindCartByID(ctx context.Context, id string) (*models.Cart, error) {
+	start := time.Now()
+
+	// The TTL monitor runs periodically, so expired carts are filtered out explicitly
+	filter := bson.M{"id": id, "expires_at": bson.M{"$gt": time.Now()}}
+
+	var c models.Cart
+	err := r.collection.FindOne(ctx, filter).Decode(&c)
+	if err == mongo.ErrNoDocuments {
+		metrics.RecordDatabaseQuery("find_one", "carts", "not_found", time.Since(start).Seconds())
+		return nil, nil
+	}
+	if err != nil {
+		metrics.RecordDatabaseQuery("find_one", "carts", "error", time.Since(start).Seconds())
+		return nil, err
+	}
+
+	metrics.RecordDatabaseQuery("find_one", "carts", "success", time.Since(start).Seconds())
+	return &c, nil
+}
+
+// UpdateCart replaces a cart only if its stored version still matches cart.Version,
+// so that concurrent changes to the same cart cannot overwrite each other.
+// Returns the updated cart, or nil if the cart was changed or deleted in the meantime.
+func (r *cartRepository) UpdateCart(ctx context.Context, cart *models.Cart) (*models.Cart, error) {
+	start := time.Now()
+
+	filter := bson.M{"id": cart.ID, "version": cart.Version}
+	replacement := *cart
+	replacement.Version++
+	opts := options.FindOneAndReplace().SetReturnDocument(options.After)
+
+	var c models.Cart
+	err := r.collection.FindOneAndReplace(ctx, filter, &replacement, opts).Decode(&c)
+	if err == mongo.ErrNoDocuments {
+		metrics.RecordDatabaseQuery("find_one_and_replace", "carts", "conflict", time.Since(start).Seconds())
+		return nil, nil
+	}
+	if err != nil {
+		metrics.RecordDatabaseQuery("find_one_and_replace", "carts", "error", time.Since(start).Seconds())
+		return nil, err
+	}
+
+	metrics.RecordDatabaseQuery("find_one_and_replace", "carts", "success", time.Since(start).Seconds())
+	return &c, nil
+}
+
+// DeleteCart removes a cart. Returns false if no cart has the given ID.
+func (r *cartRepository) DeleteCart(ctx context.Context, id string) (bool, error) {
+	start := time.Now()
+
+	res, err := r.collection.DeleteOne(ctx, bson.M{"id": id})
+	if err != nil {
+		metrics.RecordDatabaseQuery("delete_one", "carts", "error", time.Since(start).Seconds())
+		return false, err
+	}
+	if res.DeletedCount == 0 {
+		metrics.RecordDatabaseQuery("delete_one", "carts", "not_found", time.Since(start).Seconds())
+		return false, nil
+	}
+
+	metrics.RecordDatabaseQuery("delete_one", "carts", "success", time.Since(start).Seconds())
+	return true, nil
+}
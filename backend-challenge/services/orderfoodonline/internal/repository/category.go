@@ -0,0 +1,116 @@
+package repository
+
+import (
+	"context"
+	"orderfoodonline/internal/metrics"
+	"orderfoodonline/internal/repository/models"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// categoryRepository provides MongoDB-backed access to category data.
+type categoryRepository struct {
+	collection *mongo.Collection
+}
+
+// NewCategoryRepository creates a new CategoryRepository using the given Repository.
+func NewCategoryRepository(repo *Repository) (CategoryRepository, error) {
+	collection := repo.db.Collection("categories")
+
+	categoryRepo := &categoryRepository{collection: collection}
+	if err := categoryRepo.createCategoryIndexes(context.Background()); err != nil {
+		return nil, err
+	}
+	return categoryRepo, nil
+}
+
+func (r *categoryRepository) createCategoryIndexes(ctx context.Context) error {
+	indexModels := []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "id", Value: 1}},
+			Options: options.Index().SetUnique(true).SetName("category_id_idx"),
+		},
+	}
+
+	// Set a timeout context
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	_, err := r.collection.Indexes().CreateMany(ctx, indexModels)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// ListCategories returns the active categories ordered by sort order, then name.
+func (r *categoryRepository) ListCategories(ctx context.Context) ([]models.Category, error) {
+	start := time.Now()
+
+	opts := options.Find().SetSort(bson.D{{Key: "sort_order", Value: 1}, {Key: "name", Value: 1}})
+	cur, err := r.collection.Find(ctx, bson.M{"active": true}, opts)
+	if err != nil {
+		metrics.RecordDatabaseQuery("find", "categories", "error", time.Since(start).Seconds())
+		return nil, err
+	}
+	defer cur.Close(ctx)
+
+	categories := []models.Category{}
+	for cur.Next(ctx) {
+		var c models.Category
+		if err := cur.Decode(&c); err != nil {
+			metrics.RecordDatabaseQuery("find", "categories", "error", time.Since(start).Seconds())
+			return nil, err
+		}
+		categories = append(categories, c)
+	}
+	if err := cur.Err(); err != nil {
+		metrics.RecordDatabaseQuery("find", "categories", "error", time.Since(start).Seconds())
+		return nil, err
+	}
+
+	metrics.RecordDatabaseQuery("find", "categories", "success", time.Since(start).Seconds())
+	return categories, nil
+}
+
+// FindCategoryByID returns a category by its ID, or nil if not found.
+func (r *categoryRepository) FindCategoryByID(ctx context.Context, id string) (*models.Category, error) {
+	start := time.Now()
+
+	var c models.Category
+	err := r.collection.FindOne(ctx, bson.M{"id": id}).Decode(&c)
+	if err == mongo.ErrNoDocuments {
+		metrics.RecordDatabaseQuery("find_one", "categories", "not_found", time.Since(start).Seconds())
+		return nil, nil
+	}
+	if err != nil {
+		metrics.RecordDatabaseQuery("find_one", "categories", "error", time.Since(start).Seconds())
+		return nil, err
+	}
+
+	metrics.RecordDatabaseQuery("find_one", "categories", "success", time.Since(start).Seconds())
+	return &c, nil
+}
+
+// InsertCategoryIfMissing inserts the category unless one with the same ID already exists,
+// in which case the stored category is left untouched. Returns the stored category.
+func (r *categoryRepository) InsertCategoryIfMissing(ctx context.Context, category *models.Category) (*models.Category, error) {
+	start := time.Now()
+
+	update := bson.M{"$setOnInsert": category}
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+
+	var c models.Category
+	err := r.collection.FindOneAndUpdate(ctx, bson.M{"id": category.ID}, update, opts).Decode(&c)
+	if err != nil {
+		metrics.RecordDatabaseQuery("find_one_and_update", "categories", "error", time.Since(start).Seconds())
+		return nil, err
+	}
+
+	metrics.RecordDatabaseQuery("find_one_and_update", "categories", "success", time.Since(start).Seconds())
+	return &c, nil
+}
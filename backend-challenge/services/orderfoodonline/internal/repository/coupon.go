@@ -3,6 +3,8 @@ package repository
 import (
 	"context"
 	"fmt"
+	"orderfoodonline/internal/metrics"
+	"orderfoodonline/internal/repository/models"
 
 	"time"
 
@@ -13,17 +15,24 @@ import (
 
 // couponRepository provides MongoDB-backed access to coupon data.
 type couponRepository struct {
-	collection *mongo.Collection
+	collection  *mongo.Collection
+	usage       *mongo.Collection // One redemption counter per coupon
+	redemptions *mongo.Collection // One record per redemption
 }
 
 // NewCouponRepository creates a new CouponRepository using the given Repository.
 func NewCouponRepository(repo *Repository) (CouponRepository, error) {
-	collection := repo.db.Collection("coupons")
-
-	couponRepo := &couponRepository{collection: collection}
+	couponRepo := &couponRepository{
+		collection:  repo.db.Collection("coupons"),
+		usage:       repo.db.Collection("coupon_usage"),
+		redemptions: repo.db.Collection("coupon_redemptions"),
+	}
 	if err := couponRepo.createCouponIndex(context.Background()); err != nil {
 		return nil, err
 	}
+	if err := couponRepo.createRedemptionIndexes(context.Background()); err != nil {
+		return nil, err
+	}
 	return couponRepo, nil
 }
 
@@ -50,6 +59,33 @@ func (c *couponRepository) createCouponIndex(ctx context.Context) error {
 	return nil
 }
 
+// createRedemptionIndexes makes the usage counter unique per coupon and allows each customer
+// a single exclusive redemption per coupon. Other redemptions are not constrained.
+func (c *couponRepository) createRedemptionIndexes(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	_, err := c.usage.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "coupon_code", Value: 1}},
+		Options: options.Index().SetUnique(true).SetName("coupon_usage_code_idx"),
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = c.redemptions.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "coupon_code", Value: 1},
+			{Key: "customer_id", Value: 1},
+		},
+		Options: options.Index().
+			SetUnique(true).
+			SetPartialFilterExpression(bson.D{{Key: "exclusive", Value: true}}).
+			SetName("coupon_redemptions_customer_idx"),
+	})
+	return err
+}
+
 // ValidateCouponCode validates a coupon code according to the business rules:
 // 1. Must be found in at least two files (couponcode, filename distinct combo > 1)
 func (c *couponRepository) ValidateCouponCode(ctx context.Context, couponCode string) (bool, error) {
@@ -83,3 +119,85 @@ func (c *couponRepository) ValidateCouponCode(ctx context.Context, couponCode st
 	}
 	return false, nil
 }
+
+// FindCouponUsage returns the redemption counter of a coupon, or nil if it was never redeemed.
+func (c *couponRepository) FindCouponUsage(ctx context.Context, couponCode string) (*models.CouponUsage, error) {
+	start := time.Now()
+
+	var u models.CouponUsage
+	err := c.usage.FindOne(ctx, bson.M{"coupon_code": couponCode}).Decode(&u)
+	if err == mongo.ErrNoDocuments {
+		metrics.RecordDatabaseQuery("find_one", "coupon_usage", "not_found", time.Since(start).Seconds())
+		return nil, nil
+	}
+	if err != nil {
+		metrics.RecordDatabaseQuery("find_one", "coupon_usage", "error", time.Since(start).Seconds())
+		return nil, err
+	}
+
+	metrics.RecordDatabaseQuery("find_one", "coupon_usage", "success", time.Since(start).Seconds())
+	return &u, nil
+}
+
+// HasCustomerRedeemed reports whether a redemption of the coupon by the customer exists.
+func (c *couponRepository) HasCustomerRedeemed(ctx context.Context, couponCode, customerID string) (bool, error) {
+	start := time.Now()
+
+	filter := bson.M{"coupon_code": couponCode, "customer_id": customerID}
+	count, err := c.redemptions.CountDocuments(ctx, filter, options.Count().SetLimit(1))
+	if err != nil {
+		metrics.RecordDatabaseQuery("count", "coupon_redemptions", "error", time.Since(start).Seconds())
+		return false, err
+	}
+
+	metrics.RecordDatabaseQuery("count", "coupon_redemptions", "success", time.Since(start).Seconds())
+	return count > 0, nil
+}
+
+// IncrementCouponUsage adds one to the coupon's redemption counter, creating it on first use.
+// With a limit, the counter is only matched while it is below maxRedemptions; once it has
+// reached the limit the upsert tries to insert a second counter for the coupon, which the
+// unique index rejects, so concurrent redemptions can never exceed the limit.
+func (c *couponRepository) IncrementCouponUsage(ctx context.Context, couponCode string, maxRedemptions int64) (bool, error) {
+	start := time.Now()
+
+	filter := bson.M{"coupon_code": couponCode}
+	if maxRedemptions > 0 {
+		filter["redemptions"] = bson.M{"$lt": maxRedemptions}
+	}
+	update := bson.M{
+		"$inc": bson.M{"redemptions": 1},
+		"$set": bson.M{"updated_at": time.Now().Unix()},
+	}
+	_, err := c.usage.UpdateOne(ctx, filter, update, options.Update().SetUpsert(true))
+	if mongo.IsDuplicateKeyError(err) {
+		metrics.RecordDatabaseQuery("update_one", "coupon_usage", "limit_reached", time.Since(start).Seconds())
+		return false, nil
+	}
+	if err != nil {
+		metrics.RecordDatabaseQuery("update_one", "coupon_usage", "error", time.Since(start).Seconds())
+		return false, err
+	}
+
+	metrics.RecordDatabaseQuery("update_one", "coupon_usage", "success", time.Since(start).Seconds())
+	return true, nil
+}
+
+// InsertCouponRedemption stores a redemption record. A second exclusive redemption by the
+// same customer is rejected by the unique index and reported as false.
+func (c *couponRepository) InsertCouponRedemption(ctx context.Context, redemption *models.CouponRedemption) (bool, error) {
+	start := time.Now()
+
+	_, err := c.redemptions.InsertOne(ctx, redemption)
+	if mongo.IsDuplicateKeyError(err) {
+		metrics.RecordDatabaseQuery("insert_one", "coupon_redemptions", "duplicate", time.Since(start).Seconds())
+		return false, nil
+	}
+	if err != nil {
+		metrics.RecordDatabaseQuery("insert_one", "coupon_redemptions", "error", time.Since(start).Seconds())
+		return false, err
+	}
+
+	metrics.RecordDatabaseQuery("insert_one", "coupon_redemptions", "success", time.Since(start).Seconds())
+	return true, nil
+}
@@ -0,0 +1,162 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"orderfoodonline/internal/metrics"
+	"orderfoodonline/internal/repository/models"
+	"os"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// DefaultCouponRuleCacheTTL is how long the coupon rules are cached when no TTL is configured.
+const DefaultCouponRuleCacheTTL = 30 * time.Second
+
+// couponRuleDocument is a coupon rule as stored in the coupon_rules collection. Amounts are
+// stored in major currency units, as in the rules file, so that amount: 5 means 5.00.
+type couponRuleDocument struct {
+	models.CouponRule `bson:",inline"`
+	Amount            float64 `bson:"amount,omitempty"`
+	MinSubtotal       float64 `bson:"min_subtotal,omitempty"`
+}
+
+// couponRuleRepository provides MongoDB-backed access to coupon rules.
+type couponRuleRepository struct {
+	collection *mongo.Collection
+}
+
+// NewCouponRuleRepository creates a CouponRuleRepository reading the coupon_rules collection.
+func NewCouponRuleRepository(repo *Repository) (CouponRuleRepository, error) {
+	ruleRepo := &couponRuleRepository{collection: repo.db.Collection("coupon_rules")}
+	if err := ruleRepo.createCouponRuleIndexes(context.Background()); err != nil {
+		return nil, err
+	}
+	return ruleRepo, nil
+}
+
+func (r *couponRuleRepository) createCouponRuleIndexes(ctx context.Context) error {
+	indexModel := mongo.IndexModel{
+		Keys:    bson.D{{Key: "id", Value: 1}},
+		Options: options.Index().SetUnique(true).SetName("coupon_rule_id_idx"),
+	}
+
+	// Set a timeout context
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	_, err := r.collection.Indexes().CreateOne(ctx, indexModel)
+	return err
+}
+
+// ListCouponRules returns the active coupon rules ordered by ID.
+func (r *couponRuleRepository) ListCouponRules(ctx context.Context) ([]models.CouponRule, error) {
+	start := time.Now()
+
+	opts := options.Find().SetSort(bson.D{{Key: "id", Value: 1}})
+	cur, err := r.collection.Find(ctx, bson.M{"active": true}, opts)
+	if err != nil {
+		metrics.RecordDatabaseQuery("find", "coupon_rules", "error", time.Since(start).Seconds())
+		return nil, err
+	}
+	defer cur.Close(ctx)
+
+	var docs []couponRuleDocument
+	if err := cur.All(ctx, &docs); err != nil {
+		metrics.RecordDatabaseQuery("find", "coupon_rules", "error", time.Since(start).Seconds())
+		return nil, err
+	}
+
+	rules := make([]models.CouponRule, 0, len(docs))
+	for _, doc := range docs {
+		rule := doc.CouponRule
+		rule.Amount = models.MoneyFromFloat(doc.Amount)
+		rule.MinSubtotal = models.MoneyFromFloat(doc.MinSubtotal)
+		rules = append(rules, rule)
+	}
+
+	metrics.RecordDatabaseQuery("find", "coupon_rules", "success", time.Since(start).Seconds())
+	return rules, nil
+}
+
+// fileCouponRuleRepository serves coupon rules read once from a JSON file.
+type fileCouponRuleRepository struct {
+	rules []models.CouponRule
+}
+
+// NewFileCouponRuleRepository creates a CouponRuleRepository serving the rules in the JSON file
+// at path, which holds an array of coupon rules. The file is read once, when the repository is created.
+func NewFileCouponRuleRepository(path string) (CouponRuleRepository, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading coupon rules file: %w", err)
+	}
+	var rules []models.CouponRule
+	if err := json.Unmarshal(data, &rules); err != nil {
+		return nil, fmt.Errorf("error parsing coupon rules file: %w", err)
+	}
+
+	active := make([]models.CouponRule, 0, len(rules))
+	for _, rule := range rules {
+		if rule.Active {
+			active = append(active, rule)
+		}
+	}
+	return &fileCouponRuleRepository{rules: active}, nil
+}
+
+// ListCouponRules returns the active rules in file order.
+func (r *fileCouponRuleRepository) ListCouponRules(_ context.Context) ([]models.CouponRule, error) {
+	return r.rules, nil
+}
+
+// cachedCouponRuleRepository serves the rules of another CouponRuleRepository from memory.
+type cachedCouponRuleRepository struct {
+	repo      CouponRuleRepository
+	ttl       time.Duration
+	now       func() time.Time
+	mu        sync.Mutex
+	rules     []models.CouponRule
+	expiresAt time.Time
+}
+
+// NewCachedCouponRuleRepository creates a CouponRuleRepository caching the rules listed by repo
+// for ttl, so that orders, cart repricing and previews do not query them every time and a rule
+// change takes at most ttl to be noticed. A non-positive ttl falls back to DefaultCouponRuleCacheTTL.
+func NewCachedCouponRuleRepository(repo CouponRuleRepository, ttl time.Duration) CouponRuleRepository {
+	if ttl <= 0 {
+		ttl = DefaultCouponRuleCacheTTL
+	}
+	return &cachedCouponRuleRepository{repo: repo, ttl: ttl, now: time.Now}
+}
+
+// ListCouponRules returns the cached rules, or lists them again once they have expired.
+// Listings that fail with an error are not cached.
+func (r *cachedCouponRuleRepository) ListCouponRules(ctx context.Context) ([]models.CouponRule, error) {
+	now := r.now()
+
+	r.mu.Lock()
+	rules, expiresAt := r.rules, r.expiresAt
+	r.mu.Unlock()
+	if rules != nil && now.Before(expiresAt) {
+		return rules, nil
+	}
+
+	rules, err := r.repo.ListCouponRules(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if rules == nil {
+		rules = []models.CouponRule{}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.rules, r.expiresAt = rules, now.Add(r.ttl)
+	return rules, nil
+}
@@ -0,0 +1,72 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"orderfoodonline/internal/repository/mocks"
+	"orderfoodonline/internal/repository/models"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.uber.org/mock/gomock"
+)
+
+func TestCouponRuleDocument_AmountsInMajorUnits(t *testing.T) {
+	// Given: A fixed rule stored with whole and fractional amounts
+	data, err := bson.Marshal(bson.M{"id": "five-off", "type": "fixed", "amount": 5, "min_subtotal": 20.5, "active": true})
+	assert.NoError(t, err)
+
+	// When: Decoding it as the coupon_rules collection does
+	var doc couponRuleDocument
+	err = bson.Unmarshal(data, &doc)
+
+	// Then: The amounts should be read in major units, like the rules file
+	assert.NoError(t, err)
+	assert.Equal(t, "five-off", doc.ID)
+	assert.Equal(t, models.Money(500), models.MoneyFromFloat(doc.Amount))
+	assert.Equal(t, models.Money(2050), models.MoneyFromFloat(doc.MinSubtotal))
+}
+
+func TestCachedCouponRuleRepository_ListCouponRules(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// Given: A cached repository in front of one listing a single rule
+	rules := []models.CouponRule{{ID: "happy-hours", Type: models.DiscountPercent, Percent: 15, Active: true}}
+	backing := mocks.NewMockCouponRuleRepository(ctrl)
+	backing.EXPECT().ListCouponRules(gomock.Any()).Return(rules, nil).Times(1)
+	repo := NewCachedCouponRuleRepository(backing, 0)
+
+	// When: Listing the rules repeatedly within the TTL
+	for i := 0; i < 3; i++ {
+		found, err := repo.ListCouponRules(context.Background())
+
+		// Then: The rules should be served from the cache after the first listing
+		assert.NoError(t, err)
+		assert.Equal(t, rules, found)
+	}
+}
+
+func TestCachedCouponRuleRepository_DoesNotCacheErrors(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// Given: A cached repository in front of one that fails once
+	rules := []models.CouponRule{{ID: "happy-hours", Active: true}}
+	backing := mocks.NewMockCouponRuleRepository(ctrl)
+	gomock.InOrder(
+		backing.EXPECT().ListCouponRules(gomock.Any()).Return(nil, errors.New("database error")),
+		backing.EXPECT().ListCouponRules(gomock.Any()).Return(rules, nil),
+	)
+	repo := NewCachedCouponRuleRepository(backing, 0)
+
+	// When: Listing the rules after the failure
+	_, err := repo.ListCouponRules(context.Background())
+	assert.Error(t, err)
+	found, err := repo.ListCouponRules(context.Background())
+
+	// Then: The backing repository should have been asked again
+	assert.NoError(t, err)
+	assert.Equal(t, rules, found)
+}
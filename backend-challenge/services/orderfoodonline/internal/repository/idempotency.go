@@ -0,0 +1,153 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"orderfoodonline/internal/metrics"
+	"orderfoodonline/internal/repository/models"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// idempotencyRepository provides MongoDB-backed storage for idempotency keys.
+type idempotencyRepository struct {
+	collection *mongo.Collection
+}
+
+// NewIdempotencyRepository creates a new IdempotencyRepository using the given Repository.
+func NewIdempotencyRepository(repo *Repository) (IdempotencyRepository, error) {
+	collection := repo.db.Collection("idempotency_keys")
+
+	idempotencyRepo := &idempotencyRepository{collection: collection}
+	if err := idempotencyRepo.createIdempotencyIndexes(context.Background()); err != nil {
+		return nil, err
+	}
+	return idempotencyRepo, nil
+}
+
+// legacyIdempotencyKeyIndex is the name of the index that made keys unique across all callers.
+const legacyIdempotencyKeyIndex = "idempotency_key_idx"
+
+func (r *idempotencyRepository) createIdempotencyIndexes(ctx context.Context) error {
+	indexModels := []mongo.IndexModel{
+		{
+			// Keys are only unique per caller
+			Keys:    bson.D{{Key: "scope", Value: 1}, {Key: "key", Value: 1}},
+			Options: options.Index().SetUnique(true).SetName("idempotency_scope_key_idx"),
+		},
+		{
+			// Documents are removed by MongoDB once expires_at is in the past
+			Keys:    bson.D{{Key: "expires_at", Value: 1}},
+			Options: options.Index().SetExpireAfterSeconds(0).SetName("idempotency_expires_at_ttl_idx"),
+		},
+	}
+
+	// Set a timeout context
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	_, err := r.collection.Indexes().CreateMany(ctx, indexModels)
+	if err != nil {
+		return err
+	}
+
+	// The legacy index would keep keys unique across callers
+	_, err = r.collection.Indexes().DropOne(ctx, legacyIdempotencyKeyIndex)
+	if err != nil && !isIndexNotFound(err) {
+		return err
+	}
+
+	return nil
+}
+
+// isIndexNotFound reports whether dropping an index failed because it does not exist.
+func isIndexNotFound(err error) bool {
+	var cmdErr mongo.CommandError
+	return errors.As(err, &cmdErr) && (cmdErr.Name == "IndexNotFound" || cmdErr.Name == "NamespaceNotFound")
+}
+
+// CreateIdempotencyKey inserts a new idempotency record.
+// The TTL monitor runs periodically, so an expired record of the same key may still be stored;
+// it is treated as absent and replaced. Otherwise the upsert finds no expired record and tries
+// to insert a second one, which the unique index rejects.
+// Returns false without error if an unexpired record with the same key already exists.
+func (r *idempotencyRepository) CreateIdempotencyKey(ctx context.Context, record *models.IdempotencyRecord) (bool, error) {
+	start := time.Now()
+
+	filter := bson.M{"scope": record.Scope, "key": record.Key, "expires_at": bson.M{"$lte": time.Now()}}
+	_, err := r.collection.ReplaceOne(ctx, filter, record, options.Replace().SetUpsert(true))
+	if mongo.IsDuplicateKeyError(err) {
+		metrics.RecordDatabaseQuery("replace_one", "idempotency_keys", "duplicate", time.Since(start).Seconds())
+		return false, nil
+	}
+	if err != nil {
+		metrics.RecordDatabaseQuery("replace_one", "idempotency_keys", "error", time.Since(start).Seconds())
+		return false, err
+	}
+
+	metrics.RecordDatabaseQuery("replace_one", "idempotency_keys", "success", time.Since(start).Seconds())
+	return true, nil
+}
+
+// FindIdempotencyKey returns the record for a key of the scope, or nil if not found or already expired.
+func (r *idempotencyRepository) FindIdempotencyKey(ctx context.Context, scope, key string) (*models.IdempotencyRecord, error) {
+	start := time.Now()
+
+	// The TTL monitor runs periodically, so expired records are filtered out explicitly
+	filter := bson.M{"scope": scope, "key": key, "expires_at": bson.M{"$gt": time.Now()}}
+
+	var record models.IdempotencyRecord
+	err := r.collection.FindOne(ctx, filter).Decode(&record)
+	if err == mongo.ErrNoDocuments {
+		metrics.RecordDatabaseQuery("find_one", "idempotency_keys", "not_found", time.Since(start).Seconds())
+		return nil, nil
+	}
+	if err != nil {
+		metrics.RecordDatabaseQuery("find_one", "idempotency_keys", "error", time.Since(start).Seconds())
+		return nil, err
+	}
+
+	metrics.RecordDatabaseQuery("find_one", "idempotency_keys", "success", time.Since(start).Seconds())
+	return &record, nil
+}
+
+// CompleteIdempotencyKey stores the response of the original request so it can be replayed,
+// replacing the lease of the in-progress record with the retention of the response.
+func (r *idempotencyRepository) CompleteIdempotencyKey(ctx context.Context, scope, key string, responseCode int, responseBody []byte, expiresAt time.Time) error {
+	start := time.Now()
+
+	filter := bson.M{"scope": scope, "key": key}
+	update := bson.M{"$set": bson.M{
+		"status":        models.IdempotencyStatusCompleted,
+		"response_code": responseCode,
+		"response_body": responseBody,
+		"expires_at":    expiresAt,
+	}}
+	_, err := r.collection.UpdateOne(ctx, filter, update)
+
+	if err != nil {
+		metrics.RecordDatabaseQuery("update_one", "idempotency_keys", "error", time.Since(start).Seconds())
+		return err
+	}
+
+	metrics.RecordDatabaseQuery("update_one", "idempotency_keys", "success", time.Since(start).Seconds())
+	return nil
+}
+
+// DeleteIdempotencyKey removes a record so that the request can be retried.
+func (r *idempotencyRepository) DeleteIdempotencyKey(ctx context.Context, scope, key string) error {
+	start := time.Now()
+
+	_, err := r.collection.DeleteOne(ctx, bson.M{"scope": scope, "key": key})
+
+	if err != nil {
+		metrics.RecordDatabaseQuery("delete_one", "idempotency_keys", "error", time.Since(start).Seconds())
+		return err
+	}
+
+	metrics.RecordDatabaseQuery("delete_one", "idempotency_keys", "success", time.Since(start).Seconds())
+	return nil
+}
@@ -1,21 +1,66 @@
 // Package repository provides data access layer interfaces for the Order Food Online service.
+//
+// Every repository method runs its queries with the context it is given, so calls made with
+// the context passed to a UnitOfWork callback take part in that unit's transaction.
 package repository
 
 import (
 	"context"
 	"orderfoodonline/internal/repository/models"
+	"time"
 )
 
+// UnitOfWork groups repository calls into a single atomic operation.
+type UnitOfWork interface {
+	// WithinTransaction calls fn with a context bound to a new transaction. The transaction is
+	// committed if fn returns nil and aborted otherwise, in which case fn's error is returned.
+	// fn may be called more than once if the transaction hits a transient error, so it must
+	// not have side effects outside the repositories.
+	WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
 // ProductRepository defines methods for accessing and managing product data from the database.
 // It provides operations for listing products, finding specific products by ID,
 // bulk inserting products, and managing database migrations.
 type ProductRepository interface {
-	// ListProducts retrieves all available products from the database.
-	ListProducts(ctx context.Context) ([]models.Product, error)
+	// ListProducts retrieves a page of products that have not been deleted matching the
+	// filter, together with the total number of matching products.
+	ListProducts(ctx context.Context, filter models.ProductFilter) ([]models.Product, int64, error)
 
 	// FindProductByID retrieves a specific product by its unique identifier.
 	FindProductByID(ctx context.Context, id string) (*models.Product, error)
 
+	// FindProductsByIDs retrieves all products whose IDs are in ids using a single query.
+	// Unknown IDs are skipped, so the result may be shorter than ids and is in no particular order.
+	FindProductsByIDs(ctx context.Context, ids []string) ([]models.Product, error)
+
+	// CreateProduct inserts a new product. Returns false if a product with the same ID
+	// already exists, including a soft-deleted one.
+	CreateProduct(ctx context.Context, product *models.Product) (bool, error)
+
+	// UpdateProduct replaces the editable fields of an active product and increments its version.
+	// Returns the updated product, or nil if no active product has the ID.
+	UpdateProduct(ctx context.Context, product *models.Product) (*models.Product, error)
+
+	// DeleteProduct soft deletes a product so it is hidden from the catalog and can no longer
+	// be ordered, while orders that reference it stay intact. Returns false if no active product has the ID.
+	DeleteProduct(ctx context.Context, id string) (bool, error)
+
+	// CountProductsByCategory returns the number of active products per category ID.
+	CountProductsByCategory(ctx context.Context) (map[string]int64, error)
+
+	// ListUncategorizedCategoryNames returns the distinct category names of products
+	// that are not yet linked to a category ID.
+	ListUncategorizedCategoryNames(ctx context.Context) ([]string, error)
+
+	// AssignCategory links the products filed under categoryName that have no category ID
+	// to the given category. Returns the number of products updated.
+	AssignCategory(ctx context.Context, categoryName string, category *models.Category) (int64, error)
+
+	// MarkProductsAvailable makes every product stored before the available flag existed
+	// available. Returns the number of products updated.
+	MarkProductsAvailable(ctx context.Context) (int64, error)
+
 	// BulkInsertProducts inserts multiple products into the database in a single operation.
 	BulkInsertProducts(ctx context.Context, products []models.Product) error
 
@@ -29,11 +74,60 @@ type ProductRepository interface {
 	UpdateMigration(ctx context.Context, migration *models.Migration) error
 }
 
+// CategoryRepository defines methods for accessing product categories in the database.
+type CategoryRepository interface {
+	// ListCategories retrieves the active categories ordered by sort order, then name.
+	ListCategories(ctx context.Context) ([]models.Category, error)
+
+	// FindCategoryByID retrieves a category by its ID, active or not.
+	// Returns nil if the category does not exist.
+	FindCategoryByID(ctx context.Context, id string) (*models.Category, error)
+
+	// InsertCategoryIfMissing stores the category unless its ID is already taken and
+	// returns the stored category.
+	InsertCategoryIfMissing(ctx context.Context, category *models.Category) (*models.Category, error)
+}
+
+// StockRepository defines methods for tracking how many units of each product can be ordered.
+// Products without a stock record are not tracked and are never out of stock.
+type StockRepository interface {
+	// FindStockByProductID retrieves the stock of a product.
+	// Returns nil if the product's stock is not tracked.
+	FindStockByProductID(ctx context.Context, productID string) (*models.Stock, error)
+
+	// ReserveStock atomically takes quantity units of a product out of stock.
+	// Returns false if the stock is tracked and holds fewer than quantity units.
+	ReserveStock(ctx context.Context, productID string, quantity int) (bool, error)
+
+	// AddStock adds units to a product's stock, tracking it from now on, and returns the new stock.
+	AddStock(ctx context.Context, productID string, quantity int64) (*models.Stock, error)
+
+	// SetStock overwrites a product's stock, tracking it from now on, and returns the new stock.
+	SetStock(ctx context.Context, productID string, quantity int64) (*models.Stock, error)
+}
+
 // OrderRepository defines methods for placing and managing orders in the database.
 // It provides operations for creating new orders and retrieving order information.
 type OrderRepository interface {
 	// PlaceOrder creates a new order in the database and returns the created order with its ID.
 	PlaceOrder(ctx context.Context, order *models.Order) (*models.Order, error)
+
+	// FindOrderByID retrieves a specific order by its unique identifier.
+	// Returns nil if the order does not exist.
+	FindOrderByID(ctx context.Context, id string) (*models.Order, error)
+
+	// ListOrders retrieves a page of orders matching the filter, newest first,
+	// together with the total number of matching orders.
+	ListOrders(ctx context.Context, filter models.OrderFilter) ([]models.Order, int64, error)
+
+	// UpdateOrderStatus atomically moves an order from the expected current status to change.To
+	// and appends change to its history. Returns the updated order, or nil if the order does not
+	// exist or is no longer in the expected status.
+	UpdateOrderStatus(ctx context.Context, id string, from models.OrderStatus, change models.OrderStatusChange) (*models.Order, error)
+
+	// RenameLegacyOrderTimestamps renames the snake_case timestamps of orders stored before
+	// order documents used camelCase field names, and returns the number of orders updated.
+	RenameLegacyOrderTimestamps(ctx context.Context) (int64, error)
 }
 
 // CouponRepository defines methods for validating and managing coupon codes.
@@ -42,4 +136,78 @@ type CouponRepository interface {
 	// ValidateCouponCode checks if a given coupon code is valid and can be applied to orders.
 	// Returns true if the coupon is valid, false otherwise.
 	ValidateCouponCode(ctx context.Context, couponCode string) (bool, error)
+
+	// FindCouponUsage retrieves the number of times a coupon has been redeemed.
+	// Returns nil if the coupon has never been redeemed.
+	FindCouponUsage(ctx context.Context, couponCode string) (*models.CouponUsage, error)
+
+	// HasCustomerRedeemed reports whether the customer has already redeemed the coupon.
+	HasCustomerRedeemed(ctx context.Context, couponCode, customerID string) (bool, error)
+
+	// IncrementCouponUsage atomically counts one more redemption of a coupon.
+	// Returns false, without counting, if the coupon has already been redeemed
+	// maxRedemptions times. A maxRedemptions of 0 means no limit.
+	IncrementCouponUsage(ctx context.Context, couponCode string, maxRedemptions int64) (bool, error)
+
+	// InsertCouponRedemption records a redemption. Returns false if the redemption is exclusive
+	// and the customer already has an exclusive redemption of the coupon.
+	InsertCouponRedemption(ctx context.Context, redemption *models.CouponRedemption) (bool, error)
+}
+
+// CouponRuleRepository defines methods for reading the rules that give coupon codes their discount.
+type CouponRuleRepository interface {
+	// ListCouponRules retrieves the active coupon rules.
+	ListCouponRules(ctx context.Context) ([]models.CouponRule, error)
+}
+
+// CartRepository defines methods for storing carts between requests.
+// Carts are purged by the database once they expire.
+type CartRepository interface {
+	// CreateCart inserts a new cart, assigning its ID and initial version.
+	CreateCart(ctx context.Context, cart *models.Cart) (*models.Cart, error)
+
+	// FindCartByID retrieves an unexpired cart by its unique identifier.
+	// Returns nil if the cart does not exist or has expired.
+	FindCartByID(ctx context.Context, id string) (*models.Cart, error)
+
+	// UpdateCart replaces a cart if it is still at the version it was read at, and bumps its version.
+	// Returns the updated cart, or nil if the cart was changed or deleted in the meantime.
+	UpdateCart(ctx context.Context, cart *models.Cart) (*models.Cart, error)
+
+	// DeleteCart removes a cart. Returns false if no cart has the given ID.
+	DeleteCart(ctx context.Context, id string) (bool, error)
+}
+
+// APIKeyRepository defines methods for looking up the API keys issued to clients.
+type APIKeyRepository interface {
+	// FindAPIKeyByHash retrieves the key whose secret has the given hex-encoded SHA-256 hash,
+	// whether or not it is expired or revoked. Returns nil if no key has the hash.
+	FindAPIKeyByHash(ctx context.Context, keyHash string) (*models.APIKey, error)
+}
+
+// IdempotencyRepository defines methods for storing idempotency keys and the responses
+// they produced, so that retried requests can be answered without being re-executed.
+type IdempotencyRepository interface {
+	// CreateIdempotencyKey records a new key of the record's scope in the in-progress state,
+	// replacing an expired record of the same key. Returns false if an unexpired record of the key exists.
+	CreateIdempotencyKey(ctx context.Context, record *models.IdempotencyRecord) (bool, error)
+
+	// FindIdempotencyKey retrieves an unexpired idempotency record by scope and key.
+	// Returns nil if the key does not exist or has expired.
+	FindIdempotencyKey(ctx context.Context, scope, key string) (*models.IdempotencyRecord, error)
+
+	// CompleteIdempotencyKey marks a key as completed and stores the response to replay
+	// until expiresAt.
+	CompleteIdempotencyKey(ctx context.Context, scope, key string, responseCode int, responseBody []byte, expiresAt time.Time) error
+
+	// DeleteIdempotencyKey removes a key, allowing the request to be executed again.
+	DeleteIdempotencyKey(ctx context.Context, scope, key string) error
+}
+
+// RateLimitRepository defines methods for counting requests in rate limit windows
+// shared by every instance of the service.
+type RateLimitRepository interface {
+	// IncrementRateLimit atomically adds a request to the counter with the given ID and returns
+	// the number of requests it holds. A missing counter is created to expire at expiresAt.
+	IncrementRateLimit(ctx context.Context, id string, expiresAt time.Time) (int64, error)
 }
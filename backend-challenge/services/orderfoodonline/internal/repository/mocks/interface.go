@@ -13,10 +13,49 @@ import (
 	context "context"
 	models "orderfoodonline/internal/repository/models"
 	reflect "reflect"
+	time "time"
 
 	gomock "go.uber.org/mock/gomock"
 )
 
+// MockUnitOfWork is a mock of UnitOfWork interface.
+type MockUnitOfWork struct {
+	ctrl     *gomock.Controller
+	recorder *MockUnitOfWorkMockRecorder
+	isgomock struct{}
+}
+
+// MockUnitOfWorkMockRecorder is the mock recorder for MockUnitOfWork.
+type MockUnitOfWorkMockRecorder struct {
+	mock *MockUnitOfWork
+}
+
+// NewMockUnitOfWork creates a new mock instance.
+func NewMockUnitOfWork(ctrl *gomock.Controller) *MockUnitOfWork {
+	mock := &MockUnitOfWork{ctrl: ctrl}
+	mock.recorder = &MockUnitOfWorkMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockUnitOfWork) EXPECT() *MockUnitOfWorkMockRecorder {
+	return m.recorder
+}
+
+// WithinTransaction mocks base method.
+func (m *MockUnitOfWork) WithinTransaction(ctx context.Context, fn func(context.Context) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WithinTransaction", ctx, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WithinTransaction indicates an expected call of WithinTransaction.
+func (mr *MockUnitOfWorkMockRecorder) WithinTransaction(ctx, fn any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithinTransaction", reflect.TypeOf((*MockUnitOfWork)(nil).WithinTransaction), ctx, fn)
+}
+
 // MockProductRepository is a mock of ProductRepository interface.
 type MockProductRepository struct {
 	ctrl     *gomock.Controller
@@ -41,6 +80,21 @@ func (m *MockProductRepository) EXPECT() *MockProductRepositoryMockRecorder {
 	return m.recorder
 }
 
+// AssignCategory mocks base method.
+func (m *MockProductRepository) AssignCategory(ctx context.Context, categoryName string, category *models.Category) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AssignCategory", ctx, categoryName, category)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AssignCategory indicates an expected call of AssignCategory.
+func (mr *MockProductRepositoryMockRecorder) AssignCategory(ctx, categoryName, category any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AssignCategory", reflect.TypeOf((*MockProductRepository)(nil).AssignCategory), ctx, categoryName, category)
+}
+
 // BulkInsertProducts mocks base method.
 func (m *MockProductRepository) BulkInsertProducts(ctx context.Context, products []models.Product) error {
 	m.ctrl.T.Helper()
@@ -55,6 +109,51 @@ func (mr *MockProductRepositoryMockRecorder) BulkInsertProducts(ctx, products an
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BulkInsertProducts", reflect.TypeOf((*MockProductRepository)(nil).BulkInsertProducts), ctx, products)
 }
 
+// CountProductsByCategory mocks base method.
+func (m *MockProductRepository) CountProductsByCategory(ctx context.Context) (map[string]int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountProductsByCategory", ctx)
+	ret0, _ := ret[0].(map[string]int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountProductsByCategory indicates an expected call of CountProductsByCategory.
+func (mr *MockProductRepositoryMockRecorder) CountProductsByCategory(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountProductsByCategory", reflect.TypeOf((*MockProductRepository)(nil).CountProductsByCategory), ctx)
+}
+
+// CreateProduct mocks base method.
+func (m *MockProductRepository) CreateProduct(ctx context.Context, product *models.Product) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateProduct", ctx, product)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateProduct indicates an expected call of CreateProduct.
+func (mr *MockProductRepositoryMockRecorder) CreateProduct(ctx, product any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateProduct", reflect.TypeOf((*MockProductRepository)(nil).CreateProduct), ctx, product)
+}
+
+// DeleteProduct mocks base method.
+func (m *MockProductRepository) DeleteProduct(ctx context.Context, id string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteProduct", ctx, id)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteProduct indicates an expected call of DeleteProduct.
+func (mr *MockProductRepositoryMockRecorder) DeleteProduct(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteProduct", reflect.TypeOf((*MockProductRepository)(nil).DeleteProduct), ctx, id)
+}
+
 // FindProductByID mocks base method.
 func (m *MockProductRepository) FindProductByID(ctx context.Context, id string) (*models.Product, error) {
 	m.ctrl.T.Helper()
@@ -70,6 +169,21 @@ func (mr *MockProductRepositoryMockRecorder) FindProductByID(ctx, id any) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindProductByID", reflect.TypeOf((*MockProductRepository)(nil).FindProductByID), ctx, id)
 }
 
+// FindProductsByIDs mocks base method.
+func (m *MockProductRepository) FindProductsByIDs(ctx context.Context, ids []string) ([]models.Product, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindProductsByIDs", ctx, ids)
+	ret0, _ := ret[0].([]models.Product)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindProductsByIDs indicates an expected call of FindProductsByIDs.
+func (mr *MockProductRepositoryMockRecorder) FindProductsByIDs(ctx, ids any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindProductsByIDs", reflect.TypeOf((*MockProductRepository)(nil).FindProductsByIDs), ctx, ids)
+}
+
 // GetAppliedMigrations mocks base method.
 func (m *MockProductRepository) GetAppliedMigrations(ctx context.Context) ([]models.Migration, error) {
 	m.ctrl.T.Helper()
@@ -100,18 +214,49 @@ func (mr *MockProductRepositoryMockRecorder) InsertMigration(ctx, migration any)
 }
 
 // ListProducts mocks base method.
-func (m *MockProductRepository) ListProducts(ctx context.Context) ([]models.Product, error) {
+func (m *MockProductRepository) ListProducts(ctx context.Context, filter models.ProductFilter) ([]models.Product, int64, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListProducts", ctx)
+	ret := m.ctrl.Call(m, "ListProducts", ctx, filter)
 	ret0, _ := ret[0].([]models.Product)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListProducts indicates an expected call of ListProducts.
+func (mr *MockProductRepositoryMockRecorder) ListProducts(ctx, filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListProducts", reflect.TypeOf((*MockProductRepository)(nil).ListProducts), ctx, filter)
+}
+
+// ListUncategorizedCategoryNames mocks base method.
+func (m *MockProductRepository) ListUncategorizedCategoryNames(ctx context.Context) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListUncategorizedCategoryNames", ctx)
+	ret0, _ := ret[0].([]string)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// ListProducts indicates an expected call of ListProducts.
-func (mr *MockProductRepositoryMockRecorder) ListProducts(ctx any) *gomock.Call {
+// ListUncategorizedCategoryNames indicates an expected call of ListUncategorizedCategoryNames.
+func (mr *MockProductRepositoryMockRecorder) ListUncategorizedCategoryNames(ctx any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListProducts", reflect.TypeOf((*MockProductRepository)(nil).ListProducts), ctx)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUncategorizedCategoryNames", reflect.TypeOf((*MockProductRepository)(nil).ListUncategorizedCategoryNames), ctx)
+}
+
+// MarkProductsAvailable mocks base method.
+func (m *MockProductRepository) MarkProductsAvailable(ctx context.Context) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkProductsAvailable", ctx)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MarkProductsAvailable indicates an expected call of MarkProductsAvailable.
+func (mr *MockProductRepositoryMockRecorder) MarkProductsAvailable(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkProductsAvailable", reflect.TypeOf((*MockProductRepository)(nil).MarkProductsAvailable), ctx)
 }
 
 // UpdateMigration mocks base method.
@@ -128,6 +273,174 @@ func (mr *MockProductRepositoryMockRecorder) UpdateMigration(ctx, migration any)
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateMigration", reflect.TypeOf((*MockProductRepository)(nil).UpdateMigration), ctx, migration)
 }
 
+// UpdateProduct mocks base method.
+func (m *MockProductRepository) UpdateProduct(ctx context.Context, product *models.Product) (*models.Product, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateProduct", ctx, product)
+	ret0, _ := ret[0].(*models.Product)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateProduct indicates an expected call of UpdateProduct.
+func (mr *MockProductRepositoryMockRecorder) UpdateProduct(ctx, product any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateProduct", reflect.TypeOf((*MockProductRepository)(nil).UpdateProduct), ctx, product)
+}
+
+// MockCategoryRepository is a mock of CategoryRepository interface.
+type MockCategoryRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockCategoryRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockCategoryRepositoryMockRecorder is the mock recorder for MockCategoryRepository.
+type MockCategoryRepositoryMockRecorder struct {
+	mock *MockCategoryRepository
+}
+
+// NewMockCategoryRepository creates a new mock instance.
+func NewMockCategoryRepository(ctrl *gomock.Controller) *MockCategoryRepository {
+	mock := &MockCategoryRepository{ctrl: ctrl}
+	mock.recorder = &MockCategoryRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCategoryRepository) EXPECT() *MockCategoryRepositoryMockRecorder {
+	return m.recorder
+}
+
+// FindCategoryByID mocks base method.
+func (m *MockCategoryRepository) FindCategoryByID(ctx context.Context, id string) (*models.Category, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindCategoryByID", ctx, id)
+	ret0, _ := ret[0].(*models.Category)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindCategoryByID indicates an expected call of FindCategoryByID.
+func (mr *MockCategoryRepositoryMockRecorder) FindCategoryByID(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindCategoryByID", reflect.TypeOf((*MockCategoryRepository)(nil).FindCategoryByID), ctx, id)
+}
+
+// InsertCategoryIfMissing mocks base method.
+func (m *MockCategoryRepository) InsertCategoryIfMissing(ctx context.Context, category *models.Category) (*models.Category, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertCategoryIfMissing", ctx, category)
+	ret0, _ := ret[0].(*models.Category)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertCategoryIfMissing indicates an expected call of InsertCategoryIfMissing.
+func (mr *MockCategoryRepositoryMockRecorder) InsertCategoryIfMissing(ctx, category any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertCategoryIfMissing", reflect.TypeOf((*MockCategoryRepository)(nil).InsertCategoryIfMissing), ctx, category)
+}
+
+// ListCategories mocks base method.
+func (m *MockCategoryRepository) ListCategories(ctx context.Context) ([]models.Category, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListCategories", ctx)
+	ret0, _ := ret[0].([]models.Category)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListCategories indicates an expected call of ListCategories.
+func (mr *MockCategoryRepositoryMockRecorder) ListCategories(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCategories", reflect.TypeOf((*MockCategoryRepository)(nil).ListCategories), ctx)
+}
+
+// MockStockRepository is a mock of StockRepository interface.
+type MockStockRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockStockRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockStockRepositoryMockRecorder is the mock recorder for MockStockRepository.
+type MockStockRepositoryMockRecorder struct {
+	mock *MockStockRepository
+}
+
+// NewMockStockRepository creates a new mock instance.
+func NewMockStockRepository(ctrl *gomock.Controller) *MockStockRepository {
+	mock := &MockStockRepository{ctrl: ctrl}
+	mock.recorder = &MockStockRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockStockRepository) EXPECT() *MockStockRepositoryMockRecorder {
+	return m.recorder
+}
+
+// AddStock mocks base method.
+func (m *MockStockRepository) AddStock(ctx context.Context, productID string, quantity int64) (*models.Stock, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddStock", ctx, productID, quantity)
+	ret0, _ := ret[0].(*models.Stock)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddStock indicates an expected call of AddStock.
+func (mr *MockStockRepositoryMockRecorder) AddStock(ctx, productID, quantity any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddStock", reflect.TypeOf((*MockStockRepository)(nil).AddStock), ctx, productID, quantity)
+}
+
+// FindStockByProductID mocks base method.
+func (m *MockStockRepository) FindStockByProductID(ctx context.Context, productID string) (*models.Stock, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindStockByProductID", ctx, productID)
+	ret0, _ := ret[0].(*models.Stock)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindStockByProductID indicates an expected call of FindStockByProductID.
+func (mr *MockStockRepositoryMockRecorder) FindStockByProductID(ctx, productID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindStockByProductID", reflect.TypeOf((*MockStockRepository)(nil).FindStockByProductID), ctx, productID)
+}
+
+// ReserveStock mocks base method.
+func (m *MockStockRepository) ReserveStock(ctx context.Context, productID string, quantity int) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReserveStock", ctx, productID, quantity)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReserveStock indicates an expected call of ReserveStock.
+func (mr *MockStockRepositoryMockRecorder) ReserveStock(ctx, productID, quantity any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReserveStock", reflect.TypeOf((*MockStockRepository)(nil).ReserveStock), ctx, productID, quantity)
+}
+
+// SetStock mocks base method.
+func (m *MockStockRepository) SetStock(ctx context.Context, productID string, quantity int64) (*models.Stock, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetStock", ctx, productID, quantity)
+	ret0, _ := ret[0].(*models.Stock)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetStock indicates an expected call of SetStock.
+func (mr *MockStockRepositoryMockRecorder) SetStock(ctx, productID, quantity any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetStock", reflect.TypeOf((*MockStockRepository)(nil).SetStock), ctx, productID, quantity)
+}
+
 // MockOrderRepository is a mock of OrderRepository interface.
 type MockOrderRepository struct {
 	ctrl     *gomock.Controller
@@ -152,6 +465,37 @@ func (m *MockOrderRepository) EXPECT() *MockOrderRepositoryMockRecorder {
 	return m.recorder
 }
 
+// FindOrderByID mocks base method.
+func (m *MockOrderRepository) FindOrderByID(ctx context.Context, id string) (*models.Order, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindOrderByID", ctx, id)
+	ret0, _ := ret[0].(*models.Order)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindOrderByID indicates an expected call of FindOrderByID.
+func (mr *MockOrderRepositoryMockRecorder) FindOrderByID(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindOrderByID", reflect.TypeOf((*MockOrderRepository)(nil).FindOrderByID), ctx, id)
+}
+
+// ListOrders mocks base method.
+func (m *MockOrderRepository) ListOrders(ctx context.Context, filter models.OrderFilter) ([]models.Order, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListOrders", ctx, filter)
+	ret0, _ := ret[0].([]models.Order)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListOrders indicates an expected call of ListOrders.
+func (mr *MockOrderRepositoryMockRecorder) ListOrders(ctx, filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListOrders", reflect.TypeOf((*MockOrderRepository)(nil).ListOrders), ctx, filter)
+}
+
 // PlaceOrder mocks base method.
 func (m *MockOrderRepository) PlaceOrder(ctx context.Context, order *models.Order) (*models.Order, error) {
 	m.ctrl.T.Helper()
@@ -167,6 +511,36 @@ func (mr *MockOrderRepositoryMockRecorder) PlaceOrder(ctx, order any) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PlaceOrder", reflect.TypeOf((*MockOrderRepository)(nil).PlaceOrder), ctx, order)
 }
 
+// RenameLegacyOrderTimestamps mocks base method.
+func (m *MockOrderRepository) RenameLegacyOrderTimestamps(ctx context.Context) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RenameLegacyOrderTimestamps", ctx)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RenameLegacyOrderTimestamps indicates an expected call of RenameLegacyOrderTimestamps.
+func (mr *MockOrderRepositoryMockRecorder) RenameLegacyOrderTimestamps(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RenameLegacyOrderTimestamps", reflect.TypeOf((*MockOrderRepository)(nil).RenameLegacyOrderTimestamps), ctx)
+}
+
+// UpdateOrderStatus mocks base method.
+func (m *MockOrderRepository) UpdateOrderStatus(ctx context.Context, id string, from models.OrderStatus, change models.OrderStatusChange) (*models.Order, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateOrderStatus", ctx, id, from, change)
+	ret0, _ := ret[0].(*models.Order)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateOrderStatus indicates an expected call of UpdateOrderStatus.
+func (mr *MockOrderRepositoryMockRecorder) UpdateOrderStatus(ctx, id, from, change any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateOrderStatus", reflect.TypeOf((*MockOrderRepository)(nil).UpdateOrderStatus), ctx, id, from, change)
+}
+
 // MockCouponRepository is a mock of CouponRepository interface.
 type MockCouponRepository struct {
 	ctrl     *gomock.Controller
@@ -191,6 +565,66 @@ func (m *MockCouponRepository) EXPECT() *MockCouponRepositoryMockRecorder {
 	return m.recorder
 }
 
+// FindCouponUsage mocks base method.
+func (m *MockCouponRepository) FindCouponUsage(ctx context.Context, couponCode string) (*models.CouponUsage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindCouponUsage", ctx, couponCode)
+	ret0, _ := ret[0].(*models.CouponUsage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindCouponUsage indicates an expected call of FindCouponUsage.
+func (mr *MockCouponRepositoryMockRecorder) FindCouponUsage(ctx, couponCode any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindCouponUsage", reflect.TypeOf((*MockCouponRepository)(nil).FindCouponUsage), ctx, couponCode)
+}
+
+// HasCustomerRedeemed mocks base method.
+func (m *MockCouponRepository) HasCustomerRedeemed(ctx context.Context, couponCode, customerID string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HasCustomerRedeemed", ctx, couponCode, customerID)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// HasCustomerRedeemed indicates an expected call of HasCustomerRedeemed.
+func (mr *MockCouponRepositoryMockRecorder) HasCustomerRedeemed(ctx, couponCode, customerID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HasCustomerRedeemed", reflect.TypeOf((*MockCouponRepository)(nil).HasCustomerRedeemed), ctx, couponCode, customerID)
+}
+
+// IncrementCouponUsage mocks base method.
+func (m *MockCouponRepository) IncrementCouponUsage(ctx context.Context, couponCode string, maxRedemptions int64) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IncrementCouponUsage", ctx, couponCode, maxRedemptions)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IncrementCouponUsage indicates an expected call of IncrementCouponUsage.
+func (mr *MockCouponRepositoryMockRecorder) IncrementCouponUsage(ctx, couponCode, maxRedemptions any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IncrementCouponUsage", reflect.TypeOf((*MockCouponRepository)(nil).IncrementCouponUsage), ctx, couponCode, maxRedemptions)
+}
+
+// InsertCouponRedemption mocks base method.
+func (m *MockCouponRepository) InsertCouponRedemption(ctx context.Context, redemption *models.CouponRedemption) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertCouponRedemption", ctx, redemption)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertCouponRedemption indicates an expected call of InsertCouponRedemption.
+func (mr *MockCouponRepositoryMockRecorder) InsertCouponRedemption(ctx, redemption any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertCouponRedemption", reflect.TypeOf((*MockCouponRepository)(nil).InsertCouponRedemption), ctx, redemption)
+}
+
 // ValidateCouponCode mocks base method.
 func (m *MockCouponRepository) ValidateCouponCode(ctx context.Context, couponCode string) (bool, error) {
 	m.ctrl.T.Helper()
@@ -205,3 +639,286 @@ func (mr *MockCouponRepositoryMockRecorder) ValidateCouponCode(ctx, couponCode a
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ValidateCouponCode", reflect.TypeOf((*MockCouponRepository)(nil).ValidateCouponCode), ctx, couponCode)
 }
+
+// MockCouponRuleRepository is a mock of CouponRuleRepository interface.
+type MockCouponRuleRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockCouponRuleRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockCouponRuleRepositoryMockRecorder is the mock recorder for MockCouponRuleRepository.
+type MockCouponRuleRepositoryMockRecorder struct {
+	mock *MockCouponRuleRepository
+}
+
+// NewMockCouponRuleRepository creates a new mock instance.
+func NewMockCouponRuleRepository(ctrl *gomock.Controller) *MockCouponRuleRepository {
+	mock := &MockCouponRuleRepository{ctrl: ctrl}
+	mock.recorder = &MockCouponRuleRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCouponRuleRepository) EXPECT() *MockCouponRuleRepositoryMockRecorder {
+	return m.recorder
+}
+
+// ListCouponRules mocks base method.
+func (m *MockCouponRuleRepository) ListCouponRules(ctx context.Context) ([]models.CouponRule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListCouponRules", ctx)
+	ret0, _ := ret[0].([]models.CouponRule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListCouponRules indicates an expected call of ListCouponRules.
+func (mr *MockCouponRuleRepositoryMockRecorder) ListCouponRules(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCouponRules", reflect.TypeOf((*MockCouponRuleRepository)(nil).ListCouponRules), ctx)
+}
+
+// MockCartRepository is a mock of CartRepository interface.
+type MockCartRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockCartRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockCartRepositoryMockRecorder is the mock recorder for MockCartRepository.
+type MockCartRepositoryMockRecorder struct {
+	mock *MockCartRepository
+}
+
+// NewMockCartRepository creates a new mock instance.
+func NewMockCartRepository(ctrl *gomock.Controller) *MockCartRepository {
+	mock := &MockCartRepository{ctrl: ctrl}
+	mock.recorder = &MockCartRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCartRepository) EXPECT() *MockCartRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CreateCart mocks base method.
+func (m *MockCartRepository) CreateCart(ctx context.Context, cart *models.Cart) (*models.Cart, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateCart", ctx, cart)
+	ret0, _ := ret[0].(*models.Cart)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateCart indicates an expected call of CreateCart.
+func (mr *MockCartRepositoryMockRecorder) CreateCart(ctx, cart any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateCart", reflect.TypeOf((*MockCartRepository)(nil).CreateCart), ctx, cart)
+}
+
+// DeleteCart mocks base method.
+func (m *MockCartRepository) DeleteCart(ctx context.Context, id string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteCart", ctx, id)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteCart indicates an expected call of DeleteCart.
+func (mr *MockCartRepositoryMockRecorder) DeleteCart(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteCart", reflect.TypeOf((*MockCartRepository)(nil).DeleteCart), ctx, id)
+}
+
+// FindCartByID mocks base method.
+func (m *MockCartRepository) FindCartByID(ctx context.Context, id string) (*models.Cart, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindCartByID", ctx, id)
+	ret0, _ := ret[0].(*models.Cart)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindCartByID indicates an expected call of FindCartByID.
+func (mr *MockCartRepositoryMockRecorder) FindCartByID(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindCartByID", reflect.TypeOf((*MockCartRepository)(nil).FindCartByID), ctx, id)
+}
+
+// UpdateCart mocks base method.
+func (m *MockCartRepository) UpdateCart(ctx context.Context, cart *models.Cart) (*models.Cart, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateCart", ctx, cart)
+	ret0, _ := ret[0].(*models.Cart)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateCart indicates an expected call of UpdateCart.
+func (mr *MockCartRepositoryMockRecorder) UpdateCart(ctx, cart any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateCart", reflect.TypeOf((*MockCartRepository)(nil).UpdateCart), ctx, cart)
+}
+
+// MockAPIKeyRepository is a mock of APIKeyRepository interface.
+type MockAPIKeyRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockAPIKeyRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockAPIKeyRepositoryMockRecorder is the mock recorder for MockAPIKeyRepository.
+type MockAPIKeyRepositoryMockRecorder struct {
+	mock *MockAPIKeyRepository
+}
+
+// NewMockAPIKeyRepository creates a new mock instance.
+func NewMockAPIKeyRepository(ctrl *gomock.Controller) *MockAPIKeyRepository {
+	mock := &MockAPIKeyRepository{ctrl: ctrl}
+	mock.recorder = &MockAPIKeyRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAPIKeyRepository) EXPECT() *MockAPIKeyRepositoryMockRecorder {
+	return m.recorder
+}
+
+// FindAPIKeyByHash mocks base method.
+func (m *MockAPIKeyRepository) FindAPIKeyByHash(ctx context.Context, keyHash string) (*models.APIKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindAPIKeyByHash", ctx, keyHash)
+	ret0, _ := ret[0].(*models.APIKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindAPIKeyByHash indicates an expected call of FindAPIKeyByHash.
+func (mr *MockAPIKeyRepositoryMockRecorder) FindAPIKeyByHash(ctx, keyHash any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindAPIKeyByHash", reflect.TypeOf((*MockAPIKeyRepository)(nil).FindAPIKeyByHash), ctx, keyHash)
+}
+
+// MockIdempotencyRepository is a mock of IdempotencyRepository interface.
+type MockIdempotencyRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockIdempotencyRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockIdempotencyRepositoryMockRecorder is the mock recorder for MockIdempotencyRepository.
+type MockIdempotencyRepositoryMockRecorder struct {
+	mock *MockIdempotencyRepository
+}
+
+// NewMockIdempotencyRepository creates a new mock instance.
+func NewMockIdempotencyRepository(ctrl *gomock.Controller) *MockIdempotencyRepository {
+	mock := &MockIdempotencyRepository{ctrl: ctrl}
+	mock.recorder = &MockIdempotencyRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIdempotencyRepository) EXPECT() *MockIdempotencyRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CompleteIdempotencyKey mocks base method.
+func (m *MockIdempotencyRepository) CompleteIdempotencyKey(ctx context.Context, scope, key string, responseCode int, responseBody []byte, expiresAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CompleteIdempotencyKey", ctx, scope, key, responseCode, responseBody, expiresAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CompleteIdempotencyKey indicates an expected call of CompleteIdempotencyKey.
+func (mr *MockIdempotencyRepositoryMockRecorder) CompleteIdempotencyKey(ctx, scope, key, responseCode, responseBody, expiresAt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompleteIdempotencyKey", reflect.TypeOf((*MockIdempotencyRepository)(nil).CompleteIdempotencyKey), ctx, scope, key, responseCode, responseBody, expiresAt)
+}
+
+// CreateIdempotencyKey mocks base method.
+func (m *MockIdempotencyRepository) CreateIdempotencyKey(ctx context.Context, record *models.IdempotencyRecord) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateIdempotencyKey", ctx, record)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateIdempotencyKey indicates an expected call of CreateIdempotencyKey.
+func (mr *MockIdempotencyRepositoryMockRecorder) CreateIdempotencyKey(ctx, record any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateIdempotencyKey", reflect.TypeOf((*MockIdempotencyRepository)(nil).CreateIdempotencyKey), ctx, record)
+}
+
+// DeleteIdempotencyKey mocks base method.
+func (m *MockIdempotencyRepository) DeleteIdempotencyKey(ctx context.Context, scope, key string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteIdempotencyKey", ctx, scope, key)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteIdempotencyKey indicates an expected call of DeleteIdempotencyKey.
+func (mr *MockIdempotencyRepositoryMockRecorder) DeleteIdempotencyKey(ctx, scope, key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteIdempotencyKey", reflect.TypeOf((*MockIdempotencyRepository)(nil).DeleteIdempotencyKey), ctx, scope, key)
+}
+
+// FindIdempotencyKey mocks base method.
+func (m *MockIdempotencyRepository) FindIdempotencyKey(ctx context.Context, scope, key string) (*models.IdempotencyRecord, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindIdempotencyKey", ctx, scope, key)
+	ret0, _ := ret[0].(*models.IdempotencyRecord)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindIdempotencyKey indicates an expected call of FindIdempotencyKey.
+func (mr *MockIdempotencyRepositoryMockRecorder) FindIdempotencyKey(ctx, scope, key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindIdempotencyKey", reflect.TypeOf((*MockIdempotencyRepository)(nil).FindIdempotencyKey), ctx, scope, key)
+}
+
+// MockRateLimitRepository is a mock of RateLimitRepository interface.
+type MockRateLimitRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockRateLimitRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockRateLimitRepositoryMockRecorder is the mock recorder for MockRateLimitRepository.
+type MockRateLimitRepositoryMockRecorder struct {
+	mock *MockRateLimitRepository
+}
+
+// NewMockRateLimitRepository creates a new mock instance.
+func NewMockRateLimitRepository(ctrl *gomock.Controller) *MockRateLimitRepository {
+	mock := &MockRateLimitRepository{ctrl: ctrl}
+	mock.recorder = &MockRateLimitRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRateLimitRepository) EXPECT() *MockRateLimitRepositoryMockRecorder {
+	return m.recorder
+}
+
+// IncrementRateLimit mocks base method.
+func (m *MockRateLimitRepository) IncrementRateLimit(ctx context.Context, id string, expiresAt time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IncrementRateLimit", ctx, id, expiresAt)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IncrementRateLimit indicates an expected call of IncrementRateLimit.
+func (mr *MockRateLimitRepositoryMockRecorder) IncrementRateLimit(ctx, id, expiresAt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IncrementRateLimit", reflect.TypeOf((*MockRateLimitRepository)(nil).IncrementRateLimit), ctx, id, expiresAt)
+}
@@ -0,0 +1,32 @@
+package models
+
+import "time"
+
+// APIKey is a key accepted in the api_key header, identified by its name.
+// Only the SHA-256 hash of the secret is stored.
+type APIKey struct {
+	Name       string    `bson:"name" json:"name"`                                  // Name identifying the key's holder in logs
+	KeyHash    string    `bson:"key_hash" json:"-"`                                 // Hex-encoded SHA-256 hash of the secret
+	Scopes     []string  `bson:"scopes" json:"scopes"`                              // Scopes granted to the key
+	ExpiresAt  time.Time `bson:"expires_at,omitempty" json:"expiresAt,omitempty"`   // The key is rejected after this time (zero = never)
+	Revoked    bool      `bson:"revoked" json:"revoked"`                            // The key is rejected
+	CustomerID string    `bson:"customer_id,omitempty" json:"customerId,omitempty"` // Customer the key's orders, carts and coupon redemptions are made for (empty = none)
+	CreatedAt  int64     `bson:"created_at" json:"createdAt" example:"1718000000"`  // Unix timestamp when the key was issued
+}
+
+// Expired reports whether the key has an expiry that is not after now.
+func (k *APIKey) Expired(now time.Time) bool {
+	return !k.ExpiresAt.IsZero() && !now.Before(k.ExpiresAt)
+}
+
+// InsufficientScopeCode is the error code returned when the caller lacks a scope the endpoint requires.
+const InsufficientScopeCode = "insufficient_scope"
+
+// ForbiddenResponse is the error body returned when the caller is not granted the scopes an endpoint requires.
+type ForbiddenResponse struct {
+	Error          string   `json:"error" example:"Forbidden"`                                          // Error message
+	Code           string   `json:"code" example:"insufficient_scope"`                                  // Machine-readable error code, always InsufficientScopeCode
+	RequiredScopes []string `json:"requiredScopes" example:"catalog:admin"`                             // Scopes the endpoint requires
+	MissingScopes  []string `json:"missingScopes" example:"catalog:admin"`                              // Required scopes the caller was not granted
+	RequestID      string   `json:"requestId,omitempty" example:"3f2b8c1e-0d4a-4e5b-9c6d-7a8b9c0d1e2f"` // ID of the request, to quote when reporting the error
+}
@@ -0,0 +1,47 @@
+package models
+
+import "time"
+
+// CartItem is a product and the number of units of it held in a cart.
+type CartItem struct {
+	ProductID string `bson:"productId" json:"productId" example:"10"`
+	Quantity  int    `bson:"quantity" json:"quantity" example:"2"`
+}
+
+// Cart is a basket kept on the server until it is checked out or expires.
+// Its lines and amounts are re-priced against the catalog every time the cart is read or changed.
+type Cart struct {
+	ID                    string           `bson:"id" json:"id"`
+	CustomerID            string           `bson:"customerId,omitempty" json:"customerId,omitempty" example:"cust-42"`              // Customer the cart belongs to; only requests made for this customer can see or change the cart
+	Items                 []CartItem       `bson:"items" json:"items"`                                                              // Products in the cart, in the order they were added
+	CouponCode            string           `bson:"couponCode,omitempty" json:"couponCode,omitempty" example:"HAPPYHRS"`             // Coupon applied to the cart, if any
+	CouponReason          string           `bson:"couponReason,omitempty" json:"couponReason,omitempty" example:"minimum_not_met"`  // Why the applied coupon currently grants no discount
+	Lines                 []OrderLine      `bson:"lines" json:"lines"`                                                              // Priced lines of the items that can be ordered
+	UnavailableProductIDs []string         `bson:"unavailableProductIds,omitempty" json:"unavailableProductIds,omitempty"`          // Items that were deleted or made unavailable and are left unpriced
+	Subtotal              Money            `bson:"subtotal" json:"subtotal" swaggertype:"number" example:"25.98"`                   // Sum of all line totals
+	Discount              Money            `bson:"discount" json:"discount" swaggertype:"number" example:"2.60"`                    // Discount granted by the coupon
+	Promotion             *AppliedDiscount `bson:"promotion,omitempty" json:"promotion,omitempty"`                                  // Coupon rule that grants the discount, if any
+	Total                 Money            `bson:"total" json:"total" swaggertype:"number" example:"23.38"`                         // Amount payable at checkout (Subtotal - Discount)
+	Version               int64            `bson:"version" json:"version" example:"4"`                                              // Incremented by every change to the cart
+	CreatedAt             int64            `bson:"created_at" json:"createdAt" example:"1718000000"`                                // Unix timestamp when the cart was created
+	UpdatedAt             int64            `bson:"updated_at" json:"updatedAt" example:"1718000300"`                                // Unix timestamp of the last change
+	ExpiresAt             time.Time        `bson:"expires_at" json:"expiresAt" swaggertype:"string" example:"2024-06-17T06:18:20Z"` // Expiry time, pushed back by every change; a BSON date so the TTL index can purge the cart
+}
+
+// CartCreateRequest represents the request body for creating a cart. Both fields are optional.
+// CustomerID is not read from the body; it is the subject of the bearer token or the customer bound to the API key.
+type CartCreateRequest struct {
+	Items      []CartItem `json:"items"`
+	CouponCode string     `json:"couponCode"`
+	CustomerID string     `json:"-"`
+}
+
+// CartItemUpdateRequest represents the request body for changing the quantity of a cart item.
+type CartItemUpdateRequest struct {
+	Quantity int `json:"quantity" example:"3"`
+}
+
+// CartCouponRequest represents the request body for applying a coupon to a cart.
+type CartCouponRequest struct {
+	CouponCode string `json:"couponCode" example:"HAPPYHRS"`
+}
@@ -0,0 +1,15 @@
+package models
+
+// Category represents a product category in the catalog.
+type Category struct {
+	ID        string `bson:"id" json:"id" example:"pizza"`             // Unique, URL-safe identifier
+	Name      string `bson:"name" json:"name" example:"Pizza"`         // Display name
+	SortOrder int    `bson:"sort_order" json:"sortOrder" example:"10"` // Position in category listings, lowest first
+	Active    bool   `bson:"active" json:"active" example:"true"`      // Inactive categories are hidden and cannot receive products
+}
+
+// CategoryResponse represents a category together with the number of products filed under it.
+type CategoryResponse struct {
+	Category
+	ProductCount int64 `json:"productCount" example:"3"` // Number of active products in the category
+}
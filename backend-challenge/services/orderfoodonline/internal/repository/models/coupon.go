@@ -0,0 +1,46 @@
+package models
+
+// CouponRedemption records a single use of a coupon by an order.
+// Exclusive redemptions are unique per coupon and customer.
+type CouponRedemption struct {
+	CouponCode string `bson:"coupon_code" json:"couponCode"`                      // Redeemed coupon code
+	CustomerID string `bson:"customer_id,omitempty" json:"customerId,omitempty"`  // Customer who placed the order, if known
+	OrderID    string `bson:"order_id" json:"orderId"`                            // Order the coupon was applied to
+	RedeemedAt int64  `bson:"redeemed_at" json:"redeemedAt" example:"1718000000"` // Unix timestamp of the redemption
+	Exclusive  bool   `bson:"exclusive,omitempty" json:"-"`                       // Whether the customer may redeem the coupon only once
+}
+
+// CouponUsage counts how many times a coupon has been redeemed.
+type CouponUsage struct {
+	CouponCode  string `bson:"coupon_code" json:"couponCode"`                    // Coupon code
+	Redemptions int64  `bson:"redemptions" json:"redemptions" example:"12"`      // Number of orders that redeemed the coupon
+	UpdatedAt   int64  `bson:"updated_at" json:"updatedAt" example:"1718000300"` // Unix timestamp of the last redemption
+}
+
+// Reasons reported by a coupon preview when a code does not apply.
+const (
+	// CouponReasonInvalid means the code is not a known, active coupon.
+	CouponReasonInvalid = "invalid"
+	// CouponReasonExpired means the coupon can no longer be redeemed.
+	CouponReasonExpired = "expired"
+	// CouponReasonLimitReached means the coupon has been redeemed the maximum number of times.
+	CouponReasonLimitReached = "limit_reached"
+	// CouponReasonAlreadyRedeemed means the customer has already redeemed the coupon.
+	CouponReasonAlreadyRedeemed = "already_redeemed"
+	// CouponReasonCustomerRequired means the coupon is limited per customer and no customer was given.
+	CouponReasonCustomerRequired = "customer_required"
+	// CouponReasonMinimumNotMet means the order subtotal is below the coupon's minimum basket value.
+	CouponReasonMinimumNotMet = "minimum_not_met"
+	// CouponReasonNotEligible means none of the ordered items qualify for the coupon's discount.
+	CouponReasonNotEligible = "not_eligible"
+)
+
+// CouponPreviewResponse tells whether a coupon code would currently apply to an order.
+type CouponPreviewResponse struct {
+	Code                 string      `json:"code" example:"HAPPYHRS"`                     // Coupon code that was checked
+	Applicable           bool        `json:"applicable" example:"true"`                   // Whether the code would be accepted at checkout
+	Reason               string      `json:"reason,omitempty" example:"limit_reached"`    // Why the code does not apply; one of the CouponReason values
+	Rule                 *CouponRule `json:"rule,omitempty"`                              // Discount rule the code would apply, when applicable
+	ExpiresAt            int64       `json:"expiresAt,omitempty" example:"1735689600"`    // Unix timestamp after which the coupon expires, if it does
+	RemainingRedemptions *int64      `json:"remainingRedemptions,omitempty" example:"88"` // Redemptions left before the limit is reached, if there is one
+}
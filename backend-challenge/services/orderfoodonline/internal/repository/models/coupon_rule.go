@@ -0,0 +1,55 @@
+package models
+
+import "time"
+
+// DiscountType is the kind of discount a coupon rule grants.
+type DiscountType string
+
+const (
+	// DiscountPercent takes a percentage off the eligible lines.
+	DiscountPercent DiscountType = "percent"
+	// DiscountFixed takes a fixed amount off the eligible lines.
+	DiscountFixed DiscountType = "fixed"
+	// DiscountBuyOneGetOne makes every second unit of each eligible product free.
+	DiscountBuyOneGetOne DiscountType = "bogo"
+	// DiscountFreeCheapest makes the cheapest eligible unit free when at least two are ordered.
+	DiscountFreeCheapest DiscountType = "free_cheapest"
+)
+
+// IsValid reports whether the type is a known discount type.
+func (t DiscountType) IsValid() bool {
+	switch t {
+	case DiscountPercent, DiscountFixed, DiscountBuyOneGetOne, DiscountFreeCheapest:
+		return true
+	default:
+		return false
+	}
+}
+
+// CouponRule maps a family of coupon codes to the discount they grant.
+// A code belongs to the family of the active rule with the longest matching CodePrefix.
+// Amount and MinSubtotal are written in major currency units both in the rules file and in the
+// coupon_rules collection, so the collection decodes them itself rather than through Money's cents.
+type CouponRule struct {
+	ID          string       `bson:"id" json:"id" example:"happy-hours"`                                  // Unique rule identifier
+	Description string       `bson:"description" json:"description" example:"15% off pizzas"`             // Explanation shown to customers
+	CodePrefix  string       `bson:"code_prefix" json:"codePrefix" example:"HAPPY"`                       // Codes starting with this prefix, ignoring case, use the rule; empty matches every code
+	Type        DiscountType `bson:"type" json:"type" swaggertype:"string" example:"percent"`             // Kind of discount granted
+	Percent     int64        `bson:"percent,omitempty" json:"percent,omitempty" example:"15"`             // Percentage off, for DiscountPercent
+	Amount      Money        `bson:"-" json:"amount,omitempty" swaggertype:"number" example:"5.00"`       // Amount off, for DiscountFixed
+	CategoryID  string       `bson:"category_id,omitempty" json:"categoryId,omitempty" example:"pizza"`   // Only lines of this category are discounted (empty = every line)
+	MinSubtotal Money        `bson:"-" json:"minSubtotal,omitempty" swaggertype:"number" example:"20.00"` // Smallest order subtotal the coupon applies to
+	Active      bool         `bson:"active" json:"active"`                                                // Inactive rules are ignored
+
+	OncePerCustomer bool      `bson:"once_per_customer,omitempty" json:"oncePerCustomer,omitempty"`                                        // Each customer may redeem a code of the family only once; orders must then come from an authenticated customer
+	MaxRedemptions  int64     `bson:"max_redemptions,omitempty" json:"maxRedemptions,omitempty" example:"100"`                             // Maximum number of redemptions of each code of the family (0 = unlimited)
+	ExpiresAt       time.Time `bson:"expires_at,omitempty" json:"expiresAt,omitempty" swaggertype:"string" example:"2024-12-31T23:59:59Z"` // Codes of the family cannot be redeemed after this time (zero = never)
+}
+
+// AppliedDiscount explains the discount granted on an order.
+type AppliedDiscount struct {
+	RuleID      string       `bson:"ruleId" json:"ruleId" example:"happy-hours"`               // Rule that was applied
+	Type        DiscountType `bson:"type" json:"type" swaggertype:"string" example:"percent"`  // Kind of discount granted
+	Description string       `bson:"description" json:"description" example:"15% off pizzas"`  // Explanation of the rule
+	Amount      Money        `bson:"amount" json:"amount" swaggertype:"number" example:"2.60"` // Amount taken off the subtotal
+}
@@ -0,0 +1,23 @@
+package models
+
+import "time"
+
+const (
+	// IdempotencyStatusInProgress marks a key whose original request is still being processed.
+	IdempotencyStatusInProgress = "in_progress"
+	// IdempotencyStatusCompleted marks a key whose response has been stored for replay.
+	IdempotencyStatusCompleted = "completed"
+)
+
+// IdempotencyRecord stores the outcome of a request made with an Idempotency-Key header,
+// so that retries of the same request can be answered with the original response.
+type IdempotencyRecord struct {
+	Scope        string    `bson:"scope" json:"scope"`                 // Caller the key belongs to, so that callers cannot see or block each other's keys
+	Key          string    `bson:"key" json:"key"`                     // Client-supplied Idempotency-Key
+	RequestHash  string    `bson:"request_hash" json:"request_hash"`   // SHA-256 of the method, path and body of the original request
+	Status       string    `bson:"status" json:"status"`               // Processing status (e.g., "in_progress", "completed")
+	ResponseCode int       `bson:"response_code" json:"response_code"` // HTTP status code of the original response
+	ResponseBody []byte    `bson:"response_body" json:"response_body"` // Body of the original response
+	CreatedAt    int64     `bson:"created_at" json:"created_at"`       // Unix timestamp when the key was first seen
+	ExpiresAt    time.Time `bson:"expires_at" json:"expires_at"`       // End of the lease while in progress, of the retention once completed; a BSON date so the TTL index can purge the record
+}
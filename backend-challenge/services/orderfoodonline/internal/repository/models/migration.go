@@ -11,7 +11,26 @@ type Migration struct {
 
 // MigrationData represents the structure of a migration JSON file.
 type MigrationData struct {
-	Version     string    `json:"version"`     // Migration version
-	Description string    `json:"description"` // Migration description
-	Products    []Product `json:"products"`    // Products to seed
+	Version            string             `json:"version"`             // Migration version
+	Description        string             `json:"description"`         // Migration description
+	Categories         []Category         `json:"categories"`          // Categories to seed
+	Products           []MigrationProduct `json:"products"`            // Products to seed
+	BackfillCategories bool               `json:"backfill_categories"` // Link products without a category ID to a category matching their category name
+
+	BackfillAvailability  bool `json:"backfill_availability"`   // Mark products stored before the available flag existed as available
+	RenameOrderTimestamps bool `json:"rename_order_timestamps"` // Rename the created_at and updated_at fields of orders to camelCase
+}
+
+// MigrationProduct is a product as described in a migration file.
+// Unlike the stored product, it is available unless the file says otherwise.
+type MigrationProduct struct {
+	Product
+	Available *bool `json:"available,omitempty"` // Whether the product can be ordered (default true)
+}
+
+// ToProduct returns the product to store.
+func (p MigrationProduct) ToProduct() Product {
+	product := p.Product
+	product.Available = p.Available == nil || *p.Available
+	return product
 }
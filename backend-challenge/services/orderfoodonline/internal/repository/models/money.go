@@ -0,0 +1,64 @@
+package models
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// Money is a monetary amount expressed in minor currency units (cents).
+// Keeping amounts as integers makes order arithmetic exact and deterministic,
+// avoiding the rounding drift that accumulates when summing float64 prices.
+// It is persisted as an int64 number of cents and rendered in JSON as a
+// decimal number with two fraction digits (e.g. 12.99).
+type Money int64
+
+// MoneyFromFloat converts a decimal amount (such as a catalog price) to Money,
+// rounding half away from zero to the nearest cent.
+func MoneyFromFloat(amount float64) Money {
+	return Money(math.Round(amount * 100))
+}
+
+// IsWholeCents reports whether a decimal amount has at most two fraction digits,
+// so that MoneyFromFloat converts it without rounding.
+func IsWholeCents(amount float64) bool {
+	cents := amount * 100
+	return math.Abs(cents-math.Round(cents)) < 1e-6
+}
+
+// Mul returns the amount multiplied by the given quantity.
+func (m Money) Mul(quantity int) Money {
+	return m * Money(quantity)
+}
+
+// Float64 returns the amount as a decimal value in major currency units.
+// It is intended for presentation and metrics, never for further arithmetic.
+func (m Money) Float64() float64 {
+	return float64(m) / 100
+}
+
+// String returns the amount formatted with two fraction digits (e.g. "12.99").
+func (m Money) String() string {
+	sign := ""
+	cents := int64(m)
+	if cents < 0 {
+		sign = "-"
+		cents = -cents
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, cents/100, cents%100)
+}
+
+// MarshalJSON encodes the amount as a JSON number in major currency units.
+func (m Money) MarshalJSON() ([]byte, error) {
+	return []byte(m.String()), nil
+}
+
+// UnmarshalJSON decodes a JSON number in major currency units into Money.
+func (m *Money) UnmarshalJSON(data []byte) error {
+	amount, err := strconv.ParseFloat(string(data), 64)
+	if err != nil {
+		return fmt.Errorf("invalid money amount %s: %w", data, err)
+	}
+	*m = MoneyFromFloat(amount)
+	return nil
+}
@@ -1,20 +1,140 @@
 package models
 
+// OrderStatus is the lifecycle state of an order.
+type OrderStatus string
+
+const (
+	// OrderStatusPlaced is the initial status of every newly placed order.
+	OrderStatusPlaced OrderStatus = "placed"
+	// OrderStatusConfirmed indicates the order has been accepted by the restaurant.
+	OrderStatusConfirmed OrderStatus = "confirmed"
+	// OrderStatusPreparing indicates the order is being prepared.
+	OrderStatusPreparing OrderStatus = "preparing"
+	// OrderStatusReady indicates the order is ready for pick-up or delivery.
+	OrderStatusReady OrderStatus = "ready"
+	// OrderStatusDelivered indicates the order has been handed over to the customer.
+	OrderStatusDelivered OrderStatus = "delivered"
+	// OrderStatusCancelled indicates the order was cancelled before delivery.
+	OrderStatusCancelled OrderStatus = "cancelled"
+	// OrderStatusRefunded indicates the order amount was refunded to the customer.
+	OrderStatusRefunded OrderStatus = "refunded"
+)
+
+// IsValid reports whether the status is a known order status.
+func (s OrderStatus) IsValid() bool {
+	switch s {
+	case OrderStatusPlaced, OrderStatusConfirmed, OrderStatusPreparing, OrderStatusReady,
+		OrderStatusDelivered, OrderStatusCancelled, OrderStatusRefunded:
+		return true
+	default:
+		return false
+	}
+}
+
 // OrderItem represents an item in an order.
+// ExpectedPrice and ExpectedVersion are optional; when set, the order is rejected
+// if the product's current price or catalog version no longer matches.
 type OrderItem struct {
-	ProductID string `bson:"productId" json:"productId"`
-	Quantity  int    `bson:"quantity" json:"quantity"`
+	ProductID       string `bson:"productId" json:"productId"`
+	Quantity        int    `bson:"quantity" json:"quantity"`
+	ExpectedPrice   *Money `bson:"expectedPrice,omitempty" json:"expectedPrice,omitempty" swaggertype:"number" example:"12.99"` // Unit price the client saw
+	ExpectedVersion *int64 `bson:"expectedVersion,omitempty" json:"expectedVersion,omitempty" example:"3"`                      // Catalog version the client saw
+}
+
+// OrderLine represents a priced line of an order. It is a snapshot of the product
+// taken when the order was placed and is never rewritten by later catalog edits.
+type OrderLine struct {
+	ProductID      string `bson:"productId" json:"productId"`                                        // Product being ordered
+	Name           string `bson:"name" json:"name" example:"Chicken Waffle"`                         // Product name at order time
+	Category       string `bson:"category" json:"category" example:"Waffle"`                         // Product category at order time
+	CategoryID     string `bson:"categoryId,omitempty" json:"categoryId,omitempty" example:"waffle"` // Product category ID at order time
+	ProductVersion int64  `bson:"productVersion" json:"productVersion" example:"3"`                  // Catalog version the line was priced from
+	Quantity       int    `bson:"quantity" json:"quantity"`                                          // Number of units ordered
+	UnitPrice      Money  `bson:"unitPrice" json:"unitPrice" swaggertype:"number" example:"12.99"`   // Price of a single unit
+	LineTotal      Money  `bson:"lineTotal" json:"lineTotal" swaggertype:"number" example:"25.98"`   // UnitPrice × Quantity
+}
+
+// StaleOrderLine describes an order item whose expected price or version no longer matches the catalog.
+type StaleOrderLine struct {
+	ProductID       string `json:"productId" example:"1"`                                        // Product that changed
+	ExpectedPrice   *Money `json:"expectedPrice,omitempty" swaggertype:"number" example:"12.99"` // Unit price sent by the client
+	CurrentPrice    Money  `json:"currentPrice" swaggertype:"number" example:"13.49"`            // Current catalog unit price
+	ExpectedVersion *int64 `json:"expectedVersion,omitempty" example:"3"`                        // Catalog version sent by the client
+	CurrentVersion  int64  `json:"currentVersion" example:"4"`                                   // Current catalog version
+}
+
+// StaleCartResponse is returned when an order is rejected because the catalog changed.
+type StaleCartResponse struct {
+	Error        string           `json:"error" example:"Cart is out of date"`                                // Error message
+	ChangedLines []StaleOrderLine `json:"changedLines"`                                                       // Items whose price or version changed
+	RequestID    string           `json:"requestId,omitempty" example:"3f2b8c1e-0d4a-4e5b-9c6d-7a8b9c0d1e2f"` // ID of the request, to quote when reporting the error
+}
+
+// ProductUnavailableCode is the error code returned when an order contains a product that cannot be ordered right now.
+const ProductUnavailableCode = "product_unavailable"
+
+// ProductUnavailableResponse is returned when an order is rejected because a product is unavailable.
+type ProductUnavailableResponse struct {
+	Error     string `json:"error" example:"Product unavailable"`                                // Error message
+	Code      string `json:"code" example:"product_unavailable"`                                 // Machine-readable error code, always ProductUnavailableCode
+	ProductID string `json:"productId" example:"10"`                                             // First unavailable product of the order
+	RequestID string `json:"requestId,omitempty" example:"3f2b8c1e-0d4a-4e5b-9c6d-7a8b9c0d1e2f"` // ID of the request, to quote when reporting the error
 }
 
 // Order represents a placed order.
 type Order struct {
-	ID       string      `bson:"id" json:"id"`
-	Items    []OrderItem `bson:"items" json:"items"`
-	Products []Product   `bson:"products" json:"products"`
+	ID         string              `bson:"id" json:"id"`
+	Items      []OrderItem         `bson:"items" json:"items"`
+	Products   []Product           `bson:"products" json:"products"`
+	Lines      []OrderLine         `bson:"lines" json:"lines"`
+	CouponCode string              `bson:"couponCode,omitempty" json:"couponCode,omitempty"`
+	CustomerID string              `bson:"customerId,omitempty" json:"customerId,omitempty" example:"cust-42"` // Customer who placed the order, if known
+	Subtotal   Money               `bson:"subtotal" json:"subtotal" swaggertype:"number" example:"25.98"`      // Sum of all line totals
+	Discount   Money               `bson:"discount" json:"discount" swaggertype:"number" example:"2.60"`       // Discount granted by the coupon
+	Promotion  *AppliedDiscount    `bson:"promotion,omitempty" json:"promotion,omitempty"`                     // Coupon rule that granted the discount, if any
+	Total      Money               `bson:"total" json:"total" swaggertype:"number" example:"23.38"`            // Amount payable (Subtotal - Discount)
+	Status     OrderStatus         `bson:"status" json:"status" swaggertype:"string" example:"placed"`         // Current lifecycle status
+	CreatedAt  int64               `bson:"createdAt" json:"createdAt" example:"1718000000"`                    // Unix timestamp when the order was placed
+	UpdatedAt  int64               `bson:"updatedAt" json:"updatedAt" example:"1718000300"`                    // Unix timestamp of the last status change
+	History    []OrderStatusChange `bson:"history" json:"history"`                                             // Audit trail of status transitions, oldest first, starting with the order being placed
+}
+
+// OrderStatusChange records a single status transition of an order.
+type OrderStatusChange struct {
+	From   OrderStatus `bson:"from,omitempty" json:"from,omitempty" swaggertype:"string" example:"placed"` // Status before the transition, empty when the order was placed
+	To     OrderStatus `bson:"to" json:"to" swaggertype:"string" example:"confirmed"`                      // Status after the transition
+	At     int64       `bson:"at" json:"at" example:"1718000300"`                                          // Unix timestamp of the transition
+	Reason string      `bson:"reason,omitempty" json:"reason,omitempty" example:"accepted"`                // Optional free-text reason
+}
+
+// OrderStatusUpdateRequest represents the request body for changing an order's status.
+type OrderStatusUpdateRequest struct {
+	Status OrderStatus `json:"status" swaggertype:"string" example:"confirmed"`
+	Reason string      `json:"reason,omitempty" example:"accepted by kitchen"`
 }
 
 // OrderCreateRequest represents the request body for placing an order.
+// CustomerID is not read from the body; it is the subject of the bearer token or the customer bound to the API key.
 type OrderCreateRequest struct {
 	CouponCode string      `json:"couponCode"`
 	Items      []OrderItem `json:"items"`
+	CustomerID string      `json:"-"`
+}
+
+// OrderFilter holds the criteria used to list orders.
+type OrderFilter struct {
+	CustomerID string      // Only orders placed by this customer (empty = any)
+	From       int64       // Only orders created at or after this Unix timestamp (0 = unbounded)
+	To         int64       // Only orders created at or before this Unix timestamp (0 = unbounded)
+	Status     OrderStatus // Only orders in this status (empty = any)
+	Limit      int         // Maximum number of orders to return
+	Offset     int         // Number of matching orders to skip
+}
+
+// OrderListResponse represents a page of orders.
+type OrderListResponse struct {
+	Orders []Order `json:"orders"`             // Orders in this page, newest first
+	Total  int64   `json:"total" example:"42"` // Total number of orders matching the filter
+	Limit  int     `json:"limit" example:"20"` // Page size used for this page
+	Offset int     `json:"offset" example:"0"` // Offset of the first order in this page
 }
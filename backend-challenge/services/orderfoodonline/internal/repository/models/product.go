@@ -2,10 +2,54 @@
 package models
 
 // Product represents a product in the catalog.
-// It contains basic product information including pricing and categorization.
+// It contains basic product information including pricing and categorization,
+// along with the details shown on the menu and whether the product can be ordered.
 type Product struct {
-	ID       string  `bson:"id" json:"id"`             // Unique identifier for the product
-	Name     string  `bson:"name" json:"name"`         // Product name
-	Price    float64 `bson:"price" json:"price"`       // Product price
-	Category string  `bson:"category" json:"category"` // Product category
+	ID          string        `bson:"id" json:"id"`                                        // Unique identifier for the product
+	Name        string        `bson:"name" json:"name"`                                    // Product name
+	Price       float64       `bson:"price" json:"price"`                                  // Product price
+	Category    string        `bson:"category" json:"category"`                            // Display name of the product category
+	CategoryID  string        `bson:"category_id,omitempty" json:"categoryId,omitempty"`   // ID of the category the product is filed under
+	Description string        `bson:"description,omitempty" json:"description,omitempty"`  // Menu description
+	Image       *ProductImage `bson:"image,omitempty" json:"image,omitempty"`              // Product pictures for each screen size
+	Allergens   []string      `bson:"allergens,omitempty" json:"allergens,omitempty"`      // Allergens the product contains, see Allergens
+	DietaryTags []string      `bson:"dietary_tags,omitempty" json:"dietaryTags,omitempty"` // Diets the product is suitable for, see DietaryTags
+	Available   bool          `bson:"available" json:"available"`                          // Whether the product can currently be ordered
+	Version     int64         `bson:"version" json:"version"`                              // Catalog version, incremented on every change to the product
+	UpdatedAt   int64         `bson:"updated_at,omitempty" json:"updatedAt,omitempty"`     // Unix timestamp of the last admin change
+	DeletedAt   int64         `bson:"deleted_at,omitempty" json:"-"`                       // Unix timestamp of the soft delete (0 = active)
+}
+
+// ProductImage holds the URLs of a product picture rendered for each screen size.
+type ProductImage struct {
+	Thumbnail string `bson:"thumbnail,omitempty" json:"thumbnail,omitempty" example:"https://cdn.example.com/10-thumbnail.jpg"`
+	Mobile    string `bson:"mobile,omitempty" json:"mobile,omitempty" example:"https://cdn.example.com/10-mobile.jpg"`
+	Tablet    string `bson:"tablet,omitempty" json:"tablet,omitempty" example:"https://cdn.example.com/10-tablet.jpg"`
+	Desktop   string `bson:"desktop,omitempty" json:"desktop,omitempty" example:"https://cdn.example.com/10-desktop.jpg"`
+}
+
+// ProductRequest represents the request body for creating or replacing a product.
+type ProductRequest struct {
+	ID          string        `json:"id,omitempty" example:"10"`                                    // Optional ID for new products; generated when empty
+	Name        string        `json:"name" example:"Margherita Pizza"`                              // Product name (required)
+	Price       float64       `json:"price" example:"12.99"`                                        // Product price, must be positive with at most two decimal places
+	CategoryID  string        `json:"categoryId" example:"pizza"`                                   // ID of an active category
+	Description string        `json:"description,omitempty" example:"Tomato, mozzarella and basil"` // Menu description
+	Image       *ProductImage `json:"image,omitempty"`                                              // Product pictures for each screen size
+	Allergens   []string      `json:"allergens,omitempty" example:"gluten,dairy"`                   // Allergens from the Allergens list
+	DietaryTags []string      `json:"dietaryTags,omitempty" example:"vegetarian"`                   // Dietary tags from the DietaryTags list
+	Available   *bool         `json:"available,omitempty" example:"true"`                           // Whether the product can be ordered (default true)
+}
+
+// ProductPatchRequest represents the request body for partially updating a product.
+// Only the fields that are set are changed.
+type ProductPatchRequest struct {
+	Name        *string       `json:"name,omitempty" example:"Margherita Pizza"`
+	Price       *float64      `json:"price,omitempty" example:"12.99"`
+	CategoryID  *string       `json:"categoryId,omitempty" example:"pizza"`
+	Description *string       `json:"description,omitempty" example:"Tomato, mozzarella and basil"`
+	Image       *ProductImage `json:"image,omitempty"`
+	Allergens   *[]string     `json:"allergens,omitempty" example:"gluten,dairy"`
+	DietaryTags *[]string     `json:"dietaryTags,omitempty" example:"vegetarian"`
+	Available   *bool         `json:"available,omitempty" example:"false"`
 }
@@ -0,0 +1,79 @@
+package models
+
+import (
+	"encoding/base64"
+	"encoding/json"
+)
+
+const (
+	// ProductSortName orders products by name, A to Z.
+	ProductSortName = "name"
+	// ProductSortNameDesc orders products by name, Z to A.
+	ProductSortNameDesc = "-name"
+	// ProductSortPrice orders products from cheapest to most expensive.
+	ProductSortPrice = "price"
+	// ProductSortPriceDesc orders products from most expensive to cheapest.
+	ProductSortPriceDesc = "-price"
+)
+
+// ProductFilter holds the criteria used to list products.
+// Ties in the sort order are always broken by ID so pages are stable.
+type ProductFilter struct {
+	CategoryID string         // Only products in the category with this ID (empty = any)
+	MinPrice   float64        // Only products priced at or above this amount (0 = unbounded)
+	MaxPrice   float64        // Only products priced at or below this amount (0 = unbounded)
+	Search     string         // Text search on the product name (empty = no search)
+	Sort       string         // One of the ProductSort values (empty = by ID)
+	Limit      int            // Maximum number of products to return
+	After      *ProductCursor // Only products after this position in the sort order (nil = first page)
+
+	DietaryTags      []string // Only products tagged with every one of these diets (empty = any)
+	ExcludeAllergens []string // Only products containing none of these allergens (empty = any)
+}
+
+// ProductCursor marks the position of the last product of a page.
+// It is handed to clients as an opaque token and sent back to fetch the next page.
+type ProductCursor struct {
+	Sort  string  `json:"s,omitempty"` // Sort order the cursor was issued for
+	ID    string  `json:"i"`           // ID of the last product
+	Name  string  `json:"n,omitempty"` // Name of the last product, when sorting by name
+	Price float64 `json:"p,omitempty"` // Price of the last product, when sorting by price
+}
+
+// NewProductCursor returns the cursor positioned just after the given product.
+func NewProductCursor(sort string, product Product) *ProductCursor {
+	cursor := &ProductCursor{Sort: sort, ID: product.ID}
+	switch sort {
+	case ProductSortName, ProductSortNameDesc:
+		cursor.Name = product.Name
+	case ProductSortPrice, ProductSortPriceDesc:
+		cursor.Price = product.Price
+	}
+	return cursor
+}
+
+// Encode returns the cursor as an opaque URL-safe token.
+func (c *ProductCursor) Encode() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodeProductCursor parses a token produced by ProductCursor.Encode.
+func DecodeProductCursor(token string) (*ProductCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, err
+	}
+	var cursor ProductCursor
+	if err := json.Unmarshal(data, &cursor); err != nil {
+		return nil, err
+	}
+	return &cursor, nil
+}
+
+// ProductListResponse represents a page of products.
+type ProductListResponse struct {
+	Products   []Product `json:"products"`                                       // Products in this page
+	NextCursor string    `json:"next_cursor,omitempty" example:"eyJpIjoiMTAifQ"` // Token for the next page; absent on the last page
+	Total      int64     `json:"-"`                                              // Total number of matching products, sent as X-Total-Count
+}
@@ -0,0 +1,49 @@
+package models
+
+// DietaryTags lists the diets a product can be tagged as suitable for.
+var DietaryTags = []string{
+	"vegetarian",
+	"vegan",
+	"gluten-free",
+	"dairy-free",
+	"nut-free",
+	"halal",
+	"kosher",
+}
+
+// Allergens lists the allergens a product can be tagged as containing.
+var Allergens = []string{
+	"gluten",
+	"crustaceans",
+	"eggs",
+	"fish",
+	"peanuts",
+	"soy",
+	"dairy",
+	"nuts",
+	"celery",
+	"mustard",
+	"sesame",
+	"sulphites",
+	"lupin",
+	"molluscs",
+}
+
+// IsDietaryTag reports whether tag is one of the DietaryTags.
+func IsDietaryTag(tag string) bool {
+	return contains(DietaryTags, tag)
+}
+
+// IsAllergen reports whether tag is one of the Allergens.
+func IsAllergen(tag string) bool {
+	return contains(Allergens, tag)
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,10 @@
+package models
+
+import "time"
+
+// RateLimitCounter counts the requests made against a rate limit key in one window.
+type RateLimitCounter struct {
+	ID        string    `bson:"_id" json:"id"`                // Rate limit key and end of the window, in Unix seconds
+	Count     int64     `bson:"count" json:"count"`           // Requests counted in the window
+	ExpiresAt time.Time `bson:"expires_at" json:"expires_at"` // End of the window; a BSON date so the TTL index can purge the counter
+}
@@ -0,0 +1,21 @@
+package models
+
+// Stock is the number of units of a product that can still be ordered.
+// Products without a stock record are not tracked and can be ordered without limit.
+type Stock struct {
+	ProductID string `bson:"product_id" json:"productId" example:"10"`         // Product the stock belongs to
+	Quantity  int64  `bson:"quantity" json:"quantity" example:"25"`            // Units left to order
+	UpdatedAt int64  `bson:"updated_at" json:"updatedAt" example:"1718000300"` // Unix timestamp of the last change
+}
+
+// StockRequest represents the request body for setting or adding to a product's stock.
+type StockRequest struct {
+	Quantity int64 `json:"quantity" example:"10"` // Units to set or to add, must not be negative
+}
+
+// OutOfStockResponse is returned when an order is rejected because products ran out of stock.
+type OutOfStockResponse struct {
+	Error      string   `json:"error" example:"Out of stock"`                                       // Error message
+	ProductIDs []string `json:"productIds" example:"10,12"`                                         // Products without enough stock for the order
+	RequestID  string   `json:"requestId,omitempty" example:"3f2b8c1e-0d4a-4e5b-9c6d-7a8b9c0d1e2f"` // ID of the request, to quote when reporting the error
+}
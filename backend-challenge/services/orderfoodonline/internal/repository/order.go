@@ -7,7 +7,9 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 // orderRepository provides MongoDB-backed access to order data.
@@ -16,9 +18,73 @@ type orderRepository struct {
 }
 
 // NewOrderRepository creates a new OrderRepository using the given Repository.
-func NewOrderRepository(repo *Repository) OrderRepository {
+func NewOrderRepository(repo *Repository) (OrderRepository, error) {
 	collection := repo.db.Collection("orders")
-	return &orderRepository{collection: collection}
+
+	orderRepo := &orderRepository{collection: collection}
+	if err := orderRepo.createOrderIndexes(context.Background()); err != nil {
+		return nil, err
+	}
+	return orderRepo, nil
+}
+
+// legacyOrderIndexes are the names of the indexes on the snake_case order timestamps.
+var legacyOrderIndexes = []string{"order_created_at_idx", "order_status_created_at_idx"}
+
+func (r *orderRepository) createOrderIndexes(ctx context.Context) error {
+	indexModels := []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "id", Value: 1}},
+			Options: options.Index().SetUnique(true).SetName("order_id_idx"),
+		},
+		{
+			Keys:    bson.D{{Key: "createdAt", Value: -1}},
+			Options: options.Index().SetName("order_createdAt_idx"),
+		},
+		{
+			Keys: bson.D{
+				{Key: "status", Value: 1},
+				{Key: "createdAt", Value: -1},
+			},
+			Options: options.Index().SetName("order_status_createdAt_idx"),
+		},
+	}
+
+	// Set a timeout context
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	_, err := r.collection.Indexes().CreateMany(ctx, indexModels)
+	if err != nil {
+		return err
+	}
+
+	// The legacy indexes covered the snake_case timestamps, see RenameLegacyOrderTimestamps
+	for _, name := range legacyOrderIndexes {
+		_, err = r.collection.Indexes().DropOne(ctx, name)
+		if err != nil && !isIndexNotFound(err) {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// RenameLegacyOrderTimestamps renames the created_at and updated_at fields of orders stored
+// before order documents used camelCase field names. Returns the number of orders updated.
+func (r *orderRepository) RenameLegacyOrderTimestamps(ctx context.Context) (int64, error) {
+	start := time.Now()
+
+	filter := bson.M{"created_at": bson.M{"$exists": true}}
+	update := bson.M{"$rename": bson.M{"created_at": "createdAt", "updated_at": "updatedAt"}}
+	res, err := r.collection.UpdateMany(ctx, filter, update)
+	if err != nil {
+		metrics.RecordDatabaseQuery("update_many", "orders", "error", time.Since(start).Seconds())
+		return 0, err
+	}
+
+	metrics.RecordDatabaseQuery("update_many", "orders", "success", time.Since(start).Seconds())
+	return res.ModifiedCount, nil
 }
 
 // PlaceOrder inserts a new order into the database and returns the created order.
@@ -26,6 +92,8 @@ func (r *orderRepository) PlaceOrder(ctx context.Context, order *models.Order) (
 	start := time.Now()
 
 	order.ID = uuid.New().String()
+	order.CreatedAt = time.Now().Unix()
+	order.UpdatedAt = order.CreatedAt
 	_, err := r.collection.InsertOne(ctx, order)
 	if err != nil {
 		metrics.RecordDatabaseQuery("insert_one", "orders", "error", time.Since(start).Seconds())
@@ -35,3 +103,115 @@ func (r *orderRepository) PlaceOrder(ctx context.Context, order *models.Order) (
 	metrics.RecordDatabaseQuery("insert_one", "orders", "success", time.Since(start).Seconds())
 	return order, nil
 }
+
+// FindOrderByID returns an order by its ID, or nil if not found.
+func (r *orderRepository) FindOrderByID(ctx context.Context, id string) (*models.Order, error) {
+	start := time.Now()
+
+	var o models.Order
+	err := r.collection.FindOne(ctx, bson.M{"id": id}).Decode(&o)
+	if err == mongo.ErrNoDocuments {
+		metrics.RecordDatabaseQuery("find_one", "orders", "not_found", time.Since(start).Seconds())
+		return nil, nil
+	}
+	if err != nil {
+		metrics.RecordDatabaseQuery("find_one", "orders", "error", time.Since(start).Seconds())
+		return nil, err
+	}
+
+	metrics.RecordDatabaseQuery("find_one", "orders", "success", time.Since(start).Seconds())
+	return &o, nil
+}
+
+// ListOrders returns a page of orders matching the filter, newest first,
+// along with the total number of matching orders.
+func (r *orderRepository) ListOrders(ctx context.Context, filter models.OrderFilter) ([]models.Order, int64, error) {
+	start := time.Now()
+
+	query := orderFilterQuery(filter)
+
+	total, err := r.collection.CountDocuments(ctx, query)
+	if err != nil {
+		metrics.RecordDatabaseQuery("count", "orders", "error", time.Since(start).Seconds())
+		return nil, 0, err
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "createdAt", Value: -1}, {Key: "id", Value: 1}}).
+		SetSkip(int64(filter.Offset)).
+		SetLimit(int64(filter.Limit))
+
+	cur, err := r.collection.Find(ctx, query, opts)
+	if err != nil {
+		metrics.RecordDatabaseQuery("find", "orders", "error", time.Since(start).Seconds())
+		return nil, 0, err
+	}
+	defer cur.Close(ctx)
+
+	orders := []models.Order{}
+	for cur.Next(ctx) {
+		var o models.Order
+		if err := cur.Decode(&o); err != nil {
+			metrics.RecordDatabaseQuery("find", "orders", "error", time.Since(start).Seconds())
+			return nil, 0, err
+		}
+		orders = append(orders, o)
+	}
+	if err := cur.Err(); err != nil {
+		metrics.RecordDatabaseQuery("find", "orders", "error", time.Since(start).Seconds())
+		return nil, 0, err
+	}
+
+	metrics.RecordDatabaseQuery("find", "orders", "success", time.Since(start).Seconds())
+	return orders, total, nil
+}
+
+// UpdateOrderStatus moves an order to a new status only if it is still in the expected status,
+// appending the change to its history. Returns nil if no order matched.
+func (r *orderRepository) UpdateOrderStatus(ctx context.Context, id string, from models.OrderStatus,
+	change models.OrderStatusChange) (*models.Order, error) {
+	start := time.Now()
+
+	filter := bson.M{"id": id, "status": from}
+	update := bson.M{
+		"$set":  bson.M{"status": change.To, "updatedAt": change.At},
+		"$push": bson.M{"history": change},
+	}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+	var o models.Order
+	err := r.collection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&o)
+	if err == mongo.ErrNoDocuments {
+		metrics.RecordDatabaseQuery("find_one_and_update", "orders", "not_found", time.Since(start).Seconds())
+		return nil, nil
+	}
+	if err != nil {
+		metrics.RecordDatabaseQuery("find_one_and_update", "orders", "error", time.Since(start).Seconds())
+		return nil, err
+	}
+
+	metrics.RecordDatabaseQuery("find_one_and_update", "orders", "success", time.Since(start).Seconds())
+	return &o, nil
+}
+
+// orderFilterQuery builds the MongoDB query document for an order filter.
+func orderFilterQuery(filter models.OrderFilter) bson.M {
+	query := bson.M{}
+	if filter.CustomerID != "" {
+		query["customerId"] = filter.CustomerID
+	}
+	createdAt := bson.M{}
+	if filter.From > 0 {
+		createdAt["$gte"] = filter.From
+	}
+	if filter.To > 0 {
+		createdAt["$lte"] = filter.To
+	}
+	if len(createdAt) > 0 {
+		query["createdAt"] = createdAt
+	}
+	if filter.Status != "" {
+		query["status"] = filter.Status
+	}
+	return query
+}
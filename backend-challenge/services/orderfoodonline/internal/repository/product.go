@@ -8,6 +8,7 @@ import (
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 // productRepository provides MongoDB-backed access to product data.
@@ -20,47 +21,118 @@ type productRepository struct {
 func NewProductRepository(repo *Repository) (ProductRepository, error) {
 	collection := repo.db.Collection("products")
 	migrationsCollection := repo.db.Collection("migrations")
-	return &productRepository{
+	productRepo := &productRepository{
 		collection:           collection,
 		migrationsCollection: migrationsCollection,
-	}, nil
+	}
+	if err := productRepo.createProductIndexes(context.Background()); err != nil {
+		return nil, err
+	}
+	return productRepo, nil
 }
 
-// ListProducts returns all products from the database.
-func (r *productRepository) ListProducts(ctx context.Context) ([]models.Product, error) {
+func (r *productRepository) createProductIndexes(ctx context.Context) error {
+	indexModels := []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "id", Value: 1}},
+			Options: options.Index().SetUnique(true).SetName("product_id_idx"),
+		},
+		{
+			Keys:    bson.D{{Key: "name", Value: "text"}},
+			Options: options.Index().SetName("product_name_text_idx"),
+		},
+		{
+			// Support the name and price sort orders and the cursor conditions built on them
+			Keys:    bson.D{{Key: "name", Value: 1}, {Key: "id", Value: 1}},
+			Options: options.Index().SetName("product_name_id_idx"),
+		},
+		{
+			Keys:    bson.D{{Key: "price", Value: 1}, {Key: "id", Value: 1}},
+			Options: options.Index().SetName("product_price_id_idx"),
+		},
+		{
+			Keys:    bson.D{{Key: "category_id", Value: 1}},
+			Options: options.Index().SetName("product_category_id_idx"),
+		},
+		{
+			Keys:    bson.D{{Key: "dietary_tags", Value: 1}},
+			Options: options.Index().SetName("product_dietary_tags_idx"),
+		},
+	}
+
+	// Set a timeout context
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	_, err := r.collection.Indexes().CreateMany(ctx, indexModels)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// activeProducts returns a query matching the products that have not been soft deleted,
+// narrowed by the given conditions.
+func activeProducts(conditions bson.M) bson.M {
+	query := bson.M{"deleted_at": bson.M{"$exists": false}}
+	for k, v := range conditions {
+		query[k] = v
+	}
+	return query
+}
+
+// ListProducts returns a page of active products matching the filter, along with the total
+// number of matching products. Products are ordered by filter.Sort with ties broken by ID.
+func (r *productRepository) ListProducts(ctx context.Context, filter models.ProductFilter) ([]models.Product, int64, error) {
 	start := time.Now()
 
-	cur, err := r.collection.Find(ctx, bson.M{})
+	query := productFilterQuery(filter)
+
+	total, err := r.collection.CountDocuments(ctx, query)
+	if err != nil {
+		metrics.RecordDatabaseQuery("count", "products", "error", time.Since(start).Seconds())
+		return nil, 0, err
+	}
+
+	if filter.After != nil {
+		query["$or"] = productCursorQuery(filter.Sort, filter.After)
+	}
+	opts := options.Find().
+		SetSort(productSortOrder(filter.Sort)).
+		SetLimit(int64(filter.Limit))
+
+	cur, err := r.collection.Find(ctx, query, opts)
 	if err != nil {
 		metrics.RecordDatabaseQuery("find", "products", "error", time.Since(start).Seconds())
-		return nil, err
+		return nil, 0, err
 	}
 	defer cur.Close(ctx)
 
-	var products []models.Product
+	products := []models.Product{}
 	for cur.Next(ctx) {
 		var p models.Product
 		if err := cur.Decode(&p); err != nil {
 			metrics.RecordDatabaseQuery("find", "products", "error", time.Since(start).Seconds())
-			return nil, err
+			return nil, 0, err
 		}
 		products = append(products, p)
 	}
 	if err := cur.Err(); err != nil {
 		metrics.RecordDatabaseQuery("find", "products", "error", time.Since(start).Seconds())
-		return nil, err
+		return nil, 0, err
 	}
 
 	metrics.RecordDatabaseQuery("find", "products", "success", time.Since(start).Seconds())
-	return products, nil
+	return products, total, nil
 }
 
-// FindProductByID returns a product by its ID, or nil if not found.
+// FindProductByID returns an active product by its ID, or nil if not found or deleted.
 func (r *productRepository) FindProductByID(ctx context.Context, id string) (*models.Product, error) {
 	start := time.Now()
 
 	var p models.Product
-	err := r.collection.FindOne(ctx, bson.M{"id": id}).Decode(&p)
+	err := r.collection.FindOne(ctx, activeProducts(bson.M{"id": id})).Decode(&p)
 	if err == mongo.ErrNoDocuments {
 		metrics.RecordDatabaseQuery("find_one", "products", "not_found", time.Since(start).Seconds())
 		return nil, nil
@@ -74,6 +146,213 @@ func (r *productRepository) FindProductByID(ctx context.Context, id string) (*mo
 	return &p, nil
 }
 
+// FindProductsByIDs returns the active products matching the given IDs using a single $in query.
+// IDs that do not match an active product are omitted from the result.
+func (r *productRepository) FindProductsByIDs(ctx context.Context, ids []string) ([]models.Product, error) {
+	products := []models.Product{}
+	if len(ids) == 0 {
+		return products, nil
+	}
+
+	start := time.Now()
+
+	cur, err := r.collection.Find(ctx, activeProducts(bson.M{"id": bson.M{"$in": ids}}))
+	if err != nil {
+		metrics.RecordDatabaseQuery("find", "products", "error", time.Since(start).Seconds())
+		return nil, err
+	}
+	defer cur.Close(ctx)
+
+	for cur.Next(ctx) {
+		var p models.Product
+		if err := cur.Decode(&p); err != nil {
+			metrics.RecordDatabaseQuery("find", "products", "error", time.Since(start).Seconds())
+			return nil, err
+		}
+		products = append(products, p)
+	}
+	if err := cur.Err(); err != nil {
+		metrics.RecordDatabaseQuery("find", "products", "error", time.Since(start).Seconds())
+		return nil, err
+	}
+
+	metrics.RecordDatabaseQuery("find", "products", "success", time.Since(start).Seconds())
+	return products, nil
+}
+
+// CreateProduct inserts a new product.
+// Returns false without error if a product with the same ID already exists, including a deleted one.
+func (r *productRepository) CreateProduct(ctx context.Context, product *models.Product) (bool, error) {
+	start := time.Now()
+
+	_, err := r.collection.InsertOne(ctx, product)
+	if mongo.IsDuplicateKeyError(err) {
+		metrics.RecordDatabaseQuery("insert_one", "products", "duplicate", time.Since(start).Seconds())
+		return false, nil
+	}
+	if err != nil {
+		metrics.RecordDatabaseQuery("insert_one", "products", "error", time.Since(start).Seconds())
+		return false, err
+	}
+
+	metrics.RecordDatabaseQuery("insert_one", "products", "success", time.Since(start).Seconds())
+	return true, nil
+}
+
+// UpdateProduct replaces the editable fields of an active product and bumps its version.
+// Returns the updated product, or nil if no active product has the given ID.
+func (r *productRepository) UpdateProduct(ctx context.Context, product *models.Product) (*models.Product, error) {
+	start := time.Now()
+
+	update := bson.M{
+		"$set": bson.M{
+			"name":         product.Name,
+			"price":        product.Price,
+			"category":     product.Category,
+			"category_id":  product.CategoryID,
+			"description":  product.Description,
+			"image":        product.Image,
+			"allergens":    product.Allergens,
+			"dietary_tags": product.DietaryTags,
+			"available":    product.Available,
+			"updated_at":   product.UpdatedAt,
+		},
+		"$inc": bson.M{"version": 1},
+	}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+	var p models.Product
+	err := r.collection.FindOneAndUpdate(ctx, activeProducts(bson.M{"id": product.ID}), update, opts).Decode(&p)
+	if err == mongo.ErrNoDocuments {
+		metrics.RecordDatabaseQuery("find_one_and_update", "products", "not_found", time.Since(start).Seconds())
+		return nil, nil
+	}
+	if err != nil {
+		metrics.RecordDatabaseQuery("find_one_and_update", "products", "error", time.Since(start).Seconds())
+		return nil, err
+	}
+
+	metrics.RecordDatabaseQuery("find_one_and_update", "products", "success", time.Since(start).Seconds())
+	return &p, nil
+}
+
+// DeleteProduct soft deletes an active product so it disappears from the catalog while
+// remaining in the collection. Returns false if no active product has the given ID.
+func (r *productRepository) DeleteProduct(ctx context.Context, id string) (bool, error) {
+	start := time.Now()
+
+	now := time.Now().Unix()
+	update := bson.M{
+		"$set": bson.M{"deleted_at": now, "updated_at": now},
+		"$inc": bson.M{"version": 1},
+	}
+	res, err := r.collection.UpdateOne(ctx, activeProducts(bson.M{"id": id}), update)
+	if err != nil {
+		metrics.RecordDatabaseQuery("update_one", "products", "error", time.Since(start).Seconds())
+		return false, err
+	}
+	if res.MatchedCount == 0 {
+		metrics.RecordDatabaseQuery("update_one", "products", "not_found", time.Since(start).Seconds())
+		return false, nil
+	}
+
+	metrics.RecordDatabaseQuery("update_one", "products", "success", time.Since(start).Seconds())
+	return true, nil
+}
+
+// CountProductsByCategory returns the number of active products filed under each category ID.
+func (r *productRepository) CountProductsByCategory(ctx context.Context) (map[string]int64, error) {
+	start := time.Now()
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: activeProducts(bson.M{"category_id": bson.M{"$exists": true}})}},
+		{{Key: "$group", Value: bson.M{"_id": "$category_id", "count": bson.M{"$sum": 1}}}},
+	}
+	cur, err := r.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		metrics.RecordDatabaseQuery("aggregate", "products", "error", time.Since(start).Seconds())
+		return nil, err
+	}
+	defer cur.Close(ctx)
+
+	counts := map[string]int64{}
+	for cur.Next(ctx) {
+		var row struct {
+			ID    string `bson:"_id"`
+			Count int64  `bson:"count"`
+		}
+		if err := cur.Decode(&row); err != nil {
+			metrics.RecordDatabaseQuery("aggregate", "products", "error", time.Since(start).Seconds())
+			return nil, err
+		}
+		counts[row.ID] = row.Count
+	}
+	if err := cur.Err(); err != nil {
+		metrics.RecordDatabaseQuery("aggregate", "products", "error", time.Since(start).Seconds())
+		return nil, err
+	}
+
+	metrics.RecordDatabaseQuery("aggregate", "products", "success", time.Since(start).Seconds())
+	return counts, nil
+}
+
+// ListUncategorizedCategoryNames returns the distinct category names of products that
+// are not yet linked to a category ID, including soft-deleted products.
+func (r *productRepository) ListUncategorizedCategoryNames(ctx context.Context) ([]string, error) {
+	start := time.Now()
+
+	values, err := r.collection.Distinct(ctx, "category", bson.M{"category_id": bson.M{"$exists": false}})
+	if err != nil {
+		metrics.RecordDatabaseQuery("distinct", "products", "error", time.Since(start).Seconds())
+		return nil, err
+	}
+
+	names := make([]string, 0, len(values))
+	for _, v := range values {
+		if name, ok := v.(string); ok {
+			names = append(names, name)
+		}
+	}
+
+	metrics.RecordDatabaseQuery("distinct", "products", "success", time.Since(start).Seconds())
+	return names, nil
+}
+
+// AssignCategory links every product still filed under categoryName without a category ID
+// to the given category, replacing the name with the category's display name.
+// Returns the number of products updated.
+func (r *productRepository) AssignCategory(ctx context.Context, categoryName string, category *models.Category) (int64, error) {
+	start := time.Now()
+
+	filter := bson.M{"category": categoryName, "category_id": bson.M{"$exists": false}}
+	update := bson.M{"$set": bson.M{"category_id": category.ID, "category": category.Name}}
+	res, err := r.collection.UpdateMany(ctx, filter, update)
+	if err != nil {
+		metrics.RecordDatabaseQuery("update_many", "products", "error", time.Since(start).Seconds())
+		return 0, err
+	}
+
+	metrics.RecordDatabaseQuery("update_many", "products", "success", time.Since(start).Seconds())
+	return res.ModifiedCount, nil
+}
+
+// MarkProductsAvailable sets the available flag on every product that predates it.
+// Returns the number of products updated.
+func (r *productRepository) MarkProductsAvailable(ctx context.Context) (int64, error) {
+	start := time.Now()
+
+	filter := bson.M{"available": bson.M{"$exists": false}}
+	update := bson.M{"$set": bson.M{"available": true}}
+	res, err := r.collection.UpdateMany(ctx, filter, update)
+	if err != nil {
+		metrics.RecordDatabaseQuery("update_many", "products", "error", time.Since(start).Seconds())
+		return 0, err
+	}
+
+	metrics.RecordDatabaseQuery("update_many", "products", "success", time.Since(start).Seconds())
+	return res.ModifiedCount, nil
+}
+
 // BulkInsertProducts inserts multiple products into the database.
 func (r *productRepository) BulkInsertProducts(ctx context.Context, products []models.Product) error {
 	if len(products) == 0 {
@@ -156,3 +435,84 @@ func (r *productRepository) UpdateMigration(ctx context.Context, migration *mode
 	metrics.RecordDatabaseQuery("update_one", "migrations", "success", time.Since(start).Seconds())
 	return nil
 }
+
+// productFilterQuery builds the MongoDB query document for a product filter, excluding the cursor.
+func productFilterQuery(filter models.ProductFilter) bson.M {
+	query := activeProducts(nil)
+	if filter.CategoryID != "" {
+		query["category_id"] = filter.CategoryID
+	}
+	price := bson.M{}
+	if filter.MinPrice > 0 {
+		price["$gte"] = filter.MinPrice
+	}
+	if filter.MaxPrice > 0 {
+		price["$lte"] = filter.MaxPrice
+	}
+	if len(price) > 0 {
+		query["price"] = price
+	}
+	if len(filter.DietaryTags) > 0 {
+		query["dietary_tags"] = bson.M{"$all": filter.DietaryTags}
+	}
+	if len(filter.ExcludeAllergens) > 0 {
+		query["allergens"] = bson.M{"$nin": filter.ExcludeAllergens}
+	}
+	if filter.Search != "" {
+		// Text search is case-insensitive and matches whole (stemmed) words of the name
+		query["$text"] = bson.M{"$search": filter.Search}
+	}
+	return query
+}
+
+// productSortKey returns the field a product sort orders by, and whether it is descending.
+// An unknown or empty sort orders by ID.
+func productSortKey(sort string) (string, bool) {
+	switch sort {
+	case models.ProductSortName:
+		return "name", false
+	case models.ProductSortNameDesc:
+		return "name", true
+	case models.ProductSortPrice:
+		return "price", false
+	case models.ProductSortPriceDesc:
+		return "price", true
+	default:
+		return "id", false
+	}
+}
+
+// productSortOrder returns the sort document for a product sort, using ID as tie-breaker.
+func productSortOrder(sort string) bson.D {
+	field, desc := productSortKey(sort)
+	if field == "id" {
+		return bson.D{{Key: "id", Value: 1}}
+	}
+	direction := 1
+	if desc {
+		direction = -1
+	}
+	return bson.D{{Key: field, Value: direction}, {Key: "id", Value: 1}}
+}
+
+// productCursorQuery returns the $or conditions selecting the products that come after
+// the cursor in the given sort order.
+func productCursorQuery(sort string, after *models.ProductCursor) bson.A {
+	field, desc := productSortKey(sort)
+	if field == "id" {
+		return bson.A{bson.M{"id": bson.M{"$gt": after.ID}}}
+	}
+
+	var value interface{} = after.Name
+	if field == "price" {
+		value = after.Price
+	}
+	op := "$gt"
+	if desc {
+		op = "$lt"
+	}
+	return bson.A{
+		bson.M{field: bson.M{op: value}},
+		bson.M{field: value, "id": bson.M{"$gt": after.ID}},
+	}
+}
@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"context"
+	"orderfoodonline/internal/metrics"
+	"orderfoodonline/internal/repository/models"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// rateLimitRepository provides MongoDB-backed storage for rate limit counters.
+type rateLimitRepository struct {
+	collection *mongo.Collection
+}
+
+// NewRateLimitRepository creates a new RateLimitRepository using the given Repository.
+func NewRateLimitRepository(repo *Repository) (RateLimitRepository, error) {
+	collection := repo.db.Collection("rate_limits")
+
+	rateLimitRepo := &rateLimitRepository{collection: collection}
+	if err := rateLimitRepo.createRateLimitIndexes(context.Background()); err != nil {
+		return nil, err
+	}
+	return rateLimitRepo, nil
+}
+
+func (r *rateLimitRepository) createRateLimitIndexes(ctx context.Context) error {
+	indexModels := []mongo.IndexModel{
+		{
+			// Counters are removed by MongoDB once their window has ended
+			Keys:    bson.D{{Key: "expires_at", Value: 1}},
+			Options: options.Index().SetExpireAfterSeconds(0).SetName("rate_limit_expires_at_ttl_idx"),
+		},
+	}
+
+	// Set a timeout context
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	_, err := r.collection.Indexes().CreateMany(ctx, indexModels)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// IncrementRateLimit atomically increments the counter with the given ID, creating it if needed,
+// and returns its new count.
+func (r *rateLimitRepository) IncrementRateLimit(ctx context.Context, id string, expiresAt time.Time) (int64, error) {
+	start := time.Now()
+
+	filter := bson.M{"_id": id}
+	update := bson.M{
+		"$inc":         bson.M{"count": 1},
+		"$setOnInsert": bson.M{"expires_at": expiresAt},
+	}
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+
+	var counter models.RateLimitCounter
+	err := r.collection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&counter)
+	if mongo.IsDuplicateKeyError(err) {
+		// Another instance created the counter concurrently; it now exists, so the retry only increments it
+		err = r.collection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&counter)
+	}
+	if err != nil {
+		metrics.RecordDatabaseQuery("find_one_and_update", "rate_limits", "error", time.Since(start).Seconds())
+		return 0, err
+	}
+
+	metrics.RecordDatabaseQuery("find_one_and_update", "rate_limits", "success", time.Since(start).Seconds())
+	return counter.Count, nil
+}
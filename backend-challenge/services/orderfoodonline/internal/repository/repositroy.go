@@ -3,12 +3,14 @@ package repository
 import (
 	"context"
 	"fmt"
+	"net/url"
 	"orderfoodonline/internal/config"
 	"orderfoodonline/internal/metrics"
 	"time"
 
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.opentelemetry.io/contrib/instrumentation/go.mongodb.org/mongo-driver/mongo/otelmongo"
 )
 
 // Repository provides database access for the orderfoodonline service.
@@ -18,12 +20,16 @@ type Repository struct {
 }
 
 // NewRepository creates a new Repository instance with MongoDB connection.
+// Every command sent to MongoDB is traced as a child span of the span in its context.
 func NewRepository(ctx context.Context, cfg *config.DbConfig) (*Repository, error) {
 	start := time.Now()
 
 	mongoURI := fmt.Sprintf("%s://%s:%d", cfg.Type, cfg.Host, cfg.Port)
+	if cfg.ReplicaSet != "" {
+		mongoURI += "/?replicaSet=" + url.QueryEscape(cfg.ReplicaSet)
+	}
 
-	mongoClient, err := mongo.Connect(ctx, options.Client().ApplyURI(mongoURI))
+	mongoClient, err := mongo.Connect(ctx, options.Client().ApplyURI(mongoURI).SetMonitor(otelmongo.NewMonitor()))
 	if err != nil {
 		metrics.RecordDatabaseQuery("connect", "database", "error", time.Since(start).Seconds())
 		return nil, fmt.Errorf("error connecting to mongodb: %v", err)
@@ -0,0 +1,139 @@
+package repository
+
+import (
+	"context"
+	"orderfoodonline/internal/metrics"
+	"orderfoodonline/internal/repository/models"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// stockRepository provides MongoDB-backed access to product stock levels.
+type stockRepository struct {
+	collection *mongo.Collection
+}
+
+// NewStockRepository creates a new StockRepository using the given Repository.
+func NewStockRepository(repo *Repository) (StockRepository, error) {
+	collection := repo.db.Collection("stock")
+
+	stockRepo := &stockRepository{collection: collection}
+	if err := stockRepo.createStockIndexes(context.Background()); err != nil {
+		return nil, err
+	}
+	return stockRepo, nil
+}
+
+func (r *stockRepository) createStockIndexes(ctx context.Context) error {
+	indexModels := []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "product_id", Value: 1}},
+			Options: options.Index().SetUnique(true).SetName("stock_product_id_idx"),
+		},
+	}
+
+	// Set a timeout context
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	_, err := r.collection.Indexes().CreateMany(ctx, indexModels)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// FindStockByProductID returns the stock of a product, or nil if its stock is not tracked.
+func (r *stockRepository) FindStockByProductID(ctx context.Context, productID string) (*models.Stock, error) {
+	start := time.Now()
+
+	var s models.Stock
+	err := r.collection.FindOne(ctx, bson.M{"product_id": productID}).Decode(&s)
+	if err == mongo.ErrNoDocuments {
+		metrics.RecordDatabaseQuery("find_one", "stock", "not_found", time.Since(start).Seconds())
+		return nil, nil
+	}
+	if err != nil {
+		metrics.RecordDatabaseQuery("find_one", "stock", "error", time.Since(start).Seconds())
+		return nil, err
+	}
+
+	metrics.RecordDatabaseQuery("find_one", "stock", "success", time.Since(start).Seconds())
+	return &s, nil
+}
+
+// ReserveStock takes quantity units of a product out of stock. The decrement is guarded by
+// the current quantity in the same update, so concurrent orders can never oversell.
+// Returns false if the product's stock is tracked and fewer than quantity units are left.
+func (r *stockRepository) ReserveStock(ctx context.Context, productID string, quantity int) (bool, error) {
+	start := time.Now()
+
+	filter := bson.M{"product_id": productID, "quantity": bson.M{"$gte": quantity}}
+	update := bson.M{
+		"$inc": bson.M{"quantity": -quantity},
+		"$set": bson.M{"updated_at": time.Now().Unix()},
+	}
+	res, err := r.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		metrics.RecordDatabaseQuery("update_one", "stock", "error", time.Since(start).Seconds())
+		return false, err
+	}
+	if res.MatchedCount == 1 {
+		metrics.RecordDatabaseQuery("update_one", "stock", "success", time.Since(start).Seconds())
+		return true, nil
+	}
+
+	// Nothing matched: either the stock is too low or the product is not tracked at all
+	tracked, err := r.collection.CountDocuments(ctx, bson.M{"product_id": productID}, options.Count().SetLimit(1))
+	if err != nil {
+		metrics.RecordDatabaseQuery("count", "stock", "error", time.Since(start).Seconds())
+		return false, err
+	}
+	if tracked > 0 {
+		metrics.RecordDatabaseQuery("update_one", "stock", "insufficient", time.Since(start).Seconds())
+		return false, nil
+	}
+
+	metrics.RecordDatabaseQuery("update_one", "stock", "untracked", time.Since(start).Seconds())
+	return true, nil
+}
+
+// AddStock adds quantity units to a product's stock, starting to track it if needed,
+// and returns the new stock.
+func (r *stockRepository) AddStock(ctx context.Context, productID string, quantity int64) (*models.Stock, error) {
+	update := bson.M{
+		"$inc": bson.M{"quantity": quantity},
+		"$set": bson.M{"updated_at": time.Now().Unix()},
+	}
+	return r.upsertStock(ctx, productID, update)
+}
+
+// SetStock sets a product's stock to quantity units, starting to track it if needed,
+// and returns the new stock.
+func (r *stockRepository) SetStock(ctx context.Context, productID string, quantity int64) (*models.Stock, error) {
+	update := bson.M{
+		"$set": bson.M{"quantity": quantity, "updated_at": time.Now().Unix()},
+	}
+	return r.upsertStock(ctx, productID, update)
+}
+
+// upsertStock applies update to a product's stock record, creating the record if needed.
+func (r *stockRepository) upsertStock(ctx context.Context, productID string, update bson.M) (*models.Stock, error) {
+	start := time.Now()
+
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+
+	var s models.Stock
+	err := r.collection.FindOneAndUpdate(ctx, bson.M{"product_id": productID}, update, opts).Decode(&s)
+	if err != nil {
+		metrics.RecordDatabaseQuery("find_one_and_update", "stock", "error", time.Since(start).Seconds())
+		return nil, err
+	}
+
+	metrics.RecordDatabaseQuery("find_one_and_update", "stock", "success", time.Since(start).Seconds())
+	return &s, nil
+}
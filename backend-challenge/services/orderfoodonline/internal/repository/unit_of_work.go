@@ -0,0 +1,51 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"orderfoodonline/internal/metrics"
+	"time"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readconcern"
+	"go.mongodb.org/mongo-driver/mongo/writeconcern"
+)
+
+// unitOfWork implements UnitOfWork with MongoDB multi-document transactions.
+type unitOfWork struct {
+	client *mongo.Client
+}
+
+// NewUnitOfWork creates a UnitOfWork on the repository's MongoDB connection.
+// Transactions require MongoDB to run as a replica set or sharded cluster.
+func NewUnitOfWork(repo *Repository) UnitOfWork {
+	return &unitOfWork{client: repo.client}
+}
+
+// WithinTransaction starts a session and runs fn inside a snapshot transaction, committing
+// with majority write concern. The driver retries fn and the commit on transient errors.
+func (u *unitOfWork) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	start := time.Now()
+
+	session, err := u.client.StartSession()
+	if err != nil {
+		metrics.RecordDatabaseQuery("start_session", "database", "error", time.Since(start).Seconds())
+		return fmt.Errorf("error starting mongodb session: %v", err)
+	}
+	defer session.EndSession(context.WithoutCancel(ctx))
+
+	opts := options.Transaction().
+		SetReadConcern(readconcern.Snapshot()).
+		SetWriteConcern(writeconcern.Majority())
+	_, err = session.WithTransaction(ctx, func(sc mongo.SessionContext) (interface{}, error) {
+		return nil, fn(sc)
+	}, opts)
+	if err != nil {
+		metrics.RecordDatabaseQuery("transaction", "database", "aborted", time.Since(start).Seconds())
+		return err
+	}
+
+	metrics.RecordDatabaseQuery("transaction", "database", "committed", time.Since(start).Seconds())
+	return nil
+}
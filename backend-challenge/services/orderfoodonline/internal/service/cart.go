@@ -0,0 +1,412 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"library/logger"
+	"orderfoodonline/internal/metrics"
+	"orderfoodonline/internal/repository"
+	"orderfoodonline/internal/repository/models"
+	"strings"
+	"time"
+)
+
+// DefaultCartTTL is how long an untouched cart is kept when no TTL is configured.
+const DefaultCartTTL = 7 * 24 * time.Hour
+
+type cartService struct {
+	repo        repository.CartRepository
+	productRepo repository.ProductRepository
+	couponRepo  repository.CouponRepository
+	orders      OrderService
+	coupons     *couponPolicy
+	ttl         time.Duration
+	now         func() time.Time
+	logger      logger.ILogger
+}
+
+// NewCartService creates a new CartService. Carts are priced with the coupon rules of ruleRepo,
+// subject to their limits, as orders are, and checked out through orders.
+// Carts expire ttl after their last change; a non-positive ttl falls back to DefaultCartTTL.
+func NewCartService(repo repository.CartRepository, productRepo repository.ProductRepository,
+	couponRepo repository.CouponRepository, ruleRepo repository.CouponRuleRepository, orders OrderService,
+	ttl time.Duration, logger logger.ILogger) CartService {
+	if ttl <= 0 {
+		ttl = DefaultCartTTL
+	}
+	return &cartService{repo: repo, productRepo: productRepo, couponRepo: couponRepo, orders: orders,
+		coupons: newCouponPolicy(couponRepo, ruleRepo, logger), ttl: ttl, now: time.Now, logger: logger}
+}
+
+// CreateCart validates the requested items and coupon and stores them in a new cart.
+// Repeated product IDs are merged, and every product must exist and be available.
+func (s *cartService) CreateCart(ctx context.Context, req *models.CartCreateRequest) (*models.Cart, error) {
+	cart := &models.Cart{CustomerID: strings.TrimSpace(req.CustomerID), Items: []models.CartItem{}}
+	added := make([]string, 0, len(req.Items))
+	for _, item := range req.Items {
+		if err := addCartItem(cart, item); err != nil {
+			return nil, err
+		}
+		added = append(added, strings.TrimSpace(item.ProductID))
+	}
+	if code := strings.TrimSpace(req.CouponCode); code != "" {
+		if err := s.validateCoupon(ctx, code, cart.CustomerID); err != nil {
+			return nil, err
+		}
+		cart.CouponCode = code
+	}
+
+	byID, err := s.price(ctx, cart)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkOrderable(byID, added); err != nil {
+		return nil, err
+	}
+	cart.CreatedAt = s.now().Unix()
+	s.touch(cart)
+
+	created, err := s.repo.CreateCart(ctx, cart)
+	if err != nil {
+		s.logger.WithContext(ctx).Error("%s: %v", SaveCartError, err)
+		return nil, errors.New(SaveCartError)
+	}
+	return created, nil
+}
+
+// GetCart returns a cart re-priced against the current catalog. The new prices are not saved;
+// they are only persisted by the next change to the cart.
+// Returns nil if the cart does not exist, has expired or belongs to another customer.
+func (s *cartService) GetCart(ctx context.Context, id, customerID string) (*models.Cart, error) {
+	cart, err := s.find(ctx, id, customerID)
+	if err != nil {
+		if err.Error() == CartNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if _, err := s.price(ctx, cart); err != nil {
+		return nil, err
+	}
+	return cart, nil
+}
+
+// DeleteCart removes a cart.
+func (s *cartService) DeleteCart(ctx context.Context, id, customerID string) error {
+	if _, err := s.find(ctx, id, customerID); err != nil {
+		return err
+	}
+	deleted, err := s.repo.DeleteCart(ctx, id)
+	if err != nil {
+		s.logger.WithContext(ctx).Error("%s: %v", DeleteCartError, err)
+		return errors.New(DeleteCartError)
+	}
+	if !deleted {
+		return errors.New(CartNotFound)
+	}
+	return nil
+}
+
+// AddCartItem adds units of an existing, available product to a cart.
+func (s *cartService) AddCartItem(ctx context.Context, id, customerID string, item models.CartItem) (*models.Cart, error) {
+	cart, err := s.find(ctx, id, customerID)
+	if err != nil {
+		return nil, err
+	}
+	if err := addCartItem(cart, item); err != nil {
+		return nil, err
+	}
+	return s.save(ctx, cart, strings.TrimSpace(item.ProductID))
+}
+
+// UpdateCartItem sets the quantity of a product already in a cart to a positive number.
+func (s *cartService) UpdateCartItem(ctx context.Context, id, customerID, productID string, quantity int) (*models.Cart, error) {
+	if quantity <= 0 {
+		return nil, errors.New(InvalidProductOrQuantity)
+	}
+	cart, err := s.find(ctx, id, customerID)
+	if err != nil {
+		return nil, err
+	}
+	i := cartItemIndex(cart, productID)
+	if i < 0 {
+		return nil, errors.New(CartItemNotFound)
+	}
+	cart.Items[i].Quantity = quantity
+	return s.save(ctx, cart)
+}
+
+// RemoveCartItem removes a product from a cart.
+func (s *cartService) RemoveCartItem(ctx context.Context, id, customerID, productID string) (*models.Cart, error) {
+	cart, err := s.find(ctx, id, customerID)
+	if err != nil {
+		return nil, err
+	}
+	i := cartItemIndex(cart, productID)
+	if i < 0 {
+		return nil, errors.New(CartItemNotFound)
+	}
+	cart.Items = append(cart.Items[:i], cart.Items[i+1:]...)
+	return s.save(ctx, cart)
+}
+
+// ApplyCartCoupon applies a coupon to a cart. The code must be valid and redeemable by the
+// cart's customer. A coupon whose rule does not apply to the current items is still kept,
+// with the reason reported in CouponReason, since later changes to the cart may qualify it.
+func (s *cartService) ApplyCartCoupon(ctx context.Context, id, customerID, code string) (*models.Cart, error) {
+	code = strings.TrimSpace(code)
+	cart, err := s.find(ctx, id, customerID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.validateCoupon(ctx, code, cart.CustomerID); err != nil {
+		return nil, err
+	}
+	cart.CouponCode = code
+	return s.save(ctx, cart)
+}
+
+// RemoveCartCoupon removes the coupon from a cart, if it has one.
+func (s *cartService) RemoveCartCoupon(ctx context.Context, id, customerID string) (*models.Cart, error) {
+	cart, err := s.find(ctx, id, customerID)
+	if err != nil {
+		return nil, err
+	}
+	cart.CouponCode = ""
+	return s.save(ctx, cart)
+}
+
+// CheckoutCart places an order for the items and coupon of a cart. The order is priced and
+// validated by PlaceOrder exactly as a direct order would be, and fails with the same errors.
+// Each item is expected at the unit price and product version of its stored line, so that the
+// order fails with a StaleCartError rather than charging a price the cart did not show; the cart
+// is then stored at the current prices, so that it can be checked out again once reviewed.
+// The cart is deleted once the order is placed; if that fails, the cart is left to expire.
+func (s *cartService) CheckoutCart(ctx context.Context, id, customerID string) (*models.Order, error) {
+	cart, err := s.find(ctx, id, customerID)
+	if err != nil {
+		return nil, err
+	}
+	if len(cart.Items) == 0 {
+		return nil, errors.New(EmptyCart)
+	}
+
+	lines := make(map[string]models.OrderLine, len(cart.Lines))
+	for _, line := range cart.Lines {
+		lines[line.ProductID] = line
+	}
+	items := make([]models.OrderItem, 0, len(cart.Items))
+	for _, item := range cart.Items {
+		orderItem := models.OrderItem{ProductID: item.ProductID, Quantity: item.Quantity}
+		if line, ok := lines[item.ProductID]; ok {
+			price, version := line.UnitPrice, line.ProductVersion
+			orderItem.ExpectedPrice = &price
+			orderItem.ExpectedVersion = &version
+		}
+		items = append(items, orderItem)
+	}
+	order, err := s.orders.PlaceOrder(ctx, &models.OrderCreateRequest{
+		CouponCode: cart.CouponCode,
+		Items:      items,
+		CustomerID: cart.CustomerID,
+	})
+	var staleErr *StaleCartError
+	if errors.As(err, &staleErr) {
+		// A cart that cannot be re-priced or was changed concurrently is left as it is;
+		// the next read prices it anyway, and failures are logged by save.
+		_, _ = s.save(ctx, cart)
+		return nil, err
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.repo.DeleteCart(ctx, cart.ID); err != nil {
+		s.logger.WithContext(ctx).Error("%s: %v", DeleteCartError, err)
+	}
+	return order, nil
+}
+
+// find returns a cart of the customer by ID, failing with CartNotFound if it does not exist,
+// has expired or belongs to another customer. The carts of other customers are reported as
+// not found rather than forbidden, so that their IDs cannot be probed.
+func (s *cartService) find(ctx context.Context, id, customerID string) (*models.Cart, error) {
+	cart, err := s.repo.FindCartByID(ctx, id)
+	if err != nil {
+		s.logger.WithContext(ctx).Error("%s: %v", FindCartError, err)
+		return nil, errors.New(FindCartError)
+	}
+	if cart == nil || cart.CustomerID != strings.TrimSpace(customerID) {
+		return nil, errors.New(CartNotFound)
+	}
+	return cart, nil
+}
+
+// save re-prices a changed cart, checks that the products just added to it can be ordered,
+// and stores it. A cart changed by another request since it was read fails with CartConflict.
+func (s *cartService) save(ctx context.Context, cart *models.Cart, added ...string) (*models.Cart, error) {
+	byID, err := s.price(ctx, cart)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkOrderable(byID, added); err != nil {
+		return nil, err
+	}
+	s.touch(cart)
+
+	updated, err := s.repo.UpdateCart(ctx, cart)
+	if err != nil {
+		s.logger.WithContext(ctx).Error("%s: %v", SaveCartError, err)
+		return nil, errors.New(SaveCartError)
+	}
+	if updated == nil {
+		return nil, errors.New(CartConflict)
+	}
+	return updated, nil
+}
+
+// touch records a change to the cart and pushes back its expiry.
+func (s *cartService) touch(cart *models.Cart) {
+	now := s.now()
+	cart.UpdatedAt = now.Unix()
+	cart.ExpiresAt = now.Add(s.ttl)
+}
+
+// price fetches the cart's products and prices the cart the way PlaceOrder prices an order.
+// Products that were deleted or made unavailable are listed in UnavailableProductIDs and left
+// out of the lines. A coupon that cannot currently be redeemed, or whose rule does not apply
+// to the priced lines, grants no discount and its reason is reported in CouponReason.
+// It returns the fetched products by ID.
+func (s *cartService) price(ctx context.Context, cart *models.Cart) (map[string]models.Product, error) {
+	ids := make([]string, 0, len(cart.Items))
+	for _, item := range cart.Items {
+		ids = append(ids, item.ProductID)
+	}
+	found, err := s.productRepo.FindProductsByIDs(ctx, ids)
+	if err != nil {
+		s.logger.WithContext(ctx).Error("%s: %v", FindProductByIDError, err)
+		return nil, errors.New(FindProductByIDError)
+	}
+	byID := make(map[string]models.Product, len(found))
+	for _, prod := range found {
+		byID[prod.ID] = prod
+	}
+
+	order := &models.Order{}
+	cart.UnavailableProductIDs = nil
+	for _, item := range cart.Items {
+		prod, ok := byID[item.ProductID]
+		if !ok || !prod.Available {
+			cart.UnavailableProductIDs = append(cart.UnavailableProductIDs, item.ProductID)
+			continue
+		}
+		order.Items = append(order.Items, models.OrderItem{ProductID: item.ProductID, Quantity: item.Quantity})
+		order.Products = append(order.Products, prod)
+	}
+
+	cart.CouponReason = ""
+	policy, err := s.discountPolicy(ctx, cart)
+	if err != nil {
+		return nil, err
+	}
+	if reason := priceOrder(order, policy); reason != "" {
+		cart.CouponReason = reason
+		priceOrder(order, nil)
+	}
+
+	cart.Lines = order.Lines
+	cart.Subtotal = order.Subtotal
+	cart.Discount = order.Discount
+	cart.Promotion = order.Promotion
+	cart.Total = order.Total
+	return byID, nil
+}
+
+// discountPolicy returns the discount policy of the cart's coupon. It returns nil if the cart
+// has no coupon, or if the coupon can no longer be redeemed, in which case CouponReason says why.
+func (s *cartService) discountPolicy(ctx context.Context, cart *models.Cart) (DiscountPolicy, error) {
+	if cart.CouponCode == "" {
+		return nil, nil
+	}
+	rule, err := s.coupons.rule(ctx, cart.CouponCode)
+	if err != nil {
+		s.logger.WithContext(ctx).Error("%s: %v", CheckCouponError, err)
+		return nil, errors.New(CheckCouponError)
+	}
+	status, err := s.coupons.check(ctx, rule, cart.CouponCode, cart.CustomerID)
+	if err != nil {
+		s.logger.WithContext(ctx).Error("%s: %v", CheckCouponError, err)
+		return nil, errors.New(CheckCouponError)
+	}
+	if status.reason != "" {
+		cart.CouponReason = status.reason
+		return nil, nil
+	}
+	return ruleDiscount{rule: rule}, nil
+}
+
+// validateCoupon checks that a coupon code is valid and redeemable by the customer,
+// with the same rules PlaceOrder applies before pricing an order.
+func (s *cartService) validateCoupon(ctx context.Context, code, customerID string) error {
+	outcome, err := validateCouponCode(ctx, s.couponRepo, code)
+	if err != nil {
+		s.logger.WithContext(ctx).Error("%s: %v", CheckCouponError, err)
+		return errors.New(CheckCouponError)
+	}
+	if outcome != metrics.CouponValid {
+		return errors.New(InvalidPromoCode)
+	}
+	rule, err := s.coupons.rule(ctx, code)
+	if err != nil {
+		s.logger.WithContext(ctx).Error("%s: %v", CheckCouponError, err)
+		return errors.New(CheckCouponError)
+	}
+	status, err := s.coupons.check(ctx, rule, code, customerID)
+	if err != nil {
+		s.logger.WithContext(ctx).Error("%s: %v", CheckCouponError, err)
+		return errors.New(CheckCouponError)
+	}
+	if status.reason != "" {
+		return &CouponRejectedError{Reason: status.reason}
+	}
+	return nil
+}
+
+// addCartItem validates an item and adds it to the cart, adding its quantity to the
+// existing item if the product is already in the cart.
+func addCartItem(cart *models.Cart, item models.CartItem) error {
+	item.ProductID = strings.TrimSpace(item.ProductID)
+	if item.ProductID == "" || item.Quantity <= 0 {
+		return errors.New(InvalidProductOrQuantity)
+	}
+	if i := cartItemIndex(cart, item.ProductID); i >= 0 {
+		cart.Items[i].Quantity += item.Quantity
+		return nil
+	}
+	cart.Items = append(cart.Items, item)
+	return nil
+}
+
+// cartItemIndex returns the index of the product's item in the cart, or -1 if it is not in the cart.
+func cartItemIndex(cart *models.Cart, productID string) int {
+	for i, item := range cart.Items {
+		if item.ProductID == productID {
+			return i
+		}
+	}
+	return -1
+}
+
+// checkOrderable reports the first of the given products that does not exist or is unavailable.
+func checkOrderable(byID map[string]models.Product, productIDs []string) error {
+	for _, id := range productIDs {
+		prod, ok := byID[id]
+		if !ok {
+			return errors.New(ProductNotFound + id)
+		}
+		if !prod.Available {
+			return errors.New(ProductUnavailable + id)
+		}
+	}
+	return nil
+}
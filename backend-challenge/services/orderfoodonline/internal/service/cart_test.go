@@ -0,0 +1,585 @@
+package service
+
+import (
+	"context"
+	"errors"
+	libmocks "library/logger/mocks"
+	"testing"
+	"time"
+
+	"orderfoodonline/internal/repository"
+	"orderfoodonline/internal/repository/mocks"
+	"orderfoodonline/internal/repository/models"
+	servicemocks "orderfoodonline/internal/service/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+// cartTestNow is the fixed time carts are changed at in these tests.
+var cartTestNow = time.Date(2024, 6, 10, 6, 0, 0, 0, time.UTC)
+
+type cartTestDeps struct {
+	carts    *mocks.MockCartRepository
+	products *mocks.MockProductRepository
+	coupons  *mocks.MockCouponRepository
+	orders   *servicemocks.MockOrderService
+}
+
+// newTestCartService creates a cart service with mocked dependencies, a one hour TTL and a fixed clock.
+// A nil rule repository gives every coupon the default rule.
+func newTestCartService(ctrl *gomock.Controller, rules repository.CouponRuleRepository) (*cartService, cartTestDeps) {
+	deps := cartTestDeps{
+		carts:    mocks.NewMockCartRepository(ctrl),
+		products: mocks.NewMockProductRepository(ctrl),
+		coupons:  mocks.NewMockCouponRepository(ctrl),
+		orders:   servicemocks.NewMockOrderService(ctrl),
+	}
+	mockLogger := libmocks.NewMockILogger(ctrl)
+	mockLogger.EXPECT().WithContext(gomock.Any()).Return(mockLogger).AnyTimes()
+	mockLogger.EXPECT().Error(gomock.Any(), gomock.Any()).AnyTimes()
+	svc := NewCartService(deps.carts, deps.products, deps.coupons, rules, deps.orders, time.Hour, mockLogger).(*cartService)
+	svc.now = func() time.Time { return cartTestNow }
+	svc.coupons.now = svc.now
+	return svc, deps
+}
+
+func TestNewCartService_DefaultTTL(t *testing.T) {
+	// Given / When: A cart service created without a TTL
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	svc := NewCartService(nil, nil, nil, nil, nil, 0, libmocks.NewMockILogger(ctrl))
+
+	// Then: Carts are kept for the default TTL
+	assert.Equal(t, DefaultCartTTL, svc.(*cartService).ttl)
+}
+
+func TestCartService_CreateCart(t *testing.T) {
+	pizza := models.Product{ID: "1", Name: "Margherita", Price: 9.5, CategoryID: "pizza", Version: 2, Available: true}
+	soldOut := models.Product{ID: "2", Name: "Calzone", Price: 11, Available: false}
+
+	tests := []struct {
+		name          string
+		req           models.CartCreateRequest
+		setup         func(deps cartTestDeps)
+		expected      *models.Cart
+		expectedError string
+	}{
+		{
+			name:          "invalid quantity",
+			req:           models.CartCreateRequest{Items: []models.CartItem{{ProductID: "1", Quantity: 0}}},
+			expectedError: InvalidProductOrQuantity,
+		},
+		{
+			name: "invalid coupon",
+			req:  models.CartCreateRequest{CouponCode: "HAPPYHRS"},
+			setup: func(deps cartTestDeps) {
+				deps.coupons.EXPECT().ValidateCouponCode(gomock.Any(), "HAPPYHRS").Return(false, nil)
+			},
+			expectedError: InvalidPromoCode,
+		},
+		{
+			name: "unknown product",
+			req:  models.CartCreateRequest{Items: []models.CartItem{{ProductID: "9", Quantity: 1}}},
+			setup: func(deps cartTestDeps) {
+				deps.products.EXPECT().FindProductsByIDs(gomock.Any(), []string{"9"}).Return([]models.Product{}, nil)
+			},
+			expectedError: ProductNotFound + "9",
+		},
+		{
+			name: "unavailable product",
+			req:  models.CartCreateRequest{Items: []models.CartItem{{ProductID: "2", Quantity: 1}}},
+			setup: func(deps cartTestDeps) {
+				deps.products.EXPECT().FindProductsByIDs(gomock.Any(), []string{"2"}).Return([]models.Product{soldOut}, nil)
+			},
+			expectedError: ProductUnavailable + "2",
+		},
+		{
+			name: "empty cart",
+			req:  models.CartCreateRequest{CustomerID: " cust-1 "},
+			setup: func(deps cartTestDeps) {
+				deps.products.EXPECT().FindProductsByIDs(gomock.Any(), []string{}).Return([]models.Product{}, nil)
+				deps.carts.EXPECT().CreateCart(gomock.Any(), gomock.Any()).DoAndReturn(
+					func(_ context.Context, c *models.Cart) (*models.Cart, error) { return c, nil })
+			},
+			expected: &models.Cart{
+				CustomerID: "cust-1", Items: []models.CartItem{}, Lines: []models.OrderLine{},
+				CreatedAt: cartTestNow.Unix(), UpdatedAt: cartTestNow.Unix(), ExpiresAt: cartTestNow.Add(time.Hour),
+			},
+		},
+		{
+			name: "merges repeated products and applies the coupon",
+			req: models.CartCreateRequest{
+				CouponCode: " HAPPYHRS ",
+				Items:      []models.CartItem{{ProductID: "1", Quantity: 1}, {ProductID: " 1", Quantity: 1}},
+			},
+			setup: func(deps cartTestDeps) {
+				deps.coupons.EXPECT().ValidateCouponCode(gomock.Any(), "HAPPYHRS").Return(true, nil)
+				deps.products.EXPECT().FindProductsByIDs(gomock.Any(), []string{"1"}).Return([]models.Product{pizza}, nil)
+				deps.carts.EXPECT().CreateCart(gomock.Any(), gomock.Any()).DoAndReturn(
+					func(_ context.Context, c *models.Cart) (*models.Cart, error) { return c, nil })
+			},
+			expected: &models.Cart{
+				Items:      []models.CartItem{{ProductID: "1", Quantity: 2}},
+				CouponCode: "HAPPYHRS",
+				Lines: []models.OrderLine{{
+					ProductID: "1", Name: "Margherita", CategoryID: "pizza", ProductVersion: 2,
+					Quantity: 2, UnitPrice: 950, LineTotal: 1900,
+				}},
+				Subtotal:  1900,
+				Discount:  190,
+				Promotion: &models.AppliedDiscount{RuleID: DefaultCouponRuleID, Type: models.DiscountPercent, Description: defaultCouponRule.Description, Amount: 190},
+				Total:     1710,
+				CreatedAt: cartTestNow.Unix(), UpdatedAt: cartTestNow.Unix(), ExpiresAt: cartTestNow.Add(time.Hour),
+			},
+		},
+		{
+			name: "repository error",
+			req:  models.CartCreateRequest{},
+			setup: func(deps cartTestDeps) {
+				deps.products.EXPECT().FindProductsByIDs(gomock.Any(), []string{}).Return([]models.Product{}, nil)
+				deps.carts.EXPECT().CreateCart(gomock.Any(), gomock.Any()).Return(nil, errors.New("db error"))
+			},
+			expectedError: SaveCartError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given: A cart service
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			svc, deps := newTestCartService(ctrl, nil)
+			if tt.setup != nil {
+				tt.setup(deps)
+			}
+
+			// When: Creating the cart
+			cart, err := svc.CreateCart(context.Background(), &tt.req)
+
+			// Then: The priced cart or the matching error is returned
+			if tt.expectedError != "" {
+				require.Error(t, err)
+				assert.Equal(t, tt.expectedError, err.Error())
+				assert.Nil(t, cart)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, cart)
+		})
+	}
+}
+
+func TestCartService_GetCart_RepricesAgainstCatalog(t *testing.T) {
+	// Given: A cart priced before one product got dearer and another was withdrawn
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	svc, deps := newTestCartService(ctrl, nil)
+	stored := &models.Cart{
+		ID:    "cart-1",
+		Items: []models.CartItem{{ProductID: "1", Quantity: 2}, {ProductID: "2", Quantity: 1}},
+		Lines: []models.OrderLine{{ProductID: "1", Quantity: 2, UnitPrice: 900, LineTotal: 1800}},
+	}
+	deps.carts.EXPECT().FindCartByID(gomock.Any(), "cart-1").Return(stored, nil)
+	deps.products.EXPECT().FindProductsByIDs(gomock.Any(), []string{"1", "2"}).Return([]models.Product{
+		{ID: "1", Price: 9.5, Available: true},
+	}, nil)
+
+	// When: Reading the cart
+	cart, err := svc.GetCart(context.Background(), "cart-1", "")
+
+	// Then: It is priced at the current price and the withdrawn product is reported
+	require.NoError(t, err)
+	assert.Equal(t, models.Money(1900), cart.Subtotal)
+	assert.Equal(t, models.Money(1900), cart.Total)
+	assert.Equal(t, []string{"2"}, cart.UnavailableProductIDs)
+	require.Len(t, cart.Lines, 1)
+	assert.Equal(t, models.Money(950), cart.Lines[0].UnitPrice)
+}
+
+func TestCartService_GetCart_OtherCustomer(t *testing.T) {
+	// Given: A cart belonging to another customer
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	svc, deps := newTestCartService(ctrl, nil)
+	deps.carts.EXPECT().FindCartByID(gomock.Any(), "cart-1").Return(&models.Cart{ID: "cart-1", CustomerID: "cust-2"}, nil)
+
+	// When: Reading the cart, as an anonymous caller and as another customer
+	anonymous, anonymousErr := svc.GetCart(context.Background(), "cart-1", "")
+	deps.carts.EXPECT().FindCartByID(gomock.Any(), "cart-1").Return(&models.Cart{ID: "cart-1", CustomerID: "cust-2"}, nil)
+	other, otherErr := svc.GetCart(context.Background(), "cart-1", "cust-1")
+
+	// Then: The cart is reported as not found, without being priced
+	require.NoError(t, anonymousErr)
+	assert.Nil(t, anonymous)
+	require.NoError(t, otherErr)
+	assert.Nil(t, other)
+}
+
+func TestCartService_GetCart_NotFound(t *testing.T) {
+	// Given: No cart with the requested ID
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	svc, deps := newTestCartService(ctrl, nil)
+	deps.carts.EXPECT().FindCartByID(gomock.Any(), "missing").Return(nil, nil)
+
+	// When: Reading the cart
+	cart, err := svc.GetCart(context.Background(), "missing", "")
+
+	// Then: Nil is returned without error
+	require.NoError(t, err)
+	assert.Nil(t, cart)
+}
+
+func TestCartService_AddCartItem(t *testing.T) {
+	tests := []struct {
+		name          string
+		item          models.CartItem
+		found         *models.Cart
+		products      []models.Product
+		saves         bool
+		conflict      bool
+		expectedItems []models.CartItem
+		expectedError string
+	}{
+		{
+			name:          "cart not found",
+			item:          models.CartItem{ProductID: "1", Quantity: 1},
+			expectedError: CartNotFound,
+		},
+		{
+			name:          "invalid quantity",
+			item:          models.CartItem{ProductID: "1", Quantity: -1},
+			found:         &models.Cart{ID: "cart-1", Version: 3},
+			expectedError: InvalidProductOrQuantity,
+		},
+		{
+			name:          "unavailable product",
+			item:          models.CartItem{ProductID: "2", Quantity: 1},
+			found:         &models.Cart{ID: "cart-1", Version: 3},
+			products:      []models.Product{{ID: "2", Price: 4, Available: false}},
+			expectedError: ProductUnavailable + "2",
+		},
+		{
+			name:          "adds to the quantity of a product already in the cart",
+			item:          models.CartItem{ProductID: "1", Quantity: 2},
+			found:         &models.Cart{ID: "cart-1", Version: 3, Items: []models.CartItem{{ProductID: "1", Quantity: 1}}},
+			products:      []models.Product{{ID: "1", Price: 9.5, Available: true}},
+			saves:         true,
+			expectedItems: []models.CartItem{{ProductID: "1", Quantity: 3}},
+		},
+		{
+			name:          "changed concurrently",
+			item:          models.CartItem{ProductID: "1", Quantity: 1},
+			found:         &models.Cart{ID: "cart-1", Version: 3},
+			products:      []models.Product{{ID: "1", Price: 9.5, Available: true}},
+			saves:         true,
+			conflict:      true,
+			expectedError: CartConflict,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given: A stored cart
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			svc, deps := newTestCartService(ctrl, nil)
+			deps.carts.EXPECT().FindCartByID(gomock.Any(), "cart-1").Return(tt.found, nil)
+			if tt.products != nil {
+				deps.products.EXPECT().FindProductsByIDs(gomock.Any(), gomock.Any()).Return(tt.products, nil)
+			}
+			if tt.saves {
+				deps.carts.EXPECT().UpdateCart(gomock.Any(), gomock.Any()).DoAndReturn(
+					func(_ context.Context, c *models.Cart) (*models.Cart, error) {
+						// The update is conditional on the version the cart was read at
+						assert.Equal(t, int64(3), c.Version)
+						assert.Equal(t, cartTestNow.Add(time.Hour), c.ExpiresAt)
+						if tt.conflict {
+							return nil, nil
+						}
+						return c, nil
+					})
+			}
+
+			// When: Adding the item
+			cart, err := svc.AddCartItem(context.Background(), "cart-1", "", tt.item)
+
+			// Then: The re-priced cart or the matching error is returned
+			if tt.expectedError != "" {
+				require.Error(t, err)
+				assert.Equal(t, tt.expectedError, err.Error())
+				assert.Nil(t, cart)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedItems, cart.Items)
+			assert.Equal(t, models.Money(2850), cart.Total)
+		})
+	}
+}
+
+func TestCartService_UpdateAndRemoveCartItem(t *testing.T) {
+	ctx := context.Background()
+	stored := func() *models.Cart {
+		return &models.Cart{ID: "cart-1", Items: []models.CartItem{{ProductID: "1", Quantity: 1}, {ProductID: "2", Quantity: 1}}}
+	}
+	products := []models.Product{{ID: "1", Price: 9.5, Available: true}, {ID: "2", Price: 2.5, Available: true}}
+
+	// Given: A cart holding two products
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	svc, deps := newTestCartService(ctrl, nil)
+	deps.carts.EXPECT().FindCartByID(ctx, "cart-1").DoAndReturn(
+		func(context.Context, string) (*models.Cart, error) { return stored(), nil }).Times(3)
+	deps.products.EXPECT().FindProductsByIDs(ctx, gomock.Any()).Return(products, nil).Times(2)
+	deps.carts.EXPECT().UpdateCart(ctx, gomock.Any()).DoAndReturn(
+		func(_ context.Context, c *models.Cart) (*models.Cart, error) { return c, nil }).Times(2)
+
+	// When: Changing the quantity of a product to zero
+	cart, err := svc.UpdateCartItem(ctx, "cart-1", "", "1", 0)
+
+	// Then: The quantity is rejected
+	assert.Nil(t, cart)
+	assert.EqualError(t, err, InvalidProductOrQuantity)
+
+	// When: Changing the quantity of a product in the cart
+	cart, err = svc.UpdateCartItem(ctx, "cart-1", "", "1", 3)
+
+	// Then: The cart is re-priced with the new quantity
+	require.NoError(t, err)
+	assert.Equal(t, []models.CartItem{{ProductID: "1", Quantity: 3}, {ProductID: "2", Quantity: 1}}, cart.Items)
+	assert.Equal(t, models.Money(3100), cart.Total)
+
+	// When: Removing a product from the cart
+	cart, err = svc.RemoveCartItem(ctx, "cart-1", "", "1")
+
+	// Then: Only the other product is left
+	require.NoError(t, err)
+	assert.Equal(t, []models.CartItem{{ProductID: "2", Quantity: 1}}, cart.Items)
+	assert.Equal(t, models.Money(250), cart.Total)
+
+	// When: Removing a product that is not in the cart
+	cart, err = svc.RemoveCartItem(ctx, "cart-1", "", "9")
+
+	// Then: The item is reported as not found
+	assert.Nil(t, cart)
+	assert.EqualError(t, err, CartItemNotFound)
+}
+
+func TestCartService_ApplyCartCoupon(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("coupon rule not met yet is kept", func(t *testing.T) {
+		// Given: A cart below the minimum basket value of a coupon rule
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		mockRuleRepo := mocks.NewMockCouponRuleRepository(ctrl)
+		svc, deps := newTestCartService(ctrl, mockRuleRepo)
+		deps.carts.EXPECT().FindCartByID(ctx, "cart-1").Return(&models.Cart{ID: "cart-1", Items: []models.CartItem{{ProductID: "1", Quantity: 1}}}, nil)
+		deps.coupons.EXPECT().ValidateCouponCode(ctx, "BIGSPEND10").Return(true, nil)
+		// The rule is read once to check the coupon's limits and once to price the cart
+		mockRuleRepo.EXPECT().ListCouponRules(ctx).Return([]models.CouponRule{
+			{ID: "big", CodePrefix: "BIGSPEND", Type: models.DiscountFixed, Amount: 500, MinSubtotal: 2000, Active: true},
+		}, nil).Times(2)
+		deps.products.EXPECT().FindProductsByIDs(ctx, []string{"1"}).Return([]models.Product{{ID: "1", Price: 9.5, Available: true}}, nil)
+		deps.carts.EXPECT().UpdateCart(ctx, gomock.Any()).DoAndReturn(
+			func(_ context.Context, c *models.Cart) (*models.Cart, error) { return c, nil })
+
+		// When: Applying the coupon
+		cart, err := svc.ApplyCartCoupon(ctx, "cart-1", "", " BIGSPEND10 ")
+
+		// Then: The coupon is kept without discount and the reason is reported
+		require.NoError(t, err)
+		assert.Equal(t, "BIGSPEND10", cart.CouponCode)
+		assert.Equal(t, models.CouponReasonMinimumNotMet, cart.CouponReason)
+		assert.Equal(t, models.Money(0), cart.Discount)
+		assert.Nil(t, cart.Promotion)
+		assert.Equal(t, models.Money(950), cart.Total)
+	})
+
+	t.Run("coupon used up is rejected", func(t *testing.T) {
+		// Given: A coupon that has been redeemed the maximum number of times
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		rules := mocks.NewMockCouponRuleRepository(ctrl)
+		rules.EXPECT().ListCouponRules(ctx).Return([]models.CouponRule{
+			{ID: "happy-hours", CodePrefix: "HAPPY", Type: models.DiscountPercent, Percent: 15, Active: true, MaxRedemptions: 1},
+		}, nil)
+		svc, deps := newTestCartService(ctrl, rules)
+		deps.carts.EXPECT().FindCartByID(ctx, "cart-1").Return(&models.Cart{ID: "cart-1"}, nil)
+		deps.coupons.EXPECT().ValidateCouponCode(ctx, "HAPPYHRS").Return(true, nil)
+		deps.coupons.EXPECT().FindCouponUsage(ctx, "HAPPYHRS").Return(&models.CouponUsage{CouponCode: "HAPPYHRS", Redemptions: 1}, nil)
+
+		// When: Applying the coupon
+		cart, err := svc.ApplyCartCoupon(ctx, "cart-1", "", "HAPPYHRS")
+
+		// Then: The coupon is rejected with the reason and the cart is not saved
+		assert.Nil(t, cart)
+		var couponErr *CouponRejectedError
+		require.ErrorAs(t, err, &couponErr)
+		assert.Equal(t, models.CouponReasonLimitReached, couponErr.Reason)
+	})
+
+	t.Run("coupon removed", func(t *testing.T) {
+		// Given: A cart with a coupon
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		svc, deps := newTestCartService(ctrl, nil)
+		deps.carts.EXPECT().FindCartByID(ctx, "cart-1").Return(&models.Cart{ID: "cart-1", CouponCode: "HAPPYHRS", CouponReason: "expired"}, nil)
+		deps.products.EXPECT().FindProductsByIDs(ctx, []string{}).Return([]models.Product{}, nil)
+		deps.carts.EXPECT().UpdateCart(ctx, gomock.Any()).DoAndReturn(
+			func(_ context.Context, c *models.Cart) (*models.Cart, error) { return c, nil })
+
+		// When: Removing the coupon
+		cart, err := svc.RemoveCartCoupon(ctx, "cart-1", "")
+
+		// Then: Neither the coupon nor its reason are left
+		require.NoError(t, err)
+		assert.Empty(t, cart.CouponCode)
+		assert.Empty(t, cart.CouponReason)
+	})
+}
+
+func TestCartService_CheckoutCart(t *testing.T) {
+	ctx := context.Background()
+	stored := &models.Cart{
+		ID: "cart-1", CustomerID: "cust-1", CouponCode: "HAPPYHRS",
+		Items: []models.CartItem{{ProductID: "1", Quantity: 2}},
+		Lines: []models.OrderLine{{ProductID: "1", Quantity: 2, UnitPrice: 950, ProductVersion: 2}},
+	}
+	price, version := models.Money(950), int64(2)
+
+	tests := []struct {
+		name          string
+		found         *models.Cart
+		placeErr      error
+		deleteErr     error
+		expectedError string
+	}{
+		{name: "cart not found", expectedError: CartNotFound},
+		{name: "cart of another customer", found: &models.Cart{ID: "cart-1", CustomerID: "cust-2", Items: stored.Items}, expectedError: CartNotFound},
+		{name: "empty cart", found: &models.Cart{ID: "cart-1", CustomerID: "cust-1"}, expectedError: EmptyCart},
+		{name: "order rejected", found: stored, placeErr: &OutOfStockError{ProductIDs: []string{"1"}}, expectedError: OutOfStock},
+		{name: "order placed", found: stored},
+		{name: "order placed but cart not deleted", found: stored, deleteErr: errors.New("db error")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given: A stored cart
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			svc, deps := newTestCartService(ctrl, nil)
+			deps.carts.EXPECT().FindCartByID(ctx, "cart-1").Return(tt.found, nil)
+			if tt.found != nil && tt.found.CustomerID == "cust-1" && len(tt.found.Items) > 0 {
+				var placed *models.Order
+				if tt.placeErr == nil {
+					placed = &models.Order{ID: "order-1"}
+					deps.carts.EXPECT().DeleteCart(ctx, "cart-1").Return(tt.deleteErr == nil, tt.deleteErr)
+				}
+				deps.orders.EXPECT().PlaceOrder(ctx, &models.OrderCreateRequest{
+					CouponCode: "HAPPYHRS",
+					Items:      []models.OrderItem{{ProductID: "1", Quantity: 2, ExpectedPrice: &price, ExpectedVersion: &version}},
+					CustomerID: "cust-1",
+				}).Return(placed, tt.placeErr)
+			}
+
+			// When: Checking out the cart
+			order, err := svc.CheckoutCart(ctx, "cart-1", "cust-1")
+
+			// Then: The order is placed from the cart's contents and the cart is discarded
+			if tt.expectedError != "" {
+				require.Error(t, err)
+				assert.Equal(t, tt.expectedError, err.Error())
+				assert.Nil(t, order)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "order-1", order.ID)
+		})
+	}
+}
+
+func TestCartService_CheckoutCart_PriceChanged(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	svc, deps := newTestCartService(ctrl, nil)
+
+	// Given: A cart priced at 9.50 whose product has since been repriced to 10.50
+	deps.carts.EXPECT().FindCartByID(ctx, "cart-1").Return(&models.Cart{
+		ID: "cart-1", CustomerID: "cust-1", Version: 3,
+		Items: []models.CartItem{{ProductID: "1", Quantity: 2}},
+		Lines: []models.OrderLine{{ProductID: "1", Quantity: 2, UnitPrice: 950, LineTotal: 1900, ProductVersion: 2}},
+	}, nil)
+	price, version := models.Money(950), int64(2)
+	stale := &StaleCartError{Lines: []models.StaleOrderLine{{
+		ProductID: "1", ExpectedPrice: &price, CurrentPrice: 1050, ExpectedVersion: &version, CurrentVersion: 3,
+	}}}
+	deps.orders.EXPECT().PlaceOrder(ctx, &models.OrderCreateRequest{
+		Items:      []models.OrderItem{{ProductID: "1", Quantity: 2, ExpectedPrice: &price, ExpectedVersion: &version}},
+		CustomerID: "cust-1",
+	}).Return(nil, stale)
+	deps.products.EXPECT().FindProductsByIDs(ctx, []string{"1"}).Return([]models.Product{
+		{ID: "1", Name: "Margherita", Price: 10.5, Version: 3, Available: true},
+	}, nil)
+	var saved *models.Cart
+	deps.carts.EXPECT().UpdateCart(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, cart *models.Cart) (*models.Cart, error) {
+		saved = cart
+		return cart, nil
+	})
+
+	// When: Checking out the cart
+	order, err := svc.CheckoutCart(ctx, "cart-1", "cust-1")
+
+	// Then: No order is placed at the new price, and the cart is stored at it for review
+	assert.Nil(t, order)
+	assert.Equal(t, stale, err)
+	require.NotNil(t, saved)
+	require.Len(t, saved.Lines, 1)
+	assert.Equal(t, models.Money(1050), saved.Lines[0].UnitPrice)
+	assert.Equal(t, int64(3), saved.Lines[0].ProductVersion)
+	assert.Equal(t, models.Money(2100), saved.Total)
+}
+
+func TestCartService_DeleteCart(t *testing.T) {
+	owned := &models.Cart{ID: "cart-1", CustomerID: "cust-1"}
+	tests := []struct {
+		name          string
+		found         *models.Cart
+		deleted       bool
+		err           error
+		expectedError string
+	}{
+		{name: "deleted", found: owned, deleted: true},
+		{name: "not found", expectedError: CartNotFound},
+		{name: "cart of another customer", found: &models.Cart{ID: "cart-1", CustomerID: "cust-2"}, expectedError: CartNotFound},
+		{name: "expired while deleting", found: owned, expectedError: CartNotFound},
+		{name: "repository error", found: owned, err: errors.New("db error"), expectedError: DeleteCartError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given: A cart repository
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			svc, deps := newTestCartService(ctrl, nil)
+			deps.carts.EXPECT().FindCartByID(gomock.Any(), "cart-1").Return(tt.found, nil)
+			if tt.found == owned {
+				deps.carts.EXPECT().DeleteCart(gomock.Any(), "cart-1").Return(tt.deleted, tt.err)
+			}
+
+			// When: Deleting the cart for its customer
+			err := svc.DeleteCart(context.Background(), "cart-1", "cust-1")
+
+			// Then: Success or the matching error is returned
+			if tt.expectedError != "" {
+				assert.EqualError(t, err, tt.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
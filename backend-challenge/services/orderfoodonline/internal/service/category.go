@@ -0,0 +1,42 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"library/logger"
+	"orderfoodonline/internal/repository"
+	"orderfoodonline/internal/repository/models"
+)
+
+type categoryService struct {
+	repo        repository.CategoryRepository
+	productRepo repository.ProductRepository
+	logger      logger.ILogger
+}
+
+// NewCategoryService creates a new CategoryService.
+func NewCategoryService(repo repository.CategoryRepository, productRepo repository.ProductRepository,
+	logger logger.ILogger) CategoryService {
+	return &categoryService{repo: repo, productRepo: productRepo, logger: logger}
+}
+
+// ListCategories retrieves the active categories in display order, each with the
+// number of active products filed under it.
+func (s *categoryService) ListCategories(ctx context.Context) ([]models.CategoryResponse, error) {
+	categories, err := s.repo.ListCategories(ctx)
+	if err != nil {
+		s.logger.WithContext(ctx).Error("%s: %v", CategoryListingError, err)
+		return nil, errors.New(CategoryListingError)
+	}
+	counts, err := s.productRepo.CountProductsByCategory(ctx)
+	if err != nil {
+		s.logger.WithContext(ctx).Error("%s: %v", CategoryListingError, err)
+		return nil, errors.New(CategoryListingError)
+	}
+
+	resp := make([]models.CategoryResponse, 0, len(categories))
+	for _, category := range categories {
+		resp = append(resp, models.CategoryResponse{Category: category, ProductCount: counts[category.ID]})
+	}
+	return resp, nil
+}
@@ -0,0 +1,86 @@
+package service
+
+import (
+	"context"
+	"errors"
+	libmocks "library/logger/mocks"
+	"testing"
+
+	"orderfoodonline/internal/repository/mocks"
+	"orderfoodonline/internal/repository/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestCategoryService_ListCategories_Success(t *testing.T) {
+	// Given: Two categories, one of which has products
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockCategoryRepo := mocks.NewMockCategoryRepository(ctrl)
+	mockProductRepo := mocks.NewMockProductRepository(ctrl)
+	service := NewCategoryService(mockCategoryRepo, mockProductRepo, libmocks.NewMockILogger(ctrl))
+	ctx := context.Background()
+
+	categories := []models.Category{
+		{ID: "pizza", Name: "Pizza", SortOrder: 10, Active: true},
+		{ID: "burgers", Name: "Burgers", SortOrder: 20, Active: true},
+	}
+	mockCategoryRepo.EXPECT().ListCategories(ctx).Return(categories, nil)
+	mockProductRepo.EXPECT().CountProductsByCategory(ctx).Return(map[string]int64{"pizza": 3, "retired": 2}, nil)
+
+	// When: Listing categories
+	resp, err := service.ListCategories(ctx)
+
+	// Then: Categories keep their order and carry their product counts
+	require.NoError(t, err)
+	assert.Equal(t, []models.CategoryResponse{
+		{Category: categories[0], ProductCount: 3},
+		{Category: categories[1], ProductCount: 0},
+	}, resp)
+}
+
+func TestCategoryService_ListCategories_Errors(t *testing.T) {
+	tests := []struct {
+		name      string
+		mockSetup func(categoryRepo *mocks.MockCategoryRepository, productRepo *mocks.MockProductRepository)
+	}{
+		{
+			name: "category repository error",
+			mockSetup: func(categoryRepo *mocks.MockCategoryRepository, productRepo *mocks.MockProductRepository) {
+				categoryRepo.EXPECT().ListCategories(gomock.Any()).Return(nil, errors.New("db error"))
+			},
+		},
+		{
+			name: "product count error",
+			mockSetup: func(categoryRepo *mocks.MockCategoryRepository, productRepo *mocks.MockProductRepository) {
+				categoryRepo.EXPECT().ListCategories(gomock.Any()).Return([]models.Category{}, nil)
+				productRepo.EXPECT().CountProductsByCategory(gomock.Any()).Return(nil, errors.New("db error"))
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given: A category service whose repositories fail
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			mockCategoryRepo := mocks.NewMockCategoryRepository(ctrl)
+			mockProductRepo := mocks.NewMockProductRepository(ctrl)
+			mockLogger := libmocks.NewMockILogger(ctrl)
+			mockLogger.EXPECT().WithContext(gomock.Any()).Return(mockLogger).AnyTimes()
+			mockLogger.EXPECT().Error(gomock.Any(), gomock.Any()).Times(1)
+			tt.mockSetup(mockCategoryRepo, mockProductRepo)
+			service := NewCategoryService(mockCategoryRepo, mockProductRepo, mockLogger)
+
+			// When: Listing categories
+			resp, err := service.ListCategories(context.Background())
+
+			// Then: The listing error should be returned
+			assert.Nil(t, resp)
+			require.Error(t, err)
+			assert.Equal(t, CategoryListingError, err.Error())
+		})
+	}
+}
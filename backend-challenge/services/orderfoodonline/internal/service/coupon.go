@@ -0,0 +1,210 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"library/logger"
+	"orderfoodonline/internal/metrics"
+	"orderfoodonline/internal/repository"
+	"orderfoodonline/internal/repository/models"
+	"strings"
+	"time"
+)
+
+const (
+	// MinCouponCodeLength is the length of the shortest valid coupon code.
+	MinCouponCodeLength = 8
+	// MaxCouponCodeLength is the length of the longest valid coupon code.
+	MaxCouponCodeLength = 10
+)
+
+// validateCouponCode checks that a trimmed coupon code has a valid length and is found in at
+// least two coupon files. It returns the outcome of the check, one of the metrics.Coupon*
+// outcomes, and the code is valid only if it is metrics.CouponValid. The outcome is recorded
+// by PlaceOrder alone, so that coupon_validations_total counts each order once rather than
+// every cart update and preview.
+func validateCouponCode(ctx context.Context, repo repository.CouponRepository, code string) (string, error) {
+	switch {
+	case len(code) < MinCouponCodeLength:
+		return metrics.CouponTooShort, nil
+	case len(code) > MaxCouponCodeLength:
+		return metrics.CouponTooLong, nil
+	}
+	valid, err := repo.ValidateCouponCode(ctx, code)
+	if err != nil {
+		return metrics.CouponError, err
+	}
+	if !valid {
+		return metrics.CouponInvalid, nil
+	}
+	return metrics.CouponValid, nil
+}
+
+// couponPolicy resolves the discount rule of coupons and enforces the redemption limits of the rule.
+type couponPolicy struct {
+	repo     repository.CouponRepository
+	ruleRepo repository.CouponRuleRepository
+	now      func() time.Time
+	logger   logger.ILogger
+}
+
+// newCouponPolicy creates a couponPolicy. A nil rule repository gives every coupon the
+// default rule, which sets no redemption limits.
+func newCouponPolicy(repo repository.CouponRepository, ruleRepo repository.CouponRuleRepository,
+	logger logger.ILogger) *couponPolicy {
+	return &couponPolicy{repo: repo, ruleRepo: ruleRepo, now: time.Now, logger: logger}
+}
+
+// rule returns the discount rule of a coupon code. Malformed rules are logged and ignored.
+func (p *couponPolicy) rule(ctx context.Context, code string) (models.CouponRule, error) {
+	if p.ruleRepo == nil {
+		return defaultCouponRule, nil
+	}
+	rules, err := p.ruleRepo.ListCouponRules(ctx)
+	if err != nil {
+		return models.CouponRule{}, err
+	}
+	valid := make([]models.CouponRule, 0, len(rules))
+	for _, rule := range rules {
+		if err := validateCouponRule(rule); err != nil {
+			p.logger.WithContext(ctx).Warn("ignoring coupon rule: %v", err)
+			continue
+		}
+		valid = append(valid, rule)
+	}
+	return matchCouponRule(valid, code), nil
+}
+
+// couponStatus is the outcome of checking a valid coupon against its limits.
+type couponStatus struct {
+	reason    string // Why the coupon cannot be redeemed; empty if it can
+	remaining *int64 // Redemptions left before the rule's limit, nil if there is none
+}
+
+// check reports whether a valid coupon can still be redeemed by the customer under the
+// limits of its rule. The check is advisory: redeem enforces the same limits atomically
+// when the order is saved.
+func (l *couponPolicy) check(ctx context.Context, rule models.CouponRule, code, customerID string) (couponStatus, error) {
+	var status couponStatus
+	if !rule.ExpiresAt.IsZero() && l.now().After(rule.ExpiresAt) {
+		status.reason = models.CouponReasonExpired
+		return status, nil
+	}
+	if rule.OncePerCustomer && customerID == "" {
+		status.reason = models.CouponReasonCustomerRequired
+		return status, nil
+	}
+
+	if rule.MaxRedemptions > 0 {
+		usage, err := l.repo.FindCouponUsage(ctx, code)
+		if err != nil {
+			return status, err
+		}
+		remaining := rule.MaxRedemptions
+		if usage != nil {
+			remaining -= usage.Redemptions
+		}
+		if remaining <= 0 {
+			remaining = 0
+			status.reason = models.CouponReasonLimitReached
+		}
+		status.remaining = &remaining
+		if status.reason != "" {
+			return status, nil
+		}
+	}
+
+	if rule.OncePerCustomer {
+		redeemed, err := l.repo.HasCustomerRedeemed(ctx, code, customerID)
+		if err != nil {
+			return status, err
+		}
+		if redeemed {
+			status.reason = models.CouponReasonAlreadyRedeemed
+		}
+	}
+	return status, nil
+}
+
+// redeem records the use of the order's coupon, counting it against the limit of its rule
+// and the customer's single use. It must run in the transaction that saves the order.
+// A limit hit by a concurrent order is reported as a CouponRejectedError.
+func (l *couponPolicy) redeem(ctx context.Context, order *models.Order, rule models.CouponRule) error {
+	ok, err := l.repo.IncrementCouponUsage(ctx, order.CouponCode, rule.MaxRedemptions)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return &CouponRejectedError{Reason: models.CouponReasonLimitReached}
+	}
+
+	ok, err = l.repo.InsertCouponRedemption(ctx, &models.CouponRedemption{
+		CouponCode: order.CouponCode,
+		CustomerID: order.CustomerID,
+		OrderID:    order.ID,
+		RedeemedAt: l.now().Unix(),
+		Exclusive:  rule.OncePerCustomer,
+	})
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return &CouponRejectedError{Reason: models.CouponReasonAlreadyRedeemed}
+	}
+	return nil
+}
+
+type couponService struct {
+	repo   repository.CouponRepository
+	policy *couponPolicy
+	logger logger.ILogger
+}
+
+// NewCouponService creates a new CouponService applying the discounts and redemption limits of the rules of ruleRepo.
+func NewCouponService(repo repository.CouponRepository, ruleRepo repository.CouponRuleRepository,
+	logger logger.ILogger) CouponService {
+	return &couponService{repo: repo, policy: newCouponPolicy(repo, ruleRepo, logger), logger: logger}
+}
+
+// PreviewCoupon checks a coupon code the same way PlaceOrder does, without redeeming it.
+// A code that does not apply is not an error; the response says why it does not apply.
+// Since there is no basket yet, the rule's minimum subtotal and category are reported
+// rather than checked.
+func (s *couponService) PreviewCoupon(ctx context.Context, code, customerID string) (*models.CouponPreviewResponse, error) {
+	code = strings.TrimSpace(code)
+	preview := &models.CouponPreviewResponse{Code: code}
+
+	outcome, err := validateCouponCode(ctx, s.repo, code)
+	if err != nil {
+		s.logger.WithContext(ctx).Error("%s: %v", CheckCouponError, err)
+		return nil, errors.New(CheckCouponError)
+	}
+	if outcome != metrics.CouponValid {
+		preview.Reason = models.CouponReasonInvalid
+		return preview, nil
+	}
+
+	rule, err := s.policy.rule(ctx, code)
+	if err != nil {
+		s.logger.WithContext(ctx).Error("%s: %v", CheckCouponError, err)
+		return nil, errors.New(CheckCouponError)
+	}
+	if !rule.ExpiresAt.IsZero() {
+		preview.ExpiresAt = rule.ExpiresAt.Unix()
+	}
+
+	status, err := s.policy.check(ctx, rule, code, strings.TrimSpace(customerID))
+	if err != nil {
+		s.logger.WithContext(ctx).Error("%s: %v", CheckCouponError, err)
+		return nil, errors.New(CheckCouponError)
+	}
+	preview.Reason = status.reason
+	preview.RemainingRedemptions = status.remaining
+	if status.reason != "" {
+		return preview, nil
+	}
+
+	preview.Applicable = true
+	preview.Rule = &rule
+	return preview, nil
+}
@@ -0,0 +1,184 @@
+package service
+
+import (
+	"context"
+	"errors"
+	libmocks "library/logger/mocks"
+	"testing"
+	"time"
+
+	"orderfoodonline/internal/repository"
+	"orderfoodonline/internal/repository/mocks"
+	"orderfoodonline/internal/repository/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestCouponService_PreviewCoupon(t *testing.T) {
+	remaining := func(n int64) *int64 { return &n }
+	defaultRule := defaultCouponRule
+	pizzaRule := models.CouponRule{ID: "pizza", CodePrefix: "happy", Type: models.DiscountPercent, Percent: 15, CategoryID: "pizza", Active: true}
+	expiresAt := time.Unix(time.Now().Add(time.Hour).Unix(), 0)
+	expiredAt := time.Unix(time.Now().Add(-time.Hour).Unix(), 0)
+	// limited returns the rule of the HAPPY family with the given redemption limits
+	limited := func(oncePerCustomer bool, maxRedemptions int64, expiresAt time.Time) models.CouponRule {
+		return models.CouponRule{
+			ID: "happy-hours", CodePrefix: "HAPPY", Type: models.DiscountPercent, Percent: 15, Active: true,
+			OncePerCustomer: oncePerCustomer, MaxRedemptions: maxRedemptions, ExpiresAt: expiresAt,
+		}
+	}
+	withinLimits := limited(true, 5, expiresAt)
+
+	tests := []struct {
+		name          string
+		code          string
+		customerID    string
+		rules         []models.CouponRule
+		setup         func(repo *mocks.MockCouponRepository)
+		expected      *models.CouponPreviewResponse
+		expectedError string
+	}{
+		{
+			name:     "code of invalid length",
+			code:     "SHORT",
+			expected: &models.CouponPreviewResponse{Code: "SHORT", Reason: models.CouponReasonInvalid},
+		},
+		{
+			name: "unknown code",
+			code: "HAPPYHRS",
+			setup: func(repo *mocks.MockCouponRepository) {
+				repo.EXPECT().ValidateCouponCode(gomock.Any(), "HAPPYHRS").Return(false, nil)
+			},
+			expected: &models.CouponPreviewResponse{Code: "HAPPYHRS", Reason: models.CouponReasonInvalid},
+		},
+		{
+			name: "applicable without limits",
+			code: " HAPPYHRS ",
+			setup: func(repo *mocks.MockCouponRepository) {
+				repo.EXPECT().ValidateCouponCode(gomock.Any(), "HAPPYHRS").Return(true, nil)
+			},
+			expected: &models.CouponPreviewResponse{Code: "HAPPYHRS", Applicable: true, Rule: &defaultRule},
+		},
+		{
+			name:  "applicable with the rule of its family",
+			code:  "HAPPYHRS",
+			rules: []models.CouponRule{{ID: "broken", CodePrefix: "HAPPYH", Type: "unknown", Active: true}, pizzaRule},
+			setup: func(repo *mocks.MockCouponRepository) {
+				repo.EXPECT().ValidateCouponCode(gomock.Any(), "HAPPYHRS").Return(true, nil)
+			},
+			expected: &models.CouponPreviewResponse{Code: "HAPPYHRS", Applicable: true, Rule: &pizzaRule},
+		},
+		{
+			name:  "expired",
+			code:  "HAPPYHRS",
+			rules: []models.CouponRule{limited(false, 0, expiredAt)},
+			setup: func(repo *mocks.MockCouponRepository) {
+				repo.EXPECT().ValidateCouponCode(gomock.Any(), "HAPPYHRS").Return(true, nil)
+			},
+			expected: &models.CouponPreviewResponse{Code: "HAPPYHRS", Reason: models.CouponReasonExpired, ExpiresAt: expiredAt.Unix()},
+		},
+		{
+			name:  "customer required",
+			code:  "HAPPYHRS",
+			rules: []models.CouponRule{limited(true, 0, time.Time{})},
+			setup: func(repo *mocks.MockCouponRepository) {
+				repo.EXPECT().ValidateCouponCode(gomock.Any(), "HAPPYHRS").Return(true, nil)
+			},
+			expected: &models.CouponPreviewResponse{Code: "HAPPYHRS", Reason: models.CouponReasonCustomerRequired},
+		},
+		{
+			name:  "limit reached",
+			code:  "HAPPYHRS",
+			rules: []models.CouponRule{limited(false, 5, time.Time{})},
+			setup: func(repo *mocks.MockCouponRepository) {
+				repo.EXPECT().ValidateCouponCode(gomock.Any(), "HAPPYHRS").Return(true, nil)
+				repo.EXPECT().FindCouponUsage(gomock.Any(), "HAPPYHRS").Return(&models.CouponUsage{CouponCode: "HAPPYHRS", Redemptions: 5}, nil)
+			},
+			expected: &models.CouponPreviewResponse{Code: "HAPPYHRS", Reason: models.CouponReasonLimitReached, RemainingRedemptions: remaining(0)},
+		},
+		{
+			name:       "already redeemed by the customer",
+			code:       "HAPPYHRS",
+			customerID: "cust-1",
+			rules:      []models.CouponRule{limited(true, 5, time.Time{})},
+			setup: func(repo *mocks.MockCouponRepository) {
+				repo.EXPECT().ValidateCouponCode(gomock.Any(), "HAPPYHRS").Return(true, nil)
+				repo.EXPECT().FindCouponUsage(gomock.Any(), "HAPPYHRS").Return(&models.CouponUsage{CouponCode: "HAPPYHRS", Redemptions: 2}, nil)
+				repo.EXPECT().HasCustomerRedeemed(gomock.Any(), "HAPPYHRS", "cust-1").Return(true, nil)
+			},
+			expected: &models.CouponPreviewResponse{Code: "HAPPYHRS", Reason: models.CouponReasonAlreadyRedeemed, RemainingRedemptions: remaining(3)},
+		},
+		{
+			name:       "applicable within every limit",
+			code:       "HAPPYHRS",
+			customerID: "cust-1",
+			rules:      []models.CouponRule{withinLimits},
+			setup: func(repo *mocks.MockCouponRepository) {
+				repo.EXPECT().ValidateCouponCode(gomock.Any(), "HAPPYHRS").Return(true, nil)
+				repo.EXPECT().FindCouponUsage(gomock.Any(), "HAPPYHRS").Return(nil, nil)
+				repo.EXPECT().HasCustomerRedeemed(gomock.Any(), "HAPPYHRS", "cust-1").Return(false, nil)
+			},
+			expected: &models.CouponPreviewResponse{
+				Code: "HAPPYHRS", Applicable: true, Rule: &withinLimits,
+				ExpiresAt: expiresAt.Unix(), RemainingRedemptions: remaining(5),
+			},
+		},
+		{
+			name:  "limits of another family do not apply",
+			code:  "WELCOME10",
+			rules: []models.CouponRule{limited(true, 5, expiredAt)},
+			setup: func(repo *mocks.MockCouponRepository) {
+				repo.EXPECT().ValidateCouponCode(gomock.Any(), "WELCOME10").Return(true, nil)
+			},
+			expected: &models.CouponPreviewResponse{Code: "WELCOME10", Applicable: true, Rule: &defaultRule},
+		},
+		{
+			name:  "repository error",
+			code:  "HAPPYHRS",
+			rules: []models.CouponRule{limited(false, 5, time.Time{})},
+			setup: func(repo *mocks.MockCouponRepository) {
+				repo.EXPECT().ValidateCouponCode(gomock.Any(), "HAPPYHRS").Return(true, nil)
+				repo.EXPECT().FindCouponUsage(gomock.Any(), "HAPPYHRS").Return(nil, errors.New("db error"))
+			},
+			expectedError: CheckCouponError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given: A coupon service with the given rules
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			mockRepo := mocks.NewMockCouponRepository(ctrl)
+			mockLogger := libmocks.NewMockILogger(ctrl)
+			mockLogger.EXPECT().WithContext(gomock.Any()).Return(mockLogger).AnyTimes()
+			mockLogger.EXPECT().Error(gomock.Any(), gomock.Any()).AnyTimes()
+			mockLogger.EXPECT().Warn(gomock.Any(), gomock.Any()).AnyTimes()
+			if tt.setup != nil {
+				tt.setup(mockRepo)
+			}
+			var ruleRepo repository.CouponRuleRepository
+			if tt.rules != nil {
+				mockRuleRepo := mocks.NewMockCouponRuleRepository(ctrl)
+				mockRuleRepo.EXPECT().ListCouponRules(gomock.Any()).Return(tt.rules, nil)
+				ruleRepo = mockRuleRepo
+			}
+			service := NewCouponService(mockRepo, ruleRepo, mockLogger)
+
+			// When: Previewing the coupon
+			preview, err := service.PreviewCoupon(context.Background(), tt.code, tt.customerID)
+
+			// Then: The preview or the matching error is returned
+			if tt.expectedError != "" {
+				require.Error(t, err)
+				assert.Equal(t, tt.expectedError, err.Error())
+				assert.Nil(t, preview)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, preview)
+		})
+	}
+}
@@ -0,0 +1,143 @@
+package service
+
+import (
+	"fmt"
+	"orderfoodonline/internal/repository/models"
+	"strings"
+)
+
+// DefaultCouponRuleID identifies the rule applied to coupons that match no coupon rule.
+const DefaultCouponRuleID = "default"
+
+// defaultCouponRule takes DefaultCouponDiscountPercent off the whole order.
+var defaultCouponRule = models.CouponRule{
+	ID:          DefaultCouponRuleID,
+	Description: fmt.Sprintf("%d%% off the order", DefaultCouponDiscountPercent),
+	Type:        models.DiscountPercent,
+	Percent:     DefaultCouponDiscountPercent,
+	Active:      true,
+}
+
+// DiscountPolicy computes the discount granted on a priced order.
+type DiscountPolicy interface {
+	// Apply returns the discount granted on the given lines and subtotal, or the
+	// models.CouponReason value explaining why the coupon does not apply to them.
+	Apply(lines []models.OrderLine, subtotal models.Money) (*models.AppliedDiscount, string)
+}
+
+// validateCouponRule reports why a coupon rule cannot be applied, or nil if it is well formed.
+func validateCouponRule(rule models.CouponRule) error {
+	if strings.TrimSpace(rule.ID) == "" {
+		return fmt.Errorf("coupon rule has no id")
+	}
+	if !rule.Type.IsValid() {
+		return fmt.Errorf("coupon rule %s has unknown type %q", rule.ID, rule.Type)
+	}
+	if rule.Type == models.DiscountPercent && (rule.Percent <= 0 || rule.Percent > 100) {
+		return fmt.Errorf("coupon rule %s needs a percent between 1 and 100", rule.ID)
+	}
+	if rule.Type == models.DiscountFixed && rule.Amount <= 0 {
+		return fmt.Errorf("coupon rule %s needs a positive amount", rule.ID)
+	}
+	if rule.MinSubtotal < 0 {
+		return fmt.Errorf("coupon rule %s has a negative minimum subtotal", rule.ID)
+	}
+	if rule.MaxRedemptions < 0 {
+		return fmt.Errorf("coupon rule %s has a negative redemption limit", rule.ID)
+	}
+	return nil
+}
+
+// matchCouponRule returns the active rule whose code prefix is the longest case-insensitive
+// prefix of code, preferring the earliest rule on ties. Codes matching no rule get defaultCouponRule.
+// Rules must have been validated with validateCouponRule.
+func matchCouponRule(rules []models.CouponRule, code string) models.CouponRule {
+	code = strings.ToUpper(code)
+	best, bestLen := defaultCouponRule, -1
+	for _, rule := range rules {
+		prefix := strings.ToUpper(rule.CodePrefix)
+		if !rule.Active || !strings.HasPrefix(code, prefix) {
+			continue
+		}
+		if len(prefix) > bestLen {
+			best, bestLen = rule, len(prefix)
+		}
+	}
+	return best
+}
+
+// ruleDiscount is the DiscountPolicy of a coupon rule.
+type ruleDiscount struct {
+	rule models.CouponRule
+}
+
+// Apply evaluates the rule against the order. The subtotal must reach the rule's minimum
+// and, for category-scoped rules, only lines of that category are discounted. The discount
+// never exceeds the total of the eligible lines.
+func (d ruleDiscount) Apply(lines []models.OrderLine, subtotal models.Money) (*models.AppliedDiscount, string) {
+	rule := d.rule
+	if subtotal < rule.MinSubtotal {
+		return nil, models.CouponReasonMinimumNotMet
+	}
+
+	var eligible []models.OrderLine
+	var eligibleTotal models.Money
+	for _, line := range lines {
+		if rule.CategoryID == "" || line.CategoryID == rule.CategoryID {
+			eligible = append(eligible, line)
+			eligibleTotal += line.LineTotal
+		}
+	}
+
+	var amount models.Money
+	switch rule.Type {
+	case models.DiscountPercent:
+		amount = percentOf(eligibleTotal, rule.Percent)
+	case models.DiscountFixed:
+		amount = rule.Amount
+	case models.DiscountBuyOneGetOne:
+		for _, line := range eligible {
+			amount += line.UnitPrice.Mul(line.Quantity / 2)
+		}
+	case models.DiscountFreeCheapest:
+		amount = freeCheapest(eligible)
+	}
+	if amount > eligibleTotal {
+		amount = eligibleTotal
+	}
+	if amount <= 0 {
+		return nil, models.CouponReasonNotEligible
+	}
+
+	return &models.AppliedDiscount{
+		RuleID:      rule.ID,
+		Type:        rule.Type,
+		Description: rule.Description,
+		Amount:      amount,
+	}, ""
+}
+
+// percentOf returns percent% of amount, rounding half up to the nearest cent.
+func percentOf(amount models.Money, percent int64) models.Money {
+	if amount <= 0 || percent <= 0 {
+		return 0
+	}
+	return models.Money((int64(amount)*percent + 50) / 100)
+}
+
+// freeCheapest returns the unit price of the cheapest of the lines, or zero if they hold
+// fewer than two units in total.
+func freeCheapest(lines []models.OrderLine) models.Money {
+	units := 0
+	var cheapest models.Money
+	for i, line := range lines {
+		units += line.Quantity
+		if i == 0 || line.UnitPrice < cheapest {
+			cheapest = line.UnitPrice
+		}
+	}
+	if units < 2 {
+		return 0
+	}
+	return cheapest
+}
@@ -0,0 +1,155 @@
+package service
+
+import (
+	"testing"
+
+	"orderfoodonline/internal/repository/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRuleDiscount_Apply(t *testing.T) {
+	pizza := models.OrderLine{ProductID: "1", CategoryID: "pizza", Quantity: 3, UnitPrice: 1000, LineTotal: 3000}
+	cola := models.OrderLine{ProductID: "2", CategoryID: "beverages", Quantity: 1, UnitPrice: 250, LineTotal: 250}
+	salad := models.OrderLine{ProductID: "3", CategoryID: "salads", Quantity: 1, UnitPrice: 800, LineTotal: 800}
+
+	tests := []struct {
+		name           string
+		rule           models.CouponRule
+		lines          []models.OrderLine
+		expectedAmount models.Money
+		expectedReason string
+	}{
+		{
+			name:           "percent off the whole order rounds half up",
+			rule:           models.CouponRule{Type: models.DiscountPercent, Percent: 15},
+			lines:          []models.OrderLine{pizza, cola},
+			expectedAmount: 488, // 15% of 32.50 = 4.875
+		},
+		{
+			name:           "percent off a single category",
+			rule:           models.CouponRule{Type: models.DiscountPercent, Percent: 50, CategoryID: "beverages"},
+			lines:          []models.OrderLine{pizza, cola},
+			expectedAmount: 125,
+		},
+		{
+			name:           "fixed amount",
+			rule:           models.CouponRule{Type: models.DiscountFixed, Amount: 500},
+			lines:          []models.OrderLine{pizza, cola},
+			expectedAmount: 500,
+		},
+		{
+			name:           "fixed amount is capped at the eligible lines",
+			rule:           models.CouponRule{Type: models.DiscountFixed, Amount: 500, CategoryID: "beverages"},
+			lines:          []models.OrderLine{pizza, cola},
+			expectedAmount: 250,
+		},
+		{
+			name:           "buy one get one frees every second unit",
+			rule:           models.CouponRule{Type: models.DiscountBuyOneGetOne},
+			lines:          []models.OrderLine{pizza, cola},
+			expectedAmount: 1000,
+		},
+		{
+			name:           "buy one get one needs two units of a product",
+			rule:           models.CouponRule{Type: models.DiscountBuyOneGetOne},
+			lines:          []models.OrderLine{cola, salad},
+			expectedReason: models.CouponReasonNotEligible,
+		},
+		{
+			name:           "cheapest unit is free",
+			rule:           models.CouponRule{Type: models.DiscountFreeCheapest},
+			lines:          []models.OrderLine{pizza, salad, cola},
+			expectedAmount: 250,
+		},
+		{
+			name:           "cheapest unit is free only with two units",
+			rule:           models.CouponRule{Type: models.DiscountFreeCheapest},
+			lines:          []models.OrderLine{cola},
+			expectedReason: models.CouponReasonNotEligible,
+		},
+		{
+			name:           "minimum subtotal not reached",
+			rule:           models.CouponRule{Type: models.DiscountPercent, Percent: 10, MinSubtotal: 5000},
+			lines:          []models.OrderLine{pizza, cola},
+			expectedReason: models.CouponReasonMinimumNotMet,
+		},
+		{
+			name:           "no line of the category",
+			rule:           models.CouponRule{Type: models.DiscountPercent, Percent: 10, CategoryID: "desserts"},
+			lines:          []models.OrderLine{pizza, cola},
+			expectedReason: models.CouponReasonNotEligible,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given: A priced order
+			var subtotal models.Money
+			for _, line := range tt.lines {
+				subtotal += line.LineTotal
+			}
+			tt.rule.ID = "rule"
+			tt.rule.Description = "test rule"
+
+			// When: Applying the rule
+			applied, reason := ruleDiscount{rule: tt.rule}.Apply(tt.lines, subtotal)
+
+			// Then: The discount is explained by the rule, or the reason it does not apply is given
+			assert.Equal(t, tt.expectedReason, reason)
+			if tt.expectedReason != "" {
+				assert.Nil(t, applied)
+				return
+			}
+			assert.Equal(t, &models.AppliedDiscount{
+				RuleID:      "rule",
+				Type:        tt.rule.Type,
+				Description: "test rule",
+				Amount:      tt.expectedAmount,
+			}, applied)
+		})
+	}
+}
+
+func TestMatchCouponRule(t *testing.T) {
+	everyCode := models.CouponRule{ID: "all", Type: models.DiscountFixed, Amount: 100, Active: true}
+	happy := models.CouponRule{ID: "happy", CodePrefix: "HAPPY", Type: models.DiscountPercent, Percent: 20, Active: true}
+	happyHours := models.CouponRule{ID: "happy-hours", CodePrefix: "happyhr", Type: models.DiscountBuyOneGetOne, Active: true}
+	inactive := models.CouponRule{ID: "off", CodePrefix: "HAPPYHRS", Type: models.DiscountFreeCheapest}
+
+	rules := []models.CouponRule{everyCode, happy, happyHours, inactive}
+
+	// The longest matching prefix wins, ignoring case and inactive rules
+	assert.Equal(t, happyHours, matchCouponRule(rules, "HAPPYHRS"))
+	assert.Equal(t, happy, matchCouponRule(rules, "happy123"))
+	assert.Equal(t, everyCode, matchCouponRule(rules, "SUPER100"))
+	// Codes without a family get the default rule
+	assert.Equal(t, defaultCouponRule, matchCouponRule([]models.CouponRule{happy}, "SUPER100"))
+}
+
+func TestValidateCouponRule(t *testing.T) {
+	tests := []struct {
+		name    string
+		rule    models.CouponRule
+		wantErr bool
+	}{
+		{name: "valid percent", rule: models.CouponRule{ID: "a", Type: models.DiscountPercent, Percent: 15}},
+		{name: "valid free cheapest", rule: models.CouponRule{ID: "a", Type: models.DiscountFreeCheapest, MinSubtotal: 2000}},
+		{name: "missing id", rule: models.CouponRule{Type: models.DiscountBuyOneGetOne}, wantErr: true},
+		{name: "unknown type", rule: models.CouponRule{ID: "a", Type: "half_off"}, wantErr: true},
+		{name: "percent above 100", rule: models.CouponRule{ID: "a", Type: models.DiscountPercent, Percent: 120}, wantErr: true},
+		{name: "fixed without amount", rule: models.CouponRule{ID: "a", Type: models.DiscountFixed}, wantErr: true},
+		{name: "negative minimum", rule: models.CouponRule{ID: "a", Type: models.DiscountBuyOneGetOne, MinSubtotal: -1}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateCouponRule(tt.rule)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
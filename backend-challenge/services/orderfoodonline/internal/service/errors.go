@@ -1,5 +1,7 @@
 package service
 
+import "orderfoodonline/internal/repository/models"
+
 const (
 	// ProductListingError indicates an error occurred while listing products.
 	ProductListingError = "error listing products"
@@ -15,4 +17,104 @@ const (
 
 	// ProductNotFound is returned when the specified product could not be found.
 	ProductNotFound = "product not found: "
+	// FindOrderByIDError indicates a failure while fetching an order by its ID.
+	FindOrderByIDError = "error fetching order by ID"
+	// OrderListingError indicates an error occurred while listing orders.
+	OrderListingError = "error listing orders"
+	// InvalidOrderFilter is returned when the order listing criteria are invalid.
+	InvalidOrderFilter = "invalid order filter"
+	// OrderNotFound is returned when the specified order could not be found.
+	OrderNotFound = "order not found"
+	// InvalidOrderStatus is returned when the requested status is not a known order status.
+	InvalidOrderStatus = "invalid order status"
+	// InvalidStatusTransition is returned when the order cannot move from its current status to the requested one.
+	InvalidStatusTransition = "invalid order status transition"
+	// UpdateOrderStatusError indicates a failure while changing the status of an order.
+	UpdateOrderStatusError = "error updating order status"
+	// CategoryListingError indicates an error occurred while listing categories.
+	CategoryListingError = "error listing categories"
+	// FindCategoryByIDError indicates a failure while fetching a category by its ID.
+	FindCategoryByIDError = "error fetching category by ID"
+	// InvalidProductFilter is returned when the product listing criteria are invalid.
+	InvalidProductFilter = "invalid product filter"
+	// ProductUnavailable is returned when an order contains a product that cannot currently be ordered.
+	ProductUnavailable = "product unavailable: "
+	// InvalidProductDetails is returned when a product has an empty name, a non-positive price,
+	// a price with more than two decimal places, an unknown category or an unknown allergen or dietary tag.
+	InvalidProductDetails = "invalid product details"
+	// ProductAlreadyExists is returned when creating a product whose ID is already taken.
+	ProductAlreadyExists = "product already exists"
+	// SaveProductError indicates a failure while creating or updating a product.
+	SaveProductError = "error saving product"
+	// DeleteProductError indicates a failure while deleting a product.
+	DeleteProductError = "error deleting product"
+	// OutOfStock is returned when an order asks for more units of a product than are left in stock.
+	OutOfStock = "out of stock"
+	// ReserveStockError indicates a failure while reserving stock for an order.
+	ReserveStockError = "error reserving stock"
+	// ReleaseStockError indicates a failure while putting the stock of a cancelled order back.
+	ReleaseStockError = "error releasing stock"
+	// InvalidStockQuantity is returned when a stock quantity is negative, or not positive for a restock.
+	InvalidStockQuantity = "invalid stock quantity"
+	// StockNotTracked is returned when asking for the stock of a product whose stock is not tracked.
+	StockNotTracked = "stock not tracked"
+	// FindStockError indicates a failure while fetching the stock of a product.
+	FindStockError = "error fetching stock"
+	// SaveStockError indicates a failure while changing the stock of a product.
+	SaveStockError = "error saving stock"
+	// CouponRejected is returned when a valid coupon cannot be redeemed because of its redemption limits.
+	CouponRejected = "coupon not applicable: "
+	// CheckCouponError indicates a failure while checking a coupon against its redemption limits.
+	CheckCouponError = "error checking coupon"
+	// RedeemCouponError indicates a failure while recording the redemption of a coupon.
+	RedeemCouponError = "error redeeming coupon"
+	// StaleCart is returned when the expected price or version of an order item no longer matches the catalog.
+	StaleCart = "cart is out of date"
+	// CartNotFound is returned when the specified cart does not exist or has expired.
+	CartNotFound = "cart not found"
+	// CartItemNotFound is returned when changing or removing a product that is not in the cart.
+	CartItemNotFound = "cart item not found"
+	// CartConflict is returned when a cart was changed by another request while being updated.
+	CartConflict = "cart was modified concurrently"
+	// EmptyCart is returned when checking out a cart that holds no items.
+	EmptyCart = "cart is empty"
+	// FindCartError indicates a failure while fetching a cart.
+	FindCartError = "error fetching cart"
+	// SaveCartError indicates a failure while creating or updating a cart.
+	SaveCartError = "error saving cart"
+	// DeleteCartError indicates a failure while deleting a cart.
+	DeleteCartError = "error deleting cart"
 )
+
+// StaleCartError is returned by PlaceOrder when one or more items were priced against
+// an outdated view of the catalog. Lines lists every item that changed.
+type StaleCartError struct {
+	Lines []models.StaleOrderLine
+}
+
+// Error implements the error interface.
+func (e *StaleCartError) Error() string {
+	return StaleCart
+}
+
+// OutOfStockError is returned by PlaceOrder when one or more products do not have enough
+// stock left for the order. ProductIDs lists every such product.
+type OutOfStockError struct {
+	ProductIDs []string
+}
+
+// Error implements the error interface.
+func (e *OutOfStockError) Error() string {
+	return OutOfStock
+}
+
+// CouponRejectedError is returned when a valid coupon cannot be redeemed by the order.
+// Reason is one of the models.CouponReason values.
+type CouponRejectedError struct {
+	Reason string
+}
+
+// Error implements the error interface.
+func (e *CouponRejectedError) Error() string {
+	return CouponRejected + e.Reason
+}
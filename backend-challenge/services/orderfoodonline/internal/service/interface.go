@@ -10,13 +10,86 @@ import (
 // It provides high-level operations for retrieving and managing product information
 // with business rules and validation.
 type ProductService interface {
-	// ListProducts retrieves all available products with any necessary business logic
-	// such as filtering, sorting, or access control.
-	ListProducts(ctx context.Context) ([]models.Product, error)
+	// ListProducts retrieves a page of catalog products, including those not available for
+	// ordering, with any necessary business logic such as filtering, sorting, or access control.
+	ListProducts(ctx context.Context, filter models.ProductFilter) (*models.ProductListResponse, error)
 
 	// FindProductByID retrieves a specific product by ID with business validation
 	// such as checking if the product is active or available.
 	FindProductByID(ctx context.Context, id string) (*models.Product, error)
+
+	// CreateProduct validates and adds a new product to the catalog.
+	CreateProduct(ctx context.Context, req *models.ProductRequest) (*models.Product, error)
+
+	// ReplaceProduct validates and overwrites all editable fields of an existing product.
+	ReplaceProduct(ctx context.Context, id string, req *models.ProductRequest) (*models.Product, error)
+
+	// PatchProduct validates and updates only the fields set in the request.
+	PatchProduct(ctx context.Context, id string, req *models.ProductPatchRequest) (*models.Product, error)
+
+	// DeleteProduct soft deletes a product so historical orders that reference it still resolve.
+	DeleteProduct(ctx context.Context, id string) error
+}
+
+// CategoryService defines business logic operations for product categories.
+type CategoryService interface {
+	// ListCategories retrieves the active categories in display order with their product counts.
+	ListCategories(ctx context.Context) ([]models.CategoryResponse, error)
+}
+
+// StockService defines business logic operations for managing product stock levels.
+type StockService interface {
+	// GetStock retrieves the stock of an existing product.
+	// Fails with StockNotTracked if the product's stock is not tracked.
+	GetStock(ctx context.Context, productID string) (*models.Stock, error)
+
+	// SetStock overwrites the stock of an existing product with a non-negative quantity.
+	SetStock(ctx context.Context, productID string, quantity int64) (*models.Stock, error)
+
+	// Restock adds a positive quantity to the stock of an existing product.
+	Restock(ctx context.Context, productID string, quantity int64) (*models.Stock, error)
+}
+
+// CouponService defines business logic operations for coupons.
+type CouponService interface {
+	// PreviewCoupon reports whether a coupon code would be accepted for the customer's next
+	// order, applying the same validation and redemption limits as PlaceOrder.
+	PreviewCoupon(ctx context.Context, code, customerID string) (*models.CouponPreviewResponse, error)
+}
+
+// CartService defines business logic operations for server-side carts.
+// Every operation re-prices the cart against the current catalog and coupon rules.
+// Carts belong to the customer they were created for: operations on a cart take the
+// customer making the request and treat the carts of other customers as not found.
+type CartService interface {
+	// CreateCart creates a cart holding the requested items and coupon, if any.
+	CreateCart(ctx context.Context, req *models.CartCreateRequest) (*models.Cart, error)
+
+	// GetCart retrieves a cart by ID, priced at the current catalog prices.
+	// Returns nil if the cart does not exist or has expired.
+	GetCart(ctx context.Context, id, customerID string) (*models.Cart, error)
+
+	// DeleteCart discards a cart.
+	DeleteCart(ctx context.Context, id, customerID string) error
+
+	// AddCartItem adds units of a product to a cart, merging them with units already in it.
+	AddCartItem(ctx context.Context, id, customerID string, item models.CartItem) (*models.Cart, error)
+
+	// UpdateCartItem sets the quantity of a product already in a cart.
+	UpdateCartItem(ctx context.Context, id, customerID, productID string, quantity int) (*models.Cart, error)
+
+	// RemoveCartItem removes a product from a cart.
+	RemoveCartItem(ctx context.Context, id, customerID, productID string) (*models.Cart, error)
+
+	// ApplyCartCoupon validates a coupon code and applies it to a cart, replacing any previous coupon.
+	ApplyCartCoupon(ctx context.Context, id, customerID, code string) (*models.Cart, error)
+
+	// RemoveCartCoupon removes the coupon from a cart.
+	RemoveCartCoupon(ctx context.Context, id, customerID string) (*models.Cart, error)
+
+	// CheckoutCart places an order for the contents of a cart through OrderService.PlaceOrder,
+	// at the prices the cart was priced at, and discards the cart once the order is placed.
+	CheckoutCart(ctx context.Context, id, customerID string) (*models.Order, error)
 }
 
 // OrderService defines business logic operations for order management.
@@ -26,4 +99,17 @@ type OrderService interface {
 	// PlaceOrder creates a new order with business validation including
 	// product availability, coupon validation, pricing calculations, and inventory updates.
 	PlaceOrder(ctx context.Context, req *models.OrderCreateRequest) (*models.Order, error)
+
+	// GetOrder retrieves a previously placed order by ID.
+	// Returns nil if the order does not exist, or if customerID is set and the order
+	// was placed by another customer.
+	GetOrder(ctx context.Context, id, customerID string) (*models.Order, error)
+
+	// ListOrders retrieves a page of orders matching the filter, applying
+	// default and maximum page sizes.
+	ListOrders(ctx context.Context, filter models.OrderFilter) (*models.OrderListResponse, error)
+
+	// UpdateOrderStatus moves an order to a new lifecycle status, enforcing the allowed
+	// transitions and recording the change in the order's history.
+	UpdateOrderStatus(ctx context.Context, id string, req *models.OrderStatusUpdateRequest) (*models.Order, error)
 }
@@ -11,6 +11,7 @@ import (
 	"strconv"
 	"strings"
 	"time"
+	"unicode"
 
 	"library/logger"
 	"orderfoodonline/internal/repository"
@@ -21,15 +22,20 @@ import (
 
 // MigrationService handles database migrations and seeding.
 type MigrationService struct {
-	repo repository.ProductRepository
-	log  logger.ILogger
+	repo         repository.ProductRepository
+	categoryRepo repository.CategoryRepository
+	orderRepo    repository.OrderRepository
+	log          logger.ILogger
 }
 
 // NewMigrationService creates a new MigrationService.
-func NewMigrationService(repo repository.ProductRepository, log logger.ILogger) *MigrationService {
+func NewMigrationService(repo repository.ProductRepository, categoryRepo repository.CategoryRepository,
+	orderRepo repository.OrderRepository, log logger.ILogger) *MigrationService {
 	return &MigrationService{
-		repo: repo,
-		log:  log,
+		repo:         repo,
+		categoryRepo: categoryRepo,
+		orderRepo:    orderRepo,
+		log:          log,
 	}
 }
 
@@ -168,14 +174,14 @@ func (m *MigrationService) applyMigration(ctx context.Context, migrationPath str
 		return fmt.Errorf("failed to insert migration record: %w", err)
 	}
 
-	// Apply the migration (seed products)
-	if err := m.seedProducts(ctx, migrationData.Products); err != nil {
+	// Apply the migration (seed categories and products, then backfill categories)
+	if err := m.apply(ctx, &migrationData); err != nil {
 		// Update migration status to failed
 		migration.Status = "failed"
 		if updateErr := m.repo.UpdateMigration(ctx, migration); updateErr != nil {
 			m.log.Error("Failed to update migration status: %v", updateErr)
 		}
-		return fmt.Errorf("failed to seed products: %w", err)
+		return err
 	}
 
 	// Update migration status to completed
@@ -188,12 +194,140 @@ func (m *MigrationService) applyMigration(ctx context.Context, migrationPath str
 	return nil
 }
 
+// apply performs the changes described by a migration file.
+func (m *MigrationService) apply(ctx context.Context, migrationData *models.MigrationData) error {
+	if err := m.seedCategories(ctx, migrationData.Categories); err != nil {
+		return fmt.Errorf("failed to seed categories: %w", err)
+	}
+	if err := m.seedProducts(ctx, migrationData.Products); err != nil {
+		return fmt.Errorf("failed to seed products: %w", err)
+	}
+	if migrationData.BackfillCategories {
+		if err := m.backfillCategories(ctx); err != nil {
+			return fmt.Errorf("failed to backfill categories: %w", err)
+		}
+	}
+	if migrationData.BackfillAvailability {
+		updated, err := m.repo.MarkProductsAvailable(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to backfill product availability: %w", err)
+		}
+		m.log.Info("Marked %d products as available", updated)
+	}
+	if migrationData.RenameOrderTimestamps {
+		updated, err := m.orderRepo.RenameLegacyOrderTimestamps(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to rename order timestamps: %w", err)
+		}
+		m.log.Info("Renamed the timestamps of %d orders", updated)
+	}
+	return nil
+}
+
+// seedCategories stores the given categories, leaving existing categories with the same ID untouched.
+func (m *MigrationService) seedCategories(ctx context.Context, categories []models.Category) error {
+	for i := range categories {
+		category := categories[i]
+		if categoryIDFor(category.ID) != category.ID || strings.TrimSpace(category.Name) == "" {
+			return fmt.Errorf("invalid category %q", category.ID)
+		}
+		if _, err := m.categoryRepo.InsertCategoryIfMissing(ctx, &category); err != nil {
+			return err
+		}
+	}
+	if len(categories) > 0 {
+		m.log.Info("Seeded %d categories", len(categories))
+	}
+	return nil
+}
+
+// backfillCategories links every product that only has a free-form category name to a
+// category. Names are matched case-insensitively on their derived ID, so "Pizza" and
+// "pizza" end up in the same category; categories that do not exist yet are created.
+func (m *MigrationService) backfillCategories(ctx context.Context) error {
+	names, err := m.repo.ListUncategorizedCategoryNames(ctx)
+	if err != nil {
+		return err
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		id := categoryIDFor(name)
+		if id == "" {
+			m.log.Error("Skipping products with unusable category name %q", name)
+			continue
+		}
+		category, err := m.categoryRepo.InsertCategoryIfMissing(ctx, &models.Category{
+			ID:     id,
+			Name:   strings.TrimSpace(name),
+			Active: true,
+		})
+		if err != nil {
+			return err
+		}
+		updated, err := m.repo.AssignCategory(ctx, name, category)
+		if err != nil {
+			return err
+		}
+		m.log.Info("Linked %d products named %q to category %s", updated, name, category.ID)
+	}
+	return nil
+}
+
+// categoryIDFor derives a category ID from a category name: lower case, with every run
+// of characters other than letters and digits replaced by a single dash.
+func categoryIDFor(name string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(strings.TrimSpace(name)) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if dash && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			b.WriteRune(r)
+			dash = false
+			continue
+		}
+		dash = true
+	}
+	return b.String()
+}
+
 // seedProducts seeds the database with product data.
-func (m *MigrationService) seedProducts(ctx context.Context, products []models.Product) error {
-	if len(products) == 0 {
+// Products that reference a category must reference an existing one, and allergen and
+// dietary tags must be known.
+func (m *MigrationService) seedProducts(ctx context.Context, seeds []models.MigrationProduct) error {
+	if len(seeds) == 0 {
 		return nil
 	}
 
+	products := make([]models.Product, 0, len(seeds))
+	for _, seed := range seeds {
+		product := seed.ToProduct()
+		for _, tag := range product.Allergens {
+			if !models.IsAllergen(tag) {
+				return fmt.Errorf("product %s has unknown allergen %s", product.ID, tag)
+			}
+		}
+		for _, tag := range product.DietaryTags {
+			if !models.IsDietaryTag(tag) {
+				return fmt.Errorf("product %s has unknown dietary tag %s", product.ID, tag)
+			}
+		}
+		products = append(products, product)
+
+		if product.CategoryID == "" {
+			continue
+		}
+		category, err := m.categoryRepo.FindCategoryByID(ctx, product.CategoryID)
+		if err != nil {
+			return err
+		}
+		if category == nil {
+			return fmt.Errorf("product %s references unknown category %s", product.ID, product.CategoryID)
+		}
+	}
+
 	m.log.Info("Seeding %d products", len(products))
 
 	// Insert products in batches
@@ -0,0 +1,125 @@
+package service
+
+import (
+	"context"
+	libmocks "library/logger/mocks"
+	"testing"
+
+	"orderfoodonline/internal/repository/mocks"
+	"orderfoodonline/internal/repository/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func Test_categoryIDFor(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{name: "Pizza", want: "pizza"},
+		{name: " pizza ", want: "pizza"},
+		{name: "Ice Cream & Sorbets", want: "ice-cream-sorbets"},
+		{name: "--", want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, categoryIDFor(tt.name))
+		})
+	}
+}
+
+func TestMigrationService_backfillCategories(t *testing.T) {
+	// Given: Products filed under differently spelled names of the same category
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockProductRepo := mocks.NewMockProductRepository(ctrl)
+	mockCategoryRepo := mocks.NewMockCategoryRepository(ctrl)
+	mockLogger := libmocks.NewMockILogger(ctrl)
+	mockLogger.EXPECT().Info(gomock.Any(), gomock.Any()).AnyTimes()
+	m := NewMigrationService(mockProductRepo, mockCategoryRepo, nil, mockLogger)
+	ctx := context.Background()
+
+	pizza := &models.Category{ID: "pizza", Name: "Pizza", SortOrder: 10, Active: true}
+	mockProductRepo.EXPECT().ListUncategorizedCategoryNames(ctx).Return([]string{"pizza", "Pizza"}, nil)
+	mockCategoryRepo.EXPECT().InsertCategoryIfMissing(ctx, &models.Category{ID: "pizza", Name: "Pizza", Active: true}).
+		Return(pizza, nil)
+	mockCategoryRepo.EXPECT().InsertCategoryIfMissing(ctx, &models.Category{ID: "pizza", Name: "pizza", Active: true}).
+		Return(pizza, nil)
+	mockProductRepo.EXPECT().AssignCategory(ctx, "Pizza", pizza).Return(int64(3), nil)
+	mockProductRepo.EXPECT().AssignCategory(ctx, "pizza", pizza).Return(int64(1), nil)
+
+	// When: Backfilling categories
+	err := m.backfillCategories(ctx)
+
+	// Then: Both spellings should be linked to the same category
+	require.NoError(t, err)
+}
+
+func TestMigrationService_seedProducts_UnknownCategory(t *testing.T) {
+	// Given: A product referencing a category that does not exist
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockProductRepo := mocks.NewMockProductRepository(ctrl)
+	mockCategoryRepo := mocks.NewMockCategoryRepository(ctrl)
+	m := NewMigrationService(mockProductRepo, mockCategoryRepo, nil, libmocks.NewMockILogger(ctrl))
+	ctx := context.Background()
+
+	mockCategoryRepo.EXPECT().FindCategoryByID(ctx, "waffles").Return(nil, nil)
+
+	// When: Seeding the product
+	err := m.seedProducts(ctx, []models.MigrationProduct{
+		{Product: models.Product{ID: "1", Name: "Chicken Waffle", Price: 12.99, CategoryID: "waffles"}},
+	})
+
+	// Then: Nothing should be inserted
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unknown category waffles")
+}
+
+func TestMigrationService_seedProducts_DefaultsToAvailable(t *testing.T) {
+	// Given: Seed products with and without an explicit available flag
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockProductRepo := mocks.NewMockProductRepository(ctrl)
+	mockLogger := libmocks.NewMockILogger(ctrl)
+	mockLogger.EXPECT().Info(gomock.Any(), gomock.Any()).AnyTimes()
+	m := NewMigrationService(mockProductRepo, mocks.NewMockCategoryRepository(ctrl), nil, mockLogger)
+	ctx := context.Background()
+
+	unavailable := false
+	seeds := []models.MigrationProduct{
+		{Product: models.Product{ID: "1", Name: "Chicken Waffle", Price: 12.99}},
+		{Product: models.Product{ID: "2", Name: "Beef Burger", Price: 15.5}, Available: &unavailable},
+	}
+	mockProductRepo.EXPECT().BulkInsertProducts(ctx, []models.Product{
+		{ID: "1", Name: "Chicken Waffle", Price: 12.99, Available: true},
+		{ID: "2", Name: "Beef Burger", Price: 15.5, Available: false},
+	}).Return(nil)
+
+	// When: Seeding the products
+	err := m.seedProducts(ctx, seeds)
+
+	// Then: Products are available unless the migration says otherwise
+	require.NoError(t, err)
+}
+
+func TestMigrationService_apply_RenameOrderTimestamps(t *testing.T) {
+	// Given: A migration renaming the timestamps of orders
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockOrderRepo := mocks.NewMockOrderRepository(ctrl)
+	mockLogger := libmocks.NewMockILogger(ctrl)
+	mockLogger.EXPECT().Info(gomock.Any(), int64(3))
+	m := NewMigrationService(mocks.NewMockProductRepository(ctrl), mocks.NewMockCategoryRepository(ctrl), mockOrderRepo, mockLogger)
+	ctx := context.Background()
+
+	mockOrderRepo.EXPECT().RenameLegacyOrderTimestamps(ctx).Return(int64(3), nil)
+
+	// When: Applying the migration
+	err := m.apply(ctx, &models.MigrationData{Version: "0004", RenameOrderTimestamps: true})
+
+	// Then: The orders should be updated
+	require.NoError(t, err)
+}
@@ -41,6 +41,35 @@ func (m *MockProductService) EXPECT() *MockProductServiceMockRecorder {
 	return m.recorder
 }
 
+// CreateProduct mocks base method.
+func (m *MockProductService) CreateProduct(ctx context.Context, req *models.ProductRequest) (*models.Product, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateProduct", ctx, req)
+	ret0, _ := ret[0].(*models.Product)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateProduct indicates an expected call of CreateProduct.
+func (mr *MockProductServiceMockRecorder) CreateProduct(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateProduct", reflect.TypeOf((*MockProductService)(nil).CreateProduct), ctx, req)
+}
+
+// DeleteProduct mocks base method.
+func (m *MockProductService) DeleteProduct(ctx context.Context, id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteProduct", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteProduct indicates an expected call of DeleteProduct.
+func (mr *MockProductServiceMockRecorder) DeleteProduct(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteProduct", reflect.TypeOf((*MockProductService)(nil).DeleteProduct), ctx, id)
+}
+
 // FindProductByID mocks base method.
 func (m *MockProductService) FindProductByID(ctx context.Context, id string) (*models.Product, error) {
 	m.ctrl.T.Helper()
@@ -57,18 +86,353 @@ func (mr *MockProductServiceMockRecorder) FindProductByID(ctx, id any) *gomock.C
 }
 
 // ListProducts mocks base method.
-func (m *MockProductService) ListProducts(ctx context.Context) ([]models.Product, error) {
+func (m *MockProductService) ListProducts(ctx context.Context, filter models.ProductFilter) (*models.ProductListResponse, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListProducts", ctx)
-	ret0, _ := ret[0].([]models.Product)
+	ret := m.ctrl.Call(m, "ListProducts", ctx, filter)
+	ret0, _ := ret[0].(*models.ProductListResponse)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // ListProducts indicates an expected call of ListProducts.
-func (mr *MockProductServiceMockRecorder) ListProducts(ctx any) *gomock.Call {
+func (mr *MockProductServiceMockRecorder) ListProducts(ctx, filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListProducts", reflect.TypeOf((*MockProductService)(nil).ListProducts), ctx, filter)
+}
+
+// PatchProduct mocks base method.
+func (m *MockProductService) PatchProduct(ctx context.Context, id string, req *models.ProductPatchRequest) (*models.Product, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PatchProduct", ctx, id, req)
+	ret0, _ := ret[0].(*models.Product)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PatchProduct indicates an expected call of PatchProduct.
+func (mr *MockProductServiceMockRecorder) PatchProduct(ctx, id, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PatchProduct", reflect.TypeOf((*MockProductService)(nil).PatchProduct), ctx, id, req)
+}
+
+// ReplaceProduct mocks base method.
+func (m *MockProductService) ReplaceProduct(ctx context.Context, id string, req *models.ProductRequest) (*models.Product, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReplaceProduct", ctx, id, req)
+	ret0, _ := ret[0].(*models.Product)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReplaceProduct indicates an expected call of ReplaceProduct.
+func (mr *MockProductServiceMockRecorder) ReplaceProduct(ctx, id, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReplaceProduct", reflect.TypeOf((*MockProductService)(nil).ReplaceProduct), ctx, id, req)
+}
+
+// MockCategoryService is a mock of CategoryService interface.
+type MockCategoryService struct {
+	ctrl     *gomock.Controller
+	recorder *MockCategoryServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockCategoryServiceMockRecorder is the mock recorder for MockCategoryService.
+type MockCategoryServiceMockRecorder struct {
+	mock *MockCategoryService
+}
+
+// NewMockCategoryService creates a new mock instance.
+func NewMockCategoryService(ctrl *gomock.Controller) *MockCategoryService {
+	mock := &MockCategoryService{ctrl: ctrl}
+	mock.recorder = &MockCategoryServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCategoryService) EXPECT() *MockCategoryServiceMockRecorder {
+	return m.recorder
+}
+
+// ListCategories mocks base method.
+func (m *MockCategoryService) ListCategories(ctx context.Context) ([]models.CategoryResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListCategories", ctx)
+	ret0, _ := ret[0].([]models.CategoryResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListCategories indicates an expected call of ListCategories.
+func (mr *MockCategoryServiceMockRecorder) ListCategories(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCategories", reflect.TypeOf((*MockCategoryService)(nil).ListCategories), ctx)
+}
+
+// MockStockService is a mock of StockService interface.
+type MockStockService struct {
+	ctrl     *gomock.Controller
+	recorder *MockStockServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockStockServiceMockRecorder is the mock recorder for MockStockService.
+type MockStockServiceMockRecorder struct {
+	mock *MockStockService
+}
+
+// NewMockStockService creates a new mock instance.
+func NewMockStockService(ctrl *gomock.Controller) *MockStockService {
+	mock := &MockStockService{ctrl: ctrl}
+	mock.recorder = &MockStockServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockStockService) EXPECT() *MockStockServiceMockRecorder {
+	return m.recorder
+}
+
+// GetStock mocks base method.
+func (m *MockStockService) GetStock(ctx context.Context, productID string) (*models.Stock, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStock", ctx, productID)
+	ret0, _ := ret[0].(*models.Stock)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetStock indicates an expected call of GetStock.
+func (mr *MockStockServiceMockRecorder) GetStock(ctx, productID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStock", reflect.TypeOf((*MockStockService)(nil).GetStock), ctx, productID)
+}
+
+// Restock mocks base method.
+func (m *MockStockService) Restock(ctx context.Context, productID string, quantity int64) (*models.Stock, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Restock", ctx, productID, quantity)
+	ret0, _ := ret[0].(*models.Stock)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Restock indicates an expected call of Restock.
+func (mr *MockStockServiceMockRecorder) Restock(ctx, productID, quantity any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Restock", reflect.TypeOf((*MockStockService)(nil).Restock), ctx, productID, quantity)
+}
+
+// SetStock mocks base method.
+func (m *MockStockService) SetStock(ctx context.Context, productID string, quantity int64) (*models.Stock, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetStock", ctx, productID, quantity)
+	ret0, _ := ret[0].(*models.Stock)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetStock indicates an expected call of SetStock.
+func (mr *MockStockServiceMockRecorder) SetStock(ctx, productID, quantity any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetStock", reflect.TypeOf((*MockStockService)(nil).SetStock), ctx, productID, quantity)
+}
+
+// MockCouponService is a mock of CouponService interface.
+type MockCouponService struct {
+	ctrl     *gomock.Controller
+	recorder *MockCouponServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockCouponServiceMockRecorder is the mock recorder for MockCouponService.
+type MockCouponServiceMockRecorder struct {
+	mock *MockCouponService
+}
+
+// NewMockCouponService creates a new mock instance.
+func NewMockCouponService(ctrl *gomock.Controller) *MockCouponService {
+	mock := &MockCouponService{ctrl: ctrl}
+	mock.recorder = &MockCouponServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCouponService) EXPECT() *MockCouponServiceMockRecorder {
+	return m.recorder
+}
+
+// PreviewCoupon mocks base method.
+func (m *MockCouponService) PreviewCoupon(ctx context.Context, code, customerID string) (*models.CouponPreviewResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PreviewCoupon", ctx, code, customerID)
+	ret0, _ := ret[0].(*models.CouponPreviewResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PreviewCoupon indicates an expected call of PreviewCoupon.
+func (mr *MockCouponServiceMockRecorder) PreviewCoupon(ctx, code, customerID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PreviewCoupon", reflect.TypeOf((*MockCouponService)(nil).PreviewCoupon), ctx, code, customerID)
+}
+
+// MockCartService is a mock of CartService interface.
+type MockCartService struct {
+	ctrl     *gomock.Controller
+	recorder *MockCartServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockCartServiceMockRecorder is the mock recorder for MockCartService.
+type MockCartServiceMockRecorder struct {
+	mock *MockCartService
+}
+
+// NewMockCartService creates a new mock instance.
+func NewMockCartService(ctrl *gomock.Controller) *MockCartService {
+	mock := &MockCartService{ctrl: ctrl}
+	mock.recorder = &MockCartServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCartService) EXPECT() *MockCartServiceMockRecorder {
+	return m.recorder
+}
+
+// AddCartItem mocks base method.
+func (m *MockCartService) AddCartItem(ctx context.Context, id, customerID string, item models.CartItem) (*models.Cart, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddCartItem", ctx, id, customerID, item)
+	ret0, _ := ret[0].(*models.Cart)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddCartItem indicates an expected call of AddCartItem.
+func (mr *MockCartServiceMockRecorder) AddCartItem(ctx, id, customerID, item any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddCartItem", reflect.TypeOf((*MockCartService)(nil).AddCartItem), ctx, id, customerID, item)
+}
+
+// ApplyCartCoupon mocks base method.
+func (m *MockCartService) ApplyCartCoupon(ctx context.Context, id, customerID, code string) (*models.Cart, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ApplyCartCoupon", ctx, id, customerID, code)
+	ret0, _ := ret[0].(*models.Cart)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ApplyCartCoupon indicates an expected call of ApplyCartCoupon.
+func (mr *MockCartServiceMockRecorder) ApplyCartCoupon(ctx, id, customerID, code any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ApplyCartCoupon", reflect.TypeOf((*MockCartService)(nil).ApplyCartCoupon), ctx, id, customerID, code)
+}
+
+// CheckoutCart mocks base method.
+func (m *MockCartService) CheckoutCart(ctx context.Context, id, customerID string) (*models.Order, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CheckoutCart", ctx, id, customerID)
+	ret0, _ := ret[0].(*models.Order)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CheckoutCart indicates an expected call of CheckoutCart.
+func (mr *MockCartServiceMockRecorder) CheckoutCart(ctx, id, customerID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckoutCart", reflect.TypeOf((*MockCartService)(nil).CheckoutCart), ctx, id, customerID)
+}
+
+// CreateCart mocks base method.
+func (m *MockCartService) CreateCart(ctx context.Context, req *models.CartCreateRequest) (*models.Cart, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateCart", ctx, req)
+	ret0, _ := ret[0].(*models.Cart)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateCart indicates an expected call of CreateCart.
+func (mr *MockCartServiceMockRecorder) CreateCart(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateCart", reflect.TypeOf((*MockCartService)(nil).CreateCart), ctx, req)
+}
+
+// DeleteCart mocks base method.
+func (m *MockCartService) DeleteCart(ctx context.Context, id, customerID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteCart", ctx, id, customerID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteCart indicates an expected call of DeleteCart.
+func (mr *MockCartServiceMockRecorder) DeleteCart(ctx, id, customerID any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListProducts", reflect.TypeOf((*MockProductService)(nil).ListProducts), ctx)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteCart", reflect.TypeOf((*MockCartService)(nil).DeleteCart), ctx, id, customerID)
+}
+
+// GetCart mocks base method.
+func (m *MockCartService) GetCart(ctx context.Context, id, customerID string) (*models.Cart, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCart", ctx, id, customerID)
+	ret0, _ := ret[0].(*models.Cart)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCart indicates an expected call of GetCart.
+func (mr *MockCartServiceMockRecorder) GetCart(ctx, id, customerID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCart", reflect.TypeOf((*MockCartService)(nil).GetCart), ctx, id, customerID)
+}
+
+// RemoveCartCoupon mocks base method.
+func (m *MockCartService) RemoveCartCoupon(ctx context.Context, id, customerID string) (*models.Cart, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveCartCoupon", ctx, id, customerID)
+	ret0, _ := ret[0].(*models.Cart)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RemoveCartCoupon indicates an expected call of RemoveCartCoupon.
+func (mr *MockCartServiceMockRecorder) RemoveCartCoupon(ctx, id, customerID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveCartCoupon", reflect.TypeOf((*MockCartService)(nil).RemoveCartCoupon), ctx, id, customerID)
+}
+
+// RemoveCartItem mocks base method.
+func (m *MockCartService) RemoveCartItem(ctx context.Context, id, customerID, productID string) (*models.Cart, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveCartItem", ctx, id, customerID, productID)
+	ret0, _ := ret[0].(*models.Cart)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RemoveCartItem indicates an expected call of RemoveCartItem.
+func (mr *MockCartServiceMockRecorder) RemoveCartItem(ctx, id, customerID, productID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveCartItem", reflect.TypeOf((*MockCartService)(nil).RemoveCartItem), ctx, id, customerID, productID)
+}
+
+// UpdateCartItem mocks base method.
+func (m *MockCartService) UpdateCartItem(ctx context.Context, id, customerID, productID string, quantity int) (*models.Cart, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateCartItem", ctx, id, customerID, productID, quantity)
+	ret0, _ := ret[0].(*models.Cart)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateCartItem indicates an expected call of UpdateCartItem.
+func (mr *MockCartServiceMockRecorder) UpdateCartItem(ctx, id, customerID, productID, quantity any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateCartItem", reflect.TypeOf((*MockCartService)(nil).UpdateCartItem), ctx, id, customerID, productID, quantity)
 }
 
 // MockOrderService is a mock of OrderService interface.
@@ -95,6 +459,36 @@ func (m *MockOrderService) EXPECT() *MockOrderServiceMockRecorder {
 	return m.recorder
 }
 
+// GetOrder mocks base method.
+func (m *MockOrderService) GetOrder(ctx context.Context, id, customerID string) (*models.Order, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOrder", ctx, id, customerID)
+	ret0, _ := ret[0].(*models.Order)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOrder indicates an expected call of GetOrder.
+func (mr *MockOrderServiceMockRecorder) GetOrder(ctx, id, customerID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrder", reflect.TypeOf((*MockOrderService)(nil).GetOrder), ctx, id, customerID)
+}
+
+// ListOrders mocks base method.
+func (m *MockOrderService) ListOrders(ctx context.Context, filter models.OrderFilter) (*models.OrderListResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListOrders", ctx, filter)
+	ret0, _ := ret[0].(*models.OrderListResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListOrders indicates an expected call of ListOrders.
+func (mr *MockOrderServiceMockRecorder) ListOrders(ctx, filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListOrders", reflect.TypeOf((*MockOrderService)(nil).ListOrders), ctx, filter)
+}
+
 // PlaceOrder mocks base method.
 func (m *MockOrderService) PlaceOrder(ctx context.Context, req *models.OrderCreateRequest) (*models.Order, error) {
 	m.ctrl.T.Helper()
@@ -109,3 +503,18 @@ func (mr *MockOrderServiceMockRecorder) PlaceOrder(ctx, req any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PlaceOrder", reflect.TypeOf((*MockOrderService)(nil).PlaceOrder), ctx, req)
 }
+
+// UpdateOrderStatus mocks base method.
+func (m *MockOrderService) UpdateOrderStatus(ctx context.Context, id string, req *models.OrderStatusUpdateRequest) (*models.Order, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateOrderStatus", ctx, id, req)
+	ret0, _ := ret[0].(*models.Order)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateOrderStatus indicates an expected call of UpdateOrderStatus.
+func (mr *MockOrderServiceMockRecorder) UpdateOrderStatus(ctx, id, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateOrderStatus", reflect.TypeOf((*MockOrderService)(nil).UpdateOrderStatus), ctx, id, req)
+}
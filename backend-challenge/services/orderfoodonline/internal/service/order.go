@@ -3,89 +3,510 @@ package service
 import (
 	"context"
 	"errors"
+	"fmt"
 	"library/logger"
 	"orderfoodonline/internal/metrics"
 	"orderfoodonline/internal/repository"
 	"orderfoodonline/internal/repository/models"
+	"orderfoodonline/internal/tracing"
 	"strings"
 	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+const (
+	// DefaultCouponDiscountPercent is the percentage taken off the subtotal by coupons that match no coupon rule.
+	DefaultCouponDiscountPercent = 10
+	// DefaultOrderPageSize is the number of orders returned when no limit is given.
+	DefaultOrderPageSize = 20
+	// MaxOrderPageSize is the largest number of orders returned in a single page.
+	MaxOrderPageSize = 100
 )
 
+// orderTransitions lists, for every order status, the statuses it may move to.
+// Statuses without an entry are terminal.
+var orderTransitions = map[models.OrderStatus][]models.OrderStatus{
+	models.OrderStatusPlaced:    {models.OrderStatusConfirmed, models.OrderStatusCancelled},
+	models.OrderStatusConfirmed: {models.OrderStatusPreparing, models.OrderStatusCancelled},
+	models.OrderStatusPreparing: {models.OrderStatusReady, models.OrderStatusCancelled},
+	models.OrderStatusReady:     {models.OrderStatusDelivered},
+	models.OrderStatusDelivered: {models.OrderStatusRefunded},
+	models.OrderStatusCancelled: {models.OrderStatusRefunded},
+}
+
+// canTransition reports whether an order may move from one status to another.
+func canTransition(from, to models.OrderStatus) bool {
+	for _, next := range orderTransitions[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
 type orderService struct {
 	repo        repository.OrderRepository
 	productRepo repository.ProductRepository
 	couponRepo  repository.CouponRepository
+	stockRepo   repository.StockRepository
+	uow         repository.UnitOfWork
+	coupons     *couponPolicy
 	logger      logger.ILogger
 }
 
-// NewOrderService creates a new OrderService.
+// NewOrderService creates a new OrderService. Orders are placed within a transaction of uow.
+// Coupons are discounted and redeemed subject to the limits of the rules of ruleRepo;
+// a nil ruleRepo gives every coupon the default rule, which sets no limits.
 func NewOrderService(repo repository.OrderRepository, productRepo repository.ProductRepository,
-	couponRepo repository.CouponRepository, logger logger.ILogger) OrderService {
-	return &orderService{repo: repo, productRepo: productRepo, couponRepo: couponRepo, logger: logger}
+	couponRepo repository.CouponRepository, ruleRepo repository.CouponRuleRepository,
+	stockRepo repository.StockRepository, uow repository.UnitOfWork, logger logger.ILogger) OrderService {
+	return &orderService{repo: repo, productRepo: productRepo, couponRepo: couponRepo, stockRepo: stockRepo,
+		uow: uow, coupons: newCouponPolicy(couponRepo, ruleRepo, logger), logger: logger}
 }
 
 // PlaceOrder creates a new order based on the given request.
 // It validates the input, applies business logic, and persists the order.
+// Repeated product IDs are merged into one item and all products are fetched in a single query.
+// Reading the products, reserving stock, saving the order and redeeming its coupon run as one
+// transaction, so either all of them take effect or none does.
 // Returns the created order or an error if the operation fails.
-func (s *orderService) PlaceOrder(ctx context.Context, req *models.OrderCreateRequest) (*models.Order, error) {
+func (s *orderService) PlaceOrder(ctx context.Context, req *models.OrderCreateRequest) (_ *models.Order, err error) {
+	ctx, span := tracing.Start(ctx, "OrderService.PlaceOrder")
+	defer func() { tracing.End(span, err) }()
+	span.SetAttributes(
+		attribute.Int("order.item_lines", len(req.Items)),
+		attribute.Bool("order.coupon", strings.TrimSpace(req.CouponCode) != ""),
+	)
 	start := time.Now()
 
+	var couponCode string
+	var rule *models.CouponRule
+	customerID := strings.TrimSpace(req.CustomerID)
+
 	// Validate coupon code if provided
 	if strings.TrimSpace(req.CouponCode) != "" {
-		// Rule 1: Check length between 8 and 10 characters
-		if len(strings.TrimSpace(req.CouponCode)) < 8 || len(strings.TrimSpace(req.CouponCode)) > 10 {
+		// Rule 1: The code must be 8 to 10 characters long and found in at least two coupon files
+		couponCode = strings.TrimSpace(req.CouponCode)
+		outcome, err := validateCouponCode(ctx, s.couponRepo, couponCode)
+		metrics.RecordCouponValidation(outcome)
+		switch outcome {
+		case metrics.CouponError:
+			metrics.RecordOrderProcessing("coupon_validation_error", time.Since(start).Seconds())
+			metrics.RecordOrder("coupon_validation_error")
+			return nil, err
+		case metrics.CouponTooShort, metrics.CouponTooLong:
 			metrics.RecordOrderProcessing("validation_error", time.Since(start).Seconds())
 			metrics.RecordOrder("validation_error")
 			return nil, errors.New(InvalidPromoCode)
+		case metrics.CouponInvalid:
+			metrics.RecordOrderProcessing("invalid_coupon", time.Since(start).Seconds())
+			metrics.RecordOrder("invalid_coupon")
+			return nil, errors.New(InvalidPromoCode)
 		}
-		isValid, err := s.couponRepo.ValidateCouponCode(ctx, req.CouponCode)
+
+		// Rule 2: The coupon's family decides the discount and the redemption limits
+		matched, err := s.coupons.rule(ctx, couponCode)
 		if err != nil {
+			s.logger.WithContext(ctx).Error("%s: %v", CheckCouponError, err)
 			metrics.RecordOrderProcessing("coupon_validation_error", time.Since(start).Seconds())
 			metrics.RecordOrder("coupon_validation_error")
-			return nil, err
+			return nil, errors.New(CheckCouponError)
 		}
-		if !isValid {
-			metrics.RecordOrderProcessing("invalid_coupon", time.Since(start).Seconds())
-			metrics.RecordOrder("invalid_coupon")
-			return nil, errors.New(InvalidPromoCode)
+
+		// Rule 3: The coupon must not be expired or used up, for everyone or for this customer
+		status, err := s.coupons.check(ctx, matched, couponCode, customerID)
+		if err != nil {
+			s.logger.WithContext(ctx).Error("%s: %v", CheckCouponError, err)
+			metrics.RecordOrderProcessing("coupon_validation_error", time.Since(start).Seconds())
+			metrics.RecordOrder("coupon_validation_error")
+			return nil, errors.New(CheckCouponError)
+		}
+		if status.reason != "" {
+			metrics.RecordOrderProcessing("coupon_rejected", time.Since(start).Seconds())
+			metrics.RecordOrder("coupon_rejected")
+			return nil, &CouponRejectedError{Reason: status.reason}
+		}
+		rule = &matched
+	}
+
+	items, err := mergeOrderItems(req.Items)
+	if err != nil {
+		metrics.RecordOrderProcessing("invalid_product_or_quantity", time.Since(start).Seconds())
+		metrics.RecordOrder("invalid_product_or_quantity")
+		return nil, err
+	}
+
+	var result *models.Order
+	status := "success"
+	defer func() { span.SetAttributes(attribute.String("order.outcome", status)) }()
+	err = s.uow.WithinTransaction(ctx, func(ctx context.Context) error {
+		order := &models.Order{
+			Items:      items,
+			CouponCode: couponCode,
+			CustomerID: customerID,
+			Status:     models.OrderStatusPlaced,
+			History:    []models.OrderStatusChange{{To: models.OrderStatusPlaced, At: time.Now().Unix()}},
+		}
+		var err error
+		result, status, err = s.placeOrder(ctx, order, rule)
+		return err
+	})
+	if err != nil && status == "success" {
+		// The transaction failed to commit
+		status = "database_error"
+	}
+	metrics.RecordOrderProcessing(status, time.Since(start).Seconds())
+	metrics.RecordOrder(status)
+	if err != nil {
+		// Stock-outs are recorded once the transaction has settled, not on every attempt of it
+		var stockErr *OutOfStockError
+		if errors.As(err, &stockErr) {
+			s.logger.WithContext(ctx).Warn("order rejected, out of stock: %s", strings.Join(stockErr.ProductIDs, ", "))
+			metrics.RecordStockOuts(len(stockErr.ProductIDs))
 		}
+		return nil, err
+	}
+	recordPlacedOrder(result)
+	span.SetAttributes(attribute.String("order.id", result.ID))
+	return result, nil
+}
+
+// recordPlacedOrder records the value, basket, discount and products of a placed order in the business metrics.
+func recordPlacedOrder(order *models.Order) {
+	items := 0
+	for _, item := range order.Items {
+		items += item.Quantity
+		metrics.RecordProductOrdered(item.ProductID, item.Quantity)
 	}
+	var discountType string
+	if order.Promotion != nil {
+		discountType = string(order.Promotion.Type)
+	}
+	metrics.RecordPlacedOrder(order.Total.Float64(), items, order.Discount.Float64(), discountType)
+}
 
-	var products []models.Product
-	for _, item := range req.Items {
+// placeOrder prices the order's merged items against the current catalog, reserves their stock,
+// saves the order and redeems its coupon under the given rule, which is nil if the order has no coupon.
+// It must run inside a transaction so that a failure part way through leaves neither stock reserved,
+// an order nor a redemption behind. Besides the saved order, it returns the status label under which
+// the attempt is recorded in the order metrics.
+func (s *orderService) placeOrder(ctx context.Context, order *models.Order, rule *models.CouponRule) (_ *models.Order, status string, err error) {
+	ctx, span := tracing.Start(ctx, "OrderService.placeOrder")
+	defer func() {
+		span.SetAttributes(attribute.String("order.outcome", status))
+		tracing.End(span, err)
+	}()
+	items := order.Items
+	ids := make([]string, 0, len(items))
+	for _, item := range items {
+		ids = append(ids, item.ProductID)
+	}
+	found, err := s.productRepo.FindProductsByIDs(ctx, ids)
+	if err != nil {
+		s.logger.WithContext(ctx).Error("%s: %v", FindProductByIDError, err)
+		return nil, "product_lookup_error", errors.New(FindProductByIDError)
+	}
+	byID := make(map[string]models.Product, len(found))
+	for _, prod := range found {
+		byID[prod.ID] = prod
+	}
+
+	products := make([]models.Product, 0, len(items))
+	var staleLines []models.StaleOrderLine
+	for _, item := range items {
+		prod, ok := byID[item.ProductID]
+		if !ok {
+			return nil, "product_not_found", errors.New(ProductNotFound + item.ProductID)
+		}
+		if !prod.Available {
+			return nil, "product_unavailable", errors.New(ProductUnavailable + item.ProductID)
+		}
+		if stale, ok := staleLine(item, &prod); ok {
+			staleLines = append(staleLines, stale)
+		}
+		products = append(products, prod)
+	}
+	if len(staleLines) > 0 {
+		return nil, "stale_cart", &StaleCartError{Lines: staleLines}
+	}
+
+	order.Products = products
+	var policy DiscountPolicy
+	if rule != nil {
+		policy = ruleDiscount{rule: *rule}
+	}
+	if reason := priceOrder(order, policy); reason != "" {
+		return nil, "coupon_rejected", &CouponRejectedError{Reason: reason}
+	}
+
+	if err := s.reserveStock(ctx, items); err != nil {
+		var stockErr *OutOfStockError
+		if errors.As(err, &stockErr) {
+			return nil, "out_of_stock", err
+		}
+		return nil, "stock_error", err
+	}
+
+	result, err := s.repo.PlaceOrder(ctx, order)
+	if err != nil {
+		return nil, "database_error", err
+	}
+
+	if result.CouponCode != "" && rule != nil {
+		if err := s.coupons.redeem(ctx, result, *rule); err != nil {
+			var rejected *CouponRejectedError
+			if errors.As(err, &rejected) {
+				return nil, "coupon_rejected", err
+			}
+			s.logger.WithContext(ctx).Error("%s: %v", RedeemCouponError, err)
+			return nil, "coupon_redemption_error", fmt.Errorf("%s: %w", RedeemCouponError, err)
+		}
+	}
+	return result, "success", nil
+}
+
+// GetOrder fetches a single order by its unique ID.
+// Returns nil if the order does not exist or, when customerID is set, was placed by another customer.
+func (s *orderService) GetOrder(ctx context.Context, id, customerID string) (_ *models.Order, err error) {
+	ctx, span := tracing.Start(ctx, "OrderService.GetOrder")
+	defer func() { tracing.End(span, err) }()
+	span.SetAttributes(attribute.String("order.id", id))
+	order, err := s.repo.FindOrderByID(ctx, id)
+	if err != nil {
+		s.logger.WithContext(ctx).Error("%s: %v", FindOrderByIDError, err)
+		return nil, errors.New(FindOrderByIDError)
+	}
+	if order != nil && customerID != "" && order.CustomerID != customerID {
+		return nil, nil
+	}
+	return order, nil
+}
+
+// ListOrders returns a page of orders matching the filter, newest first.
+// A zero limit falls back to DefaultOrderPageSize and larger limits are capped at MaxOrderPageSize.
+func (s *orderService) ListOrders(ctx context.Context, filter models.OrderFilter) (_ *models.OrderListResponse, err error) {
+	ctx, span := tracing.Start(ctx, "OrderService.ListOrders")
+	defer func() { tracing.End(span, err) }()
+	if filter.Limit < 0 || filter.Offset < 0 || filter.From < 0 || filter.To < 0 {
+		return nil, errors.New(InvalidOrderFilter)
+	}
+	if filter.From > 0 && filter.To > 0 && filter.From > filter.To {
+		return nil, errors.New(InvalidOrderFilter)
+	}
+	if filter.Status != "" && !filter.Status.IsValid() {
+		return nil, errors.New(InvalidOrderFilter)
+	}
+	if filter.Limit == 0 {
+		filter.Limit = DefaultOrderPageSize
+	}
+	if filter.Limit > MaxOrderPageSize {
+		filter.Limit = MaxOrderPageSize
+	}
+
+	orders, total, err := s.repo.ListOrders(ctx, filter)
+	if err != nil {
+		s.logger.WithContext(ctx).Error("%s: %v", OrderListingError, err)
+		return nil, errors.New(OrderListingError)
+	}
+
+	return &models.OrderListResponse{
+		Orders: orders,
+		Total:  total,
+		Limit:  filter.Limit,
+		Offset: filter.Offset,
+	}, nil
+}
+
+// UpdateOrderStatus moves an order to the requested status if the transition table allows it.
+// The change is applied only if the order is still in the status it was read in, so concurrent
+// updates cannot skip a state; a lost race is reported as an invalid transition.
+// Cancelling an order puts its units back in stock in the same transaction as the status change.
+func (s *orderService) UpdateOrderStatus(ctx context.Context, id string, req *models.OrderStatusUpdateRequest) (_ *models.Order, err error) {
+	ctx, span := tracing.Start(ctx, "OrderService.UpdateOrderStatus")
+	defer func() { tracing.End(span, err) }()
+	span.SetAttributes(attribute.String("order.id", id), attribute.String("order.status", string(req.Status)))
+	if !req.Status.IsValid() {
+		return nil, errors.New(InvalidOrderStatus)
+	}
+
+	order, err := s.repo.FindOrderByID(ctx, id)
+	if err != nil {
+		s.logger.WithContext(ctx).Error("%s: %v", FindOrderByIDError, err)
+		return nil, errors.New(UpdateOrderStatusError)
+	}
+	if order == nil {
+		return nil, errors.New(OrderNotFound)
+	}
+	if !canTransition(order.Status, req.Status) {
+		return nil, errors.New(InvalidStatusTransition)
+	}
+
+	change := models.OrderStatusChange{
+		From:   order.Status,
+		To:     req.Status,
+		At:     time.Now().Unix(),
+		Reason: strings.TrimSpace(req.Reason),
+	}
+	var updated *models.Order
+	err = s.uow.WithinTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		updated, err = s.repo.UpdateOrderStatus(ctx, id, order.Status, change)
+		if err != nil || updated == nil || change.To != models.OrderStatusCancelled {
+			return err
+		}
+		return s.releaseStock(ctx, updated.Items)
+	})
+	if err != nil {
+		s.logger.WithContext(ctx).Error("%s: %v", UpdateOrderStatusError, err)
+		return nil, errors.New(UpdateOrderStatusError)
+	}
+	if updated == nil {
+		return nil, errors.New(InvalidStatusTransition)
+	}
+
+	metrics.RecordOrderTransition(string(change.From), string(change.To))
+	return updated, nil
+}
+
+// mergeOrderItems validates the requested items and merges repeated product IDs into a
+// single item whose quantity is the sum of the repeats, keeping first-seen order. Repeats
+// that disagree on the expected price or version are rejected as invalid.
+func mergeOrderItems(items []models.OrderItem) ([]models.OrderItem, error) {
+	merged := make([]models.OrderItem, 0, len(items))
+	index := make(map[string]int, len(items))
+	for _, item := range items {
 		if item.ProductID == "" || item.Quantity <= 0 {
-			metrics.RecordOrderProcessing("invalid_product_or_quantity", time.Since(start).Seconds())
-			metrics.RecordOrder("invalid_product_or_quantity")
 			return nil, errors.New(InvalidProductOrQuantity)
 		}
-		prod, err := s.productRepo.FindProductByID(ctx, item.ProductID)
+		i, seen := index[item.ProductID]
+		if !seen {
+			index[item.ProductID] = len(merged)
+			merged = append(merged, item)
+			continue
+		}
+		existing := &merged[i]
+		if item.ExpectedPrice != nil {
+			if existing.ExpectedPrice != nil && *existing.ExpectedPrice != *item.ExpectedPrice {
+				return nil, errors.New(InvalidProductOrQuantity)
+			}
+			existing.ExpectedPrice = item.ExpectedPrice
+		}
+		if item.ExpectedVersion != nil {
+			if existing.ExpectedVersion != nil && *existing.ExpectedVersion != *item.ExpectedVersion {
+				return nil, errors.New(InvalidProductOrQuantity)
+			}
+			existing.ExpectedVersion = item.ExpectedVersion
+		}
+		existing.Quantity += item.Quantity
+	}
+	return merged, nil
+}
+
+// reserveStock takes the ordered quantities out of stock. Every item is tried so that all
+// products short of stock can be reported in an OutOfStockError. Units already reserved are
+// not put back here: the caller's transaction is aborted and discards them. Stock-outs are
+// not recorded in the metrics here either, as the transaction may be retried.
+// Database errors are wrapped rather than replaced so the transaction can still tell
+// whether they are transient and worth retrying.
+func (s *orderService) reserveStock(ctx context.Context, items []models.OrderItem) error {
+	var outOfStock []string
+	for _, item := range items {
+		ok, err := s.stockRepo.ReserveStock(ctx, item.ProductID, item.Quantity)
+		if err != nil {
+			s.logger.WithContext(ctx).Error("%s: %v", ReserveStockError, err)
+			return fmt.Errorf("%s: %w", ReserveStockError, err)
+		}
+		if !ok {
+			outOfStock = append(outOfStock, item.ProductID)
+		}
+	}
+	if len(outOfStock) > 0 {
+		return &OutOfStockError{ProductIDs: outOfStock}
+	}
+	return nil
+}
+
+// releaseStock puts the units of a cancelled order back in stock. Products whose stock is not
+// tracked are skipped, since no units were reserved for them and adding some would start
+// tracking them. Database errors are wrapped so the transaction can still tell whether they
+// are transient and worth retrying.
+func (s *orderService) releaseStock(ctx context.Context, items []models.OrderItem) error {
+	for _, item := range items {
+		stock, err := s.stockRepo.FindStockByProductID(ctx, item.ProductID)
 		if err != nil {
-			s.logger.Error("%s: %v", FindProductByIDError, err)
-			metrics.RecordOrderProcessing("product_lookup_error", time.Since(start).Seconds())
-			metrics.RecordOrder("product_lookup_error")
-			return nil, errors.New(FindProductByIDError)
+			return fmt.Errorf("%s: %w", ReleaseStockError, err)
 		}
-		if prod == nil {
-			metrics.RecordOrderProcessing("product_not_found", time.Since(start).Seconds())
-			metrics.RecordOrder("product_not_found")
-			return nil, errors.New(ProductNotFound + item.ProductID)
+		if stock == nil {
+			continue
+		}
+		if _, err := s.stockRepo.AddStock(ctx, item.ProductID, int64(item.Quantity)); err != nil {
+			return fmt.Errorf("%s: %w", ReleaseStockError, err)
 		}
-		products = append(products, *prod)
 	}
+	return nil
+}
 
-	order := &models.Order{
-		Items:    req.Items,
-		Products: products,
+// staleLine compares the price and version the client expected for an item with the
+// current product, reporting the difference when either no longer matches.
+func staleLine(item models.OrderItem, prod *models.Product) (models.StaleOrderLine, bool) {
+	currentPrice := models.MoneyFromFloat(prod.Price)
+	priceChanged := item.ExpectedPrice != nil && *item.ExpectedPrice != currentPrice
+	versionChanged := item.ExpectedVersion != nil && *item.ExpectedVersion != prod.Version
+	if !priceChanged && !versionChanged {
+		return models.StaleOrderLine{}, false
 	}
+	return models.StaleOrderLine{
+		ProductID:       item.ProductID,
+		ExpectedPrice:   item.ExpectedPrice,
+		CurrentPrice:    currentPrice,
+		ExpectedVersion: item.ExpectedVersion,
+		CurrentVersion:  prod.Version,
+	}, true
+}
 
-	result, err := s.repo.PlaceOrder(ctx, order)
-	if err != nil {
-		metrics.RecordOrderProcessing("database_error", time.Since(start).Seconds())
-		metrics.RecordOrder("database_error")
-		return nil, err
+// priceOrder snapshots each product into an order line and computes the line totals,
+// subtotal, discount and total of the order. Products must be in the same order as Items.
+// All arithmetic is done in cents.
+// A nil policy grants no discount. If the policy does not apply to the order, the
+// models.CouponReason value explaining why is returned and the order is left unpriced.
+func priceOrder(order *models.Order, policy DiscountPolicy) string {
+	lines := make([]models.OrderLine, 0, len(order.Items))
+	var subtotal models.Money
+	for i, item := range order.Items {
+		unitPrice := models.MoneyFromFloat(order.Products[i].Price)
+		lineTotal := unitPrice.Mul(item.Quantity)
+		lines = append(lines, models.OrderLine{
+			ProductID:      item.ProductID,
+			Name:           order.Products[i].Name,
+			Category:       order.Products[i].Category,
+			CategoryID:     order.Products[i].CategoryID,
+			ProductVersion: order.Products[i].Version,
+			Quantity:       item.Quantity,
+			UnitPrice:      unitPrice,
+			LineTotal:      lineTotal,
+		})
+		subtotal += lineTotal
 	}
 
-	metrics.RecordOrderProcessing("success", time.Since(start).Seconds())
-	metrics.RecordOrder("success")
-	return result, nil
+	var promotion *models.AppliedDiscount
+	var discount models.Money
+	if policy != nil {
+		var reason string
+		promotion, reason = policy.Apply(lines, subtotal)
+		if reason != "" {
+			return reason
+		}
+		discount = promotion.Amount
+	}
+	if discount > subtotal {
+		discount = subtotal
+	}
+
+	order.Lines = lines
+	order.Subtotal = subtotal
+	order.Discount = discount
+	order.Promotion = promotion
+	order.Total = subtotal - discount
+	return ""
 }
@@ -2,16 +2,31 @@ package service
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"strconv"
 	"testing"
+	"time"
 
+	"orderfoodonline/internal/metrics"
 	"orderfoodonline/internal/repository/models"
+	"orderfoodonline/internal/tracing"
 
 	libmocks "library/logger/mocks"
+	"orderfoodonline/internal/repository"
 	"orderfoodonline/internal/repository/mocks"
 
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	dto "github.com/prometheus/client_model/go"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/mock/gomock"
 )
 
@@ -25,7 +40,7 @@ func TestNewOrderService(t *testing.T) {
 	mockLogger := libmocks.NewMockILogger(ctrl)
 
 	// When: Creating a new order service
-	service := NewOrderService(mockOrderRepo, mockProductRepo, mockCouponRepo, mockLogger)
+	service := NewOrderService(mockOrderRepo, mockProductRepo, mockCouponRepo, nil, untrackedStock(ctrl), &fakeUnitOfWork{}, mockLogger)
 
 	// Then: Service should be created successfully
 	assert.NotNil(t, service)
@@ -43,7 +58,7 @@ func TestOrderService_PlaceOrder_Success(t *testing.T) {
 	mockCouponRepo := mocks.NewMockCouponRepository(ctrl)
 	mockLogger := libmocks.NewMockILogger(ctrl)
 
-	service := NewOrderService(mockOrderRepo, mockProductRepo, mockCouponRepo, mockLogger)
+	service := NewOrderService(mockOrderRepo, mockProductRepo, mockCouponRepo, nil, untrackedStock(ctrl), &fakeUnitOfWork{}, mockLogger)
 
 	request := &models.OrderCreateRequest{
 		Items: []models.OrderItem{
@@ -52,8 +67,8 @@ func TestOrderService_PlaceOrder_Success(t *testing.T) {
 		},
 	}
 
-	wafflePrd := models.Product{ID: "1", Name: "Chicken Waffle", Price: 12.99, Category: "Waffle"}
-	burgerPrd := models.Product{ID: "2", Name: "Beef Burger", Price: 15.50, Category: "Burger"}
+	wafflePrd := models.Product{ID: "1", Name: "Chicken Waffle", Price: 12.99, Category: "Waffle", Available: true}
+	burgerPrd := models.Product{ID: "2", Name: "Beef Burger", Price: 15.50, Category: "Burger", Available: true}
 	expectedProducts := []models.Product{
 		wafflePrd,
 		burgerPrd,
@@ -68,9 +83,8 @@ func TestOrderService_PlaceOrder_Success(t *testing.T) {
 	ctx := context.Background()
 
 	// Mock repository behaviors
-	mockProductRepo.EXPECT().FindProductByID(ctx, "1").Return(&wafflePrd, nil)
-	mockProductRepo.EXPECT().FindProductByID(ctx, "2").Return(&burgerPrd, nil)
-	mockOrderRepo.EXPECT().PlaceOrder(ctx, gomock.Any()).Return(expectedOrder, nil)
+	mockProductRepo.EXPECT().FindProductsByIDs(gomock.Any(), []string{"1", "2"}).Return([]models.Product{wafflePrd, burgerPrd}, nil)
+	mockOrderRepo.EXPECT().PlaceOrder(gomock.Any(), gomock.Any()).Return(expectedOrder, nil)
 
 	// When: Placing an order
 	order, err := service.PlaceOrder(ctx, request)
@@ -97,7 +111,7 @@ func TestOrderService_PlaceOrder_WithValidCoupon(t *testing.T) {
 	mockCouponRepo := mocks.NewMockCouponRepository(ctrl)
 	mockLogger := libmocks.NewMockILogger(ctrl)
 
-	service := NewOrderService(mockOrderRepo, mockProductRepo, mockCouponRepo, mockLogger)
+	service := NewOrderService(mockOrderRepo, mockProductRepo, mockCouponRepo, nil, untrackedStock(ctrl), &fakeUnitOfWork{}, mockLogger)
 
 	request := &models.OrderCreateRequest{
 		CouponCode: "SAVE20OFF",
@@ -107,7 +121,7 @@ func TestOrderService_PlaceOrder_WithValidCoupon(t *testing.T) {
 	}
 
 	expectedProduct := &models.Product{
-		ID: "1", Name: "Chicken Waffle", Price: 12.99, Category: "Waffle",
+		ID: "1", Name: "Chicken Waffle", Price: 12.99, Category: "Waffle", Available: true,
 	}
 
 	expectedOrder := &models.Order{
@@ -119,9 +133,9 @@ func TestOrderService_PlaceOrder_WithValidCoupon(t *testing.T) {
 	ctx := context.Background()
 
 	// Mock repository behaviors
-	mockCouponRepo.EXPECT().ValidateCouponCode(ctx, "SAVE20OFF").Return(true, nil)
-	mockProductRepo.EXPECT().FindProductByID(ctx, "1").Return(expectedProduct, nil)
-	mockOrderRepo.EXPECT().PlaceOrder(ctx, gomock.Any()).Return(expectedOrder, nil)
+	mockCouponRepo.EXPECT().ValidateCouponCode(gomock.Any(), "SAVE20OFF").Return(true, nil)
+	mockProductRepo.EXPECT().FindProductsByIDs(gomock.Any(), []string{"1"}).Return([]models.Product{*expectedProduct}, nil)
+	mockOrderRepo.EXPECT().PlaceOrder(gomock.Any(), gomock.Any()).Return(expectedOrder, nil)
 
 	// When: Placing an order with valid coupon
 	order, err := service.PlaceOrder(ctx, request)
@@ -143,7 +157,7 @@ func TestOrderService_PlaceOrder_WithInvalidCouponLength(t *testing.T) {
 	mockCouponRepo := mocks.NewMockCouponRepository(ctrl)
 	mockLogger := libmocks.NewMockILogger(ctrl)
 
-	service := NewOrderService(mockOrderRepo, mockProductRepo, mockCouponRepo, mockLogger)
+	service := NewOrderService(mockOrderRepo, mockProductRepo, mockCouponRepo, nil, untrackedStock(ctrl), &fakeUnitOfWork{}, mockLogger)
 
 	// Test coupon code too short
 	request := &models.OrderCreateRequest{
@@ -184,7 +198,7 @@ func TestOrderService_PlaceOrder_WithInvalidCouponValidation(t *testing.T) {
 	mockCouponRepo := mocks.NewMockCouponRepository(ctrl)
 	mockLogger := libmocks.NewMockILogger(ctrl)
 
-	service := NewOrderService(mockOrderRepo, mockProductRepo, mockCouponRepo, mockLogger)
+	service := NewOrderService(mockOrderRepo, mockProductRepo, mockCouponRepo, nil, untrackedStock(ctrl), &fakeUnitOfWork{}, mockLogger)
 
 	request := &models.OrderCreateRequest{
 		CouponCode: "INVALID20",
@@ -196,7 +210,7 @@ func TestOrderService_PlaceOrder_WithInvalidCouponValidation(t *testing.T) {
 	ctx := context.Background()
 
 	// Mock repository behavior for invalid coupon
-	mockCouponRepo.EXPECT().ValidateCouponCode(ctx, "INVALID20").Return(false, nil)
+	mockCouponRepo.EXPECT().ValidateCouponCode(gomock.Any(), "INVALID20").Return(false, nil)
 
 	// When: Placing an order with invalid coupon
 	order, err := service.PlaceOrder(ctx, request)
@@ -218,7 +232,7 @@ func TestOrderService_PlaceOrder_WithCouponValidationError(t *testing.T) {
 	mockCouponRepo := mocks.NewMockCouponRepository(ctrl)
 	mockLogger := libmocks.NewMockILogger(ctrl)
 
-	service := NewOrderService(mockOrderRepo, mockProductRepo, mockCouponRepo, mockLogger)
+	service := NewOrderService(mockOrderRepo, mockProductRepo, mockCouponRepo, nil, untrackedStock(ctrl), &fakeUnitOfWork{}, mockLogger)
 
 	request := &models.OrderCreateRequest{
 		CouponCode: "SAVE20OFF",
@@ -231,7 +245,7 @@ func TestOrderService_PlaceOrder_WithCouponValidationError(t *testing.T) {
 	ctx := context.Background()
 
 	// Mock repository behavior for coupon validation error
-	mockCouponRepo.EXPECT().ValidateCouponCode(ctx, "SAVE20OFF").Return(false, expectedError)
+	mockCouponRepo.EXPECT().ValidateCouponCode(gomock.Any(), "SAVE20OFF").Return(false, expectedError)
 
 	// When: Placing an order with coupon validation error
 	order, err := service.PlaceOrder(ctx, request)
@@ -254,7 +268,7 @@ func TestOrderService_PlaceOrder_WithEmptyProductID(t *testing.T) {
 	mockCouponRepo := mocks.NewMockCouponRepository(ctrl)
 	mockLogger := libmocks.NewMockILogger(ctrl)
 
-	service := NewOrderService(mockOrderRepo, mockProductRepo, mockCouponRepo, mockLogger)
+	service := NewOrderService(mockOrderRepo, mockProductRepo, mockCouponRepo, nil, untrackedStock(ctrl), &fakeUnitOfWork{}, mockLogger)
 
 	request := &models.OrderCreateRequest{
 		Items: []models.OrderItem{
@@ -284,7 +298,7 @@ func TestOrderService_PlaceOrder_WithInvalidQuantity(t *testing.T) {
 	mockCouponRepo := mocks.NewMockCouponRepository(ctrl)
 	mockLogger := libmocks.NewMockILogger(ctrl)
 
-	service := NewOrderService(mockOrderRepo, mockProductRepo, mockCouponRepo, mockLogger)
+	service := NewOrderService(mockOrderRepo, mockProductRepo, mockCouponRepo, nil, untrackedStock(ctrl), &fakeUnitOfWork{}, mockLogger)
 
 	request := &models.OrderCreateRequest{
 		Items: []models.OrderItem{
@@ -314,7 +328,7 @@ func TestOrderService_PlaceOrder_WithNegativeQuantity(t *testing.T) {
 	mockCouponRepo := mocks.NewMockCouponRepository(ctrl)
 	mockLogger := libmocks.NewMockILogger(ctrl)
 
-	service := NewOrderService(mockOrderRepo, mockProductRepo, mockCouponRepo, mockLogger)
+	service := NewOrderService(mockOrderRepo, mockProductRepo, mockCouponRepo, nil, untrackedStock(ctrl), &fakeUnitOfWork{}, mockLogger)
 
 	request := &models.OrderCreateRequest{
 		Items: []models.OrderItem{
@@ -344,7 +358,7 @@ func TestOrderService_PlaceOrder_WithProductNotFound(t *testing.T) {
 	mockCouponRepo := mocks.NewMockCouponRepository(ctrl)
 	mockLogger := libmocks.NewMockILogger(ctrl)
 
-	service := NewOrderService(mockOrderRepo, mockProductRepo, mockCouponRepo, mockLogger)
+	service := NewOrderService(mockOrderRepo, mockProductRepo, mockCouponRepo, nil, untrackedStock(ctrl), &fakeUnitOfWork{}, mockLogger)
 
 	request := &models.OrderCreateRequest{
 		Items: []models.OrderItem{
@@ -355,8 +369,7 @@ func TestOrderService_PlaceOrder_WithProductNotFound(t *testing.T) {
 	ctx := context.Background()
 
 	// Mock repository behavior for non-existent product
-	mockLogger.EXPECT().Error(gomock.Any(), gomock.Any())
-	mockProductRepo.EXPECT().FindProductByID(ctx, "999").Return(nil, errors.New("product not found"))
+	mockProductRepo.EXPECT().FindProductsByIDs(gomock.Any(), []string{"999"}).Return([]models.Product{}, nil)
 
 	// When: Placing an order with non-existent product
 	order, err := service.PlaceOrder(ctx, request)
@@ -364,7 +377,335 @@ func TestOrderService_PlaceOrder_WithProductNotFound(t *testing.T) {
 	// Then: Should return error for product not found
 	require.Error(t, err)
 	assert.Nil(t, order)
-	assert.Equal(t, FindProductByIDError, err.Error())
+	assert.Equal(t, ProductNotFound+"999", err.Error())
+}
+
+func TestOrderService_PlaceOrder_WithUnavailableProduct(t *testing.T) {
+	// Given: An order containing a product that is temporarily unavailable
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockOrderRepo := mocks.NewMockOrderRepository(ctrl)
+	mockProductRepo := mocks.NewMockProductRepository(ctrl)
+	service := NewOrderService(mockOrderRepo, mockProductRepo, mocks.NewMockCouponRepository(ctrl), nil, untrackedStock(ctrl), &fakeUnitOfWork{}, libmocks.NewMockILogger(ctrl))
+
+	request := &models.OrderCreateRequest{
+		Items: []models.OrderItem{
+			{ProductID: "1", Quantity: 1},
+			{ProductID: "2", Quantity: 1},
+		},
+	}
+	ctx := context.Background()
+
+	mockProductRepo.EXPECT().FindProductsByIDs(gomock.Any(), []string{"1", "2"}).Return([]models.Product{
+		{ID: "1", Name: "Chicken Waffle", Price: 12.99, Available: true},
+		{ID: "2", Name: "Beef Burger", Price: 15.50},
+	}, nil)
+
+	// When: Placing the order
+	order, err := service.PlaceOrder(ctx, request)
+
+	// Then: The unavailable product should be reported and nothing saved
+	require.Error(t, err)
+	assert.Nil(t, order)
+	assert.Equal(t, ProductUnavailable+"2", err.Error())
+}
+
+func TestOrderService_PlaceOrder_OutOfStock(t *testing.T) {
+	// Given: An order for three products, two of which do not have enough stock
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockOrderRepo := mocks.NewMockOrderRepository(ctrl)
+	mockProductRepo := mocks.NewMockProductRepository(ctrl)
+	mockStockRepo := mocks.NewMockStockRepository(ctrl)
+	mockLogger := libmocks.NewMockILogger(ctrl)
+	mockLogger.EXPECT().WithContext(gomock.Any()).Return(mockLogger)
+	mockLogger.EXPECT().Warn(gomock.Any(), "2, 3")
+	uow := &fakeUnitOfWork{}
+	service := NewOrderService(mockOrderRepo, mockProductRepo, mocks.NewMockCouponRepository(ctrl), nil, mockStockRepo, uow, mockLogger)
+
+	request := &models.OrderCreateRequest{
+		Items: []models.OrderItem{
+			{ProductID: "1", Quantity: 2},
+			{ProductID: "2", Quantity: 5},
+			{ProductID: "3", Quantity: 1},
+		},
+	}
+	ctx := context.Background()
+
+	mockProductRepo.EXPECT().FindProductsByIDs(gomock.Any(), []string{"1", "2", "3"}).Return([]models.Product{
+		{ID: "1", Price: 1, Available: true},
+		{ID: "2", Price: 2, Available: true},
+		{ID: "3", Price: 3, Available: true},
+	}, nil)
+	gomock.InOrder(
+		mockStockRepo.EXPECT().ReserveStock(gomock.Any(), "1", 2).Return(true, nil),
+		mockStockRepo.EXPECT().ReserveStock(gomock.Any(), "2", 5).Return(false, nil),
+		mockStockRepo.EXPECT().ReserveStock(gomock.Any(), "3", 1).Return(false, nil),
+	)
+
+	// When: Placing the order
+	order, err := service.PlaceOrder(ctx, request)
+
+	// Then: Every product short of stock is reported and the transaction is aborted, putting back the reserved units
+	assert.Nil(t, order)
+	var stockErr *OutOfStockError
+	require.ErrorAs(t, err, &stockErr)
+	assert.Equal(t, []string{"2", "3"}, stockErr.ProductIDs)
+	assert.Equal(t, 0, uow.commits)
+	assert.Equal(t, 1, uow.aborts)
+}
+
+func TestOrderService_PlaceOrder_OutOfStockRecordedOnce(t *testing.T) {
+	// Given: A transaction that is retried once after the product ran out of stock
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockProductRepo := mocks.NewMockProductRepository(ctrl)
+	mockStockRepo := mocks.NewMockStockRepository(ctrl)
+	mockLogger := libmocks.NewMockILogger(ctrl)
+	mockLogger.EXPECT().WithContext(gomock.Any()).Return(mockLogger)
+	mockLogger.EXPECT().Warn(gomock.Any(), "stock-out-once")
+	uow := &fakeUnitOfWork{retries: 1}
+	service := NewOrderService(mocks.NewMockOrderRepository(ctrl), mockProductRepo, mocks.NewMockCouponRepository(ctrl), nil, mockStockRepo, uow, mockLogger)
+
+	mockProductRepo.EXPECT().FindProductsByIDs(gomock.Any(), []string{"stock-out-once"}).Return([]models.Product{
+		{ID: "stock-out-once", Price: 1, Available: true},
+	}, nil).Times(2)
+	mockStockRepo.EXPECT().ReserveStock(gomock.Any(), "stock-out-once", 1).Return(false, nil).Times(2)
+	before := testutil.ToFloat64(metrics.StockOutsTotal)
+
+	// When: Placing the order
+	_, err := service.PlaceOrder(context.Background(), &models.OrderCreateRequest{
+		Items: []models.OrderItem{{ProductID: "stock-out-once", Quantity: 1}},
+	})
+
+	// Then: The stock-out is logged and recorded once, not once per attempt
+	var stockErr *OutOfStockError
+	require.ErrorAs(t, err, &stockErr)
+	assert.Equal(t, 2, uow.aborts)
+	assert.Equal(t, before+1, testutil.ToFloat64(metrics.StockOutsTotal))
+}
+
+func TestOrderService_PlaceOrder_StockErrorRollsBack(t *testing.T) {
+	// Given: A stock store that fails on the second line
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockOrderRepo := mocks.NewMockOrderRepository(ctrl)
+	mockProductRepo := mocks.NewMockProductRepository(ctrl)
+	mockStockRepo := mocks.NewMockStockRepository(ctrl)
+	mockLogger := libmocks.NewMockILogger(ctrl)
+	mockLogger.EXPECT().WithContext(gomock.Any()).Return(mockLogger).AnyTimes()
+	mockLogger.EXPECT().Error(gomock.Any(), gomock.Any()).AnyTimes()
+	uow := &fakeUnitOfWork{}
+	service := NewOrderService(mockOrderRepo, mockProductRepo, mocks.NewMockCouponRepository(ctrl), nil, mockStockRepo, uow, mockLogger)
+
+	request := &models.OrderCreateRequest{
+		Items: []models.OrderItem{
+			{ProductID: "1", Quantity: 2},
+			{ProductID: "2", Quantity: 1},
+		},
+	}
+	ctx := context.Background()
+
+	mockProductRepo.EXPECT().FindProductsByIDs(gomock.Any(), []string{"1", "2"}).Return([]models.Product{
+		{ID: "1", Price: 1, Available: true},
+		{ID: "2", Price: 2, Available: true},
+	}, nil)
+	dbErr := errors.New("db error")
+	gomock.InOrder(
+		mockStockRepo.EXPECT().ReserveStock(gomock.Any(), "1", 2).Return(true, nil),
+		mockStockRepo.EXPECT().ReserveStock(gomock.Any(), "2", 1).Return(false, dbErr),
+	)
+
+	// When: Placing the order
+	order, err := service.PlaceOrder(ctx, request)
+
+	// Then: The order fails with the database error still visible to the transaction, which is aborted
+	assert.Nil(t, order)
+	require.Error(t, err)
+	assert.ErrorIs(t, err, dbErr)
+	assert.Contains(t, err.Error(), ReserveStockError)
+	assert.Equal(t, 0, uow.commits)
+	assert.Equal(t, 1, uow.aborts)
+}
+
+func TestOrderService_PlaceOrder_RunsInTransaction(t *testing.T) {
+	// Given: A unit of work that marks the context of its transactions
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockOrderRepo := mocks.NewMockOrderRepository(ctrl)
+	mockProductRepo := mocks.NewMockProductRepository(ctrl)
+	mockStockRepo := mocks.NewMockStockRepository(ctrl)
+	uow := &fakeUnitOfWork{tagContext: true}
+	service := NewOrderService(mockOrderRepo, mockProductRepo, mocks.NewMockCouponRepository(ctrl), nil, mockStockRepo, uow, libmocks.NewMockILogger(ctrl))
+
+	request := &models.OrderCreateRequest{Items: []models.OrderItem{{ProductID: "1", Quantity: 1}}}
+
+	mockProductRepo.EXPECT().FindProductsByIDs(inTransaction(), []string{"1"}).Return([]models.Product{{ID: "1", Price: 1, Available: true}}, nil)
+	mockStockRepo.EXPECT().ReserveStock(inTransaction(), "1", 1).Return(true, nil)
+	mockOrderRepo.EXPECT().PlaceOrder(inTransaction(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, order *models.Order) (*models.Order, error) {
+			return order, nil
+		})
+
+	// When: Placing the order
+	order, err := service.PlaceOrder(context.Background(), request)
+
+	// Then: Every repository call uses the transactional context and the unit is committed once
+	require.NoError(t, err)
+	require.NotNil(t, order)
+	assert.Equal(t, 1, uow.commits)
+	assert.Equal(t, 0, uow.aborts)
+}
+
+func TestOrderService_PlaceOrder_CommitFailure(t *testing.T) {
+	// Given: A unit of work whose commit fails after every write succeeded
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockOrderRepo := mocks.NewMockOrderRepository(ctrl)
+	mockProductRepo := mocks.NewMockProductRepository(ctrl)
+	commitErr := errors.New("commit failed")
+	uow := &fakeUnitOfWork{commitErr: commitErr}
+	service := NewOrderService(mockOrderRepo, mockProductRepo, mocks.NewMockCouponRepository(ctrl), nil, untrackedStock(ctrl), uow, libmocks.NewMockILogger(ctrl))
+
+	request := &models.OrderCreateRequest{Items: []models.OrderItem{{ProductID: "1", Quantity: 1}}}
+	ctx := context.Background()
+
+	mockProductRepo.EXPECT().FindProductsByIDs(gomock.Any(), []string{"1"}).Return([]models.Product{{ID: "1", Price: 1, Available: true}}, nil)
+	mockOrderRepo.EXPECT().PlaceOrder(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, order *models.Order) (*models.Order, error) {
+			return order, nil
+		})
+
+	// When: Placing the order
+	order, err := service.PlaceOrder(ctx, request)
+
+	// Then: The order is not returned, since none of its writes were kept
+	assert.Nil(t, order)
+	assert.ErrorIs(t, err, commitErr)
+	assert.Equal(t, 1, uow.aborts)
+}
+
+func TestOrderService_PlaceOrder_CouponAlreadyRedeemed(t *testing.T) {
+	// Given: A coupon family limited to one use per customer and a customer who already used the code
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockCouponRepo := mocks.NewMockCouponRepository(ctrl)
+	rules := mocks.NewMockCouponRuleRepository(ctrl)
+	rules.EXPECT().ListCouponRules(gomock.Any()).Return([]models.CouponRule{
+		{ID: "happy-hours", CodePrefix: "HAPPY", Type: models.DiscountPercent, Percent: 15, Active: true, OncePerCustomer: true},
+	}, nil)
+	service := NewOrderService(mocks.NewMockOrderRepository(ctrl), mocks.NewMockProductRepository(ctrl), mockCouponRepo, rules,
+		untrackedStock(ctrl), &fakeUnitOfWork{}, libmocks.NewMockILogger(ctrl))
+
+	request := &models.OrderCreateRequest{
+		CouponCode: "HAPPYHRS",
+		CustomerID: "cust-1",
+		Items:      []models.OrderItem{{ProductID: "1", Quantity: 1}},
+	}
+	ctx := context.Background()
+
+	mockCouponRepo.EXPECT().ValidateCouponCode(gomock.Any(), "HAPPYHRS").Return(true, nil)
+	mockCouponRepo.EXPECT().HasCustomerRedeemed(gomock.Any(), "HAPPYHRS", "cust-1").Return(true, nil)
+
+	// When: Placing the order
+	order, err := service.PlaceOrder(ctx, request)
+
+	// Then: The coupon is rejected before any product is read
+	assert.Nil(t, order)
+	var couponErr *CouponRejectedError
+	require.ErrorAs(t, err, &couponErr)
+	assert.Equal(t, models.CouponReasonAlreadyRedeemed, couponErr.Reason)
+}
+
+func TestOrderService_PlaceOrder_RedeemsCoupon(t *testing.T) {
+	// Given: A coupon family limited to one use per customer and 100 uses of each code
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockOrderRepo := mocks.NewMockOrderRepository(ctrl)
+	mockProductRepo := mocks.NewMockProductRepository(ctrl)
+	mockCouponRepo := mocks.NewMockCouponRepository(ctrl)
+	uow := &fakeUnitOfWork{tagContext: true}
+	rules := mocks.NewMockCouponRuleRepository(ctrl)
+	rules.EXPECT().ListCouponRules(gomock.Any()).Return([]models.CouponRule{
+		{ID: "happy-hours", CodePrefix: "HAPPY", Type: models.DiscountPercent, Percent: 15, Active: true, OncePerCustomer: true, MaxRedemptions: 100},
+	}, nil)
+	service := NewOrderService(mockOrderRepo, mockProductRepo, mockCouponRepo, rules, untrackedStock(ctrl), uow, libmocks.NewMockILogger(ctrl))
+
+	request := &models.OrderCreateRequest{
+		CouponCode: "HAPPYHRS",
+		CustomerID: " cust-1 ",
+		Items:      []models.OrderItem{{ProductID: "1", Quantity: 1}},
+	}
+	ctx := context.Background()
+
+	mockCouponRepo.EXPECT().ValidateCouponCode(gomock.Any(), "HAPPYHRS").Return(true, nil)
+	mockCouponRepo.EXPECT().FindCouponUsage(gomock.Any(), "HAPPYHRS").Return(&models.CouponUsage{Redemptions: 10}, nil)
+	mockCouponRepo.EXPECT().HasCustomerRedeemed(gomock.Any(), "HAPPYHRS", "cust-1").Return(false, nil)
+	mockProductRepo.EXPECT().FindProductsByIDs(inTransaction(), []string{"1"}).Return([]models.Product{{ID: "1", Price: 1, Available: true}}, nil)
+	mockOrderRepo.EXPECT().PlaceOrder(inTransaction(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, order *models.Order) (*models.Order, error) {
+			order.ID = "order-1"
+			return order, nil
+		})
+	gomock.InOrder(
+		mockCouponRepo.EXPECT().IncrementCouponUsage(inTransaction(), "HAPPYHRS", int64(100)).Return(true, nil),
+		mockCouponRepo.EXPECT().InsertCouponRedemption(inTransaction(), gomock.Any()).DoAndReturn(
+			func(_ context.Context, r *models.CouponRedemption) (bool, error) {
+				assert.Equal(t, "HAPPYHRS", r.CouponCode)
+				assert.Equal(t, "cust-1", r.CustomerID)
+				assert.Equal(t, "order-1", r.OrderID)
+				assert.True(t, r.Exclusive)
+				return true, nil
+			}),
+	)
+
+	// When: Placing the order
+	order, err := service.PlaceOrder(ctx, request)
+
+	// Then: The redemption is recorded in the order's transaction, which is committed
+	require.NoError(t, err)
+	assert.Equal(t, "cust-1", order.CustomerID)
+	assert.Equal(t, 1, uow.commits)
+}
+
+func TestOrderService_PlaceOrder_CouponLimitReachedConcurrently(t *testing.T) {
+	// Given: A coupon whose last redemption is taken by another order after the pre-check
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockOrderRepo := mocks.NewMockOrderRepository(ctrl)
+	mockProductRepo := mocks.NewMockProductRepository(ctrl)
+	mockCouponRepo := mocks.NewMockCouponRepository(ctrl)
+	uow := &fakeUnitOfWork{}
+	rules := mocks.NewMockCouponRuleRepository(ctrl)
+	rules.EXPECT().ListCouponRules(gomock.Any()).Return([]models.CouponRule{
+		{ID: "happy-hours", CodePrefix: "HAPPY", Type: models.DiscountPercent, Percent: 15, Active: true, MaxRedemptions: 1},
+	}, nil)
+	service := NewOrderService(mockOrderRepo, mockProductRepo, mockCouponRepo, rules, untrackedStock(ctrl), uow, libmocks.NewMockILogger(ctrl))
+
+	request := &models.OrderCreateRequest{
+		CouponCode: "HAPPYHRS",
+		Items:      []models.OrderItem{{ProductID: "1", Quantity: 1}},
+	}
+	ctx := context.Background()
+
+	mockCouponRepo.EXPECT().ValidateCouponCode(gomock.Any(), "HAPPYHRS").Return(true, nil)
+	mockCouponRepo.EXPECT().FindCouponUsage(gomock.Any(), "HAPPYHRS").Return(nil, nil)
+	mockProductRepo.EXPECT().FindProductsByIDs(gomock.Any(), []string{"1"}).Return([]models.Product{{ID: "1", Price: 1, Available: true}}, nil)
+	mockOrderRepo.EXPECT().PlaceOrder(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, order *models.Order) (*models.Order, error) {
+			return order, nil
+		})
+	mockCouponRepo.EXPECT().IncrementCouponUsage(gomock.Any(), "HAPPYHRS", int64(1)).Return(false, nil)
+
+	// When: Placing the order
+	order, err := service.PlaceOrder(ctx, request)
+
+	// Then: The coupon is rejected and the transaction, including the saved order, is aborted
+	assert.Nil(t, order)
+	var couponErr *CouponRejectedError
+	require.ErrorAs(t, err, &couponErr)
+	assert.Equal(t, models.CouponReasonLimitReached, couponErr.Reason)
+	assert.Equal(t, 1, uow.aborts)
 }
 
 func TestOrderService_PlaceOrder_WithProductRepositoryError(t *testing.T) {
@@ -378,7 +719,7 @@ func TestOrderService_PlaceOrder_WithProductRepositoryError(t *testing.T) {
 	mockCouponRepo := mocks.NewMockCouponRepository(ctrl)
 	mockLogger := libmocks.NewMockILogger(ctrl)
 
-	service := NewOrderService(mockOrderRepo, mockProductRepo, mockCouponRepo, mockLogger)
+	service := NewOrderService(mockOrderRepo, mockProductRepo, mockCouponRepo, nil, untrackedStock(ctrl), &fakeUnitOfWork{}, mockLogger)
 
 	request := &models.OrderCreateRequest{
 		Items: []models.OrderItem{
@@ -390,8 +731,9 @@ func TestOrderService_PlaceOrder_WithProductRepositoryError(t *testing.T) {
 	ctx := context.Background()
 
 	// Mock repository behavior for product repository error
+	mockLogger.EXPECT().WithContext(gomock.Any()).Return(mockLogger).AnyTimes()
 	mockLogger.EXPECT().Error(gomock.Any(), gomock.Any())
-	mockProductRepo.EXPECT().FindProductByID(ctx, "1").Return(nil, expectedError)
+	mockProductRepo.EXPECT().FindProductsByIDs(gomock.Any(), []string{"1"}).Return(nil, expectedError)
 
 	// When: Placing an order with product repository error
 	order, err := service.PlaceOrder(ctx, request)
@@ -413,7 +755,9 @@ func TestOrderService_PlaceOrder_WithOrderRepositoryError(t *testing.T) {
 	mockCouponRepo := mocks.NewMockCouponRepository(ctrl)
 	mockLogger := libmocks.NewMockILogger(ctrl)
 
-	service := NewOrderService(mockOrderRepo, mockProductRepo, mockCouponRepo, mockLogger)
+	mockStockRepo := mocks.NewMockStockRepository(ctrl)
+	uow := &fakeUnitOfWork{}
+	service := NewOrderService(mockOrderRepo, mockProductRepo, mockCouponRepo, nil, mockStockRepo, uow, mockLogger)
 
 	request := &models.OrderCreateRequest{
 		Items: []models.OrderItem{
@@ -422,23 +766,25 @@ func TestOrderService_PlaceOrder_WithOrderRepositoryError(t *testing.T) {
 	}
 
 	expectedProduct := &models.Product{
-		ID: "1", Name: "Chicken Waffle", Price: 12.99, Category: "Waffle",
+		ID: "1", Name: "Chicken Waffle", Price: 12.99, Category: "Waffle", Available: true,
 	}
 
 	expectedError := errors.New("failed to save order")
 	ctx := context.Background()
 
 	// Mock repository behaviors
-	mockProductRepo.EXPECT().FindProductByID(ctx, "1").Return(expectedProduct, nil)
-	mockOrderRepo.EXPECT().PlaceOrder(ctx, gomock.Any()).Return(nil, expectedError)
+	mockProductRepo.EXPECT().FindProductsByIDs(gomock.Any(), []string{"1"}).Return([]models.Product{*expectedProduct}, nil)
+	mockStockRepo.EXPECT().ReserveStock(gomock.Any(), "1", 1).Return(true, nil)
+	mockOrderRepo.EXPECT().PlaceOrder(gomock.Any(), gomock.Any()).Return(nil, expectedError)
 
 	// When: Placing an order with order repository error
 	order, err := service.PlaceOrder(ctx, request)
 
-	// Then: Should return the original error
+	// Then: Should return the original error and abort the transaction holding the stock reservation
 	require.Error(t, err)
 	assert.Nil(t, order)
 	assert.Equal(t, expectedError.Error(), err.Error())
+	assert.Equal(t, 1, uow.aborts)
 }
 
 func TestOrderService_PlaceOrder_WithWhitespaceCouponCode(t *testing.T) {
@@ -452,7 +798,7 @@ func TestOrderService_PlaceOrder_WithWhitespaceCouponCode(t *testing.T) {
 	mockCouponRepo := mocks.NewMockCouponRepository(ctrl)
 	mockLogger := libmocks.NewMockILogger(ctrl)
 
-	service := NewOrderService(mockOrderRepo, mockProductRepo, mockCouponRepo, mockLogger)
+	service := NewOrderService(mockOrderRepo, mockProductRepo, mockCouponRepo, nil, untrackedStock(ctrl), &fakeUnitOfWork{}, mockLogger)
 
 	request := &models.OrderCreateRequest{
 		CouponCode: "   ", // Whitespace only
@@ -462,7 +808,7 @@ func TestOrderService_PlaceOrder_WithWhitespaceCouponCode(t *testing.T) {
 	}
 
 	expectedProduct := &models.Product{
-		ID: "1", Name: "Chicken Waffle", Price: 12.99, Category: "Waffle",
+		ID: "1", Name: "Chicken Waffle", Price: 12.99, Category: "Waffle", Available: true,
 	}
 
 	expectedOrder := &models.Order{
@@ -474,8 +820,8 @@ func TestOrderService_PlaceOrder_WithWhitespaceCouponCode(t *testing.T) {
 	ctx := context.Background()
 
 	// Mock repository behaviors (coupon validation should not be called for whitespace)
-	mockProductRepo.EXPECT().FindProductByID(ctx, "1").Return(expectedProduct, nil)
-	mockOrderRepo.EXPECT().PlaceOrder(ctx, gomock.Any()).Return(expectedOrder, nil)
+	mockProductRepo.EXPECT().FindProductsByIDs(gomock.Any(), []string{"1"}).Return([]models.Product{*expectedProduct}, nil)
+	mockOrderRepo.EXPECT().PlaceOrder(gomock.Any(), gomock.Any()).Return(expectedOrder, nil)
 
 	// When: Placing an order with whitespace coupon code
 	order, err := service.PlaceOrder(ctx, request)
@@ -485,3 +831,915 @@ func TestOrderService_PlaceOrder_WithWhitespaceCouponCode(t *testing.T) {
 	assert.NotNil(t, order)
 	assert.Equal(t, expectedOrder.ID, order.ID)
 }
+
+func TestOrderService_PlaceOrder_ComputesTotals(t *testing.T) {
+	// Given: An order service and an order with a valid coupon
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// Given: Mock repositories
+	mockOrderRepo := mocks.NewMockOrderRepository(ctrl)
+	mockProductRepo := mocks.NewMockProductRepository(ctrl)
+	mockCouponRepo := mocks.NewMockCouponRepository(ctrl)
+	mockLogger := libmocks.NewMockILogger(ctrl)
+
+	service := NewOrderService(mockOrderRepo, mockProductRepo, mockCouponRepo, nil, untrackedStock(ctrl), &fakeUnitOfWork{}, mockLogger)
+
+	request := &models.OrderCreateRequest{
+		CouponCode: " SAVE20OFF ",
+		Items: []models.OrderItem{
+			{ProductID: "1", Quantity: 3},
+			{ProductID: "2", Quantity: 1},
+		},
+	}
+
+	wafflePrd := models.Product{ID: "1", Name: "Chicken Waffle", Price: 0.1, Category: "Waffle", Available: true}
+	burgerPrd := models.Product{ID: "2", Name: "Beef Burger", Price: 15.55, Category: "Burger", Available: true}
+
+	ctx := context.Background()
+
+	// Mock repository behaviors, echoing back the order that would be persisted
+	mockCouponRepo.EXPECT().ValidateCouponCode(gomock.Any(), "SAVE20OFF").Return(true, nil)
+	mockProductRepo.EXPECT().FindProductsByIDs(gomock.Any(), []string{"1", "2"}).Return([]models.Product{wafflePrd, burgerPrd}, nil)
+	mockOrderRepo.EXPECT().PlaceOrder(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, o *models.Order) (*models.Order, error) { return o, nil })
+	mockCouponRepo.EXPECT().IncrementCouponUsage(gomock.Any(), "SAVE20OFF", int64(0)).Return(true, nil)
+	mockCouponRepo.EXPECT().InsertCouponRedemption(gomock.Any(), gomock.Any()).Return(true, nil)
+
+	// When: Placing the order
+	order, err := service.PlaceOrder(ctx, request)
+
+	// Then: Totals should be computed exactly in cents
+	require.NoError(t, err)
+	require.Len(t, order.Lines, 2)
+	assert.Equal(t, models.Money(10), order.Lines[0].UnitPrice)
+	assert.Equal(t, models.Money(30), order.Lines[0].LineTotal)
+	assert.Equal(t, models.Money(1555), order.Lines[1].LineTotal)
+	assert.Equal(t, models.Money(1585), order.Subtotal)
+	assert.Equal(t, models.Money(159), order.Discount) // 10% of 15.85 rounded half up
+	assert.Equal(t, models.Money(1426), order.Total)
+	assert.Equal(t, "SAVE20OFF", order.CouponCode)
+	require.NotNil(t, order.Promotion)
+	assert.Equal(t, DefaultCouponRuleID, order.Promotion.RuleID)
+	// And: The history should start with the order being placed
+	require.Len(t, order.History, 1)
+	assert.Equal(t, models.OrderStatusPlaced, order.History[0].To)
+	assert.Empty(t, order.History[0].From)
+	assert.NotZero(t, order.History[0].At)
+}
+
+func TestOrderService_PlaceOrder_AppliesCouponRule(t *testing.T) {
+	// Given: A coupon family granting buy-one-get-one on pizzas for baskets of 20.00 or more
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockOrderRepo := mocks.NewMockOrderRepository(ctrl)
+	mockProductRepo := mocks.NewMockProductRepository(ctrl)
+	mockCouponRepo := mocks.NewMockCouponRepository(ctrl)
+	mockRuleRepo := mocks.NewMockCouponRuleRepository(ctrl)
+	service := NewOrderService(mockOrderRepo, mockProductRepo, mockCouponRepo, mockRuleRepo, untrackedStock(ctrl),
+		&fakeUnitOfWork{}, libmocks.NewMockILogger(ctrl))
+
+	rule := models.CouponRule{
+		ID: "pizza-bogo", Description: "Second pizza free", CodePrefix: "PIZZA", Type: models.DiscountBuyOneGetOne,
+		CategoryID: "pizza", MinSubtotal: 2000, Active: true,
+	}
+	ctx := context.Background()
+	mockCouponRepo.EXPECT().ValidateCouponCode(gomock.Any(), gomock.Any()).Return(true, nil).Times(2)
+	mockRuleRepo.EXPECT().ListCouponRules(gomock.Any()).Return([]models.CouponRule{rule}, nil).Times(2)
+	mockProductRepo.EXPECT().FindProductsByIDs(gomock.Any(), []string{"1", "2"}).Return([]models.Product{
+		{ID: "1", Price: 9.5, CategoryID: "pizza", Available: true},
+		{ID: "2", Price: 2.5, CategoryID: "beverages", Available: true},
+	}, nil).Times(2)
+	mockOrderRepo.EXPECT().PlaceOrder(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, o *models.Order) (*models.Order, error) { return o, nil })
+	mockCouponRepo.EXPECT().IncrementCouponUsage(gomock.Any(), "PIZZA2024", int64(0)).Return(true, nil)
+	mockCouponRepo.EXPECT().InsertCouponRedemption(gomock.Any(), gomock.Any()).Return(true, nil)
+
+	// When: Ordering two pizzas and a drink
+	order, err := service.PlaceOrder(ctx, &models.OrderCreateRequest{
+		CouponCode: "PIZZA2024",
+		Items:      []models.OrderItem{{ProductID: "1", Quantity: 2}, {ProductID: "2", Quantity: 1}},
+	})
+
+	// Then: One pizza is free and the order explains the rule that was applied
+	require.NoError(t, err)
+	assert.Equal(t, models.Money(2150), order.Subtotal)
+	assert.Equal(t, models.Money(950), order.Discount)
+	assert.Equal(t, models.Money(1200), order.Total)
+	assert.Equal(t, &models.AppliedDiscount{
+		RuleID: "pizza-bogo", Type: models.DiscountBuyOneGetOne, Description: "Second pizza free", Amount: 950,
+	}, order.Promotion)
+
+	// When: Ordering a single pizza and a drink, below the minimum basket value
+	order, err = service.PlaceOrder(ctx, &models.OrderCreateRequest{
+		CouponCode: "PIZZA2024",
+		Items:      []models.OrderItem{{ProductID: "1", Quantity: 1}, {ProductID: "2", Quantity: 1}},
+	})
+
+	// Then: The coupon is rejected and nothing is saved
+	assert.Nil(t, order)
+	var couponErr *CouponRejectedError
+	require.ErrorAs(t, err, &couponErr)
+	assert.Equal(t, models.CouponReasonMinimumNotMet, couponErr.Reason)
+}
+
+// histogramSample returns the sample count and sum observed by a histogram.
+func histogramSample(t *testing.T, observer prometheus.Observer) (uint64, float64) {
+	t.Helper()
+	var m dto.Metric
+	require.NoError(t, observer.(prometheus.Histogram).Write(&m))
+	return m.GetHistogram().GetSampleCount(), m.GetHistogram().GetSampleSum()
+}
+
+func TestOrderService_PlaceOrder_RecordsBusinessMetrics(t *testing.T) {
+	// Given: A coupon family granting 10% off any basket
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockOrderRepo := mocks.NewMockOrderRepository(ctrl)
+	mockProductRepo := mocks.NewMockProductRepository(ctrl)
+	mockCouponRepo := mocks.NewMockCouponRepository(ctrl)
+	mockRuleRepo := mocks.NewMockCouponRuleRepository(ctrl)
+	service := NewOrderService(mockOrderRepo, mockProductRepo, mockCouponRepo, mockRuleRepo, untrackedStock(ctrl),
+		&fakeUnitOfWork{}, libmocks.NewMockILogger(ctrl))
+
+	ctx := context.Background()
+	mockCouponRepo.EXPECT().ValidateCouponCode(gomock.Any(), "TENOFF2024").Return(true, nil)
+	mockRuleRepo.EXPECT().ListCouponRules(gomock.Any()).Return([]models.CouponRule{
+		{ID: "ten-off", CodePrefix: "TENOFF", Type: models.DiscountPercent, Percent: 10, Active: true},
+	}, nil)
+	mockProductRepo.EXPECT().FindProductsByIDs(gomock.Any(), []string{"metrics-1", "metrics-2"}).Return([]models.Product{
+		{ID: "metrics-1", Price: 10, Available: true},
+		{ID: "metrics-2", Price: 5, Available: true},
+	}, nil)
+	mockOrderRepo.EXPECT().PlaceOrder(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, o *models.Order) (*models.Order, error) { return o, nil })
+	mockCouponRepo.EXPECT().IncrementCouponUsage(gomock.Any(), "TENOFF2024", int64(0)).Return(true, nil)
+	mockCouponRepo.EXPECT().InsertCouponRedemption(gomock.Any(), gomock.Any()).Return(true, nil)
+
+	valueCount, valueSum := histogramSample(t, metrics.OrderValue)
+	itemsCount, itemsSum := histogramSample(t, metrics.OrderItems)
+	discountCount, discountSum := histogramSample(t, metrics.OrderDiscount.WithLabelValues(string(models.DiscountPercent)))
+	ordered := metrics.ProductsOrderedTotal.WithLabelValues("metrics-1")
+	orderedBefore := testutil.ToFloat64(ordered)
+
+	// When: Ordering two of one product and one of another with the coupon
+	order, err := service.PlaceOrder(ctx, &models.OrderCreateRequest{
+		CouponCode: "TENOFF2024",
+		Items:      []models.OrderItem{{ProductID: "metrics-1", Quantity: 2}, {ProductID: "metrics-2", Quantity: 1}},
+	})
+
+	// Then: The order's value, basket size, discount and products should be recorded
+	require.NoError(t, err)
+	require.Equal(t, models.Money(2250), order.Total)
+	count, sum := histogramSample(t, metrics.OrderValue)
+	assert.Equal(t, valueCount+1, count)
+	assert.InDelta(t, valueSum+22.5, sum, 1e-9)
+	count, sum = histogramSample(t, metrics.OrderItems)
+	assert.Equal(t, itemsCount+1, count)
+	assert.Equal(t, itemsSum+3, sum)
+	count, sum = histogramSample(t, metrics.OrderDiscount.WithLabelValues(string(models.DiscountPercent)))
+	assert.Equal(t, discountCount+1, count)
+	assert.InDelta(t, discountSum+2.5, sum, 1e-9)
+	assert.Equal(t, orderedBefore+2, testutil.ToFloat64(ordered))
+}
+
+func TestOrderService_PlaceOrder_SpanTree(t *testing.T) {
+	tests := []struct {
+		name    string
+		found   []models.Product
+		outcome string
+		status  codes.Code
+	}{
+		{name: "placed", found: []models.Product{{ID: "1", Price: 4, Available: true}}, outcome: "success", status: codes.Unset},
+		{name: "product not found", outcome: "product_not_found", status: codes.Error},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given: Spans recorded in memory and an order service whose repositories note the span they are called in
+			recorder := tracetest.NewSpanRecorder()
+			previous := otel.GetTracerProvider()
+			tracing.Init(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+			t.Cleanup(func() { otel.SetTracerProvider(previous) })
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			mockOrderRepo := mocks.NewMockOrderRepository(ctrl)
+			mockProductRepo := mocks.NewMockProductRepository(ctrl)
+			service := NewOrderService(mockOrderRepo, mockProductRepo, mocks.NewMockCouponRepository(ctrl), nil,
+				untrackedStock(ctrl), &fakeUnitOfWork{}, libmocks.NewMockILogger(ctrl))
+
+			var repoSpans []trace.SpanID
+			mockProductRepo.EXPECT().FindProductsByIDs(gomock.Any(), []string{"1"}).DoAndReturn(
+				func(ctx context.Context, _ []string) ([]models.Product, error) {
+					repoSpans = append(repoSpans, trace.SpanFromContext(ctx).SpanContext().SpanID())
+					return tt.found, nil
+				})
+			mockOrderRepo.EXPECT().PlaceOrder(gomock.Any(), gomock.Any()).DoAndReturn(
+				func(ctx context.Context, o *models.Order) (*models.Order, error) {
+					repoSpans = append(repoSpans, trace.SpanFromContext(ctx).SpanContext().SpanID())
+					o.ID = "order-1"
+					return o, nil
+				}).MaxTimes(1)
+
+			// When: Placing an order within a request's span
+			ctx, request := otel.Tracer("test").Start(context.Background(), "POST /api/order")
+			_, err := service.PlaceOrder(ctx, &models.OrderCreateRequest{Items: []models.OrderItem{{ProductID: "1", Quantity: 1}}})
+			request.End()
+
+			// Then: The service span should be a child of the request's, and the transaction's a child of the service's,
+			// holding the repository calls
+			assert.Equal(t, tt.status == codes.Error, err != nil)
+			spans := recorder.Ended()
+			require.Len(t, spans, 3)
+			inner, outer, root := spans[0], spans[1], spans[2]
+			assert.Equal(t, "OrderService.placeOrder", inner.Name())
+			assert.Equal(t, "OrderService.PlaceOrder", outer.Name())
+			assert.Equal(t, "POST /api/order", root.Name())
+			assert.Equal(t, root.SpanContext().SpanID(), outer.Parent().SpanID())
+			assert.Equal(t, outer.SpanContext().SpanID(), inner.Parent().SpanID())
+			for _, span := range spans {
+				assert.Equal(t, root.SpanContext().TraceID(), span.SpanContext().TraceID())
+			}
+			require.NotEmpty(t, repoSpans)
+			for _, id := range repoSpans {
+				assert.Equal(t, inner.SpanContext().SpanID(), id)
+			}
+
+			assert.Equal(t, tt.status, outer.Status().Code)
+			assert.Equal(t, tt.status, inner.Status().Code)
+			assert.Contains(t, inner.Attributes(), attribute.String("order.outcome", tt.outcome))
+			assert.Contains(t, outer.Attributes(), attribute.String("order.outcome", tt.outcome))
+			assert.Contains(t, outer.Attributes(), attribute.Int("order.item_lines", 1))
+			if tt.status == codes.Unset {
+				assert.Contains(t, outer.Attributes(), attribute.String("order.id", "order-1"))
+			}
+		})
+	}
+}
+
+func TestOrderService_PlaceOrder_RecordsCouponValidationOutcome(t *testing.T) {
+	tests := []struct {
+		code    string
+		valid   bool
+		err     error
+		lookup  bool
+		outcome string
+	}{
+		{code: "SHORT", outcome: metrics.CouponTooShort},
+		{code: "VERYLONGCOUPONCODE", outcome: metrics.CouponTooLong},
+		{code: "UNKNOWN1", lookup: true, outcome: metrics.CouponInvalid},
+		{code: "HAPPYHRS", lookup: true, err: errors.New("db error"), outcome: metrics.CouponError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.outcome, func(t *testing.T) {
+			// Given: An order service
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			mockCouponRepo := mocks.NewMockCouponRepository(ctrl)
+			service := NewOrderService(mocks.NewMockOrderRepository(ctrl), mocks.NewMockProductRepository(ctrl),
+				mockCouponRepo, nil, untrackedStock(ctrl), &fakeUnitOfWork{}, libmocks.NewMockILogger(ctrl))
+			if tt.lookup {
+				mockCouponRepo.EXPECT().ValidateCouponCode(gomock.Any(), tt.code).Return(tt.valid, tt.err)
+			}
+			outcomes := metrics.CouponValidationsTotal.WithLabelValues(tt.outcome)
+			before := testutil.ToFloat64(outcomes)
+
+			// When: Placing an order with a coupon code that is not valid
+			_, err := service.PlaceOrder(context.Background(), &models.OrderCreateRequest{
+				CouponCode: tt.code,
+				Items:      []models.OrderItem{{ProductID: "1", Quantity: 1}},
+			})
+
+			// Then: The validation outcome should be recorded once
+			require.Error(t, err)
+			assert.Equal(t, before+1, testutil.ToFloat64(outcomes))
+		})
+	}
+}
+
+func TestOrderService_PlaceOrder_WithoutCouponHasNoDiscount(t *testing.T) {
+	// Given: An order service and an order without coupon
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// Given: Mock repositories
+	mockOrderRepo := mocks.NewMockOrderRepository(ctrl)
+	mockProductRepo := mocks.NewMockProductRepository(ctrl)
+	mockCouponRepo := mocks.NewMockCouponRepository(ctrl)
+	mockLogger := libmocks.NewMockILogger(ctrl)
+
+	service := NewOrderService(mockOrderRepo, mockProductRepo, mockCouponRepo, nil, untrackedStock(ctrl), &fakeUnitOfWork{}, mockLogger)
+
+	request := &models.OrderCreateRequest{
+		Items: []models.OrderItem{{ProductID: "1", Quantity: 2}},
+	}
+	wafflePrd := models.Product{ID: "1", Name: "Chicken Waffle", Price: 12.99, Category: "Waffle", Available: true}
+
+	ctx := context.Background()
+
+	mockProductRepo.EXPECT().FindProductsByIDs(gomock.Any(), []string{"1"}).Return([]models.Product{wafflePrd}, nil)
+	mockOrderRepo.EXPECT().PlaceOrder(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, o *models.Order) (*models.Order, error) { return o, nil })
+
+	// When: Placing the order
+	order, err := service.PlaceOrder(ctx, request)
+
+	// Then: Total should equal the subtotal
+	require.NoError(t, err)
+	assert.Equal(t, models.Money(2598), order.Subtotal)
+	assert.Equal(t, models.Money(0), order.Discount)
+	assert.Equal(t, order.Subtotal, order.Total)
+	assert.Empty(t, order.CouponCode)
+}
+
+func TestOrderService_PlaceOrder_SnapshotsProducts(t *testing.T) {
+	// Given: An order service and an order whose expectations match the catalog
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockOrderRepo := mocks.NewMockOrderRepository(ctrl)
+	mockProductRepo := mocks.NewMockProductRepository(ctrl)
+	mockCouponRepo := mocks.NewMockCouponRepository(ctrl)
+	mockLogger := libmocks.NewMockILogger(ctrl)
+
+	service := NewOrderService(mockOrderRepo, mockProductRepo, mockCouponRepo, nil, untrackedStock(ctrl), &fakeUnitOfWork{}, mockLogger)
+
+	price := models.Money(1299)
+	version := int64(3)
+	request := &models.OrderCreateRequest{
+		Items: []models.OrderItem{{ProductID: "1", Quantity: 2, ExpectedPrice: &price, ExpectedVersion: &version}},
+	}
+	wafflePrd := models.Product{ID: "1", Name: "Chicken Waffle", Price: 12.99, Category: "Waffle", Version: 3, Available: true}
+
+	ctx := context.Background()
+
+	mockProductRepo.EXPECT().FindProductsByIDs(gomock.Any(), []string{"1"}).Return([]models.Product{wafflePrd}, nil)
+	mockOrderRepo.EXPECT().PlaceOrder(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, o *models.Order) (*models.Order, error) { return o, nil })
+
+	// When: Placing the order
+	order, err := service.PlaceOrder(ctx, request)
+
+	// Then: Each line should carry a snapshot of the product at order time
+	require.NoError(t, err)
+	require.Len(t, order.Lines, 1)
+	assert.Equal(t, models.OrderLine{
+		ProductID:      "1",
+		Name:           "Chicken Waffle",
+		Category:       "Waffle",
+		ProductVersion: 3,
+		Quantity:       2,
+		UnitPrice:      1299,
+		LineTotal:      2598,
+	}, order.Lines[0])
+}
+
+func TestOrderService_PlaceOrder_StaleCart(t *testing.T) {
+	// Given: An order service and a cart priced against an older catalog
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockOrderRepo := mocks.NewMockOrderRepository(ctrl)
+	mockProductRepo := mocks.NewMockProductRepository(ctrl)
+	mockCouponRepo := mocks.NewMockCouponRepository(ctrl)
+	mockLogger := libmocks.NewMockILogger(ctrl)
+
+	service := NewOrderService(mockOrderRepo, mockProductRepo, mockCouponRepo, nil, untrackedStock(ctrl), &fakeUnitOfWork{}, mockLogger)
+
+	oldPrice := models.Money(1299)
+	currentPrice := models.Money(1555)
+	oldVersion := int64(1)
+	request := &models.OrderCreateRequest{
+		Items: []models.OrderItem{
+			{ProductID: "1", Quantity: 1, ExpectedPrice: &oldPrice},
+			{ProductID: "2", Quantity: 1, ExpectedPrice: &currentPrice},
+			{ProductID: "3", Quantity: 1, ExpectedVersion: &oldVersion},
+		},
+	}
+
+	ctx := context.Background()
+
+	mockProductRepo.EXPECT().FindProductsByIDs(gomock.Any(), []string{"1", "2", "3"}).Return([]models.Product{
+		{ID: "3", Price: 4, Version: 2, Available: true},
+		{ID: "1", Price: 13.49, Version: 2, Available: true},
+		{ID: "2", Price: 15.55, Version: 1, Available: true},
+	}, nil)
+
+	// When: Placing the order
+	order, err := service.PlaceOrder(ctx, request)
+
+	// Then: The order should be rejected, listing only the changed lines
+	assert.Nil(t, order)
+	var staleErr *StaleCartError
+	require.ErrorAs(t, err, &staleErr)
+	assert.Equal(t, StaleCart, err.Error())
+	require.Len(t, staleErr.Lines, 2)
+	assert.Equal(t, "1", staleErr.Lines[0].ProductID)
+	assert.Equal(t, &oldPrice, staleErr.Lines[0].ExpectedPrice)
+	assert.Equal(t, models.Money(1349), staleErr.Lines[0].CurrentPrice)
+	assert.Equal(t, "3", staleErr.Lines[1].ProductID)
+	assert.Equal(t, &oldVersion, staleErr.Lines[1].ExpectedVersion)
+	assert.Equal(t, int64(2), staleErr.Lines[1].CurrentVersion)
+}
+
+func TestOrderService_PlaceOrder_MergesDuplicateItems(t *testing.T) {
+	// Given: An order service and an order listing the same product twice
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockOrderRepo := mocks.NewMockOrderRepository(ctrl)
+	mockProductRepo := mocks.NewMockProductRepository(ctrl)
+	mockCouponRepo := mocks.NewMockCouponRepository(ctrl)
+	mockLogger := libmocks.NewMockILogger(ctrl)
+
+	service := NewOrderService(mockOrderRepo, mockProductRepo, mockCouponRepo, nil, untrackedStock(ctrl), &fakeUnitOfWork{}, mockLogger)
+
+	request := &models.OrderCreateRequest{
+		Items: []models.OrderItem{
+			{ProductID: "1", Quantity: 2},
+			{ProductID: "2", Quantity: 1},
+			{ProductID: "1", Quantity: 3},
+		},
+	}
+
+	ctx := context.Background()
+
+	// Then: Each product should be looked up once, in a single query
+	mockProductRepo.EXPECT().FindProductsByIDs(gomock.Any(), []string{"1", "2"}).Return([]models.Product{
+		{ID: "1", Name: "Chicken Waffle", Price: 1, Available: true},
+		{ID: "2", Name: "Beef Burger", Price: 2, Available: true},
+	}, nil)
+	mockOrderRepo.EXPECT().PlaceOrder(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, o *models.Order) (*models.Order, error) { return o, nil })
+
+	// When: Placing the order
+	order, err := service.PlaceOrder(ctx, request)
+
+	// Then: Repeated items should be merged into one line, keeping first-seen order
+	require.NoError(t, err)
+	require.Len(t, order.Items, 2)
+	assert.Equal(t, models.OrderItem{ProductID: "1", Quantity: 5}, order.Items[0])
+	assert.Equal(t, models.OrderItem{ProductID: "2", Quantity: 1}, order.Items[1])
+	require.Len(t, order.Lines, 2)
+	assert.Equal(t, models.Money(500), order.Lines[0].LineTotal)
+	assert.Equal(t, models.Money(700), order.Total)
+}
+
+func TestOrderService_PlaceOrder_RejectsConflictingDuplicateItems(t *testing.T) {
+	// Given: An order service and an order repeating a product with different expected prices
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockOrderRepo := mocks.NewMockOrderRepository(ctrl)
+	mockProductRepo := mocks.NewMockProductRepository(ctrl)
+	mockCouponRepo := mocks.NewMockCouponRepository(ctrl)
+	mockLogger := libmocks.NewMockILogger(ctrl)
+
+	service := NewOrderService(mockOrderRepo, mockProductRepo, mockCouponRepo, nil, untrackedStock(ctrl), &fakeUnitOfWork{}, mockLogger)
+
+	first, second := models.Money(100), models.Money(120)
+	request := &models.OrderCreateRequest{
+		Items: []models.OrderItem{
+			{ProductID: "1", Quantity: 1, ExpectedPrice: &first},
+			{ProductID: "1", Quantity: 1, ExpectedPrice: &second},
+		},
+	}
+
+	// When: Placing the order
+	order, err := service.PlaceOrder(context.Background(), request)
+
+	// Then: It should be rejected before any product lookup
+	require.Error(t, err)
+	assert.Nil(t, order)
+	assert.Equal(t, InvalidProductOrQuantity, err.Error())
+}
+
+func TestMoney_JSONRoundTrip(t *testing.T) {
+	// Given: An amount in cents
+	amount := models.Money(1426)
+
+	// When: Encoding and decoding it as JSON
+	data, err := json.Marshal(amount)
+	require.NoError(t, err)
+	var decoded models.Money
+	require.NoError(t, json.Unmarshal(data, &decoded))
+
+	// Then: It should render as a decimal number and round-trip exactly
+	assert.Equal(t, "14.26", string(data))
+	assert.Equal(t, amount, decoded)
+	assert.Equal(t, "-0.05", models.Money(-5).String())
+}
+
+func TestOrderService_GetOrder(t *testing.T) {
+	// Given: An order service with mock repositories
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockOrderRepo := mocks.NewMockOrderRepository(ctrl)
+	mockProductRepo := mocks.NewMockProductRepository(ctrl)
+	mockCouponRepo := mocks.NewMockCouponRepository(ctrl)
+	mockLogger := libmocks.NewMockILogger(ctrl)
+
+	service := NewOrderService(mockOrderRepo, mockProductRepo, mockCouponRepo, nil, untrackedStock(ctrl), &fakeUnitOfWork{}, mockLogger)
+	ctx := context.Background()
+
+	// When: The order exists
+	expectedOrder := &models.Order{ID: "order-123", CustomerID: "cust-1", Status: models.OrderStatusPlaced}
+	mockOrderRepo.EXPECT().FindOrderByID(gomock.Any(), "order-123").Return(expectedOrder, nil).Times(3)
+	order, err := service.GetOrder(ctx, "order-123", "")
+
+	// Then: It should be returned
+	require.NoError(t, err)
+	assert.Equal(t, expectedOrder, order)
+
+	// When: The customer who placed it asks for it
+	order, err = service.GetOrder(ctx, "order-123", "cust-1")
+
+	// Then: It should be returned
+	require.NoError(t, err)
+	assert.Equal(t, expectedOrder, order)
+
+	// When: Another customer asks for it
+	order, err = service.GetOrder(ctx, "order-123", "cust-2")
+
+	// Then: It should be reported as missing
+	require.NoError(t, err)
+	assert.Nil(t, order)
+
+	// When: The repository fails
+	mockLogger.EXPECT().WithContext(gomock.Any()).Return(mockLogger).AnyTimes()
+	mockLogger.EXPECT().Error(gomock.Any(), gomock.Any())
+	mockOrderRepo.EXPECT().FindOrderByID(gomock.Any(), "order-456").Return(nil, errors.New("db error"))
+	order, err = service.GetOrder(ctx, "order-456", "")
+
+	// Then: A generic fetch error should be returned
+	require.Error(t, err)
+	assert.Nil(t, order)
+	assert.Equal(t, FindOrderByIDError, err.Error())
+}
+
+func TestOrderService_ListOrders(t *testing.T) {
+	tests := []struct {
+		name        string
+		filter      models.OrderFilter
+		repoFilter  *models.OrderFilter
+		repoErr     error
+		expectedErr string
+	}{
+		{
+			name:       "applies default page size",
+			filter:     models.OrderFilter{Status: models.OrderStatusPlaced},
+			repoFilter: &models.OrderFilter{Status: models.OrderStatusPlaced, Limit: DefaultOrderPageSize},
+		},
+		{
+			name:       "caps page size",
+			filter:     models.OrderFilter{Limit: 1000, Offset: 40},
+			repoFilter: &models.OrderFilter{Limit: MaxOrderPageSize, Offset: 40},
+		},
+		{
+			name:        "rejects inverted time range",
+			filter:      models.OrderFilter{From: 200, To: 100},
+			expectedErr: InvalidOrderFilter,
+		},
+		{
+			name:        "rejects unknown status",
+			filter:      models.OrderFilter{Status: "teleported"},
+			expectedErr: InvalidOrderFilter,
+		},
+		{
+			name:        "rejects negative offset",
+			filter:      models.OrderFilter{Offset: -1},
+			expectedErr: InvalidOrderFilter,
+		},
+		{
+			name:        "repository error",
+			filter:      models.OrderFilter{},
+			repoFilter:  &models.OrderFilter{Limit: DefaultOrderPageSize},
+			repoErr:     errors.New("db error"),
+			expectedErr: OrderListingError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockOrderRepo := mocks.NewMockOrderRepository(ctrl)
+			mockLogger := libmocks.NewMockILogger(ctrl)
+			service := NewOrderService(mockOrderRepo, mocks.NewMockProductRepository(ctrl), mocks.NewMockCouponRepository(ctrl), nil, untrackedStock(ctrl), &fakeUnitOfWork{}, mockLogger)
+			ctx := context.Background()
+
+			orders := []models.Order{{ID: "order-1"}}
+			if tt.repoFilter != nil {
+				if tt.repoErr != nil {
+					mockLogger.EXPECT().WithContext(gomock.Any()).Return(mockLogger).AnyTimes()
+					mockLogger.EXPECT().Error(gomock.Any(), gomock.Any())
+					mockOrderRepo.EXPECT().ListOrders(gomock.Any(), *tt.repoFilter).Return(nil, int64(0), tt.repoErr)
+				} else {
+					mockOrderRepo.EXPECT().ListOrders(gomock.Any(), *tt.repoFilter).Return(orders, int64(41), nil)
+				}
+			}
+
+			resp, err := service.ListOrders(ctx, tt.filter)
+
+			if tt.expectedErr != "" {
+				require.Error(t, err)
+				assert.Equal(t, tt.expectedErr, err.Error())
+				assert.Nil(t, resp)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, orders, resp.Orders)
+			assert.Equal(t, int64(41), resp.Total)
+			assert.Equal(t, tt.repoFilter.Limit, resp.Limit)
+			assert.Equal(t, tt.repoFilter.Offset, resp.Offset)
+		})
+	}
+}
+
+func TestCanTransition(t *testing.T) {
+	tests := []struct {
+		from, to models.OrderStatus
+		allowed  bool
+	}{
+		{models.OrderStatusPlaced, models.OrderStatusConfirmed, true},
+		{models.OrderStatusConfirmed, models.OrderStatusPreparing, true},
+		{models.OrderStatusPreparing, models.OrderStatusReady, true},
+		{models.OrderStatusReady, models.OrderStatusDelivered, true},
+		{models.OrderStatusPlaced, models.OrderStatusCancelled, true},
+		{models.OrderStatusDelivered, models.OrderStatusRefunded, true},
+		{models.OrderStatusCancelled, models.OrderStatusRefunded, true},
+		{models.OrderStatusPlaced, models.OrderStatusDelivered, false},
+		{models.OrderStatusReady, models.OrderStatusCancelled, false},
+		{models.OrderStatusDelivered, models.OrderStatusPlaced, false},
+		{models.OrderStatusRefunded, models.OrderStatusPlaced, false},
+		{models.OrderStatusPlaced, models.OrderStatusPlaced, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.from)+"->"+string(tt.to), func(t *testing.T) {
+			assert.Equal(t, tt.allowed, canTransition(tt.from, tt.to))
+		})
+	}
+}
+
+func TestOrderService_UpdateOrderStatus(t *testing.T) {
+	placed := &models.Order{ID: "order-1", Status: models.OrderStatusPlaced}
+
+	tests := []struct {
+		name        string
+		req         models.OrderStatusUpdateRequest
+		found       *models.Order
+		findErr     error
+		callsUpdate bool
+		updated     *models.Order
+		updateErr   error
+		expectedErr string
+	}{
+		{
+			name:        "valid transition",
+			req:         models.OrderStatusUpdateRequest{Status: models.OrderStatusConfirmed, Reason: " accepted "},
+			found:       placed,
+			callsUpdate: true,
+			updated:     &models.Order{ID: "order-1", Status: models.OrderStatusConfirmed},
+		},
+		{
+			name:        "unknown status",
+			req:         models.OrderStatusUpdateRequest{Status: "lost"},
+			expectedErr: InvalidOrderStatus,
+		},
+		{
+			name:        "order not found",
+			req:         models.OrderStatusUpdateRequest{Status: models.OrderStatusConfirmed},
+			expectedErr: OrderNotFound,
+		},
+		{
+			name:        "transition not allowed",
+			req:         models.OrderStatusUpdateRequest{Status: models.OrderStatusDelivered},
+			found:       placed,
+			expectedErr: InvalidStatusTransition,
+		},
+		{
+			name:        "concurrent update wins",
+			req:         models.OrderStatusUpdateRequest{Status: models.OrderStatusConfirmed},
+			found:       placed,
+			callsUpdate: true,
+			expectedErr: InvalidStatusTransition,
+		},
+		{
+			name:        "lookup error",
+			req:         models.OrderStatusUpdateRequest{Status: models.OrderStatusConfirmed},
+			findErr:     errors.New("db error"),
+			expectedErr: UpdateOrderStatusError,
+		},
+		{
+			name:        "update error",
+			req:         models.OrderStatusUpdateRequest{Status: models.OrderStatusConfirmed},
+			found:       placed,
+			callsUpdate: true,
+			updateErr:   errors.New("db error"),
+			expectedErr: UpdateOrderStatusError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockOrderRepo := mocks.NewMockOrderRepository(ctrl)
+			mockLogger := libmocks.NewMockILogger(ctrl)
+			mockLogger.EXPECT().WithContext(gomock.Any()).Return(mockLogger).AnyTimes()
+			mockLogger.EXPECT().Error(gomock.Any(), gomock.Any()).AnyTimes()
+			service := NewOrderService(mockOrderRepo, mocks.NewMockProductRepository(ctrl), mocks.NewMockCouponRepository(ctrl), nil, untrackedStock(ctrl), &fakeUnitOfWork{}, mockLogger)
+			ctx := context.Background()
+
+			if tt.req.Status.IsValid() {
+				mockOrderRepo.EXPECT().FindOrderByID(gomock.Any(), "order-1").Return(tt.found, tt.findErr)
+			}
+			if tt.callsUpdate {
+				mockOrderRepo.EXPECT().UpdateOrderStatus(gomock.Any(), "order-1", models.OrderStatusPlaced, gomock.Any()).DoAndReturn(
+					func(_ context.Context, _ string, _ models.OrderStatus, change models.OrderStatusChange) (*models.Order, error) {
+						assert.Equal(t, models.OrderStatusPlaced, change.From)
+						assert.Equal(t, tt.req.Status, change.To)
+						assert.NotZero(t, change.At)
+						return tt.updated, tt.updateErr
+					})
+			}
+
+			order, err := service.UpdateOrderStatus(ctx, "order-1", &tt.req)
+
+			if tt.expectedErr != "" {
+				require.Error(t, err)
+				assert.Equal(t, tt.expectedErr, err.Error())
+				assert.Nil(t, order)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.updated, order)
+		})
+	}
+}
+
+func TestOrderService_UpdateOrderStatus_CancelReleasesStock(t *testing.T) {
+	// Given: A placed order for a product with tracked stock and one without
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockOrderRepo := mocks.NewMockOrderRepository(ctrl)
+	mockStockRepo := mocks.NewMockStockRepository(ctrl)
+	uow := &fakeUnitOfWork{tagContext: true}
+	service := NewOrderService(mockOrderRepo, mocks.NewMockProductRepository(ctrl), mocks.NewMockCouponRepository(ctrl), nil, mockStockRepo, uow, libmocks.NewMockILogger(ctrl))
+	items := []models.OrderItem{{ProductID: "tracked", Quantity: 2}, {ProductID: "untracked", Quantity: 1}}
+	cancelled := &models.Order{ID: "order-1", Items: items, Status: models.OrderStatusCancelled}
+
+	mockOrderRepo.EXPECT().FindOrderByID(gomock.Any(), "order-1").Return(&models.Order{ID: "order-1", Items: items, Status: models.OrderStatusPlaced}, nil)
+	gomock.InOrder(
+		mockOrderRepo.EXPECT().UpdateOrderStatus(inTransaction(), "order-1", models.OrderStatusPlaced, gomock.Any()).Return(cancelled, nil),
+		mockStockRepo.EXPECT().FindStockByProductID(inTransaction(), "tracked").Return(&models.Stock{ProductID: "tracked", Quantity: 3}, nil),
+		mockStockRepo.EXPECT().AddStock(inTransaction(), "tracked", int64(2)).Return(&models.Stock{ProductID: "tracked", Quantity: 5}, nil),
+		mockStockRepo.EXPECT().FindStockByProductID(inTransaction(), "untracked").Return(nil, nil),
+	)
+
+	// When: Cancelling the order
+	order, err := service.UpdateOrderStatus(context.Background(), "order-1", &models.OrderStatusUpdateRequest{Status: models.OrderStatusCancelled})
+
+	// Then: The reserved units of tracked products are put back with the status change
+	require.NoError(t, err)
+	assert.Equal(t, cancelled, order)
+	assert.Equal(t, 1, uow.commits)
+}
+
+func TestOrderService_UpdateOrderStatus_ReleaseStockErrorRollsBack(t *testing.T) {
+	// Given: A stock store that fails while the order is being cancelled
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockOrderRepo := mocks.NewMockOrderRepository(ctrl)
+	mockStockRepo := mocks.NewMockStockRepository(ctrl)
+	mockLogger := libmocks.NewMockILogger(ctrl)
+	mockLogger.EXPECT().WithContext(gomock.Any()).Return(mockLogger).AnyTimes()
+	mockLogger.EXPECT().Error(gomock.Any(), gomock.Any())
+	uow := &fakeUnitOfWork{}
+	service := NewOrderService(mockOrderRepo, mocks.NewMockProductRepository(ctrl), mocks.NewMockCouponRepository(ctrl), nil, mockStockRepo, uow, mockLogger)
+	items := []models.OrderItem{{ProductID: "tracked", Quantity: 2}}
+
+	mockOrderRepo.EXPECT().FindOrderByID(gomock.Any(), "order-1").Return(&models.Order{ID: "order-1", Items: items, Status: models.OrderStatusConfirmed}, nil)
+	mockOrderRepo.EXPECT().UpdateOrderStatus(gomock.Any(), "order-1", models.OrderStatusConfirmed, gomock.Any()).
+		Return(&models.Order{ID: "order-1", Items: items, Status: models.OrderStatusCancelled}, nil)
+	mockStockRepo.EXPECT().FindStockByProductID(gomock.Any(), "tracked").Return(&models.Stock{ProductID: "tracked", Quantity: 3}, nil)
+	mockStockRepo.EXPECT().AddStock(gomock.Any(), "tracked", int64(2)).Return(nil, errors.New("db error"))
+
+	// When: Cancelling the order
+	order, err := service.UpdateOrderStatus(context.Background(), "order-1", &models.OrderStatusUpdateRequest{Status: models.OrderStatusCancelled})
+
+	// Then: The status change is rolled back with the stock
+	require.Error(t, err)
+	assert.Nil(t, order)
+	assert.Equal(t, UpdateOrderStatusError, err.Error())
+	assert.Equal(t, 0, uow.commits)
+	assert.Equal(t, 1, uow.aborts)
+}
+
+// latencyProductRepository is a ProductRepository whose every call costs a fixed
+// round-trip latency, standing in for the network hop to MongoDB.
+type latencyProductRepository struct {
+	repository.ProductRepository
+	latency    time.Duration
+	products   map[string]models.Product
+	roundTrips int
+}
+
+func (r *latencyProductRepository) FindProductsByIDs(_ context.Context, ids []string) ([]models.Product, error) {
+	r.roundTrips++
+	time.Sleep(r.latency)
+	products := make([]models.Product, 0, len(ids))
+	for _, id := range ids {
+		if p, ok := r.products[id]; ok {
+			products = append(products, p)
+		}
+	}
+	return products, nil
+}
+
+// BenchmarkOrderService_PlaceOrder_ProductLookup places a large order against a product
+// repository with a fixed round-trip latency, reporting the round trips PlaceOrder makes to
+// resolve the order's products; it should stay at one however many items are ordered.
+func BenchmarkOrderService_PlaceOrder_ProductLookup(b *testing.B) {
+	const itemCount = 50
+
+	productRepo := &latencyProductRepository{latency: 200 * time.Microsecond, products: map[string]models.Product{}}
+	request := &models.OrderCreateRequest{}
+	for i := 0; i < itemCount; i++ {
+		id := strconv.Itoa(i)
+		productRepo.products[id] = models.Product{ID: id, Name: "Product " + id, Price: 9.99, Available: true}
+		request.Items = append(request.Items, models.OrderItem{ProductID: id, Quantity: 1})
+	}
+
+	ctrl := gomock.NewController(b)
+	mockOrderRepo := mocks.NewMockOrderRepository(ctrl)
+	mockOrderRepo.EXPECT().PlaceOrder(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, o *models.Order) (*models.Order, error) { return o, nil }).AnyTimes()
+	service := NewOrderService(mockOrderRepo, productRepo, mocks.NewMockCouponRepository(ctrl), nil, untrackedStock(ctrl), &fakeUnitOfWork{}, libmocks.NewMockILogger(ctrl))
+	ctx := context.Background()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := service.PlaceOrder(ctx, request); err != nil {
+			b.Fatal(err)
+		}
+	}
+	b.ReportMetric(float64(productRepo.roundTrips)/float64(b.N), "roundtrips/op")
+}
+
+// fakeTxKey marks contexts handed out by fakeUnitOfWork.
+type fakeTxKey struct{}
+
+// fakeUnitOfWork is an in-memory UnitOfWork. It runs fn with a context marked by fakeTxKey
+// when tagContext is set, or with the caller's context otherwise, and counts how many units
+// were committed and aborted. commitErr, if set, is returned instead of committing.
+type fakeUnitOfWork struct {
+	tagContext bool
+	commitErr  error
+	retries    int // Number of times a failing callback is run again, as for transient errors
+	commits    int
+	aborts     int
+}
+
+func (u *fakeUnitOfWork) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	if u.tagContext {
+		ctx = context.WithValue(ctx, fakeTxKey{}, true)
+	}
+	err := fn(ctx)
+	for retry := 0; err != nil && retry < u.retries; retry++ {
+		u.aborts++
+		err = fn(ctx)
+	}
+	if err != nil {
+		u.aborts++
+		return err
+	}
+	if u.commitErr != nil {
+		u.aborts++
+		return u.commitErr
+	}
+	u.commits++
+	return nil
+}
+
+// inTransaction matches contexts handed out by a fakeUnitOfWork with tagContext set.
+func inTransaction() gomock.Matcher {
+	return gomock.Cond(func(x any) bool {
+		ctx, ok := x.(context.Context)
+		return ok && ctx.Value(fakeTxKey{}) == true
+	})
+}
+
+// untrackedStock returns a stock repository for which no product's stock is tracked,
+// so every reservation succeeds.
+func untrackedStock(ctrl *gomock.Controller) *mocks.MockStockRepository {
+	stockRepo := mocks.NewMockStockRepository(ctrl)
+	stockRepo.EXPECT().ReserveStock(gomock.Any(), gomock.Any(), gomock.Any()).Return(true, nil).AnyTimes()
+	return stockRepo
+}
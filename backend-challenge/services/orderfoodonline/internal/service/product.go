@@ -6,39 +6,303 @@ import (
 	"library/logger"
 	"orderfoodonline/internal/repository"
 	"orderfoodonline/internal/repository/models"
+	"orderfoodonline/internal/tracing"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+const (
+	// DefaultProductPageSize is the number of products returned when no limit is given.
+	DefaultProductPageSize = 20
+	// MaxProductPageSize is the largest number of products returned in a single page.
+	MaxProductPageSize = 100
 )
 
 // ProductResponse is the response model for a product (for Swagger docs).
 type ProductResponse struct {
-	ID       string  `json:"id" example:"10"`
-	Name     string  `json:"name" example:"Chicken Waffle"`
-	Price    float64 `json:"price" example:"1"`
-	Category string  `json:"category" example:"Waffle"`
+	ID          string               `json:"id" example:"10"`
+	Name        string               `json:"name" example:"Chicken Waffle"`
+	Price       float64              `json:"price" example:"1"`
+	Category    string               `json:"category" example:"Waffle"`
+	CategoryID  string               `json:"categoryId" example:"waffle"`
+	Description string               `json:"description" example:"Crispy fried chicken on a Belgian waffle"`
+	Image       *models.ProductImage `json:"image"`
+	Allergens   []string             `json:"allergens" example:"gluten,eggs"`
+	DietaryTags []string             `json:"dietaryTags" example:"halal"`
+	Available   bool                 `json:"available" example:"true"`
+	Version     int64                `json:"version" example:"3"`
 }
 
 type productService struct {
-	repo   repository.ProductRepository
-	logger logger.ILogger
+	repo         repository.ProductRepository
+	categoryRepo repository.CategoryRepository
+	logger       logger.ILogger
 }
 
 // NewProductService creates a new ProductService.
-func NewProductService(repo repository.ProductRepository, logger logger.ILogger) ProductService {
-	return &productService{repo: repo, logger: logger}
+func NewProductService(repo repository.ProductRepository, categoryRepo repository.CategoryRepository,
+	logger logger.ILogger) ProductService {
+	return &productService{repo: repo, categoryRepo: categoryRepo, logger: logger}
 }
 
-// ListProducts retrieves all available products from the catalog.
-// Returns a slice of Product models or an error if the operation fails.
-func (s *productService) ListProducts(ctx context.Context) ([]models.Product, error) {
-	products, err := s.repo.ListProducts(ctx)
+// ListProducts retrieves a page of catalog products matching the filter. Products that are
+// not available for ordering are included; deleted products are not.
+// A zero limit falls back to DefaultProductPageSize and larger limits are capped at MaxProductPageSize.
+// NextCursor is set only when more products follow this page.
+func (s *productService) ListProducts(ctx context.Context, filter models.ProductFilter) (_ *models.ProductListResponse, err error) {
+	ctx, span := tracing.Start(ctx, "ProductService.ListProducts")
+	defer func() { tracing.End(span, err) }()
+	if filter.Limit < 0 || filter.MinPrice < 0 || filter.MaxPrice < 0 {
+		return nil, errors.New(InvalidProductFilter)
+	}
+	if filter.MinPrice > 0 && filter.MaxPrice > 0 && filter.MinPrice > filter.MaxPrice {
+		return nil, errors.New(InvalidProductFilter)
+	}
+	switch filter.Sort {
+	case "", models.ProductSortName, models.ProductSortNameDesc, models.ProductSortPrice, models.ProductSortPriceDesc:
+	default:
+		return nil, errors.New(InvalidProductFilter)
+	}
+	var ok bool
+	if filter.DietaryTags, ok = normalizeTags(filter.DietaryTags, models.IsDietaryTag); !ok {
+		return nil, errors.New(InvalidProductFilter)
+	}
+	if filter.ExcludeAllergens, ok = normalizeTags(filter.ExcludeAllergens, models.IsAllergen); !ok {
+		return nil, errors.New(InvalidProductFilter)
+	}
+	if filter.After != nil && filter.After.Sort != filter.Sort {
+		// A cursor only makes sense in the sort order it was issued for
+		return nil, errors.New(InvalidProductFilter)
+	}
+	if filter.Limit == 0 {
+		filter.Limit = DefaultProductPageSize
+	}
+	if filter.Limit > MaxProductPageSize {
+		filter.Limit = MaxProductPageSize
+	}
+
+	// Fetch one extra product to learn whether another page follows
+	query := filter
+	query.Limit = filter.Limit + 1
+	products, total, err := s.repo.ListProducts(ctx, query)
 	if err != nil {
-		s.logger.Error("%s: %v", ProductListingError, err)
+		s.logger.WithContext(ctx).Error("%s: %v", ProductListingError, err)
 		return nil, errors.New(ProductListingError)
 	}
-	return products, nil
+
+	resp := &models.ProductListResponse{Products: products, Total: total}
+	if len(products) > filter.Limit {
+		resp.Products = products[:filter.Limit]
+		resp.NextCursor = models.NewProductCursor(filter.Sort, resp.Products[filter.Limit-1]).Encode()
+	}
+	return resp, nil
 }
 
 // FindProductByID fetches a single product by its unique ID.
 // Returns the Product model or an error if not found or on failure.
-func (s *productService) FindProductByID(ctx context.Context, id string) (*models.Product, error) {
+func (s *productService) FindProductByID(ctx context.Context, id string) (_ *models.Product, err error) {
+	ctx, span := tracing.Start(ctx, "ProductService.FindProductByID")
+	defer func() { tracing.End(span, err) }()
+	span.SetAttributes(attribute.String("product.id", id))
 	return s.repo.FindProductByID(ctx, id)
 }
+
+// CreateProduct validates and adds a new product to the catalog.
+// A missing ID is generated; an ID that is already taken is rejected.
+func (s *productService) CreateProduct(ctx context.Context, req *models.ProductRequest) (_ *models.Product, err error) {
+	ctx, span := tracing.Start(ctx, "ProductService.CreateProduct")
+	defer func() { tracing.End(span, err) }()
+	product := productFromRequest(strings.TrimSpace(req.ID), req)
+	product.Version = 1
+	if err := s.validateProduct(ctx, product); err != nil {
+		return nil, err
+	}
+	if product.ID == "" {
+		product.ID = uuid.New().String()
+	}
+	product.UpdatedAt = time.Now().Unix()
+
+	created, err := s.repo.CreateProduct(ctx, product)
+	if err != nil {
+		s.logger.WithContext(ctx).Error("%s: %v", SaveProductError, err)
+		return nil, errors.New(SaveProductError)
+	}
+	if !created {
+		return nil, errors.New(ProductAlreadyExists)
+	}
+	return product, nil
+}
+
+// ReplaceProduct validates and overwrites the editable fields of an existing product.
+// Fields missing from the request are cleared, except availability which defaults to true.
+func (s *productService) ReplaceProduct(ctx context.Context, id string, req *models.ProductRequest) (_ *models.Product, err error) {
+	ctx, span := tracing.Start(ctx, "ProductService.ReplaceProduct")
+	defer func() { tracing.End(span, err) }()
+	span.SetAttributes(attribute.String("product.id", id))
+	product := productFromRequest(id, req)
+	if err := s.validateProduct(ctx, product); err != nil {
+		return nil, err
+	}
+	return s.saveProduct(ctx, product)
+}
+
+// PatchProduct validates and applies the fields set in the request to an existing product.
+func (s *productService) PatchProduct(ctx context.Context, id string, req *models.ProductPatchRequest) (_ *models.Product, err error) {
+	ctx, span := tracing.Start(ctx, "ProductService.PatchProduct")
+	defer func() { tracing.End(span, err) }()
+	span.SetAttributes(attribute.String("product.id", id))
+	product, err := s.repo.FindProductByID(ctx, id)
+	if err != nil {
+		s.logger.WithContext(ctx).Error("%s: %v", FindProductByIDError, err)
+		return nil, errors.New(SaveProductError)
+	}
+	if product == nil {
+		return nil, errors.New(ProductNotFound + id)
+	}
+
+	if req.Name != nil {
+		product.Name = strings.TrimSpace(*req.Name)
+	}
+	if req.Price != nil {
+		product.Price = *req.Price
+	}
+	if req.CategoryID != nil {
+		product.CategoryID = strings.TrimSpace(*req.CategoryID)
+	}
+	if req.Description != nil {
+		product.Description = strings.TrimSpace(*req.Description)
+	}
+	if req.Image != nil {
+		product.Image = trimProductImage(req.Image)
+	}
+	if req.Allergens != nil {
+		product.Allergens = *req.Allergens
+	}
+	if req.DietaryTags != nil {
+		product.DietaryTags = *req.DietaryTags
+	}
+	if req.Available != nil {
+		product.Available = *req.Available
+	}
+	if err := s.validateProduct(ctx, product); err != nil {
+		return nil, err
+	}
+	return s.saveProduct(ctx, product)
+}
+
+// DeleteProduct soft deletes a product. It disappears from the catalog and can no longer
+// be ordered, but orders that already reference it keep their snapshot.
+func (s *productService) DeleteProduct(ctx context.Context, id string) (err error) {
+	ctx, span := tracing.Start(ctx, "ProductService.DeleteProduct")
+	defer func() { tracing.End(span, err) }()
+	span.SetAttributes(attribute.String("product.id", id))
+	deleted, err := s.repo.DeleteProduct(ctx, id)
+	if err != nil {
+		s.logger.WithContext(ctx).Error("%s: %v", DeleteProductError, err)
+		return errors.New(DeleteProductError)
+	}
+	if !deleted {
+		return errors.New(ProductNotFound + id)
+	}
+	return nil
+}
+
+// saveProduct persists the editable fields of an existing product.
+func (s *productService) saveProduct(ctx context.Context, product *models.Product) (*models.Product, error) {
+	product.UpdatedAt = time.Now().Unix()
+	updated, err := s.repo.UpdateProduct(ctx, product)
+	if err != nil {
+		s.logger.WithContext(ctx).Error("%s: %v", SaveProductError, err)
+		return nil, errors.New(SaveProductError)
+	}
+	if updated == nil {
+		return nil, errors.New(ProductNotFound + product.ID)
+	}
+	return updated, nil
+}
+
+// productFromRequest builds the product described by a create or replace request.
+func productFromRequest(id string, req *models.ProductRequest) *models.Product {
+	product := &models.Product{
+		ID:          id,
+		Name:        strings.TrimSpace(req.Name),
+		Price:       req.Price,
+		CategoryID:  strings.TrimSpace(req.CategoryID),
+		Description: strings.TrimSpace(req.Description),
+		Image:       trimProductImage(req.Image),
+		Allergens:   req.Allergens,
+		DietaryTags: req.DietaryTags,
+		Available:   true,
+	}
+	if req.Available != nil {
+		product.Available = *req.Available
+	}
+	return product
+}
+
+// trimProductImage trims the image URLs, returning nil when none is set.
+func trimProductImage(image *models.ProductImage) *models.ProductImage {
+	if image == nil {
+		return nil
+	}
+	trimmed := &models.ProductImage{
+		Thumbnail: strings.TrimSpace(image.Thumbnail),
+		Mobile:    strings.TrimSpace(image.Mobile),
+		Tablet:    strings.TrimSpace(image.Tablet),
+		Desktop:   strings.TrimSpace(image.Desktop),
+	}
+	if *trimmed == (models.ProductImage{}) {
+		return nil
+	}
+	return trimmed
+}
+
+// normalizeTags lower-cases and de-duplicates tags, keeping their first-seen order.
+// It reports false if any tag is rejected by valid.
+func normalizeTags(tags []string, valid func(string) bool) ([]string, bool) {
+	if len(tags) == 0 {
+		return nil, true
+	}
+	normalized := make([]string, 0, len(tags))
+	seen := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if !valid(tag) {
+			return nil, false
+		}
+		if !seen[tag] {
+			seen[tag] = true
+			normalized = append(normalized, tag)
+		}
+	}
+	return normalized, true
+}
+
+// validateProduct checks that a product has a name, a positive price in whole cents, an active category
+// and known allergen and dietary tags. It copies the category's display name onto the
+// product and normalizes its tags.
+func (s *productService) validateProduct(ctx context.Context, product *models.Product) error {
+	if product.Name == "" || product.Price <= 0 || !models.IsWholeCents(product.Price) || product.CategoryID == "" {
+		return errors.New(InvalidProductDetails)
+	}
+	var ok bool
+	if product.Allergens, ok = normalizeTags(product.Allergens, models.IsAllergen); !ok {
+		return errors.New(InvalidProductDetails)
+	}
+	if product.DietaryTags, ok = normalizeTags(product.DietaryTags, models.IsDietaryTag); !ok {
+		return errors.New(InvalidProductDetails)
+	}
+	category, err := s.categoryRepo.FindCategoryByID(ctx, product.CategoryID)
+	if err != nil {
+		s.logger.WithContext(ctx).Error("%s: %v", FindCategoryByIDError, err)
+		return errors.New(SaveProductError)
+	}
+	if category == nil || !category.Active {
+		return errors.New(InvalidProductDetails)
+	}
+	product.Category = category.Name
+	return nil
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	libmocks "library/logger/mocks"
+	"strings"
 	"testing"
 
 	"orderfoodonline/internal/repository/mocks"
@@ -23,7 +24,7 @@ func TestNewProductService(t *testing.T) {
 	mockProductRepo := mocks.NewMockProductRepository(ctrl)
 	mockLogger := libmocks.NewMockILogger(ctrl)
 	// When: Creating a new product service
-	service := NewProductService(mockProductRepo, mockLogger)
+	service := NewProductService(mockProductRepo, mocks.NewMockCategoryRepository(ctrl), mockLogger)
 
 	// Then: Service should be created successfully
 	assert.NotNil(t, service)
@@ -39,7 +40,7 @@ func TestProductService_ListProducts_Success(t *testing.T) {
 	mockProductRepo := mocks.NewMockProductRepository(ctrl)
 	mockLogger := libmocks.NewMockILogger(ctrl)
 	// When: Creating a new product service
-	service := NewProductService(mockProductRepo, mockLogger)
+	service := NewProductService(mockProductRepo, mocks.NewMockCategoryRepository(ctrl), mockLogger)
 
 	expectedProducts := []models.Product{
 		{ID: "1", Name: "Chicken Waffle", Price: 12.99, Category: "Waffle"},
@@ -49,14 +50,17 @@ func TestProductService_ListProducts_Success(t *testing.T) {
 	ctx := context.Background()
 
 	// Mock repository behavior
-	mockProductRepo.EXPECT().ListProducts(ctx).Return(expectedProducts, nil)
+	mockProductRepo.EXPECT().ListProducts(gomock.Any(), models.ProductFilter{Limit: DefaultProductPageSize + 1}).Return(expectedProducts, int64(2), nil)
 
 	// When: Listing products
-	products, err := service.ListProducts(ctx)
+	page, err := service.ListProducts(ctx, models.ProductFilter{})
 
 	// Then: Should return products without error
 	require.NoError(t, err)
+	products := page.Products
 	assert.Len(t, products, 2)
+	assert.Equal(t, int64(2), page.Total)
+	assert.Empty(t, page.NextCursor)
 	assert.Equal(t, expectedProducts[0].ID, products[0].ID)
 	assert.Equal(t, expectedProducts[0].Name, products[0].Name)
 	assert.Equal(t, expectedProducts[0].Price, products[0].Price)
@@ -72,21 +76,22 @@ func TestProductService_ListProducts_RepositoryError(t *testing.T) {
 	mockProductRepo := mocks.NewMockProductRepository(ctrl)
 	mockLogger := libmocks.NewMockILogger(ctrl)
 	// When: Creating a new product service
-	service := NewProductService(mockProductRepo, mockLogger)
+	service := NewProductService(mockProductRepo, mocks.NewMockCategoryRepository(ctrl), mockLogger)
 
 	expectedError := errors.New("database connection failed")
 	ctx := context.Background()
 
 	// Mock repository behavior to return error
+	mockLogger.EXPECT().WithContext(gomock.Any()).Return(mockLogger).AnyTimes()
 	mockLogger.EXPECT().Error(gomock.Any(), gomock.Any())
-	mockProductRepo.EXPECT().ListProducts(ctx).Return([]models.Product{}, expectedError)
+	mockProductRepo.EXPECT().ListProducts(gomock.Any(), gomock.Any()).Return([]models.Product{}, int64(0), expectedError)
 
 	// When: Listing products
-	products, err := service.ListProducts(ctx)
+	page, err := service.ListProducts(ctx, models.ProductFilter{})
 
-	// Then: Should return error and empty products
+	// Then: Should return error and no page
 	require.Error(t, err)
-	assert.Empty(t, products)
+	assert.Nil(t, page)
 	assert.Equal(t, ProductListingError, err.Error())
 }
 
@@ -99,19 +104,96 @@ func TestProductService_ListProducts_EmptyResult(t *testing.T) {
 	mockProductRepo := mocks.NewMockProductRepository(ctrl)
 	mockLogger := libmocks.NewMockILogger(ctrl)
 	// When: Creating a new product service
-	service := NewProductService(mockProductRepo, mockLogger)
+	service := NewProductService(mockProductRepo, mocks.NewMockCategoryRepository(ctrl), mockLogger)
 
 	ctx := context.Background()
 
 	// Mock repository behavior to return empty list
-	mockProductRepo.EXPECT().ListProducts(ctx).Return([]models.Product{}, nil)
+	mockProductRepo.EXPECT().ListProducts(gomock.Any(), gomock.Any()).Return([]models.Product{}, int64(0), nil)
 
 	// When: Listing products
-	products, err := service.ListProducts(ctx)
+	page, err := service.ListProducts(ctx, models.ProductFilter{})
 
 	// Then: Should return empty list without error
 	require.NoError(t, err)
-	assert.Empty(t, products)
+	assert.Empty(t, page.Products)
+	assert.Empty(t, page.NextCursor)
+}
+
+func TestProductService_ListProducts_Pagination(t *testing.T) {
+	// Given: A product service and more matching products than fit on a page
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockProductRepo := mocks.NewMockProductRepository(ctrl)
+	service := NewProductService(mockProductRepo, mocks.NewMockCategoryRepository(ctrl), libmocks.NewMockILogger(ctrl))
+	ctx := context.Background()
+
+	filter := models.ProductFilter{CategoryID: "pizza", Sort: models.ProductSortPrice, Limit: 2}
+	mockProductRepo.EXPECT().ListProducts(gomock.Any(), models.ProductFilter{CategoryID: "pizza", Sort: models.ProductSortPrice, Limit: 3}).
+		Return([]models.Product{
+			{ID: "10", Name: "Margherita Pizza", Price: 12.99},
+			{ID: "12", Name: "Vegetarian Pizza", Price: 13.99},
+			{ID: "11", Name: "Pepperoni Pizza", Price: 14.99},
+		}, int64(3), nil)
+
+	// When: Listing the first page
+	page, err := service.ListProducts(ctx, filter)
+
+	// Then: The page is trimmed to the limit and a cursor points after its last product
+	require.NoError(t, err)
+	require.Len(t, page.Products, 2)
+	assert.Equal(t, int64(3), page.Total)
+	require.NotEmpty(t, page.NextCursor)
+	cursor, err := models.DecodeProductCursor(page.NextCursor)
+	require.NoError(t, err)
+	assert.Equal(t, &models.ProductCursor{Sort: models.ProductSortPrice, ID: "12", Price: 13.99}, cursor)
+
+	// When: Listing the next page with the cursor
+	filter.After = cursor
+	mockProductRepo.EXPECT().ListProducts(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, f models.ProductFilter) ([]models.Product, int64, error) {
+			assert.Equal(t, cursor, f.After)
+			return []models.Product{{ID: "11", Name: "Pepperoni Pizza", Price: 14.99}}, 3, nil
+		})
+	page, err = service.ListProducts(ctx, filter)
+
+	// Then: The last page has no cursor
+	require.NoError(t, err)
+	assert.Len(t, page.Products, 1)
+	assert.Empty(t, page.NextCursor)
+}
+
+func TestProductService_ListProducts_InvalidFilter(t *testing.T) {
+	tests := []struct {
+		name   string
+		filter models.ProductFilter
+	}{
+		{name: "negative limit", filter: models.ProductFilter{Limit: -1}},
+		{name: "negative price", filter: models.ProductFilter{MinPrice: -1}},
+		{name: "inverted price range", filter: models.ProductFilter{MinPrice: 10, MaxPrice: 5}},
+		{name: "unknown sort", filter: models.ProductFilter{Sort: "rating"}},
+		{name: "unknown dietary tag", filter: models.ProductFilter{DietaryTags: []string{"carnivore"}}},
+		{name: "unknown allergen", filter: models.ProductFilter{ExcludeAllergens: []string{"gluten", "love"}}},
+		{name: "cursor from another sort", filter: models.ProductFilter{Sort: models.ProductSortName,
+			After: &models.ProductCursor{Sort: models.ProductSortPrice, ID: "10"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given: A product service whose repository must not be called
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			service := NewProductService(mocks.NewMockProductRepository(ctrl), mocks.NewMockCategoryRepository(ctrl), libmocks.NewMockILogger(ctrl))
+
+			// When: Listing with an invalid filter
+			page, err := service.ListProducts(context.Background(), tt.filter)
+
+			// Then: It should be rejected
+			assert.Nil(t, page)
+			require.Error(t, err)
+			assert.Equal(t, InvalidProductFilter, err.Error())
+		})
+	}
 }
 
 func TestProductService_FindProductByID_Success(t *testing.T) {
@@ -123,7 +205,7 @@ func TestProductService_FindProductByID_Success(t *testing.T) {
 	mockProductRepo := mocks.NewMockProductRepository(ctrl)
 	mockLogger := libmocks.NewMockILogger(ctrl)
 	// When: Creating a new product service
-	service := NewProductService(mockProductRepo, mockLogger)
+	service := NewProductService(mockProductRepo, mocks.NewMockCategoryRepository(ctrl), mockLogger)
 
 	expectedProduct := &models.Product{
 		ID:       "1",
@@ -136,7 +218,7 @@ func TestProductService_FindProductByID_Success(t *testing.T) {
 	productID := "1"
 
 	// Mock repository behavior
-	mockProductRepo.EXPECT().FindProductByID(ctx, productID).Return(expectedProduct, nil)
+	mockProductRepo.EXPECT().FindProductByID(gomock.Any(), productID).Return(expectedProduct, nil)
 
 	// When: Finding product by ID
 	product, err := service.FindProductByID(ctx, productID)
@@ -159,13 +241,13 @@ func TestProductService_FindProductByID_NotFound(t *testing.T) {
 	mockProductRepo := mocks.NewMockProductRepository(ctrl)
 	mockLogger := libmocks.NewMockILogger(ctrl)
 	// When: Creating a new product service
-	service := NewProductService(mockProductRepo, mockLogger)
+	service := NewProductService(mockProductRepo, mocks.NewMockCategoryRepository(ctrl), mockLogger)
 
 	ctx := context.Background()
 	productID := "999"
 
 	// Mock repository behavior to return not found
-	mockProductRepo.EXPECT().FindProductByID(ctx, productID).Return(nil, errors.New("product not found"))
+	mockProductRepo.EXPECT().FindProductByID(gomock.Any(), productID).Return(nil, errors.New("product not found"))
 
 	// When: Finding product by ID
 	product, err := service.FindProductByID(ctx, productID)
@@ -185,14 +267,14 @@ func TestProductService_FindProductByID_RepositoryError(t *testing.T) {
 	mockProductRepo := mocks.NewMockProductRepository(ctrl)
 	mockLogger := libmocks.NewMockILogger(ctrl)
 	// When: Creating a new product service
-	service := NewProductService(mockProductRepo, mockLogger)
+	service := NewProductService(mockProductRepo, mocks.NewMockCategoryRepository(ctrl), mockLogger)
 
 	expectedError := errors.New("database connection failed")
 	ctx := context.Background()
 	productID := "1"
 
 	// Mock repository behavior to return error
-	mockProductRepo.EXPECT().FindProductByID(ctx, productID).Return(nil, expectedError)
+	mockProductRepo.EXPECT().FindProductByID(gomock.Any(), productID).Return(nil, expectedError)
 
 	// When: Finding product by ID
 	product, err := service.FindProductByID(ctx, productID)
@@ -212,13 +294,13 @@ func TestProductService_FindProductByID_EmptyID(t *testing.T) {
 	mockProductRepo := mocks.NewMockProductRepository(ctrl)
 	mockLogger := libmocks.NewMockILogger(ctrl)
 	// When: Creating a new product service
-	service := NewProductService(mockProductRepo, mockLogger)
+	service := NewProductService(mockProductRepo, mocks.NewMockCategoryRepository(ctrl), mockLogger)
 
 	ctx := context.Background()
 	emptyProductID := ""
 
 	// Mock repository behavior for empty ID
-	mockProductRepo.EXPECT().FindProductByID(ctx, emptyProductID).Return(nil, errors.New("invalid product ID"))
+	mockProductRepo.EXPECT().FindProductByID(gomock.Any(), emptyProductID).Return(nil, errors.New("invalid product ID"))
 
 	// When: Finding product with empty ID
 	product, err := service.FindProductByID(ctx, emptyProductID)
@@ -228,3 +310,321 @@ func TestProductService_FindProductByID_EmptyID(t *testing.T) {
 	assert.Nil(t, product)
 
 }
+
+func TestProductService_CreateProduct(t *testing.T) {
+	pizza := &models.Category{ID: "pizza", Name: "Pizza", Active: true}
+
+	tests := []struct {
+		name           string
+		req            models.ProductRequest
+		category       *models.Category
+		categoryErr    error
+		expectCategory bool
+		created        bool
+		repoErr        error
+		expectRepo     bool
+		expectedError  string
+	}{
+		{
+			name:           "valid product is created",
+			req:            models.ProductRequest{ID: " 42 ", Name: " Margherita Pizza ", Price: 12.99, CategoryID: " pizza "},
+			category:       pizza,
+			expectCategory: true,
+			created:        true,
+			expectRepo:     true,
+		},
+		{
+			name:          "empty name is rejected",
+			req:           models.ProductRequest{Name: "  ", Price: 12.99, CategoryID: "pizza"},
+			expectedError: InvalidProductDetails,
+		},
+		{
+			name:          "non-positive price is rejected",
+			req:           models.ProductRequest{Name: "Margherita Pizza", Price: 0, CategoryID: "pizza"},
+			expectedError: InvalidProductDetails,
+		},
+		{
+			name:          "price with more than two decimal places is rejected",
+			req:           models.ProductRequest{Name: "Margherita Pizza", Price: 12.999, CategoryID: "pizza"},
+			expectedError: InvalidProductDetails,
+		},
+		{
+			name:          "missing category is rejected",
+			req:           models.ProductRequest{Name: "Margherita Pizza", Price: 12.99},
+			expectedError: InvalidProductDetails,
+		},
+		{
+			name:           "unknown category is rejected",
+			req:            models.ProductRequest{Name: "Margherita Pizza", Price: 12.99, CategoryID: "waffles"},
+			expectCategory: true,
+			expectedError:  InvalidProductDetails,
+		},
+		{
+			name:           "inactive category is rejected",
+			req:            models.ProductRequest{Name: "Margherita Pizza", Price: 12.99, CategoryID: "pizza"},
+			category:       &models.Category{ID: "pizza", Name: "Pizza"},
+			expectCategory: true,
+			expectedError:  InvalidProductDetails,
+		},
+		{
+			name:           "category lookup error",
+			req:            models.ProductRequest{Name: "Margherita Pizza", Price: 12.99, CategoryID: "pizza"},
+			categoryErr:    errors.New("db error"),
+			expectCategory: true,
+			expectedError:  SaveProductError,
+		},
+		{
+			name:           "taken ID is rejected",
+			req:            models.ProductRequest{ID: "10", Name: "Margherita Pizza", Price: 12.99, CategoryID: "pizza"},
+			category:       pizza,
+			expectCategory: true,
+			created:        false,
+			expectRepo:     true,
+			expectedError:  ProductAlreadyExists,
+		},
+		{
+			name:           "repository error",
+			req:            models.ProductRequest{Name: "Margherita Pizza", Price: 12.99, CategoryID: "pizza"},
+			category:       pizza,
+			expectCategory: true,
+			repoErr:        errors.New("db error"),
+			expectRepo:     true,
+			expectedError:  SaveProductError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given: A product service with mock repositories
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			mockProductRepo := mocks.NewMockProductRepository(ctrl)
+			mockCategoryRepo := mocks.NewMockCategoryRepository(ctrl)
+			mockLogger := libmocks.NewMockILogger(ctrl)
+			mockLogger.EXPECT().WithContext(gomock.Any()).Return(mockLogger).AnyTimes()
+			mockLogger.EXPECT().Error(gomock.Any(), gomock.Any()).AnyTimes()
+			service := NewProductService(mockProductRepo, mockCategoryRepo, mockLogger)
+
+			if tt.expectCategory {
+				mockCategoryRepo.EXPECT().FindCategoryByID(gomock.Any(), strings.TrimSpace(tt.req.CategoryID)).
+					Return(tt.category, tt.categoryErr)
+			}
+			if tt.expectRepo {
+				mockProductRepo.EXPECT().CreateProduct(gomock.Any(), gomock.Any()).Return(tt.created, tt.repoErr)
+			}
+
+			// When: Creating the product
+			product, err := service.CreateProduct(context.Background(), &tt.req)
+
+			// Then: Valid products are stored trimmed at version 1 with the category's display name
+			if tt.expectedError != "" {
+				require.Error(t, err)
+				assert.Equal(t, tt.expectedError, err.Error())
+				assert.Nil(t, product)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "42", product.ID)
+			assert.Equal(t, "Margherita Pizza", product.Name)
+			assert.Equal(t, "pizza", product.CategoryID)
+			assert.Equal(t, "Pizza", product.Category)
+			assert.Equal(t, int64(1), product.Version)
+		})
+	}
+}
+
+func TestProductService_CreateProduct_Details(t *testing.T) {
+	// Given: A request with menu details and tags in mixed case
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockProductRepo := mocks.NewMockProductRepository(ctrl)
+	mockCategoryRepo := mocks.NewMockCategoryRepository(ctrl)
+	service := NewProductService(mockProductRepo, mockCategoryRepo, libmocks.NewMockILogger(ctrl))
+	ctx := context.Background()
+
+	mockCategoryRepo.EXPECT().FindCategoryByID(gomock.Any(), "salads").
+		Return(&models.Category{ID: "salads", Name: "Salads", Active: true}, nil)
+	mockProductRepo.EXPECT().CreateProduct(gomock.Any(), gomock.Any()).Return(true, nil)
+
+	req := &models.ProductRequest{
+		Name:        "Caesar Salad",
+		Price:       7.5,
+		CategoryID:  "salads",
+		Description: " Romaine, parmesan and croutons ",
+		Image:       &models.ProductImage{Thumbnail: " https://cdn.example.com/caesar-thumb.jpg "},
+		Allergens:   []string{"Gluten", "dairy", "gluten"},
+		DietaryTags: []string{" Vegetarian "},
+	}
+
+	// When: Creating the product
+	product, err := service.CreateProduct(ctx, req)
+
+	// Then: Details are trimmed, tags normalized and the product is available by default
+	require.NoError(t, err)
+	assert.Equal(t, "Romaine, parmesan and croutons", product.Description)
+	assert.Equal(t, &models.ProductImage{Thumbnail: "https://cdn.example.com/caesar-thumb.jpg"}, product.Image)
+	assert.Equal(t, []string{"gluten", "dairy"}, product.Allergens)
+	assert.Equal(t, []string{"vegetarian"}, product.DietaryTags)
+	assert.True(t, product.Available)
+
+	// When: Creating a product with an unknown dietary tag
+	req.DietaryTags = []string{"carnivore"}
+	_, err = service.CreateProduct(ctx, req)
+
+	// Then: It should fail validation
+	assert.Equal(t, InvalidProductDetails, err.Error())
+}
+
+func TestProductService_CreateProduct_GeneratesID(t *testing.T) {
+	// Given: A product service and a request without ID
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockProductRepo := mocks.NewMockProductRepository(ctrl)
+	mockCategoryRepo := mocks.NewMockCategoryRepository(ctrl)
+	service := NewProductService(mockProductRepo, mockCategoryRepo, libmocks.NewMockILogger(ctrl))
+
+	mockCategoryRepo.EXPECT().FindCategoryByID(gomock.Any(), "salads").
+		Return(&models.Category{ID: "salads", Name: "Salads", Active: true}, nil)
+	mockProductRepo.EXPECT().CreateProduct(gomock.Any(), gomock.Any()).Return(true, nil)
+
+	// When: Creating the product
+	product, err := service.CreateProduct(context.Background(),
+		&models.ProductRequest{Name: "Caesar Salad", Price: 7.5, CategoryID: "salads"})
+
+	// Then: An ID should be generated
+	require.NoError(t, err)
+	assert.NotEmpty(t, product.ID)
+}
+
+func TestProductService_ReplaceProduct(t *testing.T) {
+	// Given: A product service with mock repository
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockProductRepo := mocks.NewMockProductRepository(ctrl)
+	mockCategoryRepo := mocks.NewMockCategoryRepository(ctrl)
+	service := NewProductService(mockProductRepo, mockCategoryRepo, libmocks.NewMockILogger(ctrl))
+	ctx := context.Background()
+
+	updated := &models.Product{ID: "10", Name: "Margherita Pizza", Price: 13.49, Category: "Pizza", CategoryID: "pizza", Version: 2}
+	mockCategoryRepo.EXPECT().FindCategoryByID(gomock.Any(), "pizza").
+		Return(&models.Category{ID: "pizza", Name: "Pizza", Active: true}, nil).Times(2)
+	mockProductRepo.EXPECT().UpdateProduct(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, p *models.Product) (*models.Product, error) {
+			assert.Equal(t, "10", p.ID)
+			assert.Equal(t, 13.49, p.Price)
+			assert.Equal(t, "Pizza", p.Category)
+			assert.NotZero(t, p.UpdatedAt)
+			return updated, nil
+		})
+	mockProductRepo.EXPECT().UpdateProduct(gomock.Any(), gomock.Any()).Return(nil, nil)
+
+	// When: Replacing an existing product
+	product, err := service.ReplaceProduct(ctx, "10", &models.ProductRequest{Name: "Margherita Pizza", Price: 13.49, CategoryID: "pizza"})
+
+	// Then: The updated product should be returned
+	require.NoError(t, err)
+	assert.Equal(t, updated, product)
+
+	// When: Replacing a missing product
+	product, err = service.ReplaceProduct(ctx, "99", &models.ProductRequest{Name: "Margherita Pizza", Price: 13.49, CategoryID: "pizza"})
+
+	// Then: It should not be found
+	assert.Nil(t, product)
+	assert.Equal(t, ProductNotFound+"99", err.Error())
+}
+
+func TestProductService_PatchProduct(t *testing.T) {
+	// Given: A product service and an existing product
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockProductRepo := mocks.NewMockProductRepository(ctrl)
+	mockCategoryRepo := mocks.NewMockCategoryRepository(ctrl)
+	service := NewProductService(mockProductRepo, mockCategoryRepo, libmocks.NewMockILogger(ctrl))
+	ctx := context.Background()
+
+	existing := &models.Product{ID: "10", Name: "Margherita Pizza", Price: 12.99, Category: "Pizza", CategoryID: "pizza", Version: 1}
+	price := 14.99
+	mockProductRepo.EXPECT().FindProductByID(gomock.Any(), "10").Return(existing, nil)
+	mockCategoryRepo.EXPECT().FindCategoryByID(gomock.Any(), "pizza").Return(&models.Category{ID: "pizza", Name: "Pizza", Active: true}, nil)
+	mockProductRepo.EXPECT().UpdateProduct(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, p *models.Product) (*models.Product, error) { return p, nil })
+
+	// When: Patching only the price
+	product, err := service.PatchProduct(ctx, "10", &models.ProductPatchRequest{Price: &price})
+
+	// Then: Only the price should change
+	require.NoError(t, err)
+	assert.Equal(t, "Margherita Pizza", product.Name)
+	assert.Equal(t, "Pizza", product.Category)
+	assert.Equal(t, 14.99, product.Price)
+}
+
+func TestProductService_PatchProduct_Errors(t *testing.T) {
+	// Given: A product service with mock repository
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockProductRepo := mocks.NewMockProductRepository(ctrl)
+	service := NewProductService(mockProductRepo, mocks.NewMockCategoryRepository(ctrl), libmocks.NewMockILogger(ctrl))
+	ctx := context.Background()
+
+	negative, fractionalCents := -1.0, 12.995
+	mockProductRepo.EXPECT().FindProductByID(gomock.Any(), "99").Return(nil, nil)
+	mockProductRepo.EXPECT().FindProductByID(gomock.Any(), "10").Return(&models.Product{ID: "10", Name: "Margherita Pizza", Price: 12.99, Category: "Pizza", CategoryID: "pizza"}, nil).Times(2)
+
+	// When: Patching a missing product
+	_, err := service.PatchProduct(ctx, "99", &models.ProductPatchRequest{Price: &negative})
+
+	// Then: It should not be found
+	assert.Equal(t, ProductNotFound+"99", err.Error())
+
+	// When: Patching in an invalid price
+	_, err = service.PatchProduct(ctx, "10", &models.ProductPatchRequest{Price: &negative})
+
+	// Then: The result should fail validation without being saved
+	assert.Equal(t, InvalidProductDetails, err.Error())
+
+	// When: Patching in a price with fractions of a cent
+	_, err = service.PatchProduct(ctx, "10", &models.ProductPatchRequest{Price: &fractionalCents})
+
+	// Then: The result should fail validation without being saved
+	assert.Equal(t, InvalidProductDetails, err.Error())
+}
+
+func TestProductService_DeleteProduct(t *testing.T) {
+	tests := []struct {
+		name          string
+		deleted       bool
+		repoErr       error
+		expectedError string
+	}{
+		{name: "deleted", deleted: true},
+		{name: "not found", expectedError: ProductNotFound + "10"},
+		{name: "repository error", repoErr: errors.New("db error"), expectedError: DeleteProductError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given: A product service with mock repository
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			mockProductRepo := mocks.NewMockProductRepository(ctrl)
+			mockLogger := libmocks.NewMockILogger(ctrl)
+			mockLogger.EXPECT().WithContext(gomock.Any()).Return(mockLogger).AnyTimes()
+			mockLogger.EXPECT().Error(gomock.Any(), gomock.Any()).AnyTimes()
+			service := NewProductService(mockProductRepo, mocks.NewMockCategoryRepository(ctrl), mockLogger)
+
+			mockProductRepo.EXPECT().DeleteProduct(gomock.Any(), "10").Return(tt.deleted, tt.repoErr)
+
+			// When: Deleting the product
+			err := service.DeleteProduct(context.Background(), "10")
+
+			// Then: The repository outcome should be mapped to the service errors
+			if tt.expectedError == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Equal(t, tt.expectedError, err.Error())
+		})
+	}
+}
@@ -0,0 +1,82 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"library/logger"
+	"orderfoodonline/internal/repository"
+	"orderfoodonline/internal/repository/models"
+)
+
+type stockService struct {
+	repo        repository.StockRepository
+	productRepo repository.ProductRepository
+	logger      logger.ILogger
+}
+
+// NewStockService creates a new StockService.
+func NewStockService(repo repository.StockRepository, productRepo repository.ProductRepository,
+	logger logger.ILogger) StockService {
+	return &stockService{repo: repo, productRepo: productRepo, logger: logger}
+}
+
+// GetStock fetches the stock of a product.
+func (s *stockService) GetStock(ctx context.Context, productID string) (*models.Stock, error) {
+	if err := s.checkProduct(ctx, productID, FindStockError); err != nil {
+		return nil, err
+	}
+	stock, err := s.repo.FindStockByProductID(ctx, productID)
+	if err != nil {
+		s.logger.WithContext(ctx).Error("%s: %v", FindStockError, err)
+		return nil, errors.New(FindStockError)
+	}
+	if stock == nil {
+		return nil, errors.New(StockNotTracked)
+	}
+	return stock, nil
+}
+
+// SetStock overwrites the stock of a product, for instance after a stocktake.
+func (s *stockService) SetStock(ctx context.Context, productID string, quantity int64) (*models.Stock, error) {
+	if quantity < 0 {
+		return nil, errors.New(InvalidStockQuantity)
+	}
+	if err := s.checkProduct(ctx, productID, SaveStockError); err != nil {
+		return nil, err
+	}
+	stock, err := s.repo.SetStock(ctx, productID, quantity)
+	if err != nil {
+		s.logger.WithContext(ctx).Error("%s: %v", SaveStockError, err)
+		return nil, errors.New(SaveStockError)
+	}
+	return stock, nil
+}
+
+// Restock adds a delivery of quantity units to the stock of a product.
+func (s *stockService) Restock(ctx context.Context, productID string, quantity int64) (*models.Stock, error) {
+	if quantity <= 0 {
+		return nil, errors.New(InvalidStockQuantity)
+	}
+	if err := s.checkProduct(ctx, productID, SaveStockError); err != nil {
+		return nil, err
+	}
+	stock, err := s.repo.AddStock(ctx, productID, quantity)
+	if err != nil {
+		s.logger.WithContext(ctx).Error("%s: %v", SaveStockError, err)
+		return nil, errors.New(SaveStockError)
+	}
+	return stock, nil
+}
+
+// checkProduct makes sure the product exists, reporting lookup failures as failedErr.
+func (s *stockService) checkProduct(ctx context.Context, productID, failedErr string) error {
+	product, err := s.productRepo.FindProductByID(ctx, productID)
+	if err != nil {
+		s.logger.WithContext(ctx).Error("%s: %v", FindProductByIDError, err)
+		return errors.New(failedErr)
+	}
+	if product == nil {
+		return errors.New(ProductNotFound + productID)
+	}
+	return nil
+}
@@ -0,0 +1,122 @@
+package service
+
+import (
+	"context"
+	"errors"
+	libmocks "library/logger/mocks"
+	"testing"
+
+	"orderfoodonline/internal/repository/mocks"
+	"orderfoodonline/internal/repository/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestStockService_GetStock(t *testing.T) {
+	tests := []struct {
+		name          string
+		product       *models.Product
+		stock         *models.Stock
+		stockErr      error
+		expectStock   bool
+		expectedError string
+	}{
+		{
+			name:        "tracked stock is returned",
+			product:     &models.Product{ID: "10"},
+			stock:       &models.Stock{ProductID: "10", Quantity: 4},
+			expectStock: true,
+		},
+		{
+			name:          "unknown product",
+			expectedError: ProductNotFound + "10",
+		},
+		{
+			name:          "untracked stock",
+			product:       &models.Product{ID: "10"},
+			expectStock:   true,
+			expectedError: StockNotTracked,
+		},
+		{
+			name:          "repository error",
+			product:       &models.Product{ID: "10"},
+			stockErr:      errors.New("db error"),
+			expectStock:   true,
+			expectedError: FindStockError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given: A stock service with mock repositories
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			mockStockRepo := mocks.NewMockStockRepository(ctrl)
+			mockProductRepo := mocks.NewMockProductRepository(ctrl)
+			mockLogger := libmocks.NewMockILogger(ctrl)
+			mockLogger.EXPECT().WithContext(gomock.Any()).Return(mockLogger).AnyTimes()
+			mockLogger.EXPECT().Error(gomock.Any(), gomock.Any()).AnyTimes()
+			service := NewStockService(mockStockRepo, mockProductRepo, mockLogger)
+
+			mockProductRepo.EXPECT().FindProductByID(gomock.Any(), "10").Return(tt.product, nil)
+			if tt.expectStock {
+				mockStockRepo.EXPECT().FindStockByProductID(gomock.Any(), "10").Return(tt.stock, tt.stockErr)
+			}
+
+			// When: Getting the stock
+			stock, err := service.GetStock(context.Background(), "10")
+
+			// Then: The stock or the matching error is returned
+			if tt.expectedError != "" {
+				require.Error(t, err)
+				assert.Equal(t, tt.expectedError, err.Error())
+				assert.Nil(t, stock)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.stock, stock)
+		})
+	}
+}
+
+func TestStockService_SetStockAndRestock(t *testing.T) {
+	// Given: A stock service and an existing product
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockStockRepo := mocks.NewMockStockRepository(ctrl)
+	mockProductRepo := mocks.NewMockProductRepository(ctrl)
+	service := NewStockService(mockStockRepo, mockProductRepo, libmocks.NewMockILogger(ctrl))
+	ctx := context.Background()
+
+	mockProductRepo.EXPECT().FindProductByID(ctx, "10").Return(&models.Product{ID: "10"}, nil).Times(2)
+	mockStockRepo.EXPECT().SetStock(ctx, "10", int64(0)).Return(&models.Stock{ProductID: "10"}, nil)
+	mockStockRepo.EXPECT().AddStock(ctx, "10", int64(12)).Return(&models.Stock{ProductID: "10", Quantity: 12}, nil)
+
+	// When: Emptying the stock and then restocking
+	stock, err := service.SetStock(ctx, "10", 0)
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), stock.Quantity)
+	stock, err = service.Restock(ctx, "10", 12)
+
+	// Then: The new stock level is returned
+	require.NoError(t, err)
+	assert.Equal(t, int64(12), stock.Quantity)
+}
+
+func TestStockService_InvalidQuantity(t *testing.T) {
+	// Given: A stock service whose repositories must not be called
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	service := NewStockService(mocks.NewMockStockRepository(ctrl), mocks.NewMockProductRepository(ctrl), libmocks.NewMockILogger(ctrl))
+	ctx := context.Background()
+
+	// When: Setting a negative stock or restocking nothing
+	_, setErr := service.SetStock(ctx, "10", -1)
+	_, restockErr := service.Restock(ctx, "10", 0)
+
+	// Then: Both should be rejected
+	assert.Equal(t, InvalidStockQuantity, setErr.Error())
+	assert.Equal(t, InvalidStockQuantity, restockErr.Error())
+}
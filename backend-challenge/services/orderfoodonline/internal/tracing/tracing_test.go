@@ -0,0 +1,77 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"orderfoodonline/internal/config"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestNewTracerProvider(t *testing.T) {
+	tests := []struct {
+		name        string
+		exporter    string
+		sampleRatio float64
+		sampled     bool
+	}{
+		{name: "no exporter", exporter: config.TracingExporterNone, sampleRatio: 1, sampled: true},
+		{name: "stdout exporter", exporter: config.TracingExporterStdout, sampleRatio: 1, sampled: true},
+		{name: "OTLP exporter", exporter: config.TracingExporterOTLP, sampleRatio: 1, sampled: true},
+		{name: "no sampling", exporter: config.TracingExporterNone, sampleRatio: 0, sampled: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// When: Creating a tracer provider for the exporter
+			provider, err := NewTracerProvider(context.Background(), &config.TracingConfig{
+				ServiceName: "orderfoodonline", Exporter: tt.exporter, Endpoint: "localhost:4318", Insecure: true, SampleRatio: tt.sampleRatio,
+			})
+
+			// Then: New traces should be sampled at the configured ratio
+			require.NoError(t, err)
+			_, span := provider.Tracer(TracerName).Start(context.Background(), "test")
+			assert.Equal(t, tt.sampled, span.SpanContext().IsSampled())
+		})
+	}
+}
+
+func TestEnd(t *testing.T) {
+	tests := []struct {
+		name           string
+		err            error
+		expectedStatus codes.Code
+	}{
+		{name: "success", expectedStatus: codes.Unset},
+		{name: "failure", err: errors.New("database unavailable"), expectedStatus: codes.Error},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given: A span recorded in memory
+			recorder := tracetest.NewSpanRecorder()
+			provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+			Init(provider)
+			_, span := Start(context.Background(), "OrderService.GetOrder")
+
+			// When: Ending the span
+			End(span, tt.err)
+
+			// Then: The span should be ended with the status of the error
+			spans := recorder.Ended()
+			require.Len(t, spans, 1)
+			assert.Equal(t, "OrderService.GetOrder", spans[0].Name())
+			assert.Equal(t, tt.expectedStatus, spans[0].Status().Code)
+			if tt.err != nil {
+				assert.Equal(t, tt.err.Error(), spans[0].Status().Description)
+				require.Len(t, spans[0].Events(), 1)
+				assert.Equal(t, "exception", spans[0].Events()[0].Name)
+			}
+		})
+	}
+}